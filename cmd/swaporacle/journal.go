@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fsn-dev/crossChain-Bridge/cmd/utils"
+	"github.com/fsn-dev/crossChain-Bridge/internal/journal"
+	"github.com/fsn-dev/crossChain-Bridge/params"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	journalFileFlag = &cli.StringFlag{
+		Name:  "file",
+		Usage: "Journal file (default 'acceptjournal.log' in datadir)",
+	}
+	journalKeyIDFlag = &cli.StringFlag{
+		Name:  "keyid",
+		Usage: "Filter by dcrm sign key ID",
+	}
+	journalPairIDFlag = &cli.StringFlag{
+		Name:  "pairid",
+		Usage: "Filter by pair identifier",
+	}
+	journalSwapIDFlag = &cli.StringFlag{
+		Name:  "swapid",
+		Usage: "Filter by swap ID (txid of deposit)",
+	}
+	journalResultFlag = &cli.StringFlag{
+		Name:  "result",
+		Usage: "Filter by result (AGREE, DISAGREE, IGNORE)",
+	}
+	journalFromSeqFlag = &cli.Uint64Flag{
+		Name:  "from",
+		Usage: "Filter by sequence number, from (inclusive)",
+	}
+	journalToSeqFlag = &cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Filter by sequence number, to (inclusive, 0 means the last)",
+	}
+	journalLimitFlag = &cli.IntFlag{
+		Name:  "limit",
+		Usage: "Max number of entries to query (0 means no limit)",
+		Value: 20,
+	}
+	journalOutputFlag = &cli.StringFlag{
+		Name:  "output",
+		Usage: "Export to file (default stdout)",
+	}
+
+	journalFilterFlags = []cli.Flag{
+		utils.DataDirFlag,
+		journalFileFlag,
+		journalKeyIDFlag,
+		journalPairIDFlag,
+		journalSwapIDFlag,
+		journalResultFlag,
+		journalFromSeqFlag,
+		journalToSeqFlag,
+	}
+
+	journalCommand = &cli.Command{
+		Name:  "journal",
+		Usage: "Query, export and verify accept sign journal",
+		Description: `
+accept sign decisions of the oracle are appended to a hash chained journal file.
+every entry records the sign info, the rebuilt msgHash, the verification error,
+the AGREE/DISAGREE (or IGNORE) result, and the response of dcrm.`,
+		Subcommands: []*cli.Command{
+			{
+				Action:    verifyJournal,
+				Name:      "verify",
+				Usage:     "Verify hash chain of journal",
+				ArgsUsage: " ",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					journalFileFlag,
+				},
+			},
+			{
+				Action:    queryJournal,
+				Name:      "query",
+				Usage:     "Query journal entries (latest first)",
+				ArgsUsage: " ",
+				Flags:     append(journalFilterFlags, journalLimitFlag),
+			},
+			{
+				Action:    exportJournal,
+				Name:      "export",
+				Usage:     "Export journal entries in json lines (in order)",
+				ArgsUsage: " ",
+				Flags:     append(journalFilterFlags, journalOutputFlag),
+			},
+		},
+	}
+)
+
+func getJournalFile(ctx *cli.Context) string {
+	if file := ctx.String(journalFileFlag.Name); file != "" {
+		return file
+	}
+	params.SetDataDir(ctx.String(utils.DataDirFlag.Name))
+	return journal.DefaultFilePath()
+}
+
+type journalFilter struct {
+	keyID   string
+	pairID  string
+	swapID  string
+	result  string
+	fromSeq uint64
+	toSeq   uint64
+}
+
+func newJournalFilter(ctx *cli.Context) *journalFilter {
+	return &journalFilter{
+		keyID:   ctx.String(journalKeyIDFlag.Name),
+		pairID:  ctx.String(journalPairIDFlag.Name),
+		swapID:  ctx.String(journalSwapIDFlag.Name),
+		result:  ctx.String(journalResultFlag.Name),
+		fromSeq: ctx.Uint64(journalFromSeqFlag.Name),
+		toSeq:   ctx.Uint64(journalToSeqFlag.Name),
+	}
+}
+
+func (f *journalFilter) match(entry *journal.Entry) bool {
+	switch {
+	case entry.Seq < f.fromSeq,
+		f.toSeq != 0 && entry.Seq > f.toSeq,
+		f.keyID != "" && !strings.EqualFold(entry.KeyID, f.keyID),
+		f.pairID != "" && !strings.EqualFold(entry.PairID, f.pairID),
+		f.swapID != "" && !strings.EqualFold(entry.SwapID, f.swapID),
+		f.result != "" && !strings.EqualFold(entry.Result, f.result):
+		return false
+	}
+	return true
+}
+
+func verifyJournal(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	file := getJournalFile(ctx)
+	count, err := journal.Verify(file)
+	if err != nil {
+		return fmt.Errorf("verify journal %v failed after %v entries: %w", file, count, err)
+	}
+	fmt.Printf("journal %v is valid, %v entries\n", file, count)
+	return nil
+}
+
+func queryJournal(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	filter := newJournalFilter(ctx)
+	limit := ctx.Int(journalLimitFlag.Name)
+	var entries []*journal.Entry
+	err := journal.ReadEntries(getJournalFile(ctx), func(entry *journal.Entry) error {
+		if !filter.match(entry) {
+			return nil
+		}
+		entries = append(entries, entry)
+		if limit > 0 && len(entries) > limit {
+			entries = entries[1:]
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// latest first
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func exportJournal(ctx *cli.Context) (err error) {
+	utils.SetLogger(ctx)
+	filter := newJournalFilter(ctx)
+	var writer io.Writer = os.Stdout
+	if output := ctx.String(journalOutputFlag.Name); output != "" {
+		file, errf := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if errf != nil {
+			return errf
+		}
+		defer func() {
+			if errc := file.Close(); err == nil {
+				err = errc
+			}
+		}()
+		writer = file
+	}
+	encoder := json.NewEncoder(writer)
+	return journal.ReadEntries(getJournalFile(ctx), func(entry *journal.Entry) error {
+		if !filter.match(entry) {
+			return nil
+		}
+		return encoder.Encode(entry)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"sort"
 
 	"github.com/fsn-dev/crossChain-Bridge/cmd/utils"
+	"github.com/fsn-dev/crossChain-Bridge/internal/oracleindex"
 	"github.com/fsn-dev/crossChain-Bridge/log"
 	"github.com/fsn-dev/crossChain-Bridge/params"
 	"github.com/fsn-dev/crossChain-Bridge/worker"
@@ -28,6 +29,7 @@ func initApp() {
 	app.Commands = []*cli.Command{
 		utils.LicenseCommand,
 		utils.VersionCommand,
+		journalCommand,
 	}
 	app.Flags = []cli.Flag{
 		utils.DataDirFlag,
@@ -61,6 +63,10 @@ func swaporacle(ctx *cli.Context) error {
 
 	params.SetDataDir(ctx.String(utils.DataDirFlag.Name))
 
+	if err := oracleindex.Init(); err != nil {
+		log.Fatal("open oracle index failed", "file", oracleindex.DefaultFilePath(), "err", err)
+	}
+
 	worker.StartWork(false)
 
 	<-exitCh
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/fsn-dev/crossChain-Bridge/cmd/utils"
+	"github.com/fsn-dev/crossChain-Bridge/params"
+	"github.com/fsn-dev/crossChain-Bridge/rpc/client"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+	"github.com/fsn-dev/crossChain-Bridge/tokens/bridge"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	backfillPairIDFlag = &cli.StringFlag{
+		Name:  "pairid",
+		Usage: "Pair identifier (default the first pair)",
+	}
+	backfillDestFlag = &cli.BoolFlag{
+		Name:  "dest",
+		Usage: "Scan dest chain (swapouts) instead of source chain (swapins)",
+	}
+	backfillStartFlag = &cli.Uint64Flag{
+		Name:     "start",
+		Usage:    "Start block height",
+		Required: true,
+	}
+	backfillEndFlag = &cli.Uint64Flag{
+		Name:  "end",
+		Usage: "End block height, 0 means latest block",
+	}
+
+	backfillCommand = &cli.Command{
+		Action:    backfill,
+		Name:      "backfill",
+		Usage:     "Scan swap history in block range and register missing swaps",
+		ArgsUsage: " ",
+		Description: `
+scan swapin (or swapout with '--dest') logs of the contract in block range [start, end]
+by eth_getLogs, and register the swaps which are not registered yet.
+the history scan checkpoint of the running server is not changed.
+the embedded storage is locked by the running server, stop it before backfilling.`,
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.ConfigFileFlag,
+			backfillPairIDFlag,
+			backfillDestFlag,
+			backfillStartFlag,
+			backfillEndFlag,
+		},
+	}
+)
+
+func backfill(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	configFile := utils.GetConfigFilePath(ctx)
+	config := params.LoadConfig(configFile, true)
+
+	params.SetDataDir(ctx.String(utils.DataDirFlag.Name))
+
+	initStorage(config)
+
+	client.InitHTTPClient()
+	bridge.InitCrossChainBridge(true)
+
+	pairID := ctx.String(backfillPairIDFlag.Name)
+	if pairID == "" {
+		pairID = tokens.GetDefaultPairID()
+	}
+	if tokens.GetBridgePair(pairID) == nil {
+		return fmt.Errorf("pair %v is not configed", pairID)
+	}
+	isSrc := !ctx.Bool(backfillDestFlag.Name)
+	br := tokens.GetCrossChainBridge(pairID, isSrc)
+	backfiller, ok := br.(tokens.SwapHistoryBackfiller)
+	if !ok {
+		return fmt.Errorf("bridge of pair %v (isSrc=%v) does not support backfill", pairID, isSrc)
+	}
+
+	start := ctx.Uint64(backfillStartFlag.Name)
+	end := ctx.Uint64(backfillEndFlag.Name)
+	if end == 0 {
+		latest, err := br.GetLatestBlockNumber()
+		if err != nil {
+			return err
+		}
+		end = latest
+	}
+	return backfiller.BackfillSwapHistory(start, end)
+}
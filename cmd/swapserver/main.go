@@ -31,6 +31,8 @@ func initApp() {
 	app.Commands = []*cli.Command{
 		utils.LicenseCommand,
 		utils.VersionCommand,
+		migrateCommand,
+		backfillCommand,
 	}
 	app.Flags = []cli.Flag{
 		utils.DataDirFlag,
@@ -64,10 +66,7 @@ func swapserver(ctx *cli.Context) error {
 
 	params.SetDataDir(ctx.String(utils.DataDirFlag.Name))
 
-	dbConfig := config.MongoDB
-	mongoURL := dbConfig.GetURL()
-	dbName := dbConfig.DBName
-	mongodb.MongoServerInit(mongoURL, dbName)
+	initStorage(config)
 
 	worker.StartWork(true)
 	time.Sleep(100 * time.Millisecond)
@@ -76,3 +75,35 @@ func swapserver(ctx *cli.Context) error {
 	<-exitCh
 	return nil
 }
+
+func initStorage(config *params.ServerConfig) {
+	backend := config.Storage.GetBackend()
+	log.Info("init storage backend", "backend", backend)
+	switch backend {
+	case params.StorageBackendMongoDB:
+		dbConfig := config.MongoDB
+		mongoURL := dbConfig.GetURL()
+		dbName := dbConfig.DBName
+		mongodb.MongoServerInit(mongoURL, dbName)
+	case params.StorageBackendMemory:
+		mongodb.SetStore(mongodb.NewMemStore())
+	case params.StorageBackendEmbedded:
+		mongodb.SetStore(openEmbeddedStore(config))
+	default:
+		log.Fatalf("unknown storage backend %v", backend)
+	}
+	// the default pair keeps the original tables, other pairs use their own namespace
+	for _, pair := range config.Pairs {
+		mongodb.AddPairStore(pair.Identifier, mongodb.GetStore().WithNamespace(pair.Identifier))
+	}
+}
+
+func openEmbeddedStore(config *params.ServerConfig) *mongodb.BoltStore {
+	file := config.Storage.GetEmbeddedFilePath()
+	store, err := mongodb.NewBoltStore(file)
+	if err != nil {
+		log.Fatal("open embedded storage failed", "file", file, "err", err)
+	}
+	log.Info("open embedded storage success", "file", file)
+	return store
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/fsn-dev/crossChain-Bridge/cmd/utils"
+	"github.com/fsn-dev/crossChain-Bridge/mongodb"
+	"github.com/fsn-dev/crossChain-Bridge/params"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	migrateCommand = &cli.Command{
+		Action:    migrate,
+		Name:      "migrate",
+		Usage:     "Copy mongodb database into embedded storage",
+		ArgsUsage: " ",
+		Description: `
+copy all tables of the database configed in '[MongoDB]' section
+into the embedded storage file configed in '[Storage]' section.
+tables of the pairs configed in '[[Pairs]]' sections are copied
+into the namespace of their identifier.
+items already exist in the embedded storage are skipped.`,
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.ConfigFileFlag,
+		},
+	}
+)
+
+func migrate(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	configFile := utils.GetConfigFilePath(ctx)
+	config := params.LoadConfig(configFile, true)
+	if config.MongoDB == nil {
+		return errors.New("migrate must config 'MongoDB'")
+	}
+
+	params.SetDataDir(ctx.String(utils.DataDirFlag.Name))
+
+	dbConfig := config.MongoDB
+	mongodb.MongoServerInit(dbConfig.GetURL(), dbConfig.DBName)
+
+	store := openEmbeddedStore(config)
+	defer func() { _ = store.Close() }()
+
+	if err := mongodb.MigrateToStore("", store); err != nil {
+		return err
+	}
+	for _, pair := range config.Pairs {
+		if err := mongodb.MigrateToStore(pair.Identifier, store.WithNamespace(pair.Identifier)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -3,7 +3,10 @@ package dcrm
 import (
 	"crypto/rand"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/fsn-dev/crossChain-Bridge/common"
 	"github.com/fsn-dev/crossChain-Bridge/log"
@@ -12,6 +15,17 @@ import (
 	"github.com/fsn-dev/crossChain-Bridge/types"
 )
 
+var (
+	signWaitInterval  = 10 * time.Second
+	signRetryCount    = 15
+	signRetryInterval = 10 * time.Second
+
+	// ErrWrongSignRsvCount count of rsvs is not the count of msgHashes
+	ErrWrongSignRsvCount = errors.New("wrong count of sign rsv")
+	// ErrWaitSignResultTimeout sign status is not ready after retries
+	ErrWaitSignResultTimeout = errors.New("wait sign result timeout")
+)
+
 // DoSignOne dcrm sign single msgHash with context msgContext
 func DoSignOne(msgHash, msgContext string) (string, error) {
 	return DoSign([]string{msgHash}, []string{msgContext})
@@ -19,14 +33,19 @@ func DoSignOne(msgHash, msgContext string) (string, error) {
 
 // DoSign dcrm sign msgHash with context msgContext
 func DoSign(msgHash, msgContext []string) (string, error) {
-	log.Debug("dcrm DoSign", "msgHash", msgHash, "msgContext", msgContext)
+	// randomly pick sub-group to sign
+	randIndex, _ := rand.Int(rand.Reader, big.NewInt(int64(len(signGroups))))
+	signGroup := signGroups[randIndex.Int64()]
+	return DoSignInGroup(signGroup, msgHash, msgContext)
+}
+
+// DoSignInGroup dcrm sign msgHash with context msgContext by the specified sub-group, return keyID
+func DoSignInGroup(signGroup string, msgHash, msgContext []string) (string, error) {
+	log.Debug("dcrm DoSign", "msgHash", msgHash, "msgContext", msgContext, "signGroup", signGroup)
 	nonce, err := GetSignNonce()
 	if err != nil {
 		return "", err
 	}
-	// randomly pick sub-group to sign
-	randIndex, _ := rand.Int(rand.Reader, big.NewInt(int64(len(signGroups))))
-	signGroup := signGroups[randIndex.Int64()]
 	txdata := SignData{
 		TxType:     "SIGN",
 		PubKey:     signPubkey,
@@ -46,6 +65,43 @@ func DoSign(msgHash, msgContext []string) (string, error) {
 	return Sign(rawTX)
 }
 
+// GetSignResult get rsvs of sign request keyID, rsvCount is the count of signed msgHashes
+func GetSignResult(keyID string, rsvCount int) ([]string, error) {
+	signStatus, err := GetSignStatus(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(signStatus.Rsv) != rsvCount {
+		return nil, fmt.Errorf("%w: require %v rsv but have %v (keyID = %v)", ErrWrongSignRsvCount, rsvCount, len(signStatus.Rsv), keyID)
+	}
+	return signStatus.Rsv, nil
+}
+
+// IsSignFinalError is sign result error final (retry getting sign status is useless)
+func IsSignFinalError(err error) bool {
+	return err == ErrGetSignStatusFailed ||
+		err == ErrGetSignStatusTimeout ||
+		errors.Is(err, ErrWrongSignRsvCount)
+}
+
+// WaitSignResult wait rsvs of sign request keyID by polling its sign status
+func WaitSignResult(keyID string, rsvCount int) (rsv []string, err error) {
+	time.Sleep(signWaitInterval)
+	for i := 0; i < signRetryCount; i++ {
+		rsv, err = GetSignResult(keyID, rsvCount)
+		if err == nil {
+			log.Trace("dcrm get sign result success", "keyID", keyID, "rsv", rsv)
+			return rsv, nil
+		}
+		if IsSignFinalError(err) {
+			return nil, err
+		}
+		log.Warn("retry get sign status as error", "keyID", keyID, "err", err)
+		time.Sleep(signRetryInterval)
+	}
+	return nil, ErrWaitSignResultTimeout
+}
+
 // BuildDcrmRawTx build dcrm raw tx
 func BuildDcrmRawTx(nonce uint64, payload []byte) (string, error) {
 	tx := types.NewTransaction(
@@ -19,6 +19,7 @@ require (
 	github.com/sirupsen/logrus v1.5.0
 	github.com/stretchr/testify v1.5.1
 	github.com/urfave/cli/v2 v2.2.0
+	go.etcd.io/bbolt v1.3.5
 	golang.org/x/crypto v0.0.0-20200414173820-0848c9571904
 	gopkg.in/mgo.v2 v2.0.0-20190816093944-a6b53ec6cb22
 )
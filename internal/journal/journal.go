@@ -0,0 +1,245 @@
+// Package journal provides the durable append-only journal of oracle accept sign decisions.
+// every entry commits the hash of its previous entry, so modifying, deleting or reordering
+// entries breaks the hash chain and is detected when the journal is read.
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fsn-dev/crossChain-Bridge/common"
+	"github.com/fsn-dev/crossChain-Bridge/dcrm"
+	"github.com/fsn-dev/crossChain-Bridge/log"
+	"github.com/fsn-dev/crossChain-Bridge/params"
+)
+
+// accept sign results
+const (
+	ResultAgree    = "AGREE"
+	ResultDisagree = "DISAGREE"
+	ResultIgnore   = "IGNORE"   // sign info is not for this oracle or not ready to verify
+	ResultResponse = "RESPONSE" // response of dcrm to the decision sent after it's journaled
+)
+
+const (
+	defaultFileName = "acceptjournal.log"
+	maxEntrySize    = 1024 * 1024 * 10 // 10M
+)
+
+// ErrBrokenChain journal entries do not match their hashes or the hash chain
+var ErrBrokenChain = errors.New("journal hash chain is broken")
+
+// Entry accept sign decision
+type Entry struct {
+	Seq            uint64             `json:"seq"`
+	Timestamp      int64              `json:"timestamp"`
+	KeyID          string             `json:"keyID"`
+	PairID         string             `json:"pairID,omitempty"`
+	SwapID         string             `json:"swapID,omitempty"`
+	SwapType       uint32             `json:"swapType"`
+	SignInfo       *dcrm.SignInfoData `json:"signInfo"`
+	From           string             `json:"from,omitempty"`  // sender of deposit
+	Bind           string             `json:"bind,omitempty"`  // receiver of swap
+	Value          string             `json:"value,omitempty"` // deposit value (in bits)
+	RebuiltMsgHash []string           `json:"rebuiltMsgHash,omitempty"`
+	Verified       bool               `json:"verified"`
+	VerifyError    string             `json:"verifyError,omitempty"`
+	PolicyError    string             `json:"policyError,omitempty"`
+	Result         string             `json:"result"`
+	DcrmResponse   string             `json:"dcrmResponse,omitempty"`
+	DcrmError      string             `json:"dcrmError,omitempty"`
+	PrevHash       string             `json:"prevHash"`
+	Hash           string             `json:"hash"`
+}
+
+// CalcHash hash of entry (with empty hash field), it commits the previous hash
+func (e *Entry) CalcHash() (string, error) {
+	cpy := *e
+	cpy.Hash = ""
+	data, err := json.Marshal(&cpy)
+	if err != nil {
+		return "", err
+	}
+	return common.Keccak256Hash(data).String(), nil
+}
+
+// IsAccepted is decision which should not be made again. decisions are journaled before
+// they are sent to dcrm, while old journals record failed sending in the decision entry.
+func (e *Entry) IsAccepted() bool {
+	return (e.Result == ResultAgree || e.Result == ResultDisagree) && e.DcrmError == ""
+}
+
+// IsAgreed is agreement which is sent (or to be sent) to dcrm
+func (e *Entry) IsAgreed() bool {
+	return e.Result == ResultAgree && e.IsAccepted()
+}
+
+// Journal append-only journal file
+type Journal struct {
+	mu       sync.Mutex
+	path     string
+	file     *os.File
+	lastSeq  uint64
+	lastHash string
+}
+
+// DefaultFilePath default journal file in data dir
+func DefaultFilePath() string {
+	return common.AbsolutePath(params.DataDir, defaultFileName)
+}
+
+func genesisHash() string {
+	return common.Hash{}.String()
+}
+
+// Open open (create if not exist) journal, verify the hash chain and replay existing entries.
+// incomplete last line (eg. crashed in writing) is truncated.
+func Open(path string, replay func(*Entry)) (*Journal, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	j := &Journal{path: path, file: file, lastHash: genesisHash()}
+	validSize, err := readEntries(file, func(entry *Entry) error {
+		j.lastSeq = entry.Seq
+		j.lastHash = entry.Hash
+		if replay != nil {
+			replay(entry)
+		}
+		return nil
+	})
+	if err == nil {
+		err = j.truncate(validSize)
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	log.Info("open accept journal success", "path", path, "entries", j.lastSeq)
+	return j, nil
+}
+
+func (j *Journal) truncate(validSize int64) error {
+	info, err := j.file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() > validSize {
+		log.Warn("truncate incomplete journal entry", "path", j.path, "size", info.Size(), "validSize", validSize)
+		if err = j.file.Truncate(validSize); err != nil {
+			return err
+		}
+	}
+	_, err = j.file.Seek(validSize, io.SeekStart)
+	return err
+}
+
+// Append set sequence, previous hash and hash of entry, then write and sync it to disk
+func (j *Journal) Append(entry *Entry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return os.ErrClosed
+	}
+	entry.Seq = j.lastSeq + 1
+	if entry.Timestamp == 0 {
+		entry.Timestamp = time.Now().Unix()
+	}
+	entry.PrevHash = j.lastHash
+	hash, err := entry.CalcHash()
+	if err != nil {
+		return err
+	}
+	entry.Hash = hash
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err = j.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	if err = j.file.Sync(); err != nil {
+		return err
+	}
+	j.lastSeq = entry.Seq
+	j.lastHash = entry.Hash
+	return nil
+}
+
+// Close close journal
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// ReadEntries read and verify all entries of journal file in order
+func ReadEntries(path string, fn func(*Entry) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = readEntries(file, fn)
+	return err
+}
+
+// Verify verify hash chain of journal file, return count of entries
+func Verify(path string) (count uint64, err error) {
+	err = ReadEntries(path, func(entry *Entry) error {
+		count = entry.Seq
+		return nil
+	})
+	return count, err
+}
+
+// readEntries read complete lines (ended with newline), return the size of them
+func readEntries(r io.Reader, fn func(*Entry) error) (validSize int64, err error) {
+	reader := bufio.NewReader(r)
+	prevHash := genesisHash()
+	var seq uint64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			return validSize, nil // incomplete last line is ignored
+		}
+		if err != nil {
+			return validSize, err
+		}
+		if len(line) > maxEntrySize {
+			return validSize, fmt.Errorf("journal entry %v is too large", seq+1)
+		}
+		var entry Entry
+		if err = json.Unmarshal(bytes.TrimSpace(line), &entry); err != nil {
+			return validSize, fmt.Errorf("%w: entry %v is not valid json: %v", ErrBrokenChain, seq+1, err)
+		}
+		seq++
+		if entry.Seq != seq || entry.PrevHash != prevHash {
+			return validSize, fmt.Errorf("%w: entry %v has seq %v and previous hash %v", ErrBrokenChain, seq, entry.Seq, entry.PrevHash)
+		}
+		hash, err := entry.CalcHash()
+		if err != nil {
+			return validSize, err
+		}
+		if hash != entry.Hash {
+			return validSize, fmt.Errorf("%w: entry %v hash mismatch, have %v want %v", ErrBrokenChain, seq, entry.Hash, hash)
+		}
+		if err = fn(&entry); err != nil {
+			return validSize, err
+		}
+		prevHash = entry.Hash
+		validSize += int64(len(line))
+	}
+}
@@ -0,0 +1,180 @@
+package journal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestJournal(t *testing.T, count int) (path string, cleanup func()) {
+	dir, err := ioutil.TempDir("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path = filepath.Join(dir, defaultFileName)
+	j, err := Open(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	for i := 1; i <= count; i++ {
+		entry := &Entry{
+			KeyID:  fmt.Sprintf("key%v", i),
+			PairID: "btc",
+			SwapID: fmt.Sprintf("0x%02d", i),
+			Value:  fmt.Sprintf("%v", i*1000),
+			Result: ResultAgree,
+		}
+		if err = j.Append(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func readLines(t *testing.T, path string) [][]byte {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	return lines[:len(lines)-1] // the last one is empty after the ending newline
+}
+
+func writeLines(t *testing.T, path string, lines [][]byte) {
+	if err := ioutil.WriteFile(path, bytes.Join(lines, nil), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAppendAndVerify(t *testing.T) {
+	path, cleanup := newTestJournal(t, 3)
+	defer cleanup()
+
+	count, err := Verify(path)
+	if err != nil || count != 3 {
+		t.Fatalf("verify journal, want 3 entries, have %v, err %v", count, err)
+	}
+
+	var replayed []*Entry
+	j, err := Open(path, func(entry *Entry) { replayed = append(replayed, entry) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed) != 3 {
+		t.Fatalf("replay journal, want 3 entries, have %v", len(replayed))
+	}
+	prevHash := genesisHash()
+	for i, entry := range replayed {
+		if entry.Seq != uint64(i+1) || entry.SwapID != fmt.Sprintf("0x%02d", i+1) || entry.PrevHash != prevHash {
+			t.Fatalf("wrong replayed entry %v: %+v", i+1, entry)
+		}
+		prevHash = entry.Hash
+	}
+
+	// appending after reopen continues the hash chain
+	entry := &Entry{KeyID: "key4", SwapID: "0x04", Result: ResultDisagree}
+	if err = j.Append(entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Seq != 4 || entry.PrevHash != prevHash {
+		t.Fatalf("wrong appended entry, seq %v prevHash %v", entry.Seq, entry.PrevHash)
+	}
+	_ = j.Close()
+	if err = j.Append(&Entry{}); err != os.ErrClosed {
+		t.Fatalf("append to closed journal, want %v, have %v", os.ErrClosed, err)
+	}
+	if count, err = Verify(path); err != nil || count != 4 {
+		t.Fatalf("verify journal, want 4 entries, have %v, err %v", count, err)
+	}
+}
+
+func TestTruncateIncompleteEntry(t *testing.T) {
+	path, cleanup := newTestJournal(t, 2)
+	defer cleanup()
+
+	lines := readLines(t, path)
+	// crashed in writing the third entry
+	writeLines(t, path, append(lines, []byte(`{"seq":3,"keyID":"key3"`)))
+
+	j, err := Open(path, nil)
+	if err != nil {
+		t.Fatalf("open journal with incomplete last entry failed: %v", err)
+	}
+	if err = j.Append(&Entry{KeyID: "key3", Result: ResultIgnore}); err != nil {
+		t.Fatal(err)
+	}
+	_ = j.Close()
+	if count, err := Verify(path); err != nil || count != 3 {
+		t.Fatalf("verify journal, want 3 entries, have %v, err %v", count, err)
+	}
+}
+
+func TestBrokenChain(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(t *testing.T, lines [][]byte) [][]byte
+	}{
+		{
+			name: "tampered entry",
+			modify: func(t *testing.T, lines [][]byte) [][]byte {
+				lines[1] = bytes.Replace(lines[1], []byte(`"value":"2000"`), []byte(`"value":"9000"`), 1)
+				return lines
+			},
+		},
+		{
+			name: "tampered entry with recalculated hash",
+			modify: func(t *testing.T, lines [][]byte) [][]byte {
+				var entry Entry
+				if err := json.Unmarshal(lines[1], &entry); err != nil {
+					t.Fatal(err)
+				}
+				entry.Result = ResultDisagree
+				entry.Hash, _ = entry.CalcHash()
+				data, _ := json.Marshal(&entry)
+				lines[1] = append(data, '\n')
+				return lines
+			},
+		},
+		{
+			name: "reordered entries",
+			modify: func(t *testing.T, lines [][]byte) [][]byte {
+				lines[1], lines[2] = lines[2], lines[1]
+				return lines
+			},
+		},
+		{
+			name: "deleted entry",
+			modify: func(t *testing.T, lines [][]byte) [][]byte {
+				return append(lines[:1], lines[2:]...)
+			},
+		},
+		{
+			name: "invalid entry",
+			modify: func(t *testing.T, lines [][]byte) [][]byte {
+				lines[0] = []byte("not json\n")
+				return lines
+			},
+		},
+	}
+	for _, test := range tests {
+		path, cleanup := newTestJournal(t, 3)
+		writeLines(t, path, test.modify(t, readLines(t, path)))
+
+		if _, err := Verify(path); !errors.Is(err, ErrBrokenChain) {
+			t.Errorf("%v: verify journal, want %v, have %v", test.name, ErrBrokenChain, err)
+		}
+		if j, err := Open(path, nil); !errors.Is(err, ErrBrokenChain) {
+			if j != nil {
+				_ = j.Close()
+			}
+			t.Errorf("%v: open journal, want %v, have %v", test.name, ErrBrokenChain, err)
+		}
+		cleanup()
+	}
+}
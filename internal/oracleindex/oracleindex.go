@@ -0,0 +1,306 @@
+// Package oracleindex provides the oracle's own index of deposits, which are found by scanning
+// through the oracle's own gateways, and the records of swaps the oracle has agreed to sign.
+// the oracle does not trust the swap server, it only signs swaps whose deposits are in this index.
+package oracleindex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fsn-dev/crossChain-Bridge/common"
+	"github.com/fsn-dev/crossChain-Bridge/params"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	defaultFileName = "oracleindex.bolt"
+
+	bucketDeposits    = "Deposits"
+	bucketSignRecords = "SignRecords"
+	bucketP2shBinds   = "P2shBinds"
+)
+
+var (
+	index *Index
+
+	// ErrNotInitialized index is not initialized
+	ErrNotInitialized = errors.New("oracle index is not initialized")
+	// ErrDepositNotFound deposit is not in index
+	ErrDepositNotFound = errors.New("deposit is not found in oracle index")
+)
+
+// Deposit deposit found by scanning
+type Deposit struct {
+	PairID     string `json:"pairID"`
+	TxID       string `json:"txid"`
+	TxType     uint32 `json:"txType"`
+	Bind       string `json:"bind"`
+	Registered bool   `json:"registered"` // registered on swap server, registering is retried if not
+	Timestamp  int64  `json:"timestamp"`
+}
+
+// SignRecord msgHash of swap tx agreed to sign
+type SignRecord struct {
+	KeyID     string   `json:"keyID"`
+	MsgHash   []string `json:"msgHash"`
+	SpendKeys []string `json:"spendKeys"` // nonce or utxos spent by the swap tx
+	Timestamp int64    `json:"timestamp"`
+}
+
+// IsConflictWith is the swap tx conflict with another one (spend the same nonce or utxo),
+// conflicting txs can not be both confirmed, eg. replacement of swap tx.
+func (r *SignRecord) IsConflictWith(spendKeys []string) bool {
+	for _, key := range spendKeys {
+		for _, spent := range r.SpendKeys {
+			if key == spent {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsSameMsgHash is msgHash equal to the recorded one
+func (r *SignRecord) IsSameMsgHash(msgHash []string) bool {
+	if len(r.MsgHash) != len(msgHash) {
+		return false
+	}
+	for i, hash := range msgHash {
+		if !strings.EqualFold(hash, r.MsgHash[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Index embedded single-file index
+type Index struct {
+	db *bolt.DB
+}
+
+// DefaultFilePath default index file in data dir
+func DefaultFilePath() string {
+	return common.AbsolutePath(params.DataDir, defaultFileName)
+}
+
+// Init open the index file in data dir as the default index
+func Init() error {
+	idx, err := Open(DefaultFilePath())
+	if err != nil {
+		return err
+	}
+	index = idx
+	return nil
+}
+
+// Open open (create if not exist) index file
+func Open(path string) (*Index, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 3 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range []string{bucketDeposits, bucketSignRecords, bucketP2shBinds} {
+			if _, errc := tx.CreateBucketIfNotExists([]byte(bucket)); errc != nil {
+				return errc
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &Index{db: db}, nil
+}
+
+// Close close index file
+func (idx *Index) Close() error {
+	return idx.db.Close()
+}
+
+func getDepositKey(pairID string, isSwapin bool, txid string) []byte {
+	swapType := "swapout"
+	if isSwapin {
+		swapType = "swapin"
+	}
+	return []byte(strings.ToLower(fmt.Sprintf("%v/%v/%v", pairID, swapType, txid)))
+}
+
+func getSwapKey(pairID string, swapType uint32, swapID string) []byte {
+	return []byte(strings.ToLower(fmt.Sprintf("%v/%v/%v", pairID, swapType, swapID)))
+}
+
+// base58 address is case sensitive
+func getP2shBindKey(pairID, p2shAddress string) []byte {
+	return []byte(fmt.Sprintf("%v/%v", strings.ToLower(pairID), p2shAddress))
+}
+
+func (idx *Index) put(bucket string, key []byte, item interface{}) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return idx.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(bucket)).Put(key, data)
+	})
+}
+
+// get unmarshal item into result, return false if not exist
+func (idx *Index) get(bucket string, key []byte, result interface{}) (exist bool, err error) {
+	err = idx.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket([]byte(bucket)).Get(key)
+		if data == nil {
+			return nil
+		}
+		exist = true
+		return json.Unmarshal(data, result)
+	})
+	return exist, err
+}
+
+// AddDeposit add deposit (swapin if isSwapin, otherwise swapout), existing one is kept
+func (idx *Index) AddDeposit(isSwapin bool, deposit *Deposit) error {
+	key := getDepositKey(deposit.PairID, isSwapin, deposit.TxID)
+	exist, err := idx.get(bucketDeposits, key, &Deposit{})
+	if err != nil || exist {
+		return err
+	}
+	if deposit.Timestamp == 0 {
+		deposit.Timestamp = time.Now().Unix()
+	}
+	return idx.put(bucketDeposits, key, deposit)
+}
+
+// GetDeposit get deposit, return nil if not exist
+func (idx *Index) GetDeposit(pairID string, isSwapin bool, txid string) (*Deposit, error) {
+	var deposit Deposit
+	exist, err := idx.get(bucketDeposits, getDepositKey(pairID, isSwapin, txid), &deposit)
+	if err != nil || !exist {
+		return nil, err
+	}
+	return &deposit, nil
+}
+
+// SetDepositRegistered mark deposit as registered on swap server
+func (idx *Index) SetDepositRegistered(pairID string, isSwapin bool, txid string) error {
+	deposit, err := idx.GetDeposit(pairID, isSwapin, txid)
+	if err != nil {
+		return err
+	}
+	if deposit == nil {
+		return ErrDepositNotFound
+	}
+	if deposit.Registered {
+		return nil
+	}
+	deposit.Registered = true
+	return idx.put(bucketDeposits, getDepositKey(pairID, isSwapin, txid), deposit)
+}
+
+// AddP2shBindAddress add bind address of p2sh (or p2wsh) address
+func (idx *Index) AddP2shBindAddress(pairID, p2shAddress, bindAddress string) error {
+	return idx.put(bucketP2shBinds, getP2shBindKey(pairID, p2shAddress), bindAddress)
+}
+
+// GetP2shBindAddress get bind address of p2sh (or p2wsh) address, return empty if not exist
+func (idx *Index) GetP2shBindAddress(pairID, p2shAddress string) (bindAddress string, err error) {
+	_, err = idx.get(bucketP2shBinds, getP2shBindKey(pairID, p2shAddress), &bindAddress)
+	return bindAddress, err
+}
+
+// AddSignRecord add sign record of swap, record of the same msgHash is kept
+func (idx *Index) AddSignRecord(pairID string, swapType uint32, swapID string, record *SignRecord) error {
+	key := getSwapKey(pairID, swapType, swapID)
+	var records []*SignRecord
+	if _, err := idx.get(bucketSignRecords, key, &records); err != nil {
+		return err
+	}
+	for _, item := range records {
+		if item.IsSameMsgHash(record.MsgHash) {
+			return nil
+		}
+	}
+	if record.Timestamp == 0 {
+		record.Timestamp = time.Now().Unix()
+	}
+	return idx.put(bucketSignRecords, key, append(records, record))
+}
+
+// GetSignRecords get sign records of swap
+func (idx *Index) GetSignRecords(pairID string, swapType uint32, swapID string) (records []*SignRecord, err error) {
+	_, err = idx.get(bucketSignRecords, getSwapKey(pairID, swapType, swapID), &records)
+	return records, err
+}
+
+// AddDeposit add deposit to the default index
+func AddDeposit(isSwapin bool, deposit *Deposit) error {
+	if index == nil {
+		return ErrNotInitialized
+	}
+	return index.AddDeposit(isSwapin, deposit)
+}
+
+// GetDeposit get deposit from the default index
+func GetDeposit(pairID string, isSwapin bool, txid string) (*Deposit, error) {
+	if index == nil {
+		return nil, ErrNotInitialized
+	}
+	return index.GetDeposit(pairID, isSwapin, txid)
+}
+
+// IsDepositExist is deposit in the default index
+func IsDepositExist(pairID string, isSwapin bool, txid string) bool {
+	deposit, _ := GetDeposit(pairID, isSwapin, txid)
+	return deposit != nil
+}
+
+// SetDepositRegistered mark deposit in the default index as registered on swap server
+func SetDepositRegistered(pairID string, isSwapin bool, txid string) error {
+	if index == nil {
+		return ErrNotInitialized
+	}
+	return index.SetDepositRegistered(pairID, isSwapin, txid)
+}
+
+// IsDepositRegistered is deposit in the default index and registered on swap server
+func IsDepositRegistered(pairID string, isSwapin bool, txid string) bool {
+	deposit, _ := GetDeposit(pairID, isSwapin, txid)
+	return deposit != nil && deposit.Registered
+}
+
+// AddP2shBindAddress add bind address of p2sh (or p2wsh) address to the default index
+func AddP2shBindAddress(pairID, p2shAddress, bindAddress string) error {
+	if index == nil {
+		return ErrNotInitialized
+	}
+	return index.AddP2shBindAddress(pairID, p2shAddress, bindAddress)
+}
+
+// GetP2shBindAddress get bind address of p2sh (or p2wsh) address from the default index
+func GetP2shBindAddress(pairID, p2shAddress string) string {
+	if index == nil {
+		return ""
+	}
+	bindAddress, _ := index.GetP2shBindAddress(pairID, p2shAddress)
+	return bindAddress
+}
+
+// AddSignRecord add sign record to the default index
+func AddSignRecord(pairID string, swapType uint32, swapID string, record *SignRecord) error {
+	if index == nil {
+		return ErrNotInitialized
+	}
+	return index.AddSignRecord(pairID, swapType, swapID, record)
+}
+
+// GetSignRecords get sign records from the default index
+func GetSignRecords(pairID string, swapType uint32, swapID string) ([]*SignRecord, error) {
+	if index == nil {
+		return nil, ErrNotInitialized
+	}
+	return index.GetSignRecords(pairID, swapType, swapID)
+}
@@ -10,12 +10,14 @@ import (
 	"github.com/fsn-dev/crossChain-Bridge/params"
 	"github.com/fsn-dev/crossChain-Bridge/tokens"
 	"github.com/fsn-dev/crossChain-Bridge/tokens/btc"
+	"github.com/fsn-dev/crossChain-Bridge/tokens/tools"
 	rpcjson "github.com/gorilla/rpc/v2/json2"
 )
 
 var (
-	errSwapExist = newRPCError(-32097, "swap already exist")
+	errSwapExist = newRPCError(tools.ErrCodeSwapExist, "swap already exist")
 	errNotBridge = newRPCError(-32096, "bridge is not btc")
+	errNoPair    = newRPCError(-32095, "bridge pair not found")
 )
 
 func newRPCError(ec rpcjson.ErrorCode, message string) error {
@@ -29,6 +31,18 @@ func newRPCInternalError(err error) error {
 	return newRPCError(-32000, "rpcError: "+err.Error())
 }
 
+// getBridgePair get bridge pair of pair id, empty pair id means the default pair
+func getBridgePair(pairID string) (*tokens.BridgePair, error) {
+	if pairID == "" {
+		pairID = tokens.GetDefaultPairID()
+	}
+	pair := tokens.GetBridgePair(pairID)
+	if pair == nil {
+		return nil, errNoPair
+	}
+	return pair, nil
+}
+
 // GetServerInfo api
 func GetServerInfo() (*ServerInfo, error) {
 	log.Debug("[api] receive GetServerInfo")
@@ -36,38 +50,69 @@ func GetServerInfo() (*ServerInfo, error) {
 	if config == nil {
 		return nil, nil
 	}
-	return &ServerInfo{
+	info := &ServerInfo{
 		Identifier: config.Identifier,
 		SrcToken:   config.SrcToken,
 		DestToken:  config.DestToken,
 		Version:    params.VersionWithMeta,
-	}, nil
+	}
+	for _, pairCfg := range config.GetPairConfigs() {
+		info.Pairs = append(info.Pairs, &PairInfo{
+			Identifier: pairCfg.Identifier,
+			SrcToken:   pairCfg.SrcToken,
+			DestToken:  pairCfg.DestToken,
+		})
+	}
+	return info, nil
 }
 
 // GetSwapStatistics api
-func GetSwapStatistics() (*SwapStatistics, error) {
-	log.Debug("[api] receive GetSwapStatistics")
-	return mongodb.GetSwapStatistics()
+func GetSwapStatistics(pairID string) (*SwapStatistics, error) {
+	log.Debug("[api] receive GetSwapStatistics", "pairID", pairID)
+	pair, err := getBridgePair(pairID)
+	if err != nil {
+		return nil, err
+	}
+	return mongodb.GetSwapStatistics(pair.PairID)
 }
 
 // GetRawSwapin api
-func GetRawSwapin(txid *string) (*Swap, error) {
-	return mongodb.FindSwapin(*txid)
+func GetRawSwapin(pairID string, txid *string) (*Swap, error) {
+	pair, err := getBridgePair(pairID)
+	if err != nil {
+		return nil, err
+	}
+	return mongodb.FindSwapin(pair.PairID, *txid)
 }
 
 // GetRawSwapinResult api
-func GetRawSwapinResult(txid *string) (*SwapResult, error) {
-	return mongodb.FindSwapinResult(*txid)
+func GetRawSwapinResult(pairID string, txid *string) (*SwapResult, error) {
+	pair, err := getBridgePair(pairID)
+	if err != nil {
+		return nil, err
+	}
+	return mongodb.FindSwapinResult(pair.PairID, *txid)
 }
 
 // GetSwapin api
-func GetSwapin(txid *string) (*SwapInfo, error) {
+func GetSwapin(pairID string, txid *string) (*SwapInfo, error) {
+	pair, err := getBridgePair(pairID)
+	if err != nil {
+		return nil, err
+	}
 	txidstr := *txid
-	result, err := mongodb.FindSwapinResult(txidstr)
+	result, err := mongodb.FindSwapinResult(pair.PairID, txidstr)
 	if err == nil {
-		return ConvertMgoSwapResultToSwapInfo(result), nil
+		info := ConvertMgoSwapResultToSwapInfo(pair, result)
+		if result.SwapTx == "" {
+			// show recall progress before recall tx is sent
+			if register, _ := mongodb.FindSwapin(pair.PairID, txidstr); register != nil && isRecallSwapStatus(register.Status) {
+				info.StatusMsg = register.Status.String()
+			}
+		}
+		return info, nil
 	}
-	register, err := mongodb.FindSwapin(txidstr)
+	register, err := mongodb.FindSwapin(pair.PairID, txidstr)
 	if err == nil {
 		return ConvertMgoSwapToSwapInfo(register), nil
 	}
@@ -75,23 +120,35 @@ func GetSwapin(txid *string) (*SwapInfo, error) {
 }
 
 // GetRawSwapout api
-func GetRawSwapout(txid *string) (*Swap, error) {
-	return mongodb.FindSwapout(*txid)
+func GetRawSwapout(pairID string, txid *string) (*Swap, error) {
+	pair, err := getBridgePair(pairID)
+	if err != nil {
+		return nil, err
+	}
+	return mongodb.FindSwapout(pair.PairID, *txid)
 }
 
 // GetRawSwapoutResult api
-func GetRawSwapoutResult(txid *string) (*SwapResult, error) {
-	return mongodb.FindSwapoutResult(*txid)
+func GetRawSwapoutResult(pairID string, txid *string) (*SwapResult, error) {
+	pair, err := getBridgePair(pairID)
+	if err != nil {
+		return nil, err
+	}
+	return mongodb.FindSwapoutResult(pair.PairID, *txid)
 }
 
 // GetSwapout api
-func GetSwapout(txid *string) (*SwapInfo, error) {
+func GetSwapout(pairID string, txid *string) (*SwapInfo, error) {
+	pair, err := getBridgePair(pairID)
+	if err != nil {
+		return nil, err
+	}
 	txidstr := *txid
-	result, err := mongodb.FindSwapoutResult(txidstr)
+	result, err := mongodb.FindSwapoutResult(pair.PairID, txidstr)
 	if err == nil {
-		return ConvertMgoSwapResultToSwapInfo(result), nil
+		return ConvertMgoSwapResultToSwapInfo(pair, result), nil
 	}
-	register, err := mongodb.FindSwapout(txidstr)
+	register, err := mongodb.FindSwapout(pair.PairID, txidstr)
 	if err == nil {
 		return ConvertMgoSwapToSwapInfo(register), nil
 	}
@@ -108,39 +165,51 @@ func processHistoryLimit(limit int) int {
 }
 
 // GetSwapinHistory api
-func GetSwapinHistory(address string, offset, limit int) ([]*SwapInfo, error) {
-	log.Debug("[api] receive GetSwapinHistory", "address", address, "offset", offset, "limit", limit)
+func GetSwapinHistory(pairID, address string, offset, limit int) ([]*SwapInfo, error) {
+	log.Debug("[api] receive GetSwapinHistory", "pairID", pairID, "address", address, "offset", offset, "limit", limit)
+	pair, err := getBridgePair(pairID)
+	if err != nil {
+		return nil, err
+	}
 	limit = processHistoryLimit(limit)
-	result, err := mongodb.FindSwapinResults(address, offset, limit)
+	result, err := mongodb.FindSwapinResults(pair.PairID, address, offset, limit)
 	if err != nil {
 		return nil, err
 	}
-	return ConvertMgoSwapResultsToSwapInfos(result), nil
+	return ConvertMgoSwapResultsToSwapInfos(pair, result), nil
 }
 
 // GetSwapoutHistory api
-func GetSwapoutHistory(address string, offset, limit int) ([]*SwapInfo, error) {
-	log.Debug("[api] receive GetSwapoutHistory", "address", address, "offset", offset, "limit", limit)
+func GetSwapoutHistory(pairID, address string, offset, limit int) ([]*SwapInfo, error) {
+	log.Debug("[api] receive GetSwapoutHistory", "pairID", pairID, "address", address, "offset", offset, "limit", limit)
+	pair, err := getBridgePair(pairID)
+	if err != nil {
+		return nil, err
+	}
 	limit = processHistoryLimit(limit)
-	result, err := mongodb.FindSwapoutResults(address, offset, limit)
+	result, err := mongodb.FindSwapoutResults(pair.PairID, address, offset, limit)
 	if err != nil {
 		return nil, err
 	}
-	return ConvertMgoSwapResultsToSwapInfos(result), nil
+	return ConvertMgoSwapResultsToSwapInfos(pair, result), nil
 }
 
 // Swapin api
-func Swapin(txid *string) (*PostResult, error) {
-	log.Debug("[api] receive Swapin", "txid", *txid)
+func Swapin(pairID string, txid *string) (*PostResult, error) {
+	log.Debug("[api] receive Swapin", "pairID", pairID, "txid", *txid)
+	pair, err := getBridgePair(pairID)
+	if err != nil {
+		return nil, err
+	}
 	txidstr := *txid
-	if swap, _ := mongodb.FindSwapin(txidstr); swap != nil {
+	if swap, _ := mongodb.FindSwapin(pair.PairID, txidstr); swap != nil {
 		return nil, errSwapExist
 	}
-	swapInfo, err := tokens.SrcBridge.VerifyTransaction(txidstr, true)
+	swapInfo, err := pair.SrcBridge.VerifyTransaction(txidstr, true)
 	if !tokens.ShouldRegisterSwapForError(err) {
 		return nil, newRPCError(-32099, "verify swapin failed! "+err.Error())
 	}
-	err = addSwapToDatabase(txidstr, tokens.SwapinTx, swapInfo, err)
+	err = addSwapToDatabase(pair.PairID, txidstr, tokens.SwapinTx, swapInfo, err)
 	if err != nil {
 		return nil, err
 	}
@@ -148,24 +217,28 @@ func Swapin(txid *string) (*PostResult, error) {
 }
 
 // Swapout api
-func Swapout(txid *string) (*PostResult, error) {
-	log.Debug("[api] receive Swapout", "txid", *txid)
+func Swapout(pairID string, txid *string) (*PostResult, error) {
+	log.Debug("[api] receive Swapout", "pairID", pairID, "txid", *txid)
+	pair, err := getBridgePair(pairID)
+	if err != nil {
+		return nil, err
+	}
 	txidstr := *txid
-	if swap, _ := mongodb.FindSwapout(txidstr); swap != nil {
+	if swap, _ := mongodb.FindSwapout(pair.PairID, txidstr); swap != nil {
 		return nil, errSwapExist
 	}
-	swapInfo, err := tokens.DstBridge.VerifyTransaction(txidstr, true)
+	swapInfo, err := pair.DstBridge.VerifyTransaction(txidstr, true)
 	if !tokens.ShouldRegisterSwapForError(err) {
 		return nil, newRPCError(-32098, "verify swapout failed! "+err.Error())
 	}
-	err = addSwapToDatabase(txidstr, tokens.SwapoutTx, swapInfo, err)
+	err = addSwapToDatabase(pair.PairID, txidstr, tokens.SwapoutTx, swapInfo, err)
 	if err != nil {
 		return nil, err
 	}
 	return &SuccessPostResult, nil
 }
 
-func addSwapToDatabase(txid string, txType tokens.SwapTxType, swapInfo *tokens.TxSwapInfo, verifyError error) error {
+func addSwapToDatabase(pairID, txid string, txType tokens.SwapTxType, swapInfo *tokens.TxSwapInfo, verifyError error) error {
 	var memo string
 	if verifyError != nil {
 		memo = verifyError.Error()
@@ -180,54 +253,86 @@ func addSwapToDatabase(txid string, txType tokens.SwapTxType, swapInfo *tokens.T
 		Memo:      memo,
 	}
 	isSwapin := txType == tokens.SwapinTx
-	log.Info("[api] add swap", "isSwapin", isSwapin, "swap", swap)
+	log.Info("[api] add swap", "pairID", pairID, "isSwapin", isSwapin, "swap", swap)
+	var err error
 	if isSwapin {
-		return mongodb.AddSwapin(swap)
+		err = mongodb.AddSwapin(pairID, swap)
+	} else {
+		err = mongodb.AddSwapout(pairID, swap)
 	}
-	return mongodb.AddSwapout(swap)
+	if err == mongodb.ErrItemIsDup {
+		return errSwapExist
+	}
+	return err
 }
 
 // RecallSwapin api
-func RecallSwapin(txid *string) (*PostResult, error) {
-	log.Debug("[api] receive RecallSwapin", "txid", *txid)
+func RecallSwapin(pairID string, txid *string) (*PostResult, error) {
+	log.Debug("[api] receive RecallSwapin", "pairID", pairID, "txid", *txid)
+	pair, err := getBridgePair(pairID)
+	if err != nil {
+		return nil, err
+	}
 	txidstr := *txid
-	err := mongodb.RecallSwapin(txidstr)
+	err = mongodb.RecallSwapin(pair.PairID, txidstr)
 	if err != nil {
 		return nil, err
 	}
-	log.Info("[api] add recall swap", "txid", txidstr)
+	log.Info("[api] add recall swap", "pairID", pair.PairID, "txid", txidstr)
 	return &SuccessPostResult, nil
 }
 
 // IsValidSwapinBindAddress api
-func IsValidSwapinBindAddress(address *string) bool {
-	return tokens.DstBridge.IsValidAddress(*address)
+func IsValidSwapinBindAddress(pairID string, address *string) bool {
+	pair, err := getBridgePair(pairID)
+	if err != nil {
+		return false
+	}
+	return pair.DstBridge.IsValidAddress(*address)
 }
 
 // IsValidSwapoutBindAddress api
-func IsValidSwapoutBindAddress(address *string) bool {
-	return tokens.SrcBridge.IsValidAddress(*address)
+func IsValidSwapoutBindAddress(pairID string, address *string) bool {
+	pair, err := getBridgePair(pairID)
+	if err != nil {
+		return false
+	}
+	return pair.SrcBridge.IsValidAddress(*address)
 }
 
 // RegisterP2shAddress api
-func RegisterP2shAddress(bindAddress string) (*tokens.P2shAddressInfo, error) {
-	return calcP2shAddress(bindAddress, true)
+func RegisterP2shAddress(pairID, bindAddress string) (*tokens.P2shAddressInfo, error) {
+	pair, err := getBridgePair(pairID)
+	if err != nil {
+		return nil, err
+	}
+	return calcP2shAddress(pair.PairID, bindAddress, true)
 }
 
 // GetP2shAddressInfo api
-func GetP2shAddressInfo(p2shAddress string) (*tokens.P2shAddressInfo, error) {
-	bindAddress, err := mongodb.FindP2shBindAddress(p2shAddress)
+func GetP2shAddressInfo(pairID, p2shAddress string) (*tokens.P2shAddressInfo, error) {
+	pair, err := getBridgePair(pairID)
 	if err != nil {
 		return nil, err
 	}
-	return calcP2shAddress(bindAddress, false)
+	bindAddress, err := mongodb.FindP2shBindAddress(pair.PairID, p2shAddress)
+	if err != nil {
+		return nil, err
+	}
+	return calcP2shAddress(pair.PairID, bindAddress, false)
 }
 
-func calcP2shAddress(bindAddress string, addToDatabase bool) (*tokens.P2shAddressInfo, error) {
-	if btc.BridgeInstance == nil {
+func calcP2shAddress(pairID, bindAddress string, addToDatabase bool) (*tokens.P2shAddressInfo, error) {
+	btcBridge := btc.GetBridgeOfPair(pairID, true)
+	if btcBridge == nil {
 		return nil, errNotBridge
 	}
-	p2shAddr, redeemScript, err := btc.BridgeInstance.GetP2shAddress(bindAddress)
+	p2shAddr, redeemScript, err := btcBridge.GetP2shAddress(bindAddress)
+	if err != nil {
+		return nil, newRPCInternalError(err)
+	}
+	// p2wsh address has the same witness script as the p2sh redeem script
+	p2wshAddr, _, err := btcBridge.GetP2wshAddress(bindAddress)
 	if err != nil {
 		return nil, newRPCInternalError(err)
 	}
@@ -236,33 +341,44 @@ func calcP2shAddress(bindAddress string, addToDatabase bool) (*tokens.P2shAddres
 		return nil, newRPCInternalError(err)
 	}
 	if addToDatabase {
-		result, _ := mongodb.FindP2shAddress(bindAddress)
+		p2shAddress := &mongodb.MgoP2shAddress{
+			Key:          bindAddress,
+			P2shAddress:  p2shAddr,
+			P2wshAddress: p2wshAddr,
+		}
+		result, _ := mongodb.FindP2shAddress(pairID, bindAddress)
 		if result == nil {
-			_ = mongodb.AddP2shAddress(&mongodb.MgoP2shAddress{
-				Key:         bindAddress,
-				P2shAddress: p2shAddr,
-			})
+			_ = mongodb.AddP2shAddress(pairID, p2shAddress)
+		} else if result.P2wshAddress == "" {
+			// registered before p2wsh is supported
+			_ = mongodb.UpdateP2shAddress(pairID, p2shAddress)
 		}
 	}
 	return &tokens.P2shAddressInfo{
 		BindAddress:        bindAddress,
 		P2shAddress:        p2shAddr,
+		P2wshAddress:       p2wshAddr,
 		RedeemScript:       hex.EncodeToString(redeemScript),
 		RedeemScriptDisasm: disasm,
 	}, nil
 }
 
 // P2shSwapin api
-func P2shSwapin(txid, bindAddr *string) (*PostResult, error) {
-	log.Debug("[api] receive P2shSwapin", "txid", *txid, "bindAddress", *bindAddr)
-	if btc.BridgeInstance == nil {
+func P2shSwapin(pairID string, txid, bindAddr *string) (*PostResult, error) {
+	log.Debug("[api] receive P2shSwapin", "pairID", pairID, "txid", *txid, "bindAddress", *bindAddr)
+	pair, err := getBridgePair(pairID)
+	if err != nil {
+		return nil, err
+	}
+	btcBridge := btc.GetBridgeOfPair(pair.PairID, true)
+	if btcBridge == nil {
 		return nil, errNotBridge
 	}
 	txidstr := *txid
-	if swap, _ := mongodb.FindSwapin(txidstr); swap != nil {
+	if swap, _ := mongodb.FindSwapin(pair.PairID, txidstr); swap != nil {
 		return nil, errSwapExist
 	}
-	_, err := btc.BridgeInstance.VerifyP2shTransaction(txidstr, *bindAddr, true)
+	_, err = btcBridge.VerifyP2shTransaction(txidstr, *bindAddr, true)
 	if !tokens.ShouldRegisterSwapForError(err) {
 		return nil, newRPCError(-32099, "verify p2sh swapin failed! "+err.Error())
 	}
@@ -279,15 +395,53 @@ func P2shSwapin(txid, bindAddr *string) (*PostResult, error) {
 		Timestamp: time.Now().Unix(),
 		Memo:      memo,
 	}
-	err = mongodb.AddSwapin(swap)
+	err = mongodb.AddSwapin(pair.PairID, swap)
 	if err != nil {
 		return nil, err
 	}
-	log.Info("[api] add p2sh swapin", "swap", swap)
+	log.Info("[api] add p2sh swapin", "pairID", pair.PairID, "swap", swap)
 	return &SuccessPostResult, nil
 }
 
 // GetLatestScanInfo api
-func GetLatestScanInfo(isSrc bool) (*LatestScanInfo, error) {
-	return mongodb.FindLatestScanInfo(isSrc)
+func GetLatestScanInfo(pairID string, isSrc bool) (*LatestScanInfo, error) {
+	pair, err := getBridgePair(pairID)
+	if err != nil {
+		return nil, err
+	}
+	return mongodb.FindLatestScanInfo(pair.PairID, isSrc)
+}
+
+// GetReorgEvents api, get audit records of detected chain reorgs
+func GetReorgEvents(pairID string, offset, limit int) ([]*ReorgEvent, error) {
+	log.Debug("[api] receive GetReorgEvents", "pairID", pairID, "offset", offset, "limit", limit)
+	pair, err := getBridgePair(pairID)
+	if err != nil {
+		return nil, err
+	}
+	limit = processHistoryLimit(limit)
+	return mongodb.FindReorgEvents(pair.PairID, offset, limit)
+}
+
+// GetDcrmUtxos admin api, get tracked utxos of btc dcrm address
+func GetDcrmUtxos(pairID string) (*DcrmUtxos, error) {
+	log.Debug("[api] receive GetDcrmUtxos", "pairID", pairID)
+	pair, err := getBridgePair(pairID)
+	if err != nil {
+		return nil, err
+	}
+	isSrc := true
+	btcBridge := btc.GetBridgeOfPair(pair.PairID, isSrc)
+	if btcBridge == nil {
+		isSrc = false
+		btcBridge = btc.GetBridgeOfPair(pair.PairID, isSrc)
+	}
+	if btcBridge == nil {
+		return nil, errNotBridge
+	}
+	utxos, err := btcBridge.GetDcrmUtxos()
+	if err != nil {
+		return nil, newRPCInternalError(err)
+	}
+	return ConvertDcrmUtxos(btcBridge.TokenConfig.DcrmAddress, utxos, pair.GetLatestBlockHeight(isSrc)), nil
 }
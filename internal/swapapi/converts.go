@@ -11,6 +11,7 @@ func ConvertMgoSwapToSwapInfo(ms *mongodb.MgoSwap) *SwapInfo {
 		TxID:      ms.TxID,
 		Bind:      ms.Bind,
 		Status:    ms.Status,
+		StatusMsg: ms.Status.String(),
 		Timestamp: ms.Timestamp,
 		Memo:      ms.Memo,
 	}
@@ -26,15 +27,15 @@ func ConvertMgoSwapsToSwapInfos(msSlice []*mongodb.MgoSwap) []*SwapInfo {
 }
 
 // ConvertMgoSwapResultToSwapInfo convert
-func ConvertMgoSwapResultToSwapInfo(mr *mongodb.MgoSwapResult) *SwapInfo {
+func ConvertMgoSwapResultToSwapInfo(pair *tokens.BridgePair, mr *mongodb.MgoSwapResult) *SwapInfo {
 	var confirmations uint64
 	if mr.SwapHeight != 0 {
 		var latest uint64
 		switch mr.SwapType {
 		case uint32(tokens.SwapinType):
-			latest = tokens.DstLatestBlockHeight
+			latest = pair.DstLatestBlockHeight
 		case uint32(tokens.SwapoutType), uint32(tokens.SwapRecallType):
-			latest = tokens.SrcLatestBlockHeight
+			latest = pair.SrcLatestBlockHeight
 		}
 		if latest > mr.SwapHeight {
 			confirmations = latest - mr.SwapHeight
@@ -49,11 +50,13 @@ func ConvertMgoSwapResultToSwapInfo(mr *mongodb.MgoSwapResult) *SwapInfo {
 		Bind:          mr.Bind,
 		Value:         mr.Value,
 		SwapTx:        mr.SwapTx,
+		OldSwapTxs:    mr.OldSwapTxs,
 		SwapHeight:    mr.SwapHeight,
 		SwapTime:      mr.SwapTime,
 		SwapValue:     mr.SwapValue,
 		SwapType:      mr.SwapType,
 		Status:        mr.Status,
+		StatusMsg:     getSwapResultStatusMsg(mr),
 		Timestamp:     mr.Timestamp,
 		Memo:          mr.Memo,
 		Confirmations: confirmations,
@@ -61,10 +64,58 @@ func ConvertMgoSwapResultToSwapInfo(mr *mongodb.MgoSwapResult) *SwapInfo {
 }
 
 // ConvertMgoSwapResultsToSwapInfos convert
-func ConvertMgoSwapResultsToSwapInfos(mrSlice []*mongodb.MgoSwapResult) []*SwapInfo {
+func ConvertMgoSwapResultsToSwapInfos(pair *tokens.BridgePair, mrSlice []*mongodb.MgoSwapResult) []*SwapInfo {
 	result := make([]*SwapInfo, len(mrSlice))
 	for k, v := range mrSlice {
-		result[k] = ConvertMgoSwapResultToSwapInfo(v)
+		result[k] = ConvertMgoSwapResultToSwapInfo(pair, v)
+	}
+	return result
+}
+
+func getSwapResultStatusMsg(mr *mongodb.MgoSwapResult) string {
+	if mr.SwapType == uint32(tokens.SwapRecallType) {
+		switch mr.Status {
+		case mongodb.MatchTxNotStable:
+			return "RecallTxNotStable"
+		case mongodb.MatchTxStable:
+			return "RecallTxStable"
+		}
+	}
+	return mr.Status.String()
+}
+
+func isRecallSwapStatus(status mongodb.SwapStatus) bool {
+	switch status {
+	case mongodb.TxCanRecall, mongodb.TxToBeRecall, mongodb.TxRecallFailed:
+		return true
+	}
+	return false
+}
+
+// ConvertDcrmUtxos convert tracked utxos of dcrm address
+func ConvertDcrmUtxos(address string, utxos []*mongodb.MgoUtxo, latest uint64) *DcrmUtxos {
+	result := &DcrmUtxos{
+		Address: address,
+		Utxos:   make([]*UtxoInfo, len(utxos)),
+	}
+	for i, mu := range utxos {
+		var confirmations uint64
+		if mu.Height > 0 && latest >= mu.Height {
+			confirmations = latest - mu.Height + 1
+		}
+		result.Utxos[i] = &UtxoInfo{
+			TxID:          mu.TxID,
+			Vout:          mu.Vout,
+			Value:         mu.Value,
+			Height:        mu.Height,
+			Confirmations: confirmations,
+			LockedBy:      mu.LockedBy,
+			LockTime:      mu.LockTime,
+		}
+		result.Total += mu.Value
+		if mu.LockedBy != "" {
+			result.Locked += mu.Value
+		}
 	}
 	return result
 }
@@ -20,12 +20,23 @@ type SwapStatistics = mongodb.SwapStatistics
 // LatestScanInfo type alias
 type LatestScanInfo = mongodb.MgoLatestScanInfo
 
+// ReorgEvent type alias
+type ReorgEvent = mongodb.MgoReorgEvent
+
 // ServerInfo server info
 type ServerInfo struct {
 	Identifier string
 	SrcToken   *tokens.TokenConfig
 	DestToken  *tokens.TokenConfig
 	Version    string
+	Pairs      []*PairInfo `json:",omitempty"`
+}
+
+// PairInfo bridge pair info
+type PairInfo struct {
+	Identifier string
+	SrcToken   *tokens.TokenConfig
+	DestToken  *tokens.TokenConfig
 }
 
 // PostResult post result
@@ -44,12 +55,33 @@ type SwapInfo struct {
 	Bind          string     `json:"bind"`
 	Value         string     `json:"value"`
 	SwapTx        string     `json:"swaptx"`
+	OldSwapTxs    []string   `json:"oldswaptxs,omitempty"`
 	SwapHeight    uint64     `json:"swapheight"`
 	SwapTime      uint64     `json:"swaptime"`
 	SwapValue     string     `json:"swapvalue"`
 	SwapType      uint32     `json:"swaptype"`
 	Status        SwapStatus `json:"status"`
+	StatusMsg     string     `json:"statusmsg"`
 	Timestamp     int64      `json:"timestamp"`
 	Memo          string     `json:"memo"`
 	Confirmations uint64     `json:"confirmations"`
 }
+
+// DcrmUtxos tracked utxos of dcrm address
+type DcrmUtxos struct {
+	Address string      `json:"address"`
+	Total   uint64      `json:"total"`
+	Locked  uint64      `json:"locked"`
+	Utxos   []*UtxoInfo `json:"utxos"`
+}
+
+// UtxoInfo utxo info
+type UtxoInfo struct {
+	TxID          string `json:"txid"`
+	Vout          uint32 `json:"vout"`
+	Value         uint64 `json:"value"`
+	Height        uint64 `json:"height"`
+	Confirmations uint64 `json:"confirmations"`
+	LockedBy      string `json:"lockedby,omitempty"`
+	LockTime      int64  `json:"locktime,omitempty"`
+}
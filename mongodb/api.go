@@ -1,83 +1,29 @@
 package mongodb
 
 import (
+	"fmt"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/fsn-dev/crossChain-Bridge/log"
-	"gopkg.in/mgo.v2"
-	"gopkg.in/mgo.v2/bson"
-)
-
-var (
-	collSwapin         *mgo.Collection
-	collSwapout        *mgo.Collection
-	collSwapinResult   *mgo.Collection
-	collSwapoutResult  *mgo.Collection
-	collP2shAddress    *mgo.Collection
-	collSwapStatistics *mgo.Collection
-	collLatestScanInfo *mgo.Collection
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
 )
 
 const (
 	maxCountOfResults = 5000
 )
 
-// do this when reconnect to the database
-func deinintCollections() {
-	collSwapin = nil
-	collSwapout = nil
-	collSwapinResult = nil
-	collSwapoutResult = nil
-	collP2shAddress = nil
-	collSwapStatistics = nil
-	collLatestScanInfo = nil
-}
-
-func getOrInitCollection(table string, collection **mgo.Collection, indexKey ...string) *mgo.Collection {
-	if *collection == nil {
-		*collection = database.C(table)
-		if len(indexKey) != 0 {
-			err := (*collection).EnsureIndexKey(indexKey...)
-			if err != nil {
-				log.Error("EnsureIndexKey error", "table", table, "indexKey", indexKey)
-			}
-		}
-	}
-	return *collection
-}
-
-func getCollection(table string) *mgo.Collection {
-	switch table {
-	case tbSwapins:
-		return getOrInitCollection(table, &collSwapin, "timestamp", "status")
-	case tbSwapouts:
-		return getOrInitCollection(table, &collSwapout, "timestamp", "status")
-	case tbSwapinResults:
-		return getOrInitCollection(table, &collSwapinResult, "from", "timestamp")
-	case tbSwapoutResults:
-		return getOrInitCollection(table, &collSwapoutResult, "from", "timestamp")
-	case tbP2shAddresses:
-		return getOrInitCollection(table, &collP2shAddress, "p2shaddress")
-	case tbSwapStatistics:
-		return getOrInitCollection(table, &collSwapStatistics)
-	case tbLatestScanInfo:
-		return getOrInitCollection(table, &collLatestScanInfo)
-	default:
-		panic("unknown talbe " + table)
-	}
-}
-
 // --------------- swapin --------------------------------
 
 // AddSwapin add swapin
-func AddSwapin(ms *MgoSwap) error {
-	return addSwap(tbSwapins, ms)
+func AddSwapin(pairID string, ms *MgoSwap) error {
+	return addSwap(pairID, true, ms)
 }
 
 // RecallSwapin recall swapin
-func RecallSwapin(txid string) error {
-	swap, err := findSwap(tbSwapins, txid)
+func RecallSwapin(pairID, txid string) error {
+	swap, err := getStore(pairID).FindSwap(true, txid)
 	if err != nil {
 		return err
 	}
@@ -90,306 +36,223 @@ func RecallSwapin(txid string) error {
 	case TxToBeRecall:
 		return ErrSwapinRecallExist
 	case TxCanRecall:
-		return updateSwapStatus(tbSwapins, txid, TxToBeRecall, time.Now().Unix(), "")
+		return updateSwapStatus(pairID, true, txid, TxToBeRecall, time.Now().Unix(), "")
 	default:
 		return ErrSwapinRecalledOrForbidden
 	}
 }
 
 // UpdateSwapinStatus update swapin status
-func UpdateSwapinStatus(txid string, status SwapStatus, timestamp int64, memo string) error {
-	return updateSwapStatus(tbSwapins, txid, status, timestamp, memo)
+func UpdateSwapinStatus(pairID, txid string, status SwapStatus, timestamp int64, memo string) error {
+	return updateSwapStatus(pairID, true, txid, status, timestamp, memo)
 }
 
 // FindSwapin find swapin
-func FindSwapin(txid string) (*MgoSwap, error) {
-	return findSwap(tbSwapins, txid)
+func FindSwapin(pairID, txid string) (*MgoSwap, error) {
+	return getStore(pairID).FindSwap(true, txid)
 }
 
 // FindSwapinsWithStatus find swapin with status in the past septime
-func FindSwapinsWithStatus(status SwapStatus, septime int64) ([]*MgoSwap, error) {
-	return findSwapsWithStatus(tbSwapins, status, septime)
+func FindSwapinsWithStatus(pairID string, status SwapStatus, septime int64) ([]*MgoSwap, error) {
+	return getStore(pairID).FindSwapsWithStatus(true, status, septime)
 }
 
 // GetCountOfSwapinsWithStatus get count of swapins with status
-func GetCountOfSwapinsWithStatus(status SwapStatus) (int, error) {
-	return getCountWithStatus(tbSwapins, status)
+func GetCountOfSwapinsWithStatus(pairID string, status SwapStatus) (int, error) {
+	return getStore(pairID).GetCountOfSwapsWithStatus(true, status)
 }
 
 // --------------- swapout --------------------------------
 
 // AddSwapout add swapout
-func AddSwapout(ms *MgoSwap) error {
-	return addSwap(tbSwapouts, ms)
+func AddSwapout(pairID string, ms *MgoSwap) error {
+	return addSwap(pairID, false, ms)
 }
 
 // UpdateSwapoutStatus update swapout status
-func UpdateSwapoutStatus(txid string, status SwapStatus, timestamp int64, memo string) error {
-	return updateSwapStatus(tbSwapouts, txid, status, timestamp, memo)
+func UpdateSwapoutStatus(pairID, txid string, status SwapStatus, timestamp int64, memo string) error {
+	return updateSwapStatus(pairID, false, txid, status, timestamp, memo)
 }
 
 // FindSwapout find swapout
-func FindSwapout(txid string) (*MgoSwap, error) {
-	return findSwap(tbSwapouts, txid)
+func FindSwapout(pairID, txid string) (*MgoSwap, error) {
+	return getStore(pairID).FindSwap(false, txid)
 }
 
 // FindSwapoutsWithStatus find swapout with status
-func FindSwapoutsWithStatus(status SwapStatus, septime int64) ([]*MgoSwap, error) {
-	return findSwapsWithStatus(tbSwapouts, status, septime)
+func FindSwapoutsWithStatus(pairID string, status SwapStatus, septime int64) ([]*MgoSwap, error) {
+	return getStore(pairID).FindSwapsWithStatus(false, status, septime)
 }
 
 // GetCountOfSwapoutsWithStatus get count of swapout with status
-func GetCountOfSwapoutsWithStatus(status SwapStatus) (int, error) {
-	return getCountWithStatus(tbSwapouts, status)
+func GetCountOfSwapoutsWithStatus(pairID string, status SwapStatus) (int, error) {
+	return getStore(pairID).GetCountOfSwapsWithStatus(false, status)
 }
 
 // ------------------ swapin / swapout common ------------------------
 
-func addSwap(tbName string, ms *MgoSwap) error {
-	err := getCollection(tbName).Insert(ms)
+func addSwap(pairID string, isSwapin bool, ms *MgoSwap) error {
+	err := getStore(pairID).AddSwap(isSwapin, ms)
 	if err == nil {
-		log.Info("mongodb add swap", "txid", ms.TxID, "isSwapin", tbName == tbSwapins)
+		log.Info("mongodb add swap", "pairID", pairID, "txid", ms.TxID, "isSwapin", isSwapin)
 	} else {
-		log.Debug("mongodb add swap", "txid", ms.TxID, "isSwapin", tbName == tbSwapins, "err", err)
+		log.Debug("mongodb add swap", "pairID", pairID, "txid", ms.TxID, "isSwapin", isSwapin, "err", err)
 	}
-	return mgoError(err)
+	return err
 }
 
-func updateSwapStatus(tbName, txid string, status SwapStatus, timestamp int64, memo string) error {
-	updates := bson.M{"status": status, "timestamp": timestamp}
-	if memo != "" {
-		updates["memo"] = memo
-	} else if status == TxNotSwapped {
-		updates["memo"] = ""
-	}
-	err := getCollection(tbName).UpdateId(txid, bson.M{"$set": updates})
+func updateSwapStatus(pairID string, isSwapin bool, txid string, status SwapStatus, timestamp int64, memo string) error {
+	err := getStore(pairID).UpdateSwapStatus(isSwapin, txid, status, timestamp, memo)
 	if err == nil {
 		printLog := log.Info
 		switch status {
 		case TxVerifyFailed, TxRecallFailed, TxSwapFailed:
 			printLog = log.Warn
 		}
-		printLog("mongodb update swap status", "txid", txid, "status", status, "isSwapin", tbName == tbSwapins)
+		printLog("mongodb update swap status", "pairID", pairID, "txid", txid, "status", status, "isSwapin", isSwapin)
 	} else {
-		log.Debug("mongodb update swap status", "txid", txid, "status", status, "isSwapin", tbName == tbSwapins, "err", err)
-	}
-	return mgoError(err)
-}
-
-func findSwap(tbName, txid string) (*MgoSwap, error) {
-	var result MgoSwap
-	err := getCollection(tbName).FindId(txid).One(&result)
-	if err != nil {
-		return nil, mgoError(err)
+		log.Debug("mongodb update swap status", "pairID", pairID, "txid", txid, "status", status, "isSwapin", isSwapin, "err", err)
 	}
-	return &result, nil
-}
-
-func findSwapsWithStatus(tbName string, status SwapStatus, septime int64) (result []*MgoSwap, err error) {
-	err = findSwapsOrSwapResultsWithStatus(&result, tbName, status, septime)
-	return result, err
-}
-
-func findSwapsOrSwapResultsWithStatus(result interface{}, tbName string, status SwapStatus, septime int64) error {
-	qtime := bson.M{"timestamp": bson.M{"$gte": septime}}
-	qstatus := bson.M{"status": status}
-	queries := []bson.M{qtime, qstatus}
-	q := getCollection(tbName).Find(bson.M{"$and": queries}).Limit(maxCountOfResults)
-	return mgoError(q.All(result))
+	return err
 }
 
 // --------------- swapin result --------------------------------
 
 // AddSwapinResult add swapin result
-func AddSwapinResult(mr *MgoSwapResult) error {
-	return addSwapResult(tbSwapinResults, mr)
+func AddSwapinResult(pairID string, mr *MgoSwapResult) error {
+	return addSwapResult(pairID, true, mr)
 }
 
 // UpdateSwapinResult update swapin result
-func UpdateSwapinResult(txid string, items *SwapResultUpdateItems) error {
-	return updateSwapResult(tbSwapinResults, txid, items)
+func UpdateSwapinResult(pairID, txid string, items *SwapResultUpdateItems) error {
+	return updateSwapResult(pairID, true, txid, items)
 }
 
 // UpdateSwapinResultStatus update swapin result status
-func UpdateSwapinResultStatus(txid string, status SwapStatus, timestamp int64, memo string) error {
-	return updateSwapResultStatus(tbSwapinResults, txid, status, timestamp, memo)
+func UpdateSwapinResultStatus(pairID, txid string, status SwapStatus, timestamp int64, memo string) error {
+	return updateSwapResultStatus(pairID, true, txid, status, timestamp, memo)
 }
 
 // FindSwapinResult find swapin result
-func FindSwapinResult(txid string) (*MgoSwapResult, error) {
-	return findSwapResult(tbSwapinResults, txid)
+func FindSwapinResult(pairID, txid string) (*MgoSwapResult, error) {
+	return getStore(pairID).FindSwapResult(true, txid)
 }
 
 // FindSwapinResultsWithStatus find swapin result with status
-func FindSwapinResultsWithStatus(status SwapStatus, septime int64) ([]*MgoSwapResult, error) {
-	return findSwapResultsWithStatus(tbSwapinResults, status, septime)
+func FindSwapinResultsWithStatus(pairID string, status SwapStatus, septime int64) ([]*MgoSwapResult, error) {
+	return getStore(pairID).FindSwapResultsWithStatus(true, status, septime)
 }
 
 // FindSwapinResults find swapin history results
-func FindSwapinResults(address string, offset, limit int) ([]*MgoSwapResult, error) {
-	return findSwapResults(tbSwapinResults, address, offset, limit)
+func FindSwapinResults(pairID, address string, offset, limit int) ([]*MgoSwapResult, error) {
+	return getStore(pairID).FindSwapResults(true, address, offset, limit)
 }
 
 // GetCountOfSwapinResults get count of swapin results
-func GetCountOfSwapinResults() (int, error) {
-	return getCount(tbSwapinResults)
+func GetCountOfSwapinResults(pairID string) (int, error) {
+	return getStore(pairID).GetCountOfSwapResults(true)
 }
 
 // GetCountOfSwapinResultsWithStatus get count of swapin results with status
-func GetCountOfSwapinResultsWithStatus(status SwapStatus) (int, error) {
-	return getCountWithStatus(tbSwapinResults, status)
+func GetCountOfSwapinResultsWithStatus(pairID string, status SwapStatus) (int, error) {
+	return getStore(pairID).GetCountOfSwapResultsWithStatus(true, status)
 }
 
 // --------------- swapout result --------------------------------
 
 // AddSwapoutResult add swapout result
-func AddSwapoutResult(mr *MgoSwapResult) error {
-	return addSwapResult(tbSwapoutResults, mr)
+func AddSwapoutResult(pairID string, mr *MgoSwapResult) error {
+	return addSwapResult(pairID, false, mr)
 }
 
 // UpdateSwapoutResult update swapout result
-func UpdateSwapoutResult(txid string, items *SwapResultUpdateItems) error {
-	return updateSwapResult(tbSwapoutResults, txid, items)
+func UpdateSwapoutResult(pairID, txid string, items *SwapResultUpdateItems) error {
+	return updateSwapResult(pairID, false, txid, items)
 }
 
 // UpdateSwapoutResultStatus update swapout result status
-func UpdateSwapoutResultStatus(txid string, status SwapStatus, timestamp int64, memo string) error {
-	return updateSwapResultStatus(tbSwapoutResults, txid, status, timestamp, memo)
+func UpdateSwapoutResultStatus(pairID, txid string, status SwapStatus, timestamp int64, memo string) error {
+	return updateSwapResultStatus(pairID, false, txid, status, timestamp, memo)
 }
 
 // FindSwapoutResult find swapout result
-func FindSwapoutResult(txid string) (*MgoSwapResult, error) {
-	return findSwapResult(tbSwapoutResults, txid)
+func FindSwapoutResult(pairID, txid string) (*MgoSwapResult, error) {
+	return getStore(pairID).FindSwapResult(false, txid)
 }
 
 // FindSwapoutResultsWithStatus find swapout result with status
-func FindSwapoutResultsWithStatus(status SwapStatus, septime int64) ([]*MgoSwapResult, error) {
-	return findSwapResultsWithStatus(tbSwapoutResults, status, septime)
+func FindSwapoutResultsWithStatus(pairID string, status SwapStatus, septime int64) ([]*MgoSwapResult, error) {
+	return getStore(pairID).FindSwapResultsWithStatus(false, status, septime)
 }
 
 // FindSwapoutResults find swapout history results
-func FindSwapoutResults(address string, offset, limit int) ([]*MgoSwapResult, error) {
-	return findSwapResults(tbSwapoutResults, address, offset, limit)
+func FindSwapoutResults(pairID, address string, offset, limit int) ([]*MgoSwapResult, error) {
+	return getStore(pairID).FindSwapResults(false, address, offset, limit)
 }
 
 // GetCountOfSwapoutResults get count of swapout results
-func GetCountOfSwapoutResults() (int, error) {
-	return getCount(tbSwapoutResults)
+func GetCountOfSwapoutResults(pairID string) (int, error) {
+	return getStore(pairID).GetCountOfSwapResults(false)
 }
 
 // GetCountOfSwapoutResultsWithStatus get count of swapout results with status
-func GetCountOfSwapoutResultsWithStatus(status SwapStatus) (int, error) {
-	return getCountWithStatus(tbSwapoutResults, status)
+func GetCountOfSwapoutResultsWithStatus(pairID string, status SwapStatus) (int, error) {
+	return getStore(pairID).GetCountOfSwapResultsWithStatus(false, status)
 }
 
 // ------------------ swapin / swapout result common ------------------------
 
-func addSwapResult(tbName string, ms *MgoSwapResult) error {
-	err := getCollection(tbName).Insert(ms)
+func addSwapResult(pairID string, isSwapin bool, ms *MgoSwapResult) error {
+	err := getStore(pairID).AddSwapResult(isSwapin, ms)
 	if err == nil {
-		log.Info("mongodb add swap result", "txid", ms.TxID, "swaptype", ms.SwapType, "isSwapin", tbName == tbSwapinResults)
+		log.Info("mongodb add swap result", "pairID", pairID, "txid", ms.TxID, "swaptype", ms.SwapType, "isSwapin", isSwapin)
 	} else {
-		log.Debug("mongodb add swap result", "txid", ms.TxID, "swaptype", ms.SwapType, "isSwapin", tbName == tbSwapinResults, "err", err)
+		log.Debug("mongodb add swap result", "pairID", pairID, "txid", ms.TxID, "swaptype", ms.SwapType, "isSwapin", isSwapin, "err", err)
 	}
-	return mgoError(err)
+	return err
 }
 
-func updateSwapResult(tbName, txid string, items *SwapResultUpdateItems) error {
-	updates := bson.M{
-		"status":    items.Status,
-		"timestamp": items.Timestamp,
-	}
-	if items.SwapTx != "" {
-		updates["swaptx"] = items.SwapTx
-	}
-	if items.SwapHeight != 0 {
-		updates["swapheight"] = items.SwapHeight
-	}
-	if items.SwapTime != 0 {
-		updates["swaptime"] = items.SwapTime
-	}
-	if items.SwapValue != "" {
-		updates["swapvalue"] = items.SwapValue
-	}
-	if items.SwapType != 0 {
-		updates["swaptype"] = items.SwapType
-	}
-	if items.Memo != "" {
-		updates["memo"] = items.Memo
-	} else if items.Status == MatchTxNotStable {
-		updates["memo"] = ""
-	}
-	err := getCollection(tbName).UpdateId(txid, bson.M{"$set": updates})
+func updateSwapResult(pairID string, isSwapin bool, txid string, items *SwapResultUpdateItems) error {
+	err := getStore(pairID).UpdateSwapResult(isSwapin, txid, items)
 	if err == nil {
-		log.Info("mongodb update swap result", "txid", txid, "updates", updates, "isSwapin", tbName == tbSwapinResults)
+		log.Info("mongodb update swap result", "pairID", pairID, "txid", txid, "updates", items, "isSwapin", isSwapin)
 	} else {
-		log.Debug("mongodb update swap result", "txid", txid, "updates", updates, "isSwapin", tbName == tbSwapinResults, "err", err)
+		log.Debug("mongodb update swap result", "pairID", pairID, "txid", txid, "updates", items, "isSwapin", isSwapin, "err", err)
 	}
-	return mgoError(err)
+	return err
 }
 
-func updateSwapResultStatus(tbName, txid string, status SwapStatus, timestamp int64, memo string) error {
-	updates := bson.M{"status": status, "timestamp": timestamp}
-	if memo != "" {
-		updates["memo"] = memo
-	}
-	err := getCollection(tbName).UpdateId(txid, bson.M{"$set": updates})
-	isSwapin := tbName == tbSwapinResults
+func updateSwapResultStatus(pairID string, isSwapin bool, txid string, status SwapStatus, timestamp int64, memo string) error {
+	err := getStore(pairID).UpdateSwapResultStatus(isSwapin, txid, status, timestamp, memo)
 	if err == nil {
-		log.Info("mongodb update swap result status", "txid", txid, "status", status, "isSwapin", isSwapin)
+		log.Info("mongodb update swap result status", "pairID", pairID, "txid", txid, "status", status, "isSwapin", isSwapin)
 	} else {
-		log.Debug("mongodb update swap result status", "txid", txid, "status", status, "isSwapin", isSwapin, "err", err)
+		log.Debug("mongodb update swap result status", "pairID", pairID, "txid", txid, "status", status, "isSwapin", isSwapin, "err", err)
 	}
 	if status == MatchTxStable {
-		if swapResult, errq := findSwapResult(tbName, txid); errq == nil {
-			_ = UpdateSwapStatistics(swapResult.Value, swapResult.SwapValue, isSwapin)
+		// recalled swapin is not counted in swap statistics
+		if swapResult, errq := getStore(pairID).FindSwapResult(isSwapin, txid); errq == nil &&
+			swapResult.SwapType != uint32(tokens.SwapRecallType) {
+			_ = UpdateSwapStatistics(pairID, swapResult.Value, swapResult.SwapValue, isSwapin)
 		}
 	}
-	return mgoError(err)
-}
-
-func findSwapResult(tbName, txid string) (*MgoSwapResult, error) {
-	var result MgoSwapResult
-	err := getCollection(tbName).FindId(txid).One(&result)
-	if err != nil {
-		return nil, mgoError(err)
-	}
-	return &result, nil
-}
-
-func findSwapResultsWithStatus(tbName string, status SwapStatus, septime int64) (result []*MgoSwapResult, err error) {
-	err = findSwapsOrSwapResultsWithStatus(&result, tbName, status, septime)
-	return result, err
+	return err
 }
 
-func findSwapResults(tbName, address string, offset, limit int) ([]*MgoSwapResult, error) {
-	result := make([]*MgoSwapResult, 0, 20)
-	var q *mgo.Query
-	if address == "all" {
-		q = getCollection(tbName).Find(nil).Skip(offset).Limit(limit)
-	} else {
-		q = getCollection(tbName).Find(bson.M{"from": address}).Skip(offset).Limit(limit)
-	}
-	err := q.All(&result)
-	if err != nil {
-		return nil, mgoError(err)
-	}
-	return result, nil
-}
+// ------------------ statistics ------------------------
 
-func getCount(tbName string) (int, error) {
-	return getCollection(tbName).Find(nil).Count()
+// UpdateSwapStatistics update swap statistics
+func UpdateSwapStatistics(pairID, value, swapValue string, isSwapin bool) error {
+	return updateSwapStatistics(pairID, value, swapValue, isSwapin, false)
 }
 
-func getCountWithStatus(tbName string, status SwapStatus) (int, error) {
-	return getCollection(tbName).Find(bson.M{"status": status}).Count()
+// RevertSwapStatistics revert swap statistics of stable swap which is rolled back (eg. by chain reorg)
+func RevertSwapStatistics(pairID, value, swapValue string, isSwapin bool) error {
+	return updateSwapStatistics(pairID, value, swapValue, isSwapin, true)
 }
 
-// ------------------ statistics ------------------------
-
-// UpdateSwapStatistics update swap statistics
-func UpdateSwapStatistics(value, swapValue string, isSwapin bool) error {
-	curr, err := FindSwapStatistics()
+func updateSwapStatistics(pairID, value, swapValue string, isSwapin, isRevert bool) error {
+	curr, err := FindSwapStatistics(pairID)
 	if err != nil {
 		curr = &MgoSwapStatistics{
 			Key: keyOfSwapStatistics,
@@ -399,42 +262,45 @@ func UpdateSwapStatistics(value, swapValue string, isSwapin bool) error {
 	addVal, _ := new(big.Int).SetString(value, 0)
 	addSwapVal, _ := new(big.Int).SetString(swapValue, 0)
 	addSwapFee := new(big.Int).Sub(addVal, addSwapVal)
+	addCount := 1
+	if isRevert {
+		addSwapVal.Neg(addSwapVal)
+		addSwapFee.Neg(addSwapFee)
+		addCount = -1
+	}
 
 	curVal := big.NewInt(0)
 	curFee := big.NewInt(0)
 
-	updates := bson.M{}
 	if isSwapin {
 		curVal.SetString(curr.TotalSwapinValue, 0)
 		curFee.SetString(curr.TotalSwapinFee, 0)
 		curVal.Add(curVal, addSwapVal)
 		curFee.Add(curFee, addSwapFee)
-		updates["swapincount"] = curr.StableSwapinCount + 1
-		updates["totalswapinvalue"] = curVal.String()
-		updates["totalswapinfee"] = curFee.String()
+		curr.StableSwapinCount += addCount
+		curr.TotalSwapinValue = curVal.String()
+		curr.TotalSwapinFee = curFee.String()
 	} else {
 		curVal.SetString(curr.TotalSwapinValue, 0)
 		curFee.SetString(curr.TotalSwapinFee, 0)
 		curVal.Add(curVal, addSwapVal)
 		curFee.Add(curFee, addSwapFee)
-		updates["swapoutcount"] = curr.StableSwapoutCount + 1
-		updates["totalswapoutvalue"] = curVal.String()
-		updates["totalswapoutfee"] = curFee.String()
+		curr.StableSwapoutCount += addCount
+		curr.TotalSwapoutValue = curVal.String()
+		curr.TotalSwapoutFee = curFee.String()
 	}
-	err = getCollection(tbSwapStatistics).UpdateId(keyOfSwapStatistics, bson.M{"$set": updates})
+	err = getStore(pairID).UpdateSwapStatistics(curr)
 	if err == nil {
-		log.Info("mongodb update swap statistics", "updates", updates)
+		log.Info("mongodb update swap statistics", "updates", curr)
 	} else {
-		log.Debug("mongodb update swap statistics", "updates", updates, "err", err)
+		log.Debug("mongodb update swap statistics", "updates", curr, "err", err)
 	}
-	return mgoError(err)
+	return err
 }
 
 // FindSwapStatistics find swap statistics
-func FindSwapStatistics() (*MgoSwapStatistics, error) {
-	var result MgoSwapStatistics
-	err := getCollection(tbSwapStatistics).FindId(keyOfSwapStatistics).One(&result)
-	return &result, mgoError(err)
+func FindSwapStatistics(pairID string) (*MgoSwapStatistics, error) {
+	return getStore(pairID).FindSwapStatistics()
 }
 
 // SwapStatistics rpc return struct
@@ -452,10 +318,10 @@ type SwapStatistics struct {
 }
 
 // GetSwapStatistics get swap statistics
-func GetSwapStatistics() (*SwapStatistics, error) {
+func GetSwapStatistics(pairID string) (*SwapStatistics, error) {
 	stat := &SwapStatistics{}
 
-	if curr, _ := FindSwapStatistics(); curr != nil {
+	if curr, _ := FindSwapStatistics(pairID); curr != nil {
 		stat.StableSwapinCount = curr.StableSwapinCount
 		stat.TotalSwapinValue = curr.TotalSwapinValue
 		stat.TotalSwapinFee = curr.TotalSwapinFee
@@ -464,10 +330,10 @@ func GetSwapStatistics() (*SwapStatistics, error) {
 		stat.TotalSwapoutFee = curr.TotalSwapoutFee
 	}
 
-	stat.TotalSwapinCount, _ = GetCountOfSwapinResults()
-	stat.TotalSwapoutCount, _ = GetCountOfSwapoutResults()
-	stat.PendingSwapinCount, _ = GetCountOfSwapinResultsWithStatus(MatchTxEmpty)
-	stat.PendingSwapoutCount, _ = GetCountOfSwapoutResultsWithStatus(MatchTxEmpty)
+	stat.TotalSwapinCount, _ = GetCountOfSwapinResults(pairID)
+	stat.TotalSwapoutCount, _ = GetCountOfSwapoutResults(pairID)
+	stat.PendingSwapinCount, _ = GetCountOfSwapinResultsWithStatus(pairID, MatchTxEmpty)
+	stat.PendingSwapoutCount, _ = GetCountOfSwapoutResultsWithStatus(pairID, MatchTxEmpty)
 
 	return stat, nil
 }
@@ -475,103 +341,235 @@ func GetSwapStatistics() (*SwapStatistics, error) {
 // ------------------ p2sh address ------------------------
 
 // AddP2shAddress add p2sh address
-func AddP2shAddress(ma *MgoP2shAddress) error {
-	err := getCollection(tbP2shAddresses).Insert(ma)
+func AddP2shAddress(pairID string, ma *MgoP2shAddress) error {
+	err := getStore(pairID).AddP2shAddress(ma)
 	if err == nil {
-		log.Info("mongodb add p2sh address", "key", ma.Key, "p2shaddress", ma.P2shAddress)
+		log.Info("mongodb add p2sh address", "key", ma.Key, "p2shaddress", ma.P2shAddress, "p2wshaddress", ma.P2wshAddress)
 	} else {
-		log.Debug("mongodb add p2sh address", "key", ma.Key, "p2shaddress", ma.P2shAddress, "err", err)
+		log.Debug("mongodb add p2sh address", "key", ma.Key, "p2shaddress", ma.P2shAddress, "p2wshaddress", ma.P2wshAddress, "err", err)
 	}
-	return mgoError(err)
+	return err
 }
 
-// FindP2shAddress find p2sh addrss through bind address
-func FindP2shAddress(key string) (*MgoP2shAddress, error) {
-	var result MgoP2shAddress
-	err := getCollection(tbP2shAddresses).FindId(key).One(&result)
-	if err != nil {
-		return nil, mgoError(err)
+// UpdateP2shAddress update p2sh address
+func UpdateP2shAddress(pairID string, ma *MgoP2shAddress) error {
+	err := getStore(pairID).UpdateP2shAddress(ma)
+	if err == nil {
+		log.Info("mongodb update p2sh address", "key", ma.Key, "p2shaddress", ma.P2shAddress, "p2wshaddress", ma.P2wshAddress)
+	} else {
+		log.Debug("mongodb update p2sh address", "key", ma.Key, "p2shaddress", ma.P2shAddress, "p2wshaddress", ma.P2wshAddress, "err", err)
 	}
-	return &result, nil
+	return err
 }
 
-// FindP2shBindAddress find bind address through p2sh address
-func FindP2shBindAddress(p2shAddress string) (string, error) {
-	var result MgoP2shAddress
-	err := getCollection(tbP2shAddresses).Find(bson.M{"p2shaddress": p2shAddress}).One(&result)
-	if err != nil {
-		return "", mgoError(err)
-	}
-	return result.Key, nil
+// FindP2shAddress find p2sh addrss through bind address
+func FindP2shAddress(pairID, key string) (*MgoP2shAddress, error) {
+	return getStore(pairID).FindP2shAddress(key)
+}
+
+// FindP2shBindAddress find bind address through p2sh (or p2wsh) address
+func FindP2shBindAddress(pairID, p2shAddress string) (string, error) {
+	return getStore(pairID).FindP2shBindAddress(p2shAddress)
 }
 
 // FindP2shAddresses find p2sh address
-func FindP2shAddresses(offset, limit int) ([]*MgoP2shAddress, error) {
-	result := make([]*MgoP2shAddress, 0, limit)
-	q := getCollection(tbP2shAddresses).Find(nil).Skip(offset).Limit(limit)
-	err := q.All(&result)
-	if err != nil {
-		return nil, mgoError(err)
-	}
-	return result, nil
+func FindP2shAddresses(pairID string, offset, limit int) ([]*MgoP2shAddress, error) {
+	return getStore(pairID).FindP2shAddresses(offset, limit)
 }
 
 // ------------------ latest scan info ------------------------
 
 // UpdateLatestScanInfo update latest scan info
-func UpdateLatestScanInfo(isSrc bool, blockHeight uint64) error {
-	oldInfo, _ := FindLatestScanInfo(isSrc)
+func UpdateLatestScanInfo(pairID string, isSrc bool, blockHeight uint64) error {
+	oldInfo, _ := FindLatestScanInfo(pairID, isSrc)
 	if oldInfo != nil {
 		oldHeight := oldInfo.BlockHeight
 		if blockHeight <= oldHeight {
 			return nil
 		}
 	}
-	var key string
-	if isSrc {
-		key = keyOfSrcLatestScanInfo
+	timestamp := time.Now().Unix()
+	err := getStore(pairID).UpdateLatestScanInfo(isSrc, blockHeight, timestamp)
+	if err == nil {
+		log.Info("mongodb update lastest scan info", "isSrc", isSrc, "blockHeight", blockHeight, "timestamp", timestamp)
+	} else {
+		log.Debug("mongodb update latest scan info", "isSrc", isSrc, "blockHeight", blockHeight, "timestamp", timestamp, "err", err)
+	}
+	return err
+}
+
+// FindLatestScanInfo find latest scan info
+func FindLatestScanInfo(pairID string, isSrc bool) (*MgoLatestScanInfo, error) {
+	return getStore(pairID).FindLatestScanInfo(isSrc)
+}
+
+// ------------------ history scan info ------------------------
+
+// UpdateHistoryScanInfo update history scan checkpoint
+func UpdateHistoryScanInfo(pairID string, isSrc bool, blockHeight uint64) error {
+	timestamp := time.Now().Unix()
+	err := getStore(pairID).UpdateHistoryScanInfo(isSrc, blockHeight, timestamp)
+	if err != nil {
+		log.Warn("mongodb update history scan info failed", "pairID", pairID, "isSrc", isSrc, "blockHeight", blockHeight, "err", err)
+	}
+	return err
+}
+
+// FindHistoryScanInfo find history scan checkpoint
+func FindHistoryScanInfo(pairID string, isSrc bool) (*MgoLatestScanInfo, error) {
+	return getStore(pairID).FindHistoryScanInfo(isSrc)
+}
+
+// ------------------ block hashes ------------------------
+
+// UpdateBlockHashes update hashes of recent blocks
+func UpdateBlockHashes(pairID string, isSrc bool, hashes []MgoBlockHash) error {
+	err := getStore(pairID).UpdateBlockHashes(isSrc, hashes, time.Now().Unix())
+	if err != nil {
+		log.Warn("mongodb update block hashes failed", "pairID", pairID, "isSrc", isSrc, "count", len(hashes), "err", err)
+	}
+	return err
+}
+
+// FindBlockHashes find hashes of recent blocks
+func FindBlockHashes(pairID string, isSrc bool) (*MgoBlockHashes, error) {
+	return getStore(pairID).FindBlockHashes(isSrc)
+}
+
+// ------------------ reorg events ------------------------
+
+// AddReorgEvent add reorg event
+func AddReorgEvent(pairID string, ev *MgoReorgEvent) error {
+	err := getStore(pairID).AddReorgEvent(ev)
+	if err == nil {
+		log.Info("mongodb add reorg event", "pairID", pairID, "key", ev.Key, "swapins", ev.Swapins, "swapouts", ev.Swapouts, "missingTxs", ev.MissingTxs)
 	} else {
-		key = keyOfDstLatestScanInfo
+		log.Warn("mongodb add reorg event failed", "pairID", pairID, "key", ev.Key, "err", err)
 	}
-	updates := bson.M{
-		"blockheight": blockHeight,
-		"timestamp":   time.Now().Unix(),
+	return err
+}
+
+// FindReorgEvents find reorg events
+func FindReorgEvents(pairID string, offset, limit int) ([]*MgoReorgEvent, error) {
+	return getStore(pairID).FindReorgEvents(offset, limit)
+}
+
+// ------------------ sign intents ------------------------
+
+// GetSignIntentKey get key of sign intent
+func GetSignIntentKey(txid string, swapType tokens.SwapType) string {
+	return strings.ToLower(fmt.Sprintf("%v:%v", swapType, txid))
+}
+
+// UpdateSignIntent update (insert if not exist) sign intent
+func UpdateSignIntent(pairID string, intent *MgoSignIntent) error {
+	err := getStore(pairID).UpdateSignIntent(intent)
+	if err == nil {
+		log.Info("mongodb update sign intent", "pairID", pairID, "key", intent.Key, "blockHeight", intent.BlockHeight, "extra", intent.Extra)
+	} else {
+		log.Warn("mongodb update sign intent failed", "pairID", pairID, "key", intent.Key, "err", err)
 	}
-	err := getCollection(tbLatestScanInfo).UpdateId(key, bson.M{"$set": updates})
+	return err
+}
+
+// RemoveSignIntent remove sign intent
+func RemoveSignIntent(pairID, key string) error {
+	err := getStore(pairID).RemoveSignIntent(key)
 	if err == nil {
-		log.Info("mongodb update lastest scan info", "isSrc", isSrc, "updates", updates)
+		log.Info("mongodb remove sign intent", "pairID", pairID, "key", key)
+	} else if err != ErrItemNotFound {
+		log.Warn("mongodb remove sign intent failed", "pairID", pairID, "key", key, "err", err)
+	}
+	return err
+}
+
+// FindSignIntent find sign intent
+func FindSignIntent(pairID, key string) (*MgoSignIntent, error) {
+	return getStore(pairID).FindSignIntent(key)
+}
+
+// FindSignIntents find all sign intents of pair
+func FindSignIntents(pairID string) ([]*MgoSignIntent, error) {
+	return getStore(pairID).FindSignIntents()
+}
+
+// ------------------ sign requests ------------------------
+
+// UpdateSignRequest update (insert if not exist) sign request
+func UpdateSignRequest(req *MgoSignRequest) error {
+	err := store.UpdateSignRequest(req)
+	if err == nil {
+		log.Info("mongodb update sign request", "keyID", req.Key, "pairID", req.PairID, "swapID", req.SwapID, "swapType", req.SwapType, "groupID", req.GroupID)
 	} else {
-		log.Debug("mongodb update latest scan info", "isSrc", isSrc, "updates", updates, "err", err)
+		log.Warn("mongodb update sign request failed", "keyID", req.Key, "pairID", req.PairID, "swapID", req.SwapID, "err", err)
 	}
-	return mgoError(err)
+	return err
 }
 
-// FindLatestScanInfo find latest scan info
-func FindLatestScanInfo(isSrc bool) (*MgoLatestScanInfo, error) {
-	var result MgoLatestScanInfo
-	var key string
-	if isSrc {
-		key = keyOfSrcLatestScanInfo
+// RemoveSignRequest remove sign request
+func RemoveSignRequest(keyID string) error {
+	err := store.RemoveSignRequest(keyID)
+	if err == nil {
+		log.Info("mongodb remove sign request", "keyID", keyID)
+	} else if err != ErrItemNotFound {
+		log.Warn("mongodb remove sign request failed", "keyID", keyID, "err", err)
+	}
+	return err
+}
+
+// FindSignRequests find all pending sign requests
+func FindSignRequests() ([]*MgoSignRequest, error) {
+	return store.FindSignRequests()
+}
+
+// ------------------ nonce ------------------------
+
+// UpdateNonceInfo update (insert if not exist) nonce info
+func UpdateNonceInfo(info *MgoNonceInfo) error {
+	err := store.UpdateNonceInfo(info)
+	if err == nil {
+		log.Debug("mongodb update nonce info", "key", info.Key, "nextNonce", info.NextNonce, "released", info.Released)
 	} else {
-		key = keyOfDstLatestScanInfo
+		log.Warn("mongodb update nonce info failed", "key", info.Key, "nextNonce", info.NextNonce, "released", info.Released, "err", err)
 	}
-	err := getCollection(tbLatestScanInfo).FindId(key).One(&result)
-	return &result, mgoError(err)
+	return err
 }
 
-// InitCollections init some tables
-func InitCollections() {
-	_ = getCollection(tbSwapStatistics).Insert(
-		&MgoSwapStatistics{
-			Key: keyOfSwapStatistics,
-		},
-	)
-	_ = getCollection(tbLatestScanInfo).Insert(
-		&MgoLatestScanInfo{
-			Key: keyOfSrcLatestScanInfo,
-		},
-		&MgoLatestScanInfo{
-			Key: keyOfDstLatestScanInfo,
-		},
-	)
+// FindNonceInfo find nonce info
+func FindNonceInfo(key string) (*MgoNonceInfo, error) {
+	return store.FindNonceInfo(key)
+}
+
+// ------------------ utxo ------------------------
+
+// UpdateUtxo update (insert if not exist) utxo
+func UpdateUtxo(mu *MgoUtxo) error {
+	err := store.UpdateUtxo(mu)
+	if err == nil {
+		log.Debug("mongodb update utxo", "key", mu.Key, "address", mu.Address, "value", mu.Value, "height", mu.Height, "lockedBy", mu.LockedBy)
+	} else {
+		log.Warn("mongodb update utxo failed", "key", mu.Key, "address", mu.Address, "err", err)
+	}
+	return err
+}
+
+// RemoveUtxo remove spent utxo
+func RemoveUtxo(key string) error {
+	err := store.RemoveUtxo(key)
+	if err == nil {
+		log.Debug("mongodb remove utxo", "key", key)
+	} else {
+		log.Warn("mongodb remove utxo failed", "key", key, "err", err)
+	}
+	return err
+}
+
+// FindUtxo find utxo
+func FindUtxo(key string) (*MgoUtxo, error) {
+	return store.FindUtxo(key)
+}
+
+// FindUtxos find utxos of address ('all' means all addresses)
+func FindUtxos(address string) ([]*MgoUtxo, error) {
+	return store.FindUtxos(address)
 }
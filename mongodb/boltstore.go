@@ -0,0 +1,805 @@
+package mongodb
+
+import (
+	"encoding/binary"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// bucket suffix of insertion order index (sequence -> key)
+	orderBucketSuffix = "Order"
+	// bucket of p2sh address index (p2sh address -> bind address)
+	tbP2shAddressIndex = "P2shAddressIndex"
+)
+
+// BoltStore embedded single-file storage backend
+type BoltStore struct {
+	db        *bolt.DB
+	namespace string
+}
+
+// NewBoltStore open (create if not exist) embedded storage file
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 3 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	s := &BoltStore{db: db}
+	if err = s.createBuckets(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// WithNamespace new storage backend in the same file whose buckets are prefixed with namespace.
+// if buckets can not be created, it panics as it's called at initialization.
+func (s *BoltStore) WithNamespace(namespace string) Store {
+	ns := &BoltStore{db: s.db, namespace: namespace}
+	if err := ns.createBuckets(); err != nil {
+		panic("create buckets of namespace " + namespace + " failed: " + err.Error())
+	}
+	return ns
+}
+
+func (s *BoltStore) createBuckets() error {
+	tables := []string{
+		tbSwapins, tbSwapouts,
+		tbSwapinResults, tbSwapoutResults,
+		tbP2shAddresses, tbReorgEvents,
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for _, table := range tables {
+			if _, errc := tx.CreateBucketIfNotExists([]byte(s.table(table))); errc != nil {
+				return errc
+			}
+			if _, errc := tx.CreateBucketIfNotExists([]byte(s.table(table) + orderBucketSuffix)); errc != nil {
+				return errc
+			}
+		}
+		for _, table := range []string{tbP2shAddressIndex, tbSwapStatistics, tbLatestScanInfo, tbBlockHashes, tbSignIntents} {
+			if _, errc := tx.CreateBucketIfNotExists([]byte(s.table(table))); errc != nil {
+				return errc
+			}
+		}
+		// nonces, utxos and sign requests are shared by all namespaces
+		for _, table := range []string{tbNonces, tbUtxos, tbSignRequests} {
+			if _, errc := tx.CreateBucketIfNotExists([]byte(table)); errc != nil {
+				return errc
+			}
+		}
+		return nil
+	})
+}
+
+func (s *BoltStore) table(table string) string {
+	return getNamespacedTable(s.namespace, table)
+}
+
+// Close close embedded storage file (shared by all namespaces)
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+func boltError(err error) error {
+	switch err {
+	case nil, ErrItemNotFound, ErrItemIsDup:
+		return err
+	default:
+		return newError(-32001, "boltError: "+err.Error())
+	}
+}
+
+func boltInsert(tx *bolt.Tx, table, key string, item interface{}) error {
+	bucket := tx.Bucket([]byte(table))
+	if bucket.Get([]byte(key)) != nil {
+		return ErrItemIsDup
+	}
+	data, err := bson.Marshal(item)
+	if err != nil {
+		return err
+	}
+	err = bucket.Put([]byte(key), data)
+	if err != nil {
+		return err
+	}
+	orderBucket := tx.Bucket([]byte(table + orderBucketSuffix))
+	seq, err := orderBucket.NextSequence()
+	if err != nil {
+		return err
+	}
+	seqKey := make([]byte, 8)
+	binary.BigEndian.PutUint64(seqKey, seq)
+	return orderBucket.Put(seqKey, []byte(key))
+}
+
+func boltGet(tx *bolt.Tx, table, key string, result interface{}) error {
+	data := tx.Bucket([]byte(table)).Get([]byte(key))
+	if data == nil {
+		return ErrItemNotFound
+	}
+	return bson.Unmarshal(data, result)
+}
+
+func boltPut(tx *bolt.Tx, table, key string, item interface{}) error {
+	data, err := bson.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket([]byte(table)).Put([]byte(key), data)
+}
+
+// boltForEach iterate items in insertion order until fn return false
+func boltForEach(tx *bolt.Tx, table string, fn func(data []byte) (bool, error)) error {
+	bucket := tx.Bucket([]byte(table))
+	cursor := tx.Bucket([]byte(table + orderBucketSuffix)).Cursor()
+	for _, key := cursor.First(); key != nil; _, key = cursor.Next() {
+		data := bucket.Get(key)
+		if data == nil {
+			continue
+		}
+		goon, err := fn(data)
+		if err != nil {
+			return err
+		}
+		if !goon {
+			break
+		}
+	}
+	return nil
+}
+
+// ------------------ swapin / swapout ------------------------
+
+// AddSwap add swap
+func (s *BoltStore) AddSwap(isSwapin bool, ms *MgoSwap) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return boltInsert(tx, s.table(getSwapTable(isSwapin)), ms.Key, ms)
+	})
+	return boltError(err)
+}
+
+// UpdateSwapStatus update swap status
+func (s *BoltStore) UpdateSwapStatus(isSwapin bool, txid string, status SwapStatus, timestamp int64, memo string) error {
+	table := s.table(getSwapTable(isSwapin))
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var swap MgoSwap
+		if err := boltGet(tx, table, txid, &swap); err != nil {
+			return err
+		}
+		swap.Status = status
+		swap.Timestamp = timestamp
+		if memo != "" {
+			swap.Memo = memo
+		} else if status == TxNotSwapped {
+			swap.Memo = ""
+		}
+		return boltPut(tx, table, txid, &swap)
+	})
+	return boltError(err)
+}
+
+// FindSwap find swap
+func (s *BoltStore) FindSwap(isSwapin bool, txid string) (*MgoSwap, error) {
+	var result MgoSwap
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return boltGet(tx, s.table(getSwapTable(isSwapin)), txid, &result)
+	})
+	if err != nil {
+		return nil, boltError(err)
+	}
+	return &result, nil
+}
+
+// FindSwapsWithStatus find swaps with status in the past septime
+func (s *BoltStore) FindSwapsWithStatus(isSwapin bool, status SwapStatus, septime int64) ([]*MgoSwap, error) {
+	result := make([]*MgoSwap, 0, 20)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return boltForEach(tx, s.table(getSwapTable(isSwapin)), func(data []byte) (bool, error) {
+			var swap MgoSwap
+			if err := bson.Unmarshal(data, &swap); err != nil {
+				return false, err
+			}
+			if swap.Status == status && swap.Timestamp >= septime {
+				result = append(result, &swap)
+			}
+			return len(result) < maxCountOfResults, nil
+		})
+	})
+	if err != nil {
+		return nil, boltError(err)
+	}
+	return result, nil
+}
+
+// GetCountOfSwapsWithStatus get count of swaps with status
+func (s *BoltStore) GetCountOfSwapsWithStatus(isSwapin bool, status SwapStatus) (int, error) {
+	count := 0
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return boltForEach(tx, s.table(getSwapTable(isSwapin)), func(data []byte) (bool, error) {
+			var swap MgoSwap
+			if err := bson.Unmarshal(data, &swap); err != nil {
+				return false, err
+			}
+			if swap.Status == status {
+				count++
+			}
+			return true, nil
+		})
+	})
+	return count, boltError(err)
+}
+
+// ------------------ swapin / swapout result ------------------------
+
+// AddSwapResult add swap result
+func (s *BoltStore) AddSwapResult(isSwapin bool, mr *MgoSwapResult) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return boltInsert(tx, s.table(getSwapResultTable(isSwapin)), mr.Key, mr)
+	})
+	return boltError(err)
+}
+
+// UpdateSwapResult update swap result
+func (s *BoltStore) UpdateSwapResult(isSwapin bool, txid string, items *SwapResultUpdateItems) error {
+	table := s.table(getSwapResultTable(isSwapin))
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var res MgoSwapResult
+		if err := boltGet(tx, table, txid, &res); err != nil {
+			return err
+		}
+		res.Status = items.Status
+		res.Timestamp = items.Timestamp
+		if items.TxHeight != 0 {
+			res.TxHeight = items.TxHeight
+		}
+		if items.TxTime != 0 {
+			res.TxTime = items.TxTime
+		}
+		if items.SwapTx != "" {
+			res.SwapTx = items.SwapTx
+			res.SwapHeight = 0
+			res.SwapTime = 0
+		}
+		if items.OldSwapTxs != nil {
+			res.OldSwapTxs = items.OldSwapTxs
+		}
+		if items.SwapNonce != nil {
+			res.SwapNonce = items.SwapNonce
+		}
+		if items.SwapHeight != 0 {
+			res.SwapHeight = items.SwapHeight
+		}
+		if items.SwapTime != 0 {
+			res.SwapTime = items.SwapTime
+		}
+		if items.SwapValue != "" {
+			res.SwapValue = items.SwapValue
+		}
+		if items.SwapType != 0 {
+			res.SwapType = items.SwapType
+		}
+		if items.Memo != "" {
+			res.Memo = items.Memo
+		} else if items.Status == MatchTxNotStable {
+			res.Memo = ""
+		}
+		return boltPut(tx, table, txid, &res)
+	})
+	return boltError(err)
+}
+
+// UpdateSwapResultStatus update swap result status
+func (s *BoltStore) UpdateSwapResultStatus(isSwapin bool, txid string, status SwapStatus, timestamp int64, memo string) error {
+	table := s.table(getSwapResultTable(isSwapin))
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var res MgoSwapResult
+		if err := boltGet(tx, table, txid, &res); err != nil {
+			return err
+		}
+		res.Status = status
+		res.Timestamp = timestamp
+		if memo != "" {
+			res.Memo = memo
+		}
+		return boltPut(tx, table, txid, &res)
+	})
+	return boltError(err)
+}
+
+// FindSwapResult find swap result
+func (s *BoltStore) FindSwapResult(isSwapin bool, txid string) (*MgoSwapResult, error) {
+	var result MgoSwapResult
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return boltGet(tx, s.table(getSwapResultTable(isSwapin)), txid, &result)
+	})
+	if err != nil {
+		return nil, boltError(err)
+	}
+	return &result, nil
+}
+
+// FindSwapResultsWithStatus find swap results with status in the past septime
+func (s *BoltStore) FindSwapResultsWithStatus(isSwapin bool, status SwapStatus, septime int64) ([]*MgoSwapResult, error) {
+	result := make([]*MgoSwapResult, 0, 20)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return boltForEach(tx, s.table(getSwapResultTable(isSwapin)), func(data []byte) (bool, error) {
+			var res MgoSwapResult
+			if err := bson.Unmarshal(data, &res); err != nil {
+				return false, err
+			}
+			if res.Status == status && res.Timestamp >= septime {
+				result = append(result, &res)
+			}
+			return len(result) < maxCountOfResults, nil
+		})
+	})
+	if err != nil {
+		return nil, boltError(err)
+	}
+	return result, nil
+}
+
+// FindSwapResults find swap history results
+func (s *BoltStore) FindSwapResults(isSwapin bool, address string, offset, limit int) ([]*MgoSwapResult, error) {
+	result := make([]*MgoSwapResult, 0, 20)
+	skipped := 0
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return boltForEach(tx, s.table(getSwapResultTable(isSwapin)), func(data []byte) (bool, error) {
+			var res MgoSwapResult
+			if err := bson.Unmarshal(data, &res); err != nil {
+				return false, err
+			}
+			if address != "all" && res.From != address {
+				return true, nil
+			}
+			if skipped < offset {
+				skipped++
+				return true, nil
+			}
+			result = append(result, &res)
+			return limit <= 0 || len(result) < limit, nil
+		})
+	})
+	if err != nil {
+		return nil, boltError(err)
+	}
+	return result, nil
+}
+
+// GetCountOfSwapResults get count of swap results
+func (s *BoltStore) GetCountOfSwapResults(isSwapin bool) (int, error) {
+	count := 0
+	err := s.db.View(func(tx *bolt.Tx) error {
+		count = tx.Bucket([]byte(s.table(getSwapResultTable(isSwapin)))).Stats().KeyN
+		return nil
+	})
+	return count, boltError(err)
+}
+
+// GetCountOfSwapResultsWithStatus get count of swap results with status
+func (s *BoltStore) GetCountOfSwapResultsWithStatus(isSwapin bool, status SwapStatus) (int, error) {
+	count := 0
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return boltForEach(tx, s.table(getSwapResultTable(isSwapin)), func(data []byte) (bool, error) {
+			var res MgoSwapResult
+			if err := bson.Unmarshal(data, &res); err != nil {
+				return false, err
+			}
+			if res.Status == status {
+				count++
+			}
+			return true, nil
+		})
+	})
+	return count, boltError(err)
+}
+
+// ------------------ statistics ------------------------
+
+// UpdateSwapStatistics update swap statistics
+func (s *BoltStore) UpdateSwapStatistics(stat *MgoSwapStatistics) error {
+	stat.Key = keyOfSwapStatistics
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return boltPut(tx, s.table(tbSwapStatistics), keyOfSwapStatistics, stat)
+	})
+	return boltError(err)
+}
+
+// FindSwapStatistics find swap statistics
+func (s *BoltStore) FindSwapStatistics() (*MgoSwapStatistics, error) {
+	result := MgoSwapStatistics{Key: keyOfSwapStatistics}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		err := boltGet(tx, s.table(tbSwapStatistics), keyOfSwapStatistics, &result)
+		if err == ErrItemNotFound {
+			return nil
+		}
+		return err
+	})
+	return &result, boltError(err)
+}
+
+// ------------------ p2sh address ------------------------
+
+// AddP2shAddress add p2sh address
+func (s *BoltStore) AddP2shAddress(ma *MgoP2shAddress) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		if err := boltInsert(tx, s.table(tbP2shAddresses), ma.Key, ma); err != nil {
+			return err
+		}
+		return s.indexP2shAddress(tx, ma)
+	})
+	return boltError(err)
+}
+
+// UpdateP2shAddress update p2sh address
+func (s *BoltStore) UpdateP2shAddress(ma *MgoP2shAddress) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var old MgoP2shAddress
+		if err := boltGet(tx, s.table(tbP2shAddresses), ma.Key, &old); err != nil {
+			return err
+		}
+		if err := boltPut(tx, s.table(tbP2shAddresses), ma.Key, ma); err != nil {
+			return err
+		}
+		return s.indexP2shAddress(tx, ma)
+	})
+	return boltError(err)
+}
+
+// indexP2shAddress index bind address by p2sh and p2wsh address
+func (s *BoltStore) indexP2shAddress(tx *bolt.Tx, ma *MgoP2shAddress) error {
+	index := tx.Bucket([]byte(s.table(tbP2shAddressIndex)))
+	for _, address := range []string{ma.P2shAddress, ma.P2wshAddress} {
+		if address == "" {
+			continue
+		}
+		if err := index.Put([]byte(address), []byte(ma.Key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindP2shAddress find p2sh addrss through bind address
+func (s *BoltStore) FindP2shAddress(key string) (*MgoP2shAddress, error) {
+	var result MgoP2shAddress
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return boltGet(tx, s.table(tbP2shAddresses), key, &result)
+	})
+	if err != nil {
+		return nil, boltError(err)
+	}
+	return &result, nil
+}
+
+// FindP2shBindAddress find bind address through p2sh (or p2wsh) address
+func (s *BoltStore) FindP2shBindAddress(p2shAddress string) (string, error) {
+	var bindAddress string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket([]byte(s.table(tbP2shAddressIndex))).Get([]byte(p2shAddress))
+		if data == nil {
+			return ErrItemNotFound
+		}
+		bindAddress = string(data)
+		return nil
+	})
+	if err != nil {
+		return "", boltError(err)
+	}
+	return bindAddress, nil
+}
+
+// FindP2shAddresses find p2sh address
+func (s *BoltStore) FindP2shAddresses(offset, limit int) ([]*MgoP2shAddress, error) {
+	result := make([]*MgoP2shAddress, 0, limit)
+	skipped := 0
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return boltForEach(tx, s.table(tbP2shAddresses), func(data []byte) (bool, error) {
+			if skipped < offset {
+				skipped++
+				return true, nil
+			}
+			var ma MgoP2shAddress
+			if err := bson.Unmarshal(data, &ma); err != nil {
+				return false, err
+			}
+			result = append(result, &ma)
+			return limit <= 0 || len(result) < limit, nil
+		})
+	})
+	if err != nil {
+		return nil, boltError(err)
+	}
+	return result, nil
+}
+
+// ------------------ latest scan info ------------------------
+
+// UpdateLatestScanInfo update latest scan info
+func (s *BoltStore) UpdateLatestScanInfo(isSrc bool, blockHeight uint64, timestamp int64) error {
+	info := &MgoLatestScanInfo{
+		Key:         getLatestScanInfoKey(isSrc),
+		BlockHeight: blockHeight,
+		Timestamp:   timestamp,
+	}
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return boltPut(tx, s.table(tbLatestScanInfo), info.Key, info)
+	})
+	return boltError(err)
+}
+
+// FindLatestScanInfo find latest scan info
+func (s *BoltStore) FindLatestScanInfo(isSrc bool) (*MgoLatestScanInfo, error) {
+	result := MgoLatestScanInfo{Key: getLatestScanInfoKey(isSrc)}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		err := boltGet(tx, s.table(tbLatestScanInfo), result.Key, &result)
+		if err == ErrItemNotFound {
+			return nil
+		}
+		return err
+	})
+	return &result, boltError(err)
+}
+
+// UpdateHistoryScanInfo update (insert if not exist) history scan checkpoint
+func (s *BoltStore) UpdateHistoryScanInfo(isSrc bool, blockHeight uint64, timestamp int64) error {
+	info := &MgoLatestScanInfo{
+		Key:         getHistoryScanInfoKey(isSrc),
+		BlockHeight: blockHeight,
+		Timestamp:   timestamp,
+	}
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return boltPut(tx, s.table(tbLatestScanInfo), info.Key, info)
+	})
+	return boltError(err)
+}
+
+// FindHistoryScanInfo find history scan checkpoint (empty if not exist)
+func (s *BoltStore) FindHistoryScanInfo(isSrc bool) (*MgoLatestScanInfo, error) {
+	result := MgoLatestScanInfo{Key: getHistoryScanInfoKey(isSrc)}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		err := boltGet(tx, s.table(tbLatestScanInfo), result.Key, &result)
+		if err == ErrItemNotFound {
+			return nil
+		}
+		return err
+	})
+	return &result, boltError(err)
+}
+
+// ------------------ block hashes ------------------------
+
+// UpdateBlockHashes update (insert if not exist) hashes of recent blocks
+func (s *BoltStore) UpdateBlockHashes(isSrc bool, hashes []MgoBlockHash, timestamp int64) error {
+	info := &MgoBlockHashes{
+		Key:       getBlockHashesKey(isSrc),
+		Hashes:    hashes,
+		Timestamp: timestamp,
+	}
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return boltPut(tx, s.table(tbBlockHashes), info.Key, info)
+	})
+	return boltError(err)
+}
+
+// FindBlockHashes find hashes of recent blocks (empty if not exist)
+func (s *BoltStore) FindBlockHashes(isSrc bool) (*MgoBlockHashes, error) {
+	result := MgoBlockHashes{Key: getBlockHashesKey(isSrc)}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		err := boltGet(tx, s.table(tbBlockHashes), result.Key, &result)
+		if err == ErrItemNotFound {
+			return nil
+		}
+		return err
+	})
+	return &result, boltError(err)
+}
+
+// ------------------ reorg events ------------------------
+
+// AddReorgEvent add reorg event
+func (s *BoltStore) AddReorgEvent(ev *MgoReorgEvent) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return boltInsert(tx, s.table(tbReorgEvents), ev.Key, ev)
+	})
+	return boltError(err)
+}
+
+// FindReorgEvents find reorg events
+func (s *BoltStore) FindReorgEvents(offset, limit int) ([]*MgoReorgEvent, error) {
+	result := make([]*MgoReorgEvent, 0, 20)
+	skipped := 0
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return boltForEach(tx, s.table(tbReorgEvents), func(data []byte) (bool, error) {
+			if skipped < offset {
+				skipped++
+				return true, nil
+			}
+			var ev MgoReorgEvent
+			if err := bson.Unmarshal(data, &ev); err != nil {
+				return false, err
+			}
+			result = append(result, &ev)
+			return limit <= 0 || len(result) < limit, nil
+		})
+	})
+	if err != nil {
+		return nil, boltError(err)
+	}
+	return result, nil
+}
+
+// ------------------ sign intents ------------------------
+
+// UpdateSignIntent update (insert if not exist) sign intent
+func (s *BoltStore) UpdateSignIntent(intent *MgoSignIntent) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return boltPut(tx, s.table(tbSignIntents), intent.Key, intent)
+	})
+	return boltError(err)
+}
+
+// RemoveSignIntent remove sign intent
+func (s *BoltStore) RemoveSignIntent(key string) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(s.table(tbSignIntents)))
+		if bucket.Get([]byte(key)) == nil {
+			return ErrItemNotFound
+		}
+		return bucket.Delete([]byte(key))
+	})
+	return boltError(err)
+}
+
+// FindSignIntent find sign intent
+func (s *BoltStore) FindSignIntent(key string) (*MgoSignIntent, error) {
+	var result MgoSignIntent
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return boltGet(tx, s.table(tbSignIntents), key, &result)
+	})
+	if err != nil {
+		return nil, boltError(err)
+	}
+	return &result, nil
+}
+
+// FindSignIntents find all sign intents
+func (s *BoltStore) FindSignIntents() ([]*MgoSignIntent, error) {
+	result := make([]*MgoSignIntent, 0, 20)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(s.table(tbSignIntents))).ForEach(func(_, data []byte) error {
+			var intent MgoSignIntent
+			if err := bson.Unmarshal(data, &intent); err != nil {
+				return err
+			}
+			result = append(result, &intent)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, boltError(err)
+	}
+	return result, nil
+}
+
+// ------------------ sign requests ------------------------
+
+// UpdateSignRequest update (insert if not exist) sign request
+func (s *BoltStore) UpdateSignRequest(req *MgoSignRequest) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return boltPut(tx, tbSignRequests, req.Key, req)
+	})
+	return boltError(err)
+}
+
+// RemoveSignRequest remove sign request
+func (s *BoltStore) RemoveSignRequest(keyID string) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(tbSignRequests))
+		if bucket.Get([]byte(keyID)) == nil {
+			return ErrItemNotFound
+		}
+		return bucket.Delete([]byte(keyID))
+	})
+	return boltError(err)
+}
+
+// FindSignRequests find all sign requests
+func (s *BoltStore) FindSignRequests() ([]*MgoSignRequest, error) {
+	result := make([]*MgoSignRequest, 0, 20)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(tbSignRequests)).ForEach(func(_, data []byte) error {
+			var req MgoSignRequest
+			if err := bson.Unmarshal(data, &req); err != nil {
+				return err
+			}
+			result = append(result, &req)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, boltError(err)
+	}
+	return result, nil
+}
+
+// ------------------ nonce ------------------------
+
+// UpdateNonceInfo update (insert if not exist) nonce info
+func (s *BoltStore) UpdateNonceInfo(info *MgoNonceInfo) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return boltPut(tx, tbNonces, info.Key, info)
+	})
+	return boltError(err)
+}
+
+// FindNonceInfo find nonce info
+func (s *BoltStore) FindNonceInfo(key string) (*MgoNonceInfo, error) {
+	var result MgoNonceInfo
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return boltGet(tx, tbNonces, key, &result)
+	})
+	if err != nil {
+		return nil, boltError(err)
+	}
+	return &result, nil
+}
+
+// ------------------ utxo ------------------------
+
+// UpdateUtxo update (insert if not exist) utxo
+func (s *BoltStore) UpdateUtxo(mu *MgoUtxo) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return boltPut(tx, tbUtxos, mu.Key, mu)
+	})
+	return boltError(err)
+}
+
+// RemoveUtxo remove utxo
+func (s *BoltStore) RemoveUtxo(key string) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(tbUtxos))
+		if bucket.Get([]byte(key)) == nil {
+			return ErrItemNotFound
+		}
+		return bucket.Delete([]byte(key))
+	})
+	return boltError(err)
+}
+
+// FindUtxo find utxo
+func (s *BoltStore) FindUtxo(key string) (*MgoUtxo, error) {
+	var result MgoUtxo
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return boltGet(tx, tbUtxos, key, &result)
+	})
+	if err != nil {
+		return nil, boltError(err)
+	}
+	return &result, nil
+}
+
+// FindUtxos find utxos of address ('all' means all addresses)
+func (s *BoltStore) FindUtxos(address string) ([]*MgoUtxo, error) {
+	result := make([]*MgoUtxo, 0, 20)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(tbUtxos)).ForEach(func(_, data []byte) error {
+			var mu MgoUtxo
+			if err := bson.Unmarshal(data, &mu); err != nil {
+				return err
+			}
+			if address == "all" || mu.Address == address {
+				result = append(result, &mu)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, boltError(err)
+	}
+	return result, nil
+}
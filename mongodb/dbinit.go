@@ -21,6 +21,7 @@ func MongoServerInit(mongourl, dbname string) {
 	initMongodb(mongourl, dbname)
 	mongoConnect()
 	InitCollections()
+	SetStore(NewMgoStore())
 	go checkMongoSession()
 }
 
@@ -0,0 +1,682 @@
+package mongodb
+
+import (
+	"sync"
+)
+
+// memTable keeps items in insertion order (like mongodb natural order)
+type memTable struct {
+	keys  []string
+	items map[string]interface{}
+}
+
+func newMemTable() *memTable {
+	return &memTable{
+		items: make(map[string]interface{}),
+	}
+}
+
+func (t *memTable) insert(key string, item interface{}) error {
+	if _, exist := t.items[key]; exist {
+		return ErrItemIsDup
+	}
+	t.keys = append(t.keys, key)
+	t.items[key] = item
+	return nil
+}
+
+func (t *memTable) get(key string) (interface{}, error) {
+	item, exist := t.items[key]
+	if !exist {
+		return nil, ErrItemNotFound
+	}
+	return item, nil
+}
+
+func (t *memTable) set(key string, item interface{}) {
+	if _, exist := t.items[key]; !exist {
+		t.keys = append(t.keys, key)
+	}
+	t.items[key] = item
+}
+
+func (t *memTable) remove(key string) error {
+	if _, exist := t.items[key]; !exist {
+		return ErrItemNotFound
+	}
+	delete(t.items, key)
+	for i, k := range t.keys {
+		if k == key {
+			t.keys = append(t.keys[:i], t.keys[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (t *memTable) forEach(fn func(item interface{}) bool) {
+	for _, key := range t.keys {
+		if !fn(t.items[key]) {
+			return
+		}
+	}
+}
+
+// MemStore in-memory storage backend (all data is lost after restart)
+type MemStore struct {
+	lock sync.RWMutex
+
+	swapins        *memTable
+	swapouts       *memTable
+	swapinResults  *memTable
+	swapoutResults *memTable
+	p2shAddresses  *memTable
+	nonces         *memTable
+	utxos          *memTable
+	blockHashes    *memTable
+	reorgEvents    *memTable
+	signIntents    *memTable
+	signRequests   *memTable
+	statistics     MgoSwapStatistics
+	srcLatestScan  MgoLatestScanInfo
+	dstLatestScan  MgoLatestScanInfo
+	srcHistoryScan MgoLatestScanInfo
+	dstHistoryScan MgoLatestScanInfo
+}
+
+// NewMemStore new in-memory storage backend
+func NewMemStore() *MemStore {
+	return &MemStore{
+		swapins:        newMemTable(),
+		swapouts:       newMemTable(),
+		swapinResults:  newMemTable(),
+		swapoutResults: newMemTable(),
+		p2shAddresses:  newMemTable(),
+		nonces:         newMemTable(),
+		utxos:          newMemTable(),
+		blockHashes:    newMemTable(),
+		reorgEvents:    newMemTable(),
+		signIntents:    newMemTable(),
+		signRequests:   newMemTable(),
+		statistics:     MgoSwapStatistics{Key: keyOfSwapStatistics},
+		srcLatestScan:  MgoLatestScanInfo{Key: keyOfSrcLatestScanInfo},
+		dstLatestScan:  MgoLatestScanInfo{Key: keyOfDstLatestScanInfo},
+		srcHistoryScan: MgoLatestScanInfo{Key: keyOfSrcHistoryScan},
+		dstHistoryScan: MgoLatestScanInfo{Key: keyOfDstHistoryScan},
+	}
+}
+
+// WithNamespace new in-memory storage backend (namespaces never share tables)
+func (s *MemStore) WithNamespace(namespace string) Store {
+	return NewMemStore()
+}
+
+func (s *MemStore) swapTable(isSwapin bool) *memTable {
+	if isSwapin {
+		return s.swapins
+	}
+	return s.swapouts
+}
+
+func (s *MemStore) swapResultTable(isSwapin bool) *memTable {
+	if isSwapin {
+		return s.swapinResults
+	}
+	return s.swapoutResults
+}
+
+func (s *MemStore) latestScanInfo(isSrc bool) *MgoLatestScanInfo {
+	if isSrc {
+		return &s.srcLatestScan
+	}
+	return &s.dstLatestScan
+}
+
+func (s *MemStore) historyScanInfo(isSrc bool) *MgoLatestScanInfo {
+	if isSrc {
+		return &s.srcHistoryScan
+	}
+	return &s.dstHistoryScan
+}
+
+// ------------------ swapin / swapout ------------------------
+
+// AddSwap add swap
+func (s *MemStore) AddSwap(isSwapin bool, ms *MgoSwap) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.swapTable(isSwapin).insert(ms.Key, *ms)
+}
+
+// UpdateSwapStatus update swap status
+func (s *MemStore) UpdateSwapStatus(isSwapin bool, txid string, status SwapStatus, timestamp int64, memo string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	table := s.swapTable(isSwapin)
+	item, err := table.get(txid)
+	if err != nil {
+		return err
+	}
+	swap := item.(MgoSwap)
+	swap.Status = status
+	swap.Timestamp = timestamp
+	if memo != "" {
+		swap.Memo = memo
+	} else if status == TxNotSwapped {
+		swap.Memo = ""
+	}
+	table.set(txid, swap)
+	return nil
+}
+
+// FindSwap find swap
+func (s *MemStore) FindSwap(isSwapin bool, txid string) (*MgoSwap, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	item, err := s.swapTable(isSwapin).get(txid)
+	if err != nil {
+		return nil, err
+	}
+	swap := item.(MgoSwap)
+	return &swap, nil
+}
+
+// FindSwapsWithStatus find swaps with status in the past septime
+func (s *MemStore) FindSwapsWithStatus(isSwapin bool, status SwapStatus, septime int64) ([]*MgoSwap, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	result := make([]*MgoSwap, 0, 20)
+	s.swapTable(isSwapin).forEach(func(item interface{}) bool {
+		swap := item.(MgoSwap)
+		if swap.Status == status && swap.Timestamp >= septime {
+			result = append(result, &swap)
+		}
+		return len(result) < maxCountOfResults
+	})
+	return result, nil
+}
+
+// GetCountOfSwapsWithStatus get count of swaps with status
+func (s *MemStore) GetCountOfSwapsWithStatus(isSwapin bool, status SwapStatus) (int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	count := 0
+	s.swapTable(isSwapin).forEach(func(item interface{}) bool {
+		if item.(MgoSwap).Status == status {
+			count++
+		}
+		return true
+	})
+	return count, nil
+}
+
+// ------------------ swapin / swapout result ------------------------
+
+// AddSwapResult add swap result
+func (s *MemStore) AddSwapResult(isSwapin bool, mr *MgoSwapResult) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.swapResultTable(isSwapin).insert(mr.Key, *mr)
+}
+
+// UpdateSwapResult update swap result
+func (s *MemStore) UpdateSwapResult(isSwapin bool, txid string, items *SwapResultUpdateItems) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	table := s.swapResultTable(isSwapin)
+	item, err := table.get(txid)
+	if err != nil {
+		return err
+	}
+	res := item.(MgoSwapResult)
+	res.Status = items.Status
+	res.Timestamp = items.Timestamp
+	if items.TxHeight != 0 {
+		res.TxHeight = items.TxHeight
+	}
+	if items.TxTime != 0 {
+		res.TxTime = items.TxTime
+	}
+	if items.SwapTx != "" {
+		res.SwapTx = items.SwapTx
+		res.SwapHeight = 0
+		res.SwapTime = 0
+	}
+	if items.OldSwapTxs != nil {
+		res.OldSwapTxs = append([]string(nil), items.OldSwapTxs...)
+	}
+	if items.SwapNonce != nil {
+		nonce := *items.SwapNonce
+		res.SwapNonce = &nonce
+	}
+	if items.SwapHeight != 0 {
+		res.SwapHeight = items.SwapHeight
+	}
+	if items.SwapTime != 0 {
+		res.SwapTime = items.SwapTime
+	}
+	if items.SwapValue != "" {
+		res.SwapValue = items.SwapValue
+	}
+	if items.SwapType != 0 {
+		res.SwapType = items.SwapType
+	}
+	if items.Memo != "" {
+		res.Memo = items.Memo
+	} else if items.Status == MatchTxNotStable {
+		res.Memo = ""
+	}
+	table.set(txid, res)
+	return nil
+}
+
+// UpdateSwapResultStatus update swap result status
+func (s *MemStore) UpdateSwapResultStatus(isSwapin bool, txid string, status SwapStatus, timestamp int64, memo string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	table := s.swapResultTable(isSwapin)
+	item, err := table.get(txid)
+	if err != nil {
+		return err
+	}
+	res := item.(MgoSwapResult)
+	res.Status = status
+	res.Timestamp = timestamp
+	if memo != "" {
+		res.Memo = memo
+	}
+	table.set(txid, res)
+	return nil
+}
+
+// FindSwapResult find swap result
+func (s *MemStore) FindSwapResult(isSwapin bool, txid string) (*MgoSwapResult, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	item, err := s.swapResultTable(isSwapin).get(txid)
+	if err != nil {
+		return nil, err
+	}
+	res := item.(MgoSwapResult)
+	return &res, nil
+}
+
+// FindSwapResultsWithStatus find swap results with status in the past septime
+func (s *MemStore) FindSwapResultsWithStatus(isSwapin bool, status SwapStatus, septime int64) ([]*MgoSwapResult, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	result := make([]*MgoSwapResult, 0, 20)
+	s.swapResultTable(isSwapin).forEach(func(item interface{}) bool {
+		res := item.(MgoSwapResult)
+		if res.Status == status && res.Timestamp >= septime {
+			result = append(result, &res)
+		}
+		return len(result) < maxCountOfResults
+	})
+	return result, nil
+}
+
+// FindSwapResults find swap history results
+func (s *MemStore) FindSwapResults(isSwapin bool, address string, offset, limit int) ([]*MgoSwapResult, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	result := make([]*MgoSwapResult, 0, 20)
+	skipped := 0
+	s.swapResultTable(isSwapin).forEach(func(item interface{}) bool {
+		res := item.(MgoSwapResult)
+		if address != "all" && res.From != address {
+			return true
+		}
+		if skipped < offset {
+			skipped++
+			return true
+		}
+		result = append(result, &res)
+		return limit <= 0 || len(result) < limit
+	})
+	return result, nil
+}
+
+// GetCountOfSwapResults get count of swap results
+func (s *MemStore) GetCountOfSwapResults(isSwapin bool) (int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return len(s.swapResultTable(isSwapin).keys), nil
+}
+
+// GetCountOfSwapResultsWithStatus get count of swap results with status
+func (s *MemStore) GetCountOfSwapResultsWithStatus(isSwapin bool, status SwapStatus) (int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	count := 0
+	s.swapResultTable(isSwapin).forEach(func(item interface{}) bool {
+		if item.(MgoSwapResult).Status == status {
+			count++
+		}
+		return true
+	})
+	return count, nil
+}
+
+// ------------------ statistics ------------------------
+
+// UpdateSwapStatistics update swap statistics
+func (s *MemStore) UpdateSwapStatistics(stat *MgoSwapStatistics) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.statistics = *stat
+	s.statistics.Key = keyOfSwapStatistics
+	return nil
+}
+
+// FindSwapStatistics find swap statistics
+func (s *MemStore) FindSwapStatistics() (*MgoSwapStatistics, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	stat := s.statistics
+	return &stat, nil
+}
+
+// ------------------ p2sh address ------------------------
+
+// AddP2shAddress add p2sh address
+func (s *MemStore) AddP2shAddress(ma *MgoP2shAddress) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.p2shAddresses.insert(ma.Key, *ma)
+}
+
+// UpdateP2shAddress update p2sh address
+func (s *MemStore) UpdateP2shAddress(ma *MgoP2shAddress) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, err := s.p2shAddresses.get(ma.Key); err != nil {
+		return err
+	}
+	s.p2shAddresses.set(ma.Key, *ma)
+	return nil
+}
+
+// FindP2shAddress find p2sh addrss through bind address
+func (s *MemStore) FindP2shAddress(key string) (*MgoP2shAddress, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	item, err := s.p2shAddresses.get(key)
+	if err != nil {
+		return nil, err
+	}
+	ma := item.(MgoP2shAddress)
+	return &ma, nil
+}
+
+// FindP2shBindAddress find bind address through p2sh (or p2wsh) address
+func (s *MemStore) FindP2shBindAddress(p2shAddress string) (string, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	var bindAddress string
+	s.p2shAddresses.forEach(func(item interface{}) bool {
+		ma := item.(MgoP2shAddress)
+		if ma.P2shAddress == p2shAddress || ma.P2wshAddress == p2shAddress {
+			bindAddress = ma.Key
+			return false
+		}
+		return true
+	})
+	if bindAddress == "" {
+		return "", ErrItemNotFound
+	}
+	return bindAddress, nil
+}
+
+// FindP2shAddresses find p2sh address
+func (s *MemStore) FindP2shAddresses(offset, limit int) ([]*MgoP2shAddress, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	result := make([]*MgoP2shAddress, 0, limit)
+	skipped := 0
+	s.p2shAddresses.forEach(func(item interface{}) bool {
+		if skipped < offset {
+			skipped++
+			return true
+		}
+		ma := item.(MgoP2shAddress)
+		result = append(result, &ma)
+		return limit <= 0 || len(result) < limit
+	})
+	return result, nil
+}
+
+// ------------------ latest scan info ------------------------
+
+// UpdateLatestScanInfo update latest scan info
+func (s *MemStore) UpdateLatestScanInfo(isSrc bool, blockHeight uint64, timestamp int64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	info := s.latestScanInfo(isSrc)
+	info.BlockHeight = blockHeight
+	info.Timestamp = timestamp
+	return nil
+}
+
+// FindLatestScanInfo find latest scan info
+func (s *MemStore) FindLatestScanInfo(isSrc bool) (*MgoLatestScanInfo, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	info := *s.latestScanInfo(isSrc)
+	return &info, nil
+}
+
+// UpdateHistoryScanInfo update history scan checkpoint
+func (s *MemStore) UpdateHistoryScanInfo(isSrc bool, blockHeight uint64, timestamp int64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	info := s.historyScanInfo(isSrc)
+	info.BlockHeight = blockHeight
+	info.Timestamp = timestamp
+	return nil
+}
+
+// FindHistoryScanInfo find history scan checkpoint
+func (s *MemStore) FindHistoryScanInfo(isSrc bool) (*MgoLatestScanInfo, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	info := *s.historyScanInfo(isSrc)
+	return &info, nil
+}
+
+// ------------------ block hashes ------------------------
+
+// UpdateBlockHashes update (insert if not exist) hashes of recent blocks
+func (s *MemStore) UpdateBlockHashes(isSrc bool, hashes []MgoBlockHash, timestamp int64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key := getBlockHashesKey(isSrc)
+	s.blockHashes.set(key, MgoBlockHashes{
+		Key:       key,
+		Hashes:    append([]MgoBlockHash(nil), hashes...),
+		Timestamp: timestamp,
+	})
+	return nil
+}
+
+// FindBlockHashes find hashes of recent blocks (empty if not exist)
+func (s *MemStore) FindBlockHashes(isSrc bool) (*MgoBlockHashes, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	key := getBlockHashesKey(isSrc)
+	item, err := s.blockHashes.get(key)
+	if err != nil {
+		return &MgoBlockHashes{Key: key}, nil
+	}
+	info := item.(MgoBlockHashes)
+	info.Hashes = append([]MgoBlockHash(nil), info.Hashes...)
+	return &info, nil
+}
+
+// ------------------ reorg events ------------------------
+
+// AddReorgEvent add reorg event
+func (s *MemStore) AddReorgEvent(ev *MgoReorgEvent) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.reorgEvents.insert(ev.Key, *ev)
+}
+
+// FindReorgEvents find reorg events
+func (s *MemStore) FindReorgEvents(offset, limit int) ([]*MgoReorgEvent, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	result := make([]*MgoReorgEvent, 0, 20)
+	skipped := 0
+	s.reorgEvents.forEach(func(item interface{}) bool {
+		if skipped < offset {
+			skipped++
+			return true
+		}
+		ev := item.(MgoReorgEvent)
+		result = append(result, &ev)
+		return limit <= 0 || len(result) < limit
+	})
+	return result, nil
+}
+
+// ------------------ sign intents ------------------------
+
+// UpdateSignIntent update (insert if not exist) sign intent
+func (s *MemStore) UpdateSignIntent(intent *MgoSignIntent) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.signIntents.set(intent.Key, *intent)
+	return nil
+}
+
+// RemoveSignIntent remove sign intent
+func (s *MemStore) RemoveSignIntent(key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.signIntents.remove(key)
+}
+
+// FindSignIntent find sign intent
+func (s *MemStore) FindSignIntent(key string) (*MgoSignIntent, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	item, err := s.signIntents.get(key)
+	if err != nil {
+		return nil, err
+	}
+	intent := item.(MgoSignIntent)
+	return &intent, nil
+}
+
+// FindSignIntents find all sign intents
+func (s *MemStore) FindSignIntents() ([]*MgoSignIntent, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	result := make([]*MgoSignIntent, 0, 20)
+	s.signIntents.forEach(func(item interface{}) bool {
+		intent := item.(MgoSignIntent)
+		result = append(result, &intent)
+		return true
+	})
+	return result, nil
+}
+
+// ------------------ sign requests ------------------------
+
+// UpdateSignRequest update (insert if not exist) sign request
+func (s *MemStore) UpdateSignRequest(req *MgoSignRequest) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.signRequests.set(req.Key, *req)
+	return nil
+}
+
+// RemoveSignRequest remove sign request
+func (s *MemStore) RemoveSignRequest(keyID string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.signRequests.remove(keyID)
+}
+
+// FindSignRequests find all sign requests
+func (s *MemStore) FindSignRequests() ([]*MgoSignRequest, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	result := make([]*MgoSignRequest, 0, 20)
+	s.signRequests.forEach(func(item interface{}) bool {
+		req := item.(MgoSignRequest)
+		result = append(result, &req)
+		return true
+	})
+	return result, nil
+}
+
+// ------------------ nonce ------------------------
+
+// UpdateNonceInfo update (insert if not exist) nonce info
+func (s *MemStore) UpdateNonceInfo(info *MgoNonceInfo) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	item := *info
+	item.Released = append([]uint64(nil), info.Released...)
+	s.nonces.set(info.Key, item)
+	return nil
+}
+
+// FindNonceInfo find nonce info
+func (s *MemStore) FindNonceInfo(key string) (*MgoNonceInfo, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	item, err := s.nonces.get(key)
+	if err != nil {
+		return nil, err
+	}
+	info := item.(MgoNonceInfo)
+	info.Released = append([]uint64(nil), info.Released...)
+	return &info, nil
+}
+
+// ------------------ utxo ------------------------
+
+// UpdateUtxo update (insert if not exist) utxo
+func (s *MemStore) UpdateUtxo(mu *MgoUtxo) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.utxos.set(mu.Key, *mu)
+	return nil
+}
+
+// RemoveUtxo remove utxo
+func (s *MemStore) RemoveUtxo(key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.utxos.remove(key)
+}
+
+// FindUtxo find utxo
+func (s *MemStore) FindUtxo(key string) (*MgoUtxo, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	item, err := s.utxos.get(key)
+	if err != nil {
+		return nil, err
+	}
+	mu := item.(MgoUtxo)
+	return &mu, nil
+}
+
+// FindUtxos find utxos of address ('all' means all addresses)
+func (s *MemStore) FindUtxos(address string) ([]*MgoUtxo, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	result := make([]*MgoUtxo, 0, 20)
+	s.utxos.forEach(func(item interface{}) bool {
+		mu := item.(MgoUtxo)
+		if address == "all" || mu.Address == address {
+			result = append(result, &mu)
+		}
+		return true
+	})
+	return result, nil
+}
@@ -0,0 +1,542 @@
+package mongodb
+
+import (
+	"sync"
+
+	"github.com/fsn-dev/crossChain-Bridge/log"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var (
+	// collections key is the namespaced table name
+	collections     = make(map[string]*mgo.Collection)
+	collectionsLock sync.Mutex
+)
+
+// do this when reconnect to the database
+func deinintCollections() {
+	collectionsLock.Lock()
+	defer collectionsLock.Unlock()
+	collections = make(map[string]*mgo.Collection)
+}
+
+// getIndexKeys get keys of indexes of table, every item is a (compound) index
+func getIndexKeys(table string) [][]string {
+	switch table {
+	case tbSwapins, tbSwapouts:
+		return [][]string{{"timestamp", "status"}}
+	case tbSwapinResults, tbSwapoutResults:
+		return [][]string{{"from", "timestamp"}}
+	case tbP2shAddresses:
+		return [][]string{{"p2shaddress"}, {"p2wshaddress"}}
+	case tbUtxos:
+		return [][]string{{"address"}}
+	case tbSwapStatistics, tbLatestScanInfo, tbNonces, tbBlockHashes, tbReorgEvents, tbSignIntents, tbSignRequests:
+		return nil
+	default:
+		panic("unknown talbe " + table)
+	}
+}
+
+// getNamespacedTable tables of namespace are prefixed with 'namespace_'
+func getNamespacedTable(namespace, table string) string {
+	if namespace == "" {
+		return table
+	}
+	return namespace + "_" + table
+}
+
+func getNamespacedCollection(namespace, table string) *mgo.Collection {
+	indexKeys := getIndexKeys(table)
+	name := getNamespacedTable(namespace, table)
+
+	collectionsLock.Lock()
+	defer collectionsLock.Unlock()
+	collection, exist := collections[name]
+	if !exist {
+		collection = database.C(name)
+		for _, indexKey := range indexKeys {
+			err := collection.EnsureIndexKey(indexKey...)
+			if err != nil {
+				log.Error("EnsureIndexKey error", "table", name, "indexKey", indexKey)
+			}
+		}
+		collections[name] = collection
+	}
+	return collection
+}
+
+func getSwapTable(isSwapin bool) string {
+	if isSwapin {
+		return tbSwapins
+	}
+	return tbSwapouts
+}
+
+func getSwapResultTable(isSwapin bool) string {
+	if isSwapin {
+		return tbSwapinResults
+	}
+	return tbSwapoutResults
+}
+
+// MgoStore mongodb storage backend
+type MgoStore struct {
+	namespace string
+}
+
+// NewMgoStore new mongodb storage backend (call MongoServerInit first)
+func NewMgoStore() *MgoStore {
+	return &MgoStore{}
+}
+
+// WithNamespace new mongodb storage backend whose collections are prefixed with namespace
+func (s *MgoStore) WithNamespace(namespace string) Store {
+	ns := &MgoStore{namespace: namespace}
+	ns.initCollections()
+	return ns
+}
+
+func (s *MgoStore) getCollection(table string) *mgo.Collection {
+	return getNamespacedCollection(s.namespace, table)
+}
+
+// ------------------ swapin / swapout ------------------------
+
+// AddSwap add swap
+func (s *MgoStore) AddSwap(isSwapin bool, ms *MgoSwap) error {
+	err := s.getCollection(getSwapTable(isSwapin)).Insert(ms)
+	return mgoError(err)
+}
+
+// UpdateSwapStatus update swap status
+func (s *MgoStore) UpdateSwapStatus(isSwapin bool, txid string, status SwapStatus, timestamp int64, memo string) error {
+	updates := bson.M{"status": status, "timestamp": timestamp}
+	if memo != "" {
+		updates["memo"] = memo
+	} else if status == TxNotSwapped {
+		updates["memo"] = ""
+	}
+	err := s.getCollection(getSwapTable(isSwapin)).UpdateId(txid, bson.M{"$set": updates})
+	return mgoError(err)
+}
+
+// FindSwap find swap
+func (s *MgoStore) FindSwap(isSwapin bool, txid string) (*MgoSwap, error) {
+	var result MgoSwap
+	err := s.getCollection(getSwapTable(isSwapin)).FindId(txid).One(&result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return &result, nil
+}
+
+// FindSwapsWithStatus find swaps with status in the past septime
+func (s *MgoStore) FindSwapsWithStatus(isSwapin bool, status SwapStatus, septime int64) (result []*MgoSwap, err error) {
+	err = findSwapsOrSwapResultsWithStatus(&result, s.getCollection(getSwapTable(isSwapin)), status, septime)
+	return result, err
+}
+
+// GetCountOfSwapsWithStatus get count of swaps with status
+func (s *MgoStore) GetCountOfSwapsWithStatus(isSwapin bool, status SwapStatus) (int, error) {
+	return getCountWithStatus(s.getCollection(getSwapTable(isSwapin)), status)
+}
+
+// ------------------ swapin / swapout result ------------------------
+
+// AddSwapResult add swap result
+func (s *MgoStore) AddSwapResult(isSwapin bool, mr *MgoSwapResult) error {
+	err := s.getCollection(getSwapResultTable(isSwapin)).Insert(mr)
+	return mgoError(err)
+}
+
+// UpdateSwapResult update swap result
+func (s *MgoStore) UpdateSwapResult(isSwapin bool, txid string, items *SwapResultUpdateItems) error {
+	updates := bson.M{
+		"status":    items.Status,
+		"timestamp": items.Timestamp,
+	}
+	if items.TxHeight != 0 {
+		updates["txheight"] = items.TxHeight
+	}
+	if items.TxTime != 0 {
+		updates["txtime"] = items.TxTime
+	}
+	if items.SwapTx != "" {
+		updates["swaptx"] = items.SwapTx
+		updates["swapheight"] = 0
+		updates["swaptime"] = 0
+	}
+	if items.OldSwapTxs != nil {
+		updates["oldswaptxs"] = items.OldSwapTxs
+	}
+	if items.SwapNonce != nil {
+		updates["swapnonce"] = *items.SwapNonce
+	}
+	if items.SwapHeight != 0 {
+		updates["swapheight"] = items.SwapHeight
+	}
+	if items.SwapTime != 0 {
+		updates["swaptime"] = items.SwapTime
+	}
+	if items.SwapValue != "" {
+		updates["swapvalue"] = items.SwapValue
+	}
+	if items.SwapType != 0 {
+		updates["swaptype"] = items.SwapType
+	}
+	if items.Memo != "" {
+		updates["memo"] = items.Memo
+	} else if items.Status == MatchTxNotStable {
+		updates["memo"] = ""
+	}
+	err := s.getCollection(getSwapResultTable(isSwapin)).UpdateId(txid, bson.M{"$set": updates})
+	return mgoError(err)
+}
+
+// UpdateSwapResultStatus update swap result status
+func (s *MgoStore) UpdateSwapResultStatus(isSwapin bool, txid string, status SwapStatus, timestamp int64, memo string) error {
+	updates := bson.M{"status": status, "timestamp": timestamp}
+	if memo != "" {
+		updates["memo"] = memo
+	}
+	err := s.getCollection(getSwapResultTable(isSwapin)).UpdateId(txid, bson.M{"$set": updates})
+	return mgoError(err)
+}
+
+// FindSwapResult find swap result
+func (s *MgoStore) FindSwapResult(isSwapin bool, txid string) (*MgoSwapResult, error) {
+	var result MgoSwapResult
+	err := s.getCollection(getSwapResultTable(isSwapin)).FindId(txid).One(&result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return &result, nil
+}
+
+// FindSwapResultsWithStatus find swap results with status in the past septime
+func (s *MgoStore) FindSwapResultsWithStatus(isSwapin bool, status SwapStatus, septime int64) (result []*MgoSwapResult, err error) {
+	err = findSwapsOrSwapResultsWithStatus(&result, s.getCollection(getSwapResultTable(isSwapin)), status, septime)
+	return result, err
+}
+
+// FindSwapResults find swap history results
+func (s *MgoStore) FindSwapResults(isSwapin bool, address string, offset, limit int) ([]*MgoSwapResult, error) {
+	result := make([]*MgoSwapResult, 0, 20)
+	var q *mgo.Query
+	coll := s.getCollection(getSwapResultTable(isSwapin))
+	if address == "all" {
+		q = coll.Find(nil).Skip(offset).Limit(limit)
+	} else {
+		q = coll.Find(bson.M{"from": address}).Skip(offset).Limit(limit)
+	}
+	err := q.All(&result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// GetCountOfSwapResults get count of swap results
+func (s *MgoStore) GetCountOfSwapResults(isSwapin bool) (int, error) {
+	return s.getCollection(getSwapResultTable(isSwapin)).Find(nil).Count()
+}
+
+// GetCountOfSwapResultsWithStatus get count of swap results with status
+func (s *MgoStore) GetCountOfSwapResultsWithStatus(isSwapin bool, status SwapStatus) (int, error) {
+	return getCountWithStatus(s.getCollection(getSwapResultTable(isSwapin)), status)
+}
+
+func findSwapsOrSwapResultsWithStatus(result interface{}, coll *mgo.Collection, status SwapStatus, septime int64) error {
+	qtime := bson.M{"timestamp": bson.M{"$gte": septime}}
+	qstatus := bson.M{"status": status}
+	queries := []bson.M{qtime, qstatus}
+	q := coll.Find(bson.M{"$and": queries}).Limit(maxCountOfResults)
+	return mgoError(q.All(result))
+}
+
+func getCountWithStatus(coll *mgo.Collection, status SwapStatus) (int, error) {
+	return coll.Find(bson.M{"status": status}).Count()
+}
+
+// ------------------ statistics ------------------------
+
+// UpdateSwapStatistics update swap statistics
+func (s *MgoStore) UpdateSwapStatistics(stat *MgoSwapStatistics) error {
+	_, err := s.getCollection(tbSwapStatistics).UpsertId(keyOfSwapStatistics, stat)
+	return mgoError(err)
+}
+
+// FindSwapStatistics find swap statistics
+func (s *MgoStore) FindSwapStatistics() (*MgoSwapStatistics, error) {
+	var result MgoSwapStatistics
+	err := s.getCollection(tbSwapStatistics).FindId(keyOfSwapStatistics).One(&result)
+	return &result, mgoError(err)
+}
+
+// ------------------ p2sh address ------------------------
+
+// AddP2shAddress add p2sh address
+func (s *MgoStore) AddP2shAddress(ma *MgoP2shAddress) error {
+	err := s.getCollection(tbP2shAddresses).Insert(ma)
+	return mgoError(err)
+}
+
+// UpdateP2shAddress update p2sh address
+func (s *MgoStore) UpdateP2shAddress(ma *MgoP2shAddress) error {
+	err := s.getCollection(tbP2shAddresses).UpdateId(ma.Key, ma)
+	return mgoError(err)
+}
+
+// FindP2shAddress find p2sh addrss through bind address
+func (s *MgoStore) FindP2shAddress(key string) (*MgoP2shAddress, error) {
+	var result MgoP2shAddress
+	err := s.getCollection(tbP2shAddresses).FindId(key).One(&result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return &result, nil
+}
+
+// FindP2shBindAddress find bind address through p2sh (or p2wsh) address
+func (s *MgoStore) FindP2shBindAddress(p2shAddress string) (string, error) {
+	var result MgoP2shAddress
+	query := bson.M{"$or": []bson.M{{"p2shaddress": p2shAddress}, {"p2wshaddress": p2shAddress}}}
+	err := s.getCollection(tbP2shAddresses).Find(query).One(&result)
+	if err != nil {
+		return "", mgoError(err)
+	}
+	return result.Key, nil
+}
+
+// FindP2shAddresses find p2sh address
+func (s *MgoStore) FindP2shAddresses(offset, limit int) ([]*MgoP2shAddress, error) {
+	result := make([]*MgoP2shAddress, 0, limit)
+	q := s.getCollection(tbP2shAddresses).Find(nil).Skip(offset).Limit(limit)
+	err := q.All(&result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// ------------------ latest scan info ------------------------
+
+// UpdateLatestScanInfo update latest scan info
+func (s *MgoStore) UpdateLatestScanInfo(isSrc bool, blockHeight uint64, timestamp int64) error {
+	updates := bson.M{
+		"blockheight": blockHeight,
+		"timestamp":   timestamp,
+	}
+	err := s.getCollection(tbLatestScanInfo).UpdateId(getLatestScanInfoKey(isSrc), bson.M{"$set": updates})
+	return mgoError(err)
+}
+
+// FindLatestScanInfo find latest scan info
+func (s *MgoStore) FindLatestScanInfo(isSrc bool) (*MgoLatestScanInfo, error) {
+	var result MgoLatestScanInfo
+	err := s.getCollection(tbLatestScanInfo).FindId(getLatestScanInfoKey(isSrc)).One(&result)
+	return &result, mgoError(err)
+}
+
+// UpdateHistoryScanInfo update (insert if not exist) history scan checkpoint
+func (s *MgoStore) UpdateHistoryScanInfo(isSrc bool, blockHeight uint64, timestamp int64) error {
+	info := &MgoLatestScanInfo{
+		Key:         getHistoryScanInfoKey(isSrc),
+		BlockHeight: blockHeight,
+		Timestamp:   timestamp,
+	}
+	_, err := s.getCollection(tbLatestScanInfo).UpsertId(info.Key, info)
+	return mgoError(err)
+}
+
+// FindHistoryScanInfo find history scan checkpoint (empty if not exist)
+func (s *MgoStore) FindHistoryScanInfo(isSrc bool) (*MgoLatestScanInfo, error) {
+	result := MgoLatestScanInfo{Key: getHistoryScanInfoKey(isSrc)}
+	err := s.getCollection(tbLatestScanInfo).FindId(result.Key).One(&result)
+	if err == mgo.ErrNotFound {
+		err = nil
+	}
+	return &result, mgoError(err)
+}
+
+// ------------------ block hashes ------------------------
+
+// UpdateBlockHashes update (insert if not exist) hashes of recent blocks
+func (s *MgoStore) UpdateBlockHashes(isSrc bool, hashes []MgoBlockHash, timestamp int64) error {
+	info := &MgoBlockHashes{
+		Key:       getBlockHashesKey(isSrc),
+		Hashes:    hashes,
+		Timestamp: timestamp,
+	}
+	_, err := s.getCollection(tbBlockHashes).UpsertId(info.Key, info)
+	return mgoError(err)
+}
+
+// FindBlockHashes find hashes of recent blocks (empty if not exist)
+func (s *MgoStore) FindBlockHashes(isSrc bool) (*MgoBlockHashes, error) {
+	result := MgoBlockHashes{Key: getBlockHashesKey(isSrc)}
+	err := s.getCollection(tbBlockHashes).FindId(result.Key).One(&result)
+	if err == mgo.ErrNotFound {
+		err = nil
+	}
+	return &result, mgoError(err)
+}
+
+// ------------------ reorg events ------------------------
+
+// AddReorgEvent add reorg event
+func (s *MgoStore) AddReorgEvent(ev *MgoReorgEvent) error {
+	err := s.getCollection(tbReorgEvents).Insert(ev)
+	return mgoError(err)
+}
+
+// FindReorgEvents find reorg events
+func (s *MgoStore) FindReorgEvents(offset, limit int) ([]*MgoReorgEvent, error) {
+	result := make([]*MgoReorgEvent, 0, 20)
+	q := s.getCollection(tbReorgEvents).Find(nil).Skip(offset).Limit(limit)
+	err := q.All(&result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// ------------------ sign intents ------------------------
+
+// UpdateSignIntent update (insert if not exist) sign intent
+func (s *MgoStore) UpdateSignIntent(intent *MgoSignIntent) error {
+	_, err := s.getCollection(tbSignIntents).UpsertId(intent.Key, intent)
+	return mgoError(err)
+}
+
+// RemoveSignIntent remove sign intent
+func (s *MgoStore) RemoveSignIntent(key string) error {
+	err := s.getCollection(tbSignIntents).RemoveId(key)
+	return mgoError(err)
+}
+
+// FindSignIntent find sign intent
+func (s *MgoStore) FindSignIntent(key string) (*MgoSignIntent, error) {
+	var result MgoSignIntent
+	err := s.getCollection(tbSignIntents).FindId(key).One(&result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return &result, nil
+}
+
+// FindSignIntents find all sign intents
+func (s *MgoStore) FindSignIntents() ([]*MgoSignIntent, error) {
+	result := make([]*MgoSignIntent, 0, 20)
+	err := s.getCollection(tbSignIntents).Find(nil).All(&result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// ------------------ sign requests ------------------------
+// sign requests are shared by all namespaces
+
+// UpdateSignRequest update (insert if not exist) sign request
+func (s *MgoStore) UpdateSignRequest(req *MgoSignRequest) error {
+	_, err := getNamespacedCollection("", tbSignRequests).UpsertId(req.Key, req)
+	return mgoError(err)
+}
+
+// RemoveSignRequest remove sign request
+func (s *MgoStore) RemoveSignRequest(keyID string) error {
+	err := getNamespacedCollection("", tbSignRequests).RemoveId(keyID)
+	return mgoError(err)
+}
+
+// FindSignRequests find all sign requests
+func (s *MgoStore) FindSignRequests() ([]*MgoSignRequest, error) {
+	result := make([]*MgoSignRequest, 0, 20)
+	err := getNamespacedCollection("", tbSignRequests).Find(nil).All(&result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// ------------------ nonce ------------------------
+// nonces are shared by all namespaces
+
+// UpdateNonceInfo update (insert if not exist) nonce info
+func (s *MgoStore) UpdateNonceInfo(info *MgoNonceInfo) error {
+	_, err := getNamespacedCollection("", tbNonces).UpsertId(info.Key, info)
+	return mgoError(err)
+}
+
+// FindNonceInfo find nonce info
+func (s *MgoStore) FindNonceInfo(key string) (*MgoNonceInfo, error) {
+	var result MgoNonceInfo
+	err := getNamespacedCollection("", tbNonces).FindId(key).One(&result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return &result, nil
+}
+
+// ------------------ utxo ------------------------
+// utxos are shared by all namespaces
+
+// UpdateUtxo update (insert if not exist) utxo
+func (s *MgoStore) UpdateUtxo(mu *MgoUtxo) error {
+	_, err := getNamespacedCollection("", tbUtxos).UpsertId(mu.Key, mu)
+	return mgoError(err)
+}
+
+// RemoveUtxo remove utxo
+func (s *MgoStore) RemoveUtxo(key string) error {
+	err := getNamespacedCollection("", tbUtxos).RemoveId(key)
+	return mgoError(err)
+}
+
+// FindUtxo find utxo
+func (s *MgoStore) FindUtxo(key string) (*MgoUtxo, error) {
+	var result MgoUtxo
+	err := getNamespacedCollection("", tbUtxos).FindId(key).One(&result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return &result, nil
+}
+
+// FindUtxos find utxos of address ('all' means all addresses)
+func (s *MgoStore) FindUtxos(address string) ([]*MgoUtxo, error) {
+	var query bson.M
+	if address != "all" {
+		query = bson.M{"address": address}
+	}
+	result := make([]*MgoUtxo, 0, 20)
+	err := getNamespacedCollection("", tbUtxos).Find(query).All(&result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// InitCollections init some tables
+func InitCollections() {
+	NewMgoStore().initCollections()
+}
+
+func (s *MgoStore) initCollections() {
+	_ = s.getCollection(tbSwapStatistics).Insert(
+		&MgoSwapStatistics{
+			Key: keyOfSwapStatistics,
+		},
+	)
+	_ = s.getCollection(tbLatestScanInfo).Insert(
+		&MgoLatestScanInfo{
+			Key: keyOfSrcLatestScanInfo,
+		},
+		&MgoLatestScanInfo{
+			Key: keyOfDstLatestScanInfo,
+		},
+	)
+}
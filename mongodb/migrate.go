@@ -0,0 +1,199 @@
+package mongodb
+
+import (
+	"github.com/fsn-dev/crossChain-Bridge/log"
+)
+
+// MigrateToStore copy all tables of mongodb namespace into dest storage (call MongoServerInit first).
+// Empty namespace is the default one, nonces and utxos are only migrated with it.
+// Items already exist in dest storage are skipped, so it is safe to migrate again.
+func MigrateToStore(namespace string, dst Store) error {
+	src := &MgoStore{namespace: namespace}
+	for _, isSwapin := range []bool{true, false} {
+		if err := src.migrateSwaps(dst, isSwapin); err != nil {
+			return err
+		}
+		if err := src.migrateSwapResults(dst, isSwapin); err != nil {
+			return err
+		}
+	}
+	if err := src.migrateP2shAddresses(dst); err != nil {
+		return err
+	}
+	if err := src.migrateReorgEvents(dst); err != nil {
+		return err
+	}
+	if err := src.migrateSignIntents(dst); err != nil {
+		return err
+	}
+	if namespace == "" {
+		if err := migrateNonces(dst); err != nil {
+			return err
+		}
+		if err := migrateUtxos(dst); err != nil {
+			return err
+		}
+		if err := migrateSignRequests(dst); err != nil {
+			return err
+		}
+	}
+	if stat, err := src.FindSwapStatistics(); err == nil {
+		if err = dst.UpdateSwapStatistics(stat); err != nil {
+			return err
+		}
+	}
+	for _, isSrc := range []bool{true, false} {
+		info, err := src.FindLatestScanInfo(isSrc)
+		if err != nil {
+			continue
+		}
+		if err = dst.UpdateLatestScanInfo(isSrc, info.BlockHeight, info.Timestamp); err != nil {
+			return err
+		}
+	}
+	for _, isSrc := range []bool{true, false} {
+		info, err := src.FindHistoryScanInfo(isSrc)
+		if err != nil || info.BlockHeight == 0 {
+			continue
+		}
+		if err = dst.UpdateHistoryScanInfo(isSrc, info.BlockHeight, info.Timestamp); err != nil {
+			return err
+		}
+	}
+	log.Info("migrate mongodb finished", "dbName", dbName, "namespace", namespace)
+	return nil
+}
+
+func isMigrated(err error) bool {
+	return err == nil || err == ErrItemIsDup
+}
+
+func (s *MgoStore) migrateSwaps(dst Store, isSwapin bool) error {
+	table := getSwapTable(isSwapin)
+	iter := s.getCollection(table).Find(nil).Iter()
+	count := 0
+	var swap MgoSwap
+	for iter.Next(&swap) {
+		if err := dst.AddSwap(isSwapin, &swap); !isMigrated(err) {
+			_ = iter.Close()
+			return err
+		}
+		swap = MgoSwap{}
+		count++
+	}
+	log.Info("migrate mongodb table", "table", getNamespacedTable(s.namespace, table), "count", count)
+	return mgoError(iter.Close())
+}
+
+func (s *MgoStore) migrateSwapResults(dst Store, isSwapin bool) error {
+	table := getSwapResultTable(isSwapin)
+	iter := s.getCollection(table).Find(nil).Iter()
+	count := 0
+	var res MgoSwapResult
+	for iter.Next(&res) {
+		if err := dst.AddSwapResult(isSwapin, &res); !isMigrated(err) {
+			_ = iter.Close()
+			return err
+		}
+		res = MgoSwapResult{}
+		count++
+	}
+	log.Info("migrate mongodb table", "table", getNamespacedTable(s.namespace, table), "count", count)
+	return mgoError(iter.Close())
+}
+
+func (s *MgoStore) migrateP2shAddresses(dst Store) error {
+	iter := s.getCollection(tbP2shAddresses).Find(nil).Iter()
+	count := 0
+	var ma MgoP2shAddress
+	for iter.Next(&ma) {
+		if err := dst.AddP2shAddress(&ma); !isMigrated(err) {
+			_ = iter.Close()
+			return err
+		}
+		ma = MgoP2shAddress{}
+		count++
+	}
+	log.Info("migrate mongodb table", "table", getNamespacedTable(s.namespace, tbP2shAddresses), "count", count)
+	return mgoError(iter.Close())
+}
+
+func (s *MgoStore) migrateReorgEvents(dst Store) error {
+	iter := s.getCollection(tbReorgEvents).Find(nil).Iter()
+	count := 0
+	var ev MgoReorgEvent
+	for iter.Next(&ev) {
+		if err := dst.AddReorgEvent(&ev); !isMigrated(err) {
+			_ = iter.Close()
+			return err
+		}
+		ev = MgoReorgEvent{}
+		count++
+	}
+	log.Info("migrate mongodb table", "table", getNamespacedTable(s.namespace, tbReorgEvents), "count", count)
+	return mgoError(iter.Close())
+}
+
+func (s *MgoStore) migrateSignIntents(dst Store) error {
+	iter := s.getCollection(tbSignIntents).Find(nil).Iter()
+	count := 0
+	var intent MgoSignIntent
+	for iter.Next(&intent) {
+		if err := dst.UpdateSignIntent(&intent); err != nil {
+			_ = iter.Close()
+			return err
+		}
+		intent = MgoSignIntent{}
+		count++
+	}
+	log.Info("migrate mongodb table", "table", getNamespacedTable(s.namespace, tbSignIntents), "count", count)
+	return mgoError(iter.Close())
+}
+
+func migrateNonces(dst Store) error {
+	iter := getNamespacedCollection("", tbNonces).Find(nil).Iter()
+	count := 0
+	var info MgoNonceInfo
+	for iter.Next(&info) {
+		if err := dst.UpdateNonceInfo(&info); err != nil {
+			_ = iter.Close()
+			return err
+		}
+		info = MgoNonceInfo{}
+		count++
+	}
+	log.Info("migrate mongodb table", "table", tbNonces, "count", count)
+	return mgoError(iter.Close())
+}
+
+func migrateUtxos(dst Store) error {
+	iter := getNamespacedCollection("", tbUtxos).Find(nil).Iter()
+	count := 0
+	var mu MgoUtxo
+	for iter.Next(&mu) {
+		if err := dst.UpdateUtxo(&mu); err != nil {
+			_ = iter.Close()
+			return err
+		}
+		mu = MgoUtxo{}
+		count++
+	}
+	log.Info("migrate mongodb table", "table", tbUtxos, "count", count)
+	return mgoError(iter.Close())
+}
+
+func migrateSignRequests(dst Store) error {
+	iter := getNamespacedCollection("", tbSignRequests).Find(nil).Iter()
+	count := 0
+	var req MgoSignRequest
+	for iter.Next(&req) {
+		if err := dst.UpdateSignRequest(&req); err != nil {
+			_ = iter.Close()
+			return err
+		}
+		req = MgoSignRequest{}
+		count++
+	}
+	log.Info("migrate mongodb table", "table", tbSignRequests, "count", count)
+	return mgoError(iter.Close())
+}
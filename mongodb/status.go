@@ -11,8 +11,14 @@ package mongodb
 // -----------------------------------------------
 // swap result status change graph
 //
-// TxWithWrongMemo -> |
-// MatchTxEmpty    -> | MatchTxNotStable -> MatchTxStable
+// TxWithWrongMemo  -> |
+// TxWithWrongValue -> |
+// MatchTxEmpty     -> | MatchTxNotStable -> MatchTxStable
+// -----------------------------------------------
+// chain reorg roll back
+//
+// swap whose deposit is reorged       -> TxNotStable (verify again)
+// swap result whose swap tx is reorged -> MatchTxNotStable
 // -----------------------------------------------
 
 // SwapStatus swap status
@@ -32,6 +38,7 @@ const (
 	MatchTxNotStable                   // 9
 	MatchTxStable                      // 10
 	TxWithWrongMemo                    // 11
+	TxWithWrongValue                   // 12
 )
 
 func (status SwapStatus) String() string {
@@ -60,6 +67,8 @@ func (status SwapStatus) String() string {
 		return "MatchTxStable"
 	case TxWithWrongMemo:
 		return "TxWithWrongMemo"
+	case TxWithWrongValue:
+		return "TxWithWrongValue"
 	default:
 		panic("unknown swap status")
 	}
@@ -0,0 +1,122 @@
+package mongodb
+
+// Store storage backend of swap server
+type Store interface {
+	// swapin / swapout
+	AddSwap(isSwapin bool, ms *MgoSwap) error
+	UpdateSwapStatus(isSwapin bool, txid string, status SwapStatus, timestamp int64, memo string) error
+	FindSwap(isSwapin bool, txid string) (*MgoSwap, error)
+	FindSwapsWithStatus(isSwapin bool, status SwapStatus, septime int64) ([]*MgoSwap, error)
+	GetCountOfSwapsWithStatus(isSwapin bool, status SwapStatus) (int, error)
+
+	// swapin / swapout result
+	AddSwapResult(isSwapin bool, mr *MgoSwapResult) error
+	UpdateSwapResult(isSwapin bool, txid string, items *SwapResultUpdateItems) error
+	UpdateSwapResultStatus(isSwapin bool, txid string, status SwapStatus, timestamp int64, memo string) error
+	FindSwapResult(isSwapin bool, txid string) (*MgoSwapResult, error)
+	FindSwapResultsWithStatus(isSwapin bool, status SwapStatus, septime int64) ([]*MgoSwapResult, error)
+	FindSwapResults(isSwapin bool, address string, offset, limit int) ([]*MgoSwapResult, error)
+	GetCountOfSwapResults(isSwapin bool) (int, error)
+	GetCountOfSwapResultsWithStatus(isSwapin bool, status SwapStatus) (int, error)
+
+	// statistics
+	UpdateSwapStatistics(stat *MgoSwapStatistics) error
+	FindSwapStatistics() (*MgoSwapStatistics, error)
+
+	// p2sh address
+	AddP2shAddress(ma *MgoP2shAddress) error
+	UpdateP2shAddress(ma *MgoP2shAddress) error
+	FindP2shAddress(key string) (*MgoP2shAddress, error)
+	FindP2shBindAddress(p2shAddress string) (string, error)
+	FindP2shAddresses(offset, limit int) ([]*MgoP2shAddress, error)
+
+	// latest scan info
+	UpdateLatestScanInfo(isSrc bool, blockHeight uint64, timestamp int64) error
+	FindLatestScanInfo(isSrc bool) (*MgoLatestScanInfo, error)
+
+	// history scan checkpoint (stored in latest scan info table)
+	UpdateHistoryScanInfo(isSrc bool, blockHeight uint64, timestamp int64) error
+	FindHistoryScanInfo(isSrc bool) (*MgoLatestScanInfo, error)
+
+	// nonce
+	UpdateNonceInfo(info *MgoNonceInfo) error
+	FindNonceInfo(key string) (*MgoNonceInfo, error)
+
+	// utxo (utxos are shared by all namespaces)
+	UpdateUtxo(mu *MgoUtxo) error
+	RemoveUtxo(key string) error
+	FindUtxo(key string) (*MgoUtxo, error)
+	FindUtxos(address string) ([]*MgoUtxo, error)
+
+	// block hashes of recent blocks
+	UpdateBlockHashes(isSrc bool, hashes []MgoBlockHash, timestamp int64) error
+	FindBlockHashes(isSrc bool) (*MgoBlockHashes, error)
+
+	// reorg events
+	AddReorgEvent(ev *MgoReorgEvent) error
+	FindReorgEvents(offset, limit int) ([]*MgoReorgEvent, error)
+
+	// sign intents
+	UpdateSignIntent(intent *MgoSignIntent) error
+	RemoveSignIntent(key string) error
+	FindSignIntent(key string) (*MgoSignIntent, error)
+	FindSignIntents() ([]*MgoSignIntent, error)
+
+	// sign requests (shared by all namespaces)
+	UpdateSignRequest(req *MgoSignRequest) error
+	RemoveSignRequest(keyID string) error
+	FindSignRequests() ([]*MgoSignRequest, error)
+
+	// WithNamespace new storage backend which keeps swaps of a bridge pair
+	// apart from other pairs in the same database (nonces are shared)
+	WithNamespace(namespace string) Store
+}
+
+var (
+	store      Store
+	pairStores = make(map[string]Store)
+)
+
+// SetStore set storage backend
+func SetStore(s Store) {
+	store = s
+}
+
+// GetStore get storage backend
+func GetStore() Store {
+	return store
+}
+
+// AddPairStore set storage backend of bridge pair,
+// pair without its own storage backend uses the default one.
+func AddPairStore(pairID string, s Store) {
+	pairStores[pairID] = s
+}
+
+func getStore(pairID string) Store {
+	if s, exist := pairStores[pairID]; exist {
+		return s
+	}
+	return store
+}
+
+func getLatestScanInfoKey(isSrc bool) string {
+	if isSrc {
+		return keyOfSrcLatestScanInfo
+	}
+	return keyOfDstLatestScanInfo
+}
+
+func getHistoryScanInfoKey(isSrc bool) string {
+	if isSrc {
+		return keyOfSrcHistoryScan
+	}
+	return keyOfDstHistoryScan
+}
+
+func getBlockHashesKey(isSrc bool) string {
+	if isSrc {
+		return keyOfSrcBlockHashes
+	}
+	return keyOfDstBlockHashes
+}
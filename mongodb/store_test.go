@@ -0,0 +1,183 @@
+package mongodb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testStores run test against every storage backend which does not need a server
+func testStores(t *testing.T, test func(t *testing.T, s Store)) {
+	t.Run("MemStore", func(t *testing.T) {
+		test(t, NewMemStore())
+	})
+	t.Run("BoltStore", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "boltstore")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		s, err := NewBoltStore(filepath.Join(dir, "swap.bolt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+		test(t, s)
+	})
+}
+
+func TestStoreDupDetection(t *testing.T) {
+	testStores(t, func(t *testing.T, s Store) {
+		swap := &MgoSwap{Key: "0x01", TxID: "0x01", Status: TxNotStable, Timestamp: 100}
+		if err := s.AddSwap(true, swap); err != nil {
+			t.Fatalf("add swapin failed: %v", err)
+		}
+		if err := s.AddSwap(true, swap); err != ErrItemIsDup {
+			t.Fatalf("add dup swapin, want %v, have %v", ErrItemIsDup, err)
+		}
+		// swapin and swapout are in different tables
+		if err := s.AddSwap(false, swap); err != nil {
+			t.Fatalf("add swapout with the same key as swapin failed: %v", err)
+		}
+
+		res := &MgoSwapResult{Key: "0x01", TxID: "0x01", From: "0xaa", Status: MatchTxEmpty, Timestamp: 100}
+		if err := s.AddSwapResult(true, res); err != nil {
+			t.Fatalf("add swapin result failed: %v", err)
+		}
+		if err := s.AddSwapResult(true, res); err != ErrItemIsDup {
+			t.Fatalf("add dup swapin result, want %v, have %v", ErrItemIsDup, err)
+		}
+
+		ma := &MgoP2shAddress{Key: "0xbb", P2shAddress: "2N1ZwMo1tuXsQ5e8EQ6FNfb6dU6kx2gSfaH"}
+		if err := s.AddP2shAddress(ma); err != nil {
+			t.Fatalf("add p2sh address failed: %v", err)
+		}
+		if err := s.AddP2shAddress(ma); err != ErrItemIsDup {
+			t.Fatalf("add dup p2sh address, want %v, have %v", ErrItemIsDup, err)
+		}
+
+		// dup adding does not overwrite the existing one
+		swap.Status = TxNotSwapped
+		_ = s.AddSwap(true, swap)
+		found, err := s.FindSwap(true, "0x01")
+		if err != nil || found.Status != TxNotStable {
+			t.Fatalf("existing swapin is changed by dup adding, have %+v, err %v", found, err)
+		}
+		count, _ := s.GetCountOfSwapResults(true)
+		if count != 1 {
+			t.Fatalf("count of swapin results, want 1, have %v", count)
+		}
+	})
+}
+
+func TestStoreSeptimeFiltering(t *testing.T) {
+	testStores(t, func(t *testing.T, s Store) {
+		for i, timestamp := range []int64{100, 200, 300, 400} {
+			txid := fmt.Sprintf("0x%02d", i)
+			status := TxNotSwapped
+			if i == 3 {
+				status = TxProcessed
+			}
+			if err := s.AddSwap(false, &MgoSwap{Key: txid, TxID: txid, Status: status, Timestamp: timestamp}); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.AddSwapResult(false, &MgoSwapResult{Key: txid, TxID: txid, Status: MatchTxNotStable, Timestamp: timestamp}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		swaps, err := s.FindSwapsWithStatus(false, TxNotSwapped, 200)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(swaps) != 2 || swaps[0].TxID != "0x01" || swaps[1].TxID != "0x02" {
+			t.Fatalf("find swapouts with status after septime, have %v", swapTxIDs(swaps))
+		}
+
+		results, err := s.FindSwapResultsWithStatus(false, MatchTxNotStable, 301)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 || results[0].TxID != "0x03" {
+			t.Fatalf("find swapout results with status after septime, have %v", resultTxIDs(results))
+		}
+
+		// updating status refreshes timestamp
+		if err = s.UpdateSwapStatus(false, "0x00", TxNotSwapped, 500, ""); err != nil {
+			t.Fatal(err)
+		}
+		swaps, _ = s.FindSwapsWithStatus(false, TxNotSwapped, 450)
+		if len(swaps) != 1 || swaps[0].TxID != "0x00" {
+			t.Fatalf("find swapouts with refreshed timestamp, have %v", swapTxIDs(swaps))
+		}
+	})
+}
+
+func TestStorePagination(t *testing.T) {
+	testStores(t, func(t *testing.T, s Store) {
+		for i := 0; i < 7; i++ {
+			txid := fmt.Sprintf("0x%02d", i)
+			from := "0xaa"
+			if i%2 == 1 {
+				from = "0xbb"
+			}
+			if err := s.AddSwapResult(true, &MgoSwapResult{Key: txid, TxID: txid, From: from, Timestamp: int64(i)}); err != nil {
+				t.Fatal(err)
+			}
+			ma := &MgoP2shAddress{Key: txid, P2shAddress: "p2sh" + txid}
+			if err := s.AddP2shAddress(ma); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		tests := []struct {
+			address       string
+			offset, limit int
+			want          []string
+		}{
+			{"all", 0, 3, []string{"0x00", "0x01", "0x02"}},
+			{"all", 3, 3, []string{"0x03", "0x04", "0x05"}},
+			{"all", 6, 3, []string{"0x06"}},
+			{"all", 7, 3, []string{}},
+			{"all", 5, 0, []string{"0x05", "0x06"}}, // no limit
+			{"0xaa", 0, 2, []string{"0x00", "0x02"}},
+			{"0xaa", 2, 2, []string{"0x04", "0x06"}},
+			{"0xbb", 1, 5, []string{"0x03", "0x05"}},
+		}
+		for _, test := range tests {
+			results, err := s.FindSwapResults(true, test.address, test.offset, test.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have := resultTxIDs(results); fmt.Sprint(have) != fmt.Sprint(test.want) {
+				t.Errorf("find swapin results of %v offset %v limit %v, want %v, have %v", test.address, test.offset, test.limit, test.want, have)
+			}
+		}
+
+		addresses, err := s.FindP2shAddresses(4, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(addresses) != 2 || addresses[0].Key != "0x04" || addresses[1].Key != "0x05" {
+			t.Fatalf("find p2sh addresses offset 4 limit 2, have %v", len(addresses))
+		}
+	})
+}
+
+func swapTxIDs(swaps []*MgoSwap) []string {
+	txids := make([]string, 0, len(swaps))
+	for _, swap := range swaps {
+		txids = append(txids, swap.TxID)
+	}
+	return txids
+}
+
+func resultTxIDs(results []*MgoSwapResult) []string {
+	txids := make([]string, 0, len(results))
+	for _, res := range results {
+		txids = append(txids, res.TxID)
+	}
+	return txids
+}
@@ -8,10 +8,20 @@ const (
 	tbP2shAddresses  string = "P2shAddresses"
 	tbSwapStatistics string = "SwapStatistics"
 	tbLatestScanInfo string = "LatestScanInfo"
+	tbNonces         string = "Nonces"
+	tbUtxos          string = "Utxos"
+	tbBlockHashes    string = "BlockHashes"
+	tbReorgEvents    string = "ReorgEvents"
+	tbSignIntents    string = "SignIntents"
+	tbSignRequests   string = "SignRequests"
 
 	keyOfSwapStatistics    string = "latest"
 	keyOfSrcLatestScanInfo string = "srclatest"
 	keyOfDstLatestScanInfo string = "dstlatest"
+	keyOfSrcHistoryScan    string = "srchistory"
+	keyOfDstHistoryScan    string = "dsthistory"
+	keyOfSrcBlockHashes    string = "srcblockhashes"
+	keyOfDstBlockHashes    string = "dstblockhashes"
 )
 
 // MgoSwap registered swap
@@ -43,11 +53,18 @@ type MgoSwapResult struct {
 	Status     SwapStatus `bson:"status"`
 	Timestamp  int64      `bson:"timestamp"`
 	Memo       string     `bson:"memo"`
+	OldSwapTxs []string   `bson:"oldswaptxs,omitempty"` // replaced swap txs
+	SwapNonce  *uint64    `bson:"swapnonce,omitempty"`  // nonce of swap tx (and its replacements) of account based chain
 }
 
 // SwapResultUpdateItems swap update items
+// (swap height and time are reset when swap tx is set)
 type SwapResultUpdateItems struct {
+	TxHeight   uint64
+	TxTime     uint64
 	SwapTx     string
+	OldSwapTxs []string
+	SwapNonce  *uint64
 	SwapHeight uint64
 	SwapTime   uint64
 	SwapValue  string
@@ -59,8 +76,9 @@ type SwapResultUpdateItems struct {
 
 // MgoP2shAddress key is the bind address
 type MgoP2shAddress struct {
-	Key         string `bson:"_id"`
-	P2shAddress string `bson:"p2shaddress"`
+	Key          string `bson:"_id"`
+	P2shAddress  string `bson:"p2shaddress"`
+	P2wshAddress string `bson:"p2wshaddress,omitempty"`
 }
 
 // MgoSwapStatistics swap statistics
@@ -80,3 +98,74 @@ type MgoLatestScanInfo struct {
 	BlockHeight uint64 `bson:"blockheight"`
 	Timestamp   int64  `bson:"timestamp"`
 }
+
+// MgoNonceInfo reserved nonces of account (key is chain:address)
+type MgoNonceInfo struct {
+	Key       string   `bson:"_id"`
+	NextNonce uint64   `bson:"nextnonce"`
+	Released  []uint64 `bson:"released"`  // released nonces (reuse first)
+	Timestamp int64    `bson:"timestamp"` // latest reserve time
+}
+
+// MgoUtxo tracked utxo of bitcoin address (key is txid:vout)
+type MgoUtxo struct {
+	Key       string `bson:"_id"`
+	TxID      string `bson:"txid"`
+	Vout      uint32 `bson:"vout"`
+	Address   string `bson:"address"`
+	Value     uint64 `bson:"value"`
+	Height    uint64 `bson:"height"`   // 0 means unconfirmed
+	LockedBy  string `bson:"lockedby"` // spending tx in txpool or in-flight swap (empty means not locked)
+	LockTime  int64  `bson:"locktime"`
+	Timestamp int64  `bson:"timestamp"`
+}
+
+// MgoBlockHashes hashes of recent blocks to detect chain reorg
+type MgoBlockHashes struct {
+	Key       string         `bson:"_id"`
+	Hashes    []MgoBlockHash `bson:"hashes"` // ascending order of height
+	Timestamp int64          `bson:"timestamp"`
+}
+
+// MgoBlockHash block hash of height
+type MgoBlockHash struct {
+	Height uint64 `bson:"height"`
+	Hash   string `bson:"hash"`
+}
+
+// MgoReorgEvent audit record of chain reorg (key is src|dst:forkheight:oldhash)
+type MgoReorgEvent struct {
+	Key        string   `bson:"_id"`
+	IsSrc      bool     `bson:"issrc"`
+	ForkHeight uint64   `bson:"forkheight"` // lowest height whose block is replaced
+	Depth      uint64   `bson:"depth"`      // count of replaced blocks
+	OldHash    string   `bson:"oldhash"`
+	NewHash    string   `bson:"newhash"`
+	Swapins    []string `bson:"swapins"`    // swapins whose deposit or swap tx is rolled back
+	Swapouts   []string `bson:"swapouts"`   // swapouts whose deposit or swap tx is rolled back
+	MissingTxs []string `bson:"missingtxs"` // swap txs not found after reorg
+	Timestamp  int64    `bson:"timestamp"`
+}
+
+// MgoSignIntent intent to sign swap tx, it's stored before dcrm signing and removed after
+// the signed tx is recorded, so the swap is reconciled with chain before signing again (key is swaptype:txid)
+type MgoSignIntent struct {
+	Key         string `bson:"_id"`
+	TxID        string `bson:"txid"`
+	SwapType    uint32 `bson:"swaptype"`
+	BlockHeight uint64 `bson:"blockheight"` // latest height of payout chain when first signing, search swap tx from it
+	Extra       string `bson:"extra"`       // json of build tx extra (nonce or utxos), reused when signing again
+	Timestamp   int64  `bson:"timestamp"`
+}
+
+// MgoSignRequest pending dcrm sign request (key is keyID), it's removed after the sign result is handled,
+// so the sign status of pending requests is polled again after restart.
+type MgoSignRequest struct {
+	Key       string   `bson:"_id"`
+	PairID    string   `bson:"pairid"`
+	SwapID    string   `bson:"swapid"`
+	SwapType  uint32   `bson:"swaptype"`
+	GroupID   string   `bson:"groupid"` // dcrm sign group
+	MsgHash   []string `bson:"msghash"`
+	Timestamp int64    `bson:"timestamp"`
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,6 +19,17 @@ import (
 const (
 	defaultAPIPort      = 11556
 	defServerConfigFile = "config.toml"
+
+	// StorageBackendMongoDB store in mongodb (default)
+	StorageBackendMongoDB = "mongodb"
+	// StorageBackendMemory store in memory (data is lost after restart)
+	StorageBackendMemory = "memory"
+	// StorageBackendEmbedded store in single file under datadir
+	StorageBackendEmbedded = "embedded"
+
+	defEmbeddedStorageFile = "swapdb.bolt"
+
+	defaultSignConcurrency = 3
 )
 
 var (
@@ -34,6 +46,7 @@ var (
 // ServerConfig config items (decode from toml file)
 type ServerConfig struct {
 	Identifier  string
+	Storage     *StorageConfig   `toml:",omitempty"`
 	MongoDB     *MongoDBConfig   `toml:",omitempty"`
 	APIServer   *APIServerConfig `toml:",omitempty"`
 	SrcToken    *tokens.TokenConfig
@@ -43,6 +56,18 @@ type ServerConfig struct {
 	Dcrm        *DcrmConfig
 	Oracle      *OracleConfig          `toml:",omitempty"`
 	BtcExtra    *tokens.BtcExtraConfig `toml:",omitempty"`
+	EthExtra    *tokens.EthExtraConfig `toml:",omitempty"`
+	Pairs       []*PairConfig          `toml:",omitempty"`
+}
+
+// PairConfig extra token pair served in the same process.
+// Identifier distinguishes the pair in dcrm accept, and it's the pair id in api calls.
+type PairConfig struct {
+	Identifier  string
+	SrcToken    *tokens.TokenConfig
+	SrcGateway  *tokens.GatewayConfig
+	DestToken   *tokens.TokenConfig
+	DestGateway *tokens.GatewayConfig
 }
 
 // DcrmConfig dcrm related config
@@ -57,11 +82,43 @@ type DcrmConfig struct {
 	Pubkey        *string `toml:",omitempty"`
 	KeystoreFile  *string `toml:",omitempty"`
 	PasswordFile  *string `toml:",omitempty"`
+
+	// max count of pending sign requests of each sign group (server only, default 3),
+	// SignGroupConcurrency overrides it for the specified sign groups.
+	SignConcurrency      uint32            `toml:",omitempty"`
+	SignGroupConcurrency map[string]uint32 `toml:",omitempty"`
 }
 
 // OracleConfig oracle config
 type OracleConfig struct {
 	ServerAPIAddress string
+	Policy           *OraclePolicyConfig `toml:",omitempty"`
+}
+
+// OraclePolicyConfig accept sign policy of oracle, swaps violating it are disagreed.
+// addresses are compared case insensitively, zero limits are disabled.
+type OraclePolicyConfig struct {
+	AllowList []string `toml:",omitempty"` // only agree swaps to these addresses (if not empty)
+	DenyList  []string `toml:",omitempty"` // disagree swaps from or to these addresses
+
+	// velocity limit of swaps to the same bind address
+	MaxSwapsPerBindHourly uint64 `toml:",omitempty"`
+	MaxSwapsPerBindDaily  uint64 `toml:",omitempty"`
+
+	Pairs map[string]*PairPolicyConfig `toml:",omitempty"` // key is pair identifier
+}
+
+// PairPolicyConfig outflow limits of both sides of a pair
+type PairPolicyConfig struct {
+	Src  *OutflowPolicyConfig `toml:",omitempty"` // outflow from source chain (swapout and recall)
+	Dest *OutflowPolicyConfig `toml:",omitempty"` // outflow to dest chain (swapin)
+}
+
+// OutflowPolicyConfig value caps of one bridge side, whole unit (eg. BTC, ETH)
+type OutflowPolicyConfig struct {
+	MaxSwapValue float64 // per swap
+	HourlyCap    float64 // total value in rolling hour
+	DailyCap     float64 // total value in rolling day
 }
 
 // APIServerConfig api service config
@@ -70,6 +127,39 @@ type APIServerConfig struct {
 	AllowedOrigins []string
 }
 
+// StorageConfig storage backend config
+type StorageConfig struct {
+	Backend string // mongodb, memory, embedded (default mongodb)
+	File    string `toml:",omitempty"` // embedded storage file (relative to datadir)
+}
+
+// GetBackend get storage backend
+func (c *StorageConfig) GetBackend() string {
+	if c == nil || c.Backend == "" {
+		return StorageBackendMongoDB
+	}
+	return strings.ToLower(c.Backend)
+}
+
+// CheckConfig check storage config
+func (c *StorageConfig) CheckConfig() error {
+	switch c.GetBackend() {
+	case StorageBackendMongoDB, StorageBackendMemory, StorageBackendEmbedded:
+		return nil
+	default:
+		return fmt.Errorf("unknown storage backend '%v'", c.Backend)
+	}
+}
+
+// GetEmbeddedFilePath get embedded storage file path
+func (c *StorageConfig) GetEmbeddedFilePath() string {
+	file := defEmbeddedStorageFile
+	if c != nil && c.File != "" {
+		file = c.File
+	}
+	return common.AbsolutePath(DataDir, file)
+}
+
 // MongoDBConfig mongodb config
 type MongoDBConfig struct {
 	DBURL    string
@@ -95,11 +185,24 @@ func GetAPIPort() int {
 	return apiPort
 }
 
-// GetIdentifier get identifier (to distiguish in dcrm accept)
+// GetIdentifier get identifier of the default pair (to distiguish in dcrm accept)
 func GetIdentifier() string {
 	return GetConfig().Identifier
 }
 
+// GetPairConfigs get all token pairs, the first one is the default pair
+func (c *ServerConfig) GetPairConfigs() []*PairConfig {
+	pairs := make([]*PairConfig, 0, len(c.Pairs)+1)
+	pairs = append(pairs, &PairConfig{
+		Identifier:  c.Identifier,
+		SrcToken:    c.SrcToken,
+		SrcGateway:  c.SrcGateway,
+		DestToken:   c.DestToken,
+		DestGateway: c.DestGateway,
+	})
+	return append(pairs, c.Pairs...)
+}
+
 // GetServerDcrmUser get server dcrm user (initiator of dcrm sign)
 func GetServerDcrmUser() string {
 	return GetConfig().Dcrm.ServerAccount
@@ -122,7 +225,11 @@ func CheckConfig(isServer bool) (err error) {
 		return errors.New("server must config non empty 'Identifier'")
 	}
 	if isServer {
-		if config.MongoDB == nil {
+		err = config.Storage.CheckConfig()
+		if err != nil {
+			return err
+		}
+		if config.Storage.GetBackend() == StorageBackendMongoDB && config.MongoDB == nil {
 			return errors.New("server must config 'MongoDB'")
 		}
 		if config.APIServer == nil {
@@ -137,18 +244,6 @@ func CheckConfig(isServer bool) (err error) {
 			return err
 		}
 	}
-	if config.SrcToken == nil {
-		return errors.New("server must config 'SrcToken'")
-	}
-	if config.SrcGateway == nil {
-		return errors.New("server must config 'SrcGateway'")
-	}
-	if config.DestToken == nil {
-		return errors.New("server must config 'DestToken'")
-	}
-	if config.DestGateway == nil {
-		return errors.New("server must config 'DestGateway'")
-	}
 	if config.Dcrm == nil {
 		return errors.New("server must config 'Dcrm'")
 	}
@@ -156,15 +251,67 @@ func CheckConfig(isServer bool) (err error) {
 	if err != nil {
 		return err
 	}
-	err = config.SrcToken.CheckConfig(true)
+	err = checkPairConfigs(config.GetPairConfigs())
 	if err != nil {
 		return err
 	}
-	err = config.DestToken.CheckConfig(false)
+	if !isServer && config.Oracle.Policy != nil {
+		return config.Oracle.Policy.CheckConfig(config.GetPairConfigs())
+	}
+	return nil
+}
+
+func checkPairConfigs(pairs []*PairConfig) error {
+	pairIDs := make(map[string]struct{})
+	deposits := make(map[string]string)
+	for _, pair := range pairs {
+		if pair.Identifier == "" {
+			return errors.New("pair must config non empty 'Identifier'")
+		}
+		if _, exist := pairIDs[pair.Identifier]; exist {
+			return fmt.Errorf("duplicate pair identifier '%v'", pair.Identifier)
+		}
+		pairIDs[pair.Identifier] = struct{}{}
+		if err := pair.CheckConfig(); err != nil {
+			return err
+		}
+		// a deposit can only belong to one pair
+		for _, token := range []*tokens.TokenConfig{pair.SrcToken, pair.DestToken} {
+			key := strings.ToLower(strings.Join([]string{token.BlockChain, token.NetID, token.ContractAddress, token.DcrmAddress}, ":"))
+			if other, exist := deposits[key]; exist {
+				return fmt.Errorf("pair '%v' and '%v' has the same deposit (%v)", other, pair.Identifier, key)
+			}
+			deposits[key] = pair.Identifier
+		}
+	}
+	return nil
+}
+
+// CheckConfig check pair config
+func (c *PairConfig) CheckConfig() (err error) {
+	if c.SrcToken == nil {
+		return fmt.Errorf("pair '%v' must config 'SrcToken'", c.Identifier)
+	}
+	if c.SrcGateway == nil {
+		return fmt.Errorf("pair '%v' must config 'SrcGateway'", c.Identifier)
+	}
+	if c.DestToken == nil {
+		return fmt.Errorf("pair '%v' must config 'DestToken'", c.Identifier)
+	}
+	if c.DestGateway == nil {
+		return fmt.Errorf("pair '%v' must config 'DestGateway'", c.Identifier)
+	}
+	if err = c.SrcGateway.CheckConfig(); err != nil {
+		return fmt.Errorf("pair '%v' source %v", c.Identifier, err)
+	}
+	if err = c.DestGateway.CheckConfig(); err != nil {
+		return fmt.Errorf("pair '%v' dest %v", c.Identifier, err)
+	}
+	err = c.SrcToken.CheckConfig(true)
 	if err != nil {
 		return err
 	}
-	return nil
+	return c.DestToken.CheckConfig(false)
 }
 
 // CheckConfig check dcrm config
@@ -194,6 +341,11 @@ func (c *DcrmConfig) CheckConfig(isServer bool) (err error) {
 		if len(c.SignGroups) == 0 {
 			return errors.New("swap server dcrm must config 'SignGroups'")
 		}
+		for group := range c.SignGroupConcurrency {
+			if !isInStringList(c.SignGroups, group) {
+				return fmt.Errorf("dcrm 'SignGroupConcurrency' of group '%v' which is not in 'SignGroups'", group)
+			}
+		}
 	}
 	if c.KeystoreFile == nil {
 		return errors.New("dcrm must config 'KeystoreFile'")
@@ -204,6 +356,28 @@ func (c *DcrmConfig) CheckConfig(isServer bool) (err error) {
 	return nil
 }
 
+// GetSignConcurrency get max count of pending sign requests of sign group
+func (c *DcrmConfig) GetSignConcurrency(signGroup string) int {
+	for group, concurrency := range c.SignGroupConcurrency {
+		if concurrency > 0 && strings.EqualFold(group, signGroup) {
+			return int(concurrency)
+		}
+	}
+	if c.SignConcurrency > 0 {
+		return int(c.SignConcurrency)
+	}
+	return defaultSignConcurrency
+}
+
+func isInStringList(list []string, item string) bool {
+	for _, s := range list {
+		if strings.EqualFold(s, item) {
+			return true
+		}
+	}
+	return false
+}
+
 // CheckConfig check oracle config
 func (c *OracleConfig) CheckConfig() (err error) {
 	ServerAPIAddress = c.ServerAPIAddress
@@ -223,6 +397,64 @@ func (c *OracleConfig) CheckConfig() (err error) {
 	return err
 }
 
+// CheckConfig check oracle policy config
+func (c *OraclePolicyConfig) CheckConfig(pairs []*PairConfig) error {
+	for pairID, pairPolicy := range c.Pairs {
+		found := false
+		for _, pair := range pairs {
+			if strings.EqualFold(pair.Identifier, pairID) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("oracle policy of pair '%v' which is not configed", pairID)
+		}
+		if pairPolicy == nil {
+			continue
+		}
+		if err := pairPolicy.Src.CheckConfig(); err != nil {
+			return fmt.Errorf("oracle policy of pair '%v' source %v", pairID, err)
+		}
+		if err := pairPolicy.Dest.CheckConfig(); err != nil {
+			return fmt.Errorf("oracle policy of pair '%v' dest %v", pairID, err)
+		}
+	}
+	return nil
+}
+
+// GetPairPolicy get outflow policy of pair side
+func (c *OraclePolicyConfig) GetPairPolicy(pairID string, isSrc bool) *OutflowPolicyConfig {
+	for id, pairPolicy := range c.Pairs {
+		if pairPolicy != nil && strings.EqualFold(id, pairID) {
+			if isSrc {
+				return pairPolicy.Src
+			}
+			return pairPolicy.Dest
+		}
+	}
+	return nil
+}
+
+// CheckConfig check outflow policy config
+func (c *OutflowPolicyConfig) CheckConfig() error {
+	if c == nil {
+		return nil
+	}
+	if c.MaxSwapValue < 0 || c.HourlyCap < 0 || c.DailyCap < 0 {
+		return errors.New("outflow policy has negative value")
+	}
+	return nil
+}
+
+// GetOraclePolicy get oracle policy config (nil if not configed)
+func GetOraclePolicy() *OraclePolicyConfig {
+	if oracle := GetConfig().Oracle; oracle != nil {
+		return oracle.Policy
+	}
+	return nil
+}
+
 // LoadConfig load config
 func LoadConfig(configFile string, isServer bool) *ServerConfig {
 	loadConfigStarter.Do(func() {
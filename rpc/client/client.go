@@ -3,10 +3,12 @@ package client
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -45,6 +47,13 @@ func createHTTPClient() *http.Client {
 	}
 }
 
+// IsNetworkError is error of sending request or receiving response (eg. connection refused, timeout),
+// the server is unreachable rather than it returns a failed result.
+func IsNetworkError(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
 // HTTPGet http get
 func HTTPGet(url string, params, headers map[string]string, timeout int) (*http.Response, error) {
 	req, err := http.NewRequest(http.MethodGet, url, nil)
@@ -141,6 +150,10 @@ func doRequest(req *http.Request, timeoutSeconds int) (*http.Response, error) {
 		}
 		return client.Do(req)
 	}
-	httpClient.Timeout = timeout
-	return httpClient.Do(req)
+	// share transport (connection pool), but not modify timeout of the shared client concurrently
+	client := http.Client{
+		Transport: httpClient.Transport,
+		Timeout:   timeout,
+	}
+	return client.Do(req)
 }
@@ -21,7 +21,7 @@ func RPCGetWithTimeout(result interface{}, url string, timeout int) error {
 func RPCGetRequest(result interface{}, url string, params, headers map[string]string, timeout int) error {
 	resp, err := HTTPGet(url, params, headers, timeout)
 	if err != nil {
-		return fmt.Errorf("GET request error: %v (url: %v, params: %v)", err, url, params)
+		return fmt.Errorf("GET request error: %w (url: %v, params: %v)", err, url, params)
 	}
 
 	if resp.StatusCode != 200 {
@@ -56,7 +56,7 @@ func RPCRawGetWithTimeout(url string, timeout int) (string, error) {
 func RPCRawGetRequest(url string, params, headers map[string]string, timeout int) (string, error) {
 	resp, err := HTTPGet(url, params, headers, timeout)
 	if err != nil {
-		return "", fmt.Errorf("GET request error: %v (url: %v, params: %v)", err, url, params)
+		return "", fmt.Errorf("GET request error: %w (url: %v, params: %v)", err, url, params)
 	}
 
 	defer resp.Body.Close()
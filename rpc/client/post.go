@@ -61,20 +61,21 @@ type RequestBody struct {
 	ID      int         `json:"id"`
 }
 
-type jsonError struct {
+// RPCError error object of json-rpc response
+type RPCError struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 }
 
-func (err *jsonError) Error() string {
+func (err *RPCError) Error() string {
 	return fmt.Sprintf("json-rpc error %d, %s", err.Code, err.Message)
 }
 
 type jsonrpcResponse struct {
 	Version string          `json:"jsonrpc,omitempty"`
 	ID      json.RawMessage `json:"id,omitempty"`
-	Error   *jsonError      `json:"error,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
 	Result  json.RawMessage `json:"result,omitempty"`
 }
 
@@ -113,7 +114,7 @@ func getResultFromJSONResponse(result interface{}, resp *http.Response) error {
 		return fmt.Errorf("unmarshal body error, body is \"%v\" err=\"%v\"", string(body), err)
 	}
 	if jsonResp.Error != nil {
-		return fmt.Errorf("return error:  %v", jsonResp.Error.Error())
+		return fmt.Errorf("return error:  %w", jsonResp.Error)
 	}
 	err = json.Unmarshal(jsonResp.Result, &result)
 	if err != nil {
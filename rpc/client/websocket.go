@@ -0,0 +1,277 @@
+package client
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// websocket opcodes (RFC 6455)
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xa
+)
+
+const (
+	wsAcceptGUID         = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	wsMaxMessageSize     = 1024 * 1024 * 10 // 10M
+	wsMaxControlPayload  = 125
+	wsHandshakeTimeout   = 30 * time.Second
+	wsWriteTimeout       = 10 * time.Second
+	wsDefaultReadTimeout = 90 * time.Second
+)
+
+var errWsClosed = errors.New("websocket connection closed by peer")
+
+// wsConn minimal websocket client connection (text and binary messages, no extensions)
+type wsConn struct {
+	conn        net.Conn
+	br          *bufio.Reader
+	writeLock   sync.Mutex
+	readTimeout time.Duration
+}
+
+// dialWebsocket dial ws:// or wss:// url and do opening handshake
+func dialWebsocket(rawurl string) (*wsConn, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	var defaultPort string
+	switch u.Scheme {
+	case "ws":
+		defaultPort = "80"
+	case "wss":
+		defaultPort = "443"
+	default:
+		return nil, fmt.Errorf("unsupported websocket scheme '%v'", u.Scheme)
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), defaultPort)
+	}
+
+	dialer := &net.Dialer{Timeout: wsHandshakeTimeout, KeepAlive: 30 * time.Second}
+	var conn net.Conn
+	if u.Scheme == "wss" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: u.Hostname()})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ws, err := handshakeWebsocket(conn, u)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return ws, nil
+}
+
+func handshakeWebsocket(conn net.Conn, u *url.URL) (*wsConn, error) {
+	nonce := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Path: u.Path, RawPath: u.RawPath, RawQuery: u.RawQuery},
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Host:       u.Host,
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if u.User != nil {
+		password, _ := u.User.Password()
+		req.SetBasicAuth(u.User.Username(), password)
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(wsHandshakeTimeout))
+	if err := req.Write(conn); err != nil {
+		return nil, err
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, fmt.Errorf("websocket handshake failed with status %v", resp.Status)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != computeAcceptKey(key) {
+		return nil, errors.New("websocket handshake failed with wrong accept key")
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return &wsConn{conn: conn, br: br, readTimeout: wsDefaultReadTimeout}, nil
+}
+
+func computeAcceptKey(key string) string {
+	h := sha1.New() // required by websocket handshake
+	_, _ = h.Write([]byte(key + wsAcceptGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// WriteMessage write text message
+func (c *wsConn) WriteMessage(data []byte) error {
+	return c.writeFrame(wsOpText, data)
+}
+
+// writeFrame write a final frame, client frames must be masked
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	length := len(payload)
+	frame := make([]byte, 0, length+14)
+	frame = append(frame, 0x80|opcode)
+	switch {
+	case length <= 125:
+		frame = append(frame, 0x80|byte(length))
+	case length <= 0xffff:
+		frame = append(frame, 0x80|126, 0, 0)
+		binary.BigEndian.PutUint16(frame[2:], uint16(length))
+	default:
+		frame = append(frame, 0x80|127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(frame[2:], uint64(length))
+	}
+	var mask [4]byte
+	if _, err := io.ReadFull(rand.Reader, mask[:]); err != nil {
+		return err
+	}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+	_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// ReadMessage read a whole (maybe fragmented) data message,
+// control frames are processed in place (reply pong to ping, return error on close).
+// it returns error if nothing (including pong) is received in read timeout.
+func (c *wsConn) ReadMessage() ([]byte, error) {
+	var message []byte
+	for {
+		_ = c.conn.SetReadDeadline(time.Now().Add(c.readTimeout))
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case wsOpPing:
+			if err = c.writeFrame(wsOpPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			_ = c.writeFrame(wsOpClose, payload)
+			return nil, errWsClosed
+		case wsOpText, wsOpBinary:
+			if message != nil {
+				return nil, errors.New("websocket new message in the middle of fragmented message")
+			}
+			message = payload
+		case wsOpContinuation:
+			if message == nil {
+				return nil, errors.New("websocket continuation frame without message")
+			}
+			message = append(message, payload...)
+		default:
+			return nil, fmt.Errorf("websocket unknown opcode %v", opcode)
+		}
+		if len(message) > wsMaxMessageSize {
+			return nil, errors.New("websocket message is too large")
+		}
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+func (c *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(c.br, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0f
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, errors.New("websocket reserved bits are set")
+	}
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if opcode >= wsOpClose && (length > wsMaxControlPayload || !fin) {
+		return false, 0, nil, errors.New("websocket invalid control frame")
+	}
+	if length > wsMaxMessageSize {
+		return false, 0, nil, errors.New("websocket frame is too large")
+	}
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(c.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
+
+// Ping send ping (the peer's pong keeps read alive)
+func (c *wsConn) Ping() error {
+	return c.writeFrame(wsOpPing, nil)
+}
+
+// Close send close frame and close the underlying connection
+func (c *wsConn) Close() error {
+	_ = c.writeFrame(wsOpClose, []byte{0x03, 0xe8}) // 1000 normal closure
+	return c.conn.Close()
+}
@@ -0,0 +1,239 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fsn-dev/crossChain-Bridge/log"
+)
+
+const (
+	wsPingInterval = 30 * time.Second
+	wsCallTimeout  = 60 * time.Second
+)
+
+// ErrWsClientClosed websocket client is closed
+var ErrWsClientClosed = errors.New("websocket client is closed")
+
+type wsRequest struct {
+	Version string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+	ID      uint64      `json:"id"`
+}
+
+// wsMessage response or subscription notification
+type wsMessage struct {
+	jsonrpcResponse
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+type wsNotificationParams struct {
+	Subscription string          `json:"subscription"`
+	Result       json.RawMessage `json:"result"`
+}
+
+type wsPendingCall struct {
+	resp  chan *jsonrpcResponse
+	subCh chan<- json.RawMessage // register subscription once subscribe call succeed
+}
+
+// WsClient json-rpc client over websocket, supports subscriptions (eg. eth_subscribe)
+type WsClient struct {
+	conn   *wsConn
+	nextID uint64
+
+	mu      sync.Mutex
+	pending map[uint64]*wsPendingCall
+	subs    map[string]chan<- json.RawMessage
+	err     error
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// DialWebsocket connect to json-rpc server through websocket (ws:// or wss://)
+func DialWebsocket(url string) (*WsClient, error) {
+	conn, err := dialWebsocket(url)
+	if err != nil {
+		return nil, err
+	}
+	c := &WsClient{
+		conn:    conn,
+		pending: make(map[uint64]*wsPendingCall),
+		subs:    make(map[string]chan<- json.RawMessage),
+		done:    make(chan struct{}),
+	}
+	go c.readLoop()
+	go c.pingLoop()
+	return c, nil
+}
+
+// Done is closed when the client is closed (eg. connection broken)
+func (c *WsClient) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err the error which closes the client
+func (c *WsClient) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Close close client
+func (c *WsClient) Close() {
+	c.closeWithError(ErrWsClientClosed)
+}
+
+func (c *WsClient) closeWithError(err error) {
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		c.err = err
+		c.pending = make(map[uint64]*wsPendingCall)
+		c.subs = make(map[string]chan<- json.RawMessage)
+		c.mu.Unlock()
+		close(c.done)
+		_ = c.conn.Close()
+	})
+}
+
+// Call call json-rpc method
+func (c *WsClient) Call(result interface{}, method string, params ...interface{}) error {
+	return c.call(result, nil, method, params...)
+}
+
+// Subscribe call subscribe method (eg. eth_subscribe), the subscription ID is returned.
+// notification results are sent to ch, and are dropped if ch is full (never block reading).
+// ch is not closed, watch Done() to know when the subscription is gone.
+func (c *WsClient) Subscribe(ch chan<- json.RawMessage, method string, params ...interface{}) (subID string, err error) {
+	err = c.call(&subID, ch, method, params...)
+	return subID, err
+}
+
+func (c *WsClient) call(result interface{}, subCh chan<- json.RawMessage, method string, params ...interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	call := &wsPendingCall{resp: make(chan *jsonrpcResponse, 1), subCh: subCh}
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return c.err
+	}
+	c.nextID++
+	id := c.nextID
+	c.pending[id] = call
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	data, err := json.Marshal(&wsRequest{Version: "2.0", Method: method, Params: params, ID: id})
+	if err != nil {
+		return err
+	}
+	if err = c.conn.WriteMessage(data); err != nil {
+		c.closeWithError(err)
+		return err
+	}
+
+	select {
+	case resp := <-call.resp:
+		if resp.Error != nil {
+			return fmt.Errorf("return error:  %w", resp.Error)
+		}
+		if err = json.Unmarshal(resp.Result, &result); err != nil {
+			return fmt.Errorf("unmarshal result error: %v", err)
+		}
+		return nil
+	case <-c.done:
+		return c.Err()
+	case <-time.After(wsCallTimeout):
+		return fmt.Errorf("call %v timeout", method)
+	}
+}
+
+func (c *WsClient) readLoop() {
+	for {
+		data, err := c.conn.ReadMessage()
+		if err != nil {
+			c.closeWithError(err)
+			return
+		}
+		var msg wsMessage
+		if err = json.Unmarshal(data, &msg); err != nil {
+			log.Debug("websocket client receive invalid message", "err", err)
+			continue
+		}
+		if msg.Method != "" {
+			c.dispatchNotification(&msg)
+		} else {
+			c.dispatchResponse(&msg.jsonrpcResponse)
+		}
+	}
+}
+
+func (c *WsClient) dispatchResponse(resp *jsonrpcResponse) {
+	var id uint64
+	if err := json.Unmarshal(resp.ID, &id); err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	call, exist := c.pending[id]
+	if !exist {
+		return
+	}
+	// register subscription before returning its ID,
+	// so that notifications following the response are not missed
+	if call.subCh != nil && resp.Error == nil {
+		var subID string
+		if err := json.Unmarshal(resp.Result, &subID); err == nil {
+			c.subs[subID] = call.subCh
+		}
+	}
+	call.resp <- resp
+}
+
+func (c *WsClient) dispatchNotification(msg *wsMessage) {
+	var params wsNotificationParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return
+	}
+	c.mu.Lock()
+	ch, exist := c.subs[params.Subscription]
+	c.mu.Unlock()
+	if !exist {
+		return
+	}
+	select {
+	case ch <- params.Result:
+	default:
+		log.Debug("websocket client drop notification as channel is full", "method", msg.Method, "subscription", params.Subscription)
+	}
+}
+
+// pingLoop keep connection alive, and detect broken connection (no pong in read timeout)
+func (c *WsClient) pingLoop() {
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if err := c.conn.Ping(); err != nil {
+				c.closeWithError(err)
+				return
+			}
+		}
+	}
+}
@@ -10,6 +10,11 @@ import (
 	"github.com/gorilla/mux"
 )
 
+// getPairID get pair id from url query 'pairid', empty means the default pair
+func getPairID(r *http.Request) string {
+	return r.URL.Query().Get("pairid")
+}
+
 func writeResponse(w http.ResponseWriter, resp interface{}, err error) {
 	if err == nil {
 		jsonData, _ := json.Marshal(resp)
@@ -30,7 +35,7 @@ func SeverInfoHandler(w http.ResponseWriter, r *http.Request) {
 // StatisticsHandler handler
 func StatisticsHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
-	res, err := swapapi.GetSwapStatistics()
+	res, err := swapapi.GetSwapStatistics(getPairID(r))
 	writeResponse(w, res, err)
 }
 
@@ -39,7 +44,7 @@ func GetRawSwapinHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	w.WriteHeader(http.StatusOK)
 	txid := vars["txid"]
-	res, err := swapapi.GetRawSwapin(&txid)
+	res, err := swapapi.GetRawSwapin(getPairID(r), &txid)
 	writeResponse(w, res, err)
 }
 
@@ -48,7 +53,7 @@ func GetRawSwapinResultHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	w.WriteHeader(http.StatusOK)
 	txid := vars["txid"]
-	res, err := swapapi.GetRawSwapinResult(&txid)
+	res, err := swapapi.GetRawSwapinResult(getPairID(r), &txid)
 	writeResponse(w, res, err)
 }
 
@@ -57,7 +62,7 @@ func GetSwapinHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	w.WriteHeader(http.StatusOK)
 	txid := vars["txid"]
-	res, err := swapapi.GetSwapin(&txid)
+	res, err := swapapi.GetSwapin(getPairID(r), &txid)
 	writeResponse(w, res, err)
 }
 
@@ -66,7 +71,7 @@ func GetRawSwapoutHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	w.WriteHeader(http.StatusOK)
 	txid := vars["txid"]
-	res, err := swapapi.GetRawSwapout(&txid)
+	res, err := swapapi.GetRawSwapout(getPairID(r), &txid)
 	writeResponse(w, res, err)
 }
 
@@ -75,7 +80,7 @@ func GetRawSwapoutResultHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	w.WriteHeader(http.StatusOK)
 	txid := vars["txid"]
-	res, err := swapapi.GetRawSwapoutResult(&txid)
+	res, err := swapapi.GetRawSwapoutResult(getPairID(r), &txid)
 	writeResponse(w, res, err)
 }
 
@@ -84,7 +89,7 @@ func GetSwapoutHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	w.WriteHeader(http.StatusOK)
 	txid := vars["txid"]
-	res, err := swapapi.GetSwapout(&txid)
+	res, err := swapapi.GetSwapout(getPairID(r), &txid)
 	writeResponse(w, res, err)
 }
 
@@ -120,7 +125,7 @@ func SwapinHistoryHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		writeResponse(w, nil, err)
 	} else {
-		res, err := swapapi.GetSwapinHistory(address, offset, limit)
+		res, err := swapapi.GetSwapinHistory(getPairID(r), address, offset, limit)
 		writeResponse(w, res, err)
 	}
 }
@@ -132,7 +137,19 @@ func SwapoutHistoryHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		writeResponse(w, nil, err)
 	} else {
-		res, err := swapapi.GetSwapoutHistory(address, offset, limit)
+		res, err := swapapi.GetSwapoutHistory(getPairID(r), address, offset, limit)
+		writeResponse(w, res, err)
+	}
+}
+
+// ReorgEventsHandler handler
+func ReorgEventsHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, offset, limit, err := getHistoryParams(r)
+	if err != nil {
+		writeResponse(w, nil, err)
+	} else {
+		res, err := swapapi.GetReorgEvents(getPairID(r), offset, limit)
 		writeResponse(w, res, err)
 	}
 }
@@ -142,7 +159,7 @@ func PostSwapinHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	w.WriteHeader(http.StatusOK)
 	txid := vars["txid"]
-	res, err := swapapi.Swapin(&txid)
+	res, err := swapapi.Swapin(getPairID(r), &txid)
 	writeResponse(w, res, err)
 }
 
@@ -152,7 +169,7 @@ func PostP2shSwapinHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	txid := vars["txid"]
 	bind := vars["bind"]
-	res, err := swapapi.P2shSwapin(&txid, &bind)
+	res, err := swapapi.P2shSwapin(getPairID(r), &txid, &bind)
 	writeResponse(w, res, err)
 }
 
@@ -161,7 +178,7 @@ func PostSwapoutHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	w.WriteHeader(http.StatusOK)
 	txid := vars["txid"]
-	res, err := swapapi.Swapout(&txid)
+	res, err := swapapi.Swapout(getPairID(r), &txid)
 	writeResponse(w, res, err)
 }
 
@@ -170,7 +187,7 @@ func RecallSwapinHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	w.WriteHeader(http.StatusOK)
 	txid := vars["txid"]
-	res, err := swapapi.RecallSwapin(&txid)
+	res, err := swapapi.RecallSwapin(getPairID(r), &txid)
 	writeResponse(w, res, err)
 }
 
@@ -179,7 +196,7 @@ func RegisterP2shAddress(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	w.WriteHeader(http.StatusOK)
 	address := vars["address"]
-	res, err := swapapi.RegisterP2shAddress(address)
+	res, err := swapapi.RegisterP2shAddress(getPairID(r), address)
 	writeResponse(w, res, err)
 }
 
@@ -188,6 +205,6 @@ func GetP2shAddressInfo(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	w.WriteHeader(http.StatusOK)
 	address := vars["address"]
-	res, err := swapapi.GetP2shAddressInfo(address)
+	res, err := swapapi.GetP2shAddressInfo(getPairID(r), address)
 	writeResponse(w, res, err)
 }
@@ -0,0 +1,19 @@
+package rpcapi
+
+import (
+	"net/http"
+
+	"github.com/fsn-dev/crossChain-Bridge/internal/swapapi"
+)
+
+// AdminAPI admin rpc api handler (registered as 'admin' service)
+type AdminAPI struct{}
+
+// GetDcrmUtxos api
+func (s *AdminAPI) GetDcrmUtxos(r *http.Request, args *RPCPairArgs, result *swapapi.DcrmUtxos) error {
+	res, err := swapapi.GetDcrmUtxos(args.PairID)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
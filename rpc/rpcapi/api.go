@@ -1,6 +1,7 @@
 package rpcapi
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/fsn-dev/crossChain-Bridge/internal/swapapi"
@@ -14,6 +15,56 @@ type RPCAPI struct{}
 // RPCNullArgs null args
 type RPCNullArgs struct{}
 
+// RPCPairArgs pair args, empty pair id means the default pair
+type RPCPairArgs struct {
+	PairID string `json:"pairid"`
+}
+
+// RPCTxArgs tx args
+type RPCTxArgs struct {
+	PairID string `json:"pairid"`
+	TxID   string `json:"txid"`
+}
+
+// UnmarshalJSON also accept a single txid string of the default pair
+func (args *RPCTxArgs) UnmarshalJSON(input []byte) error {
+	type txArgs RPCTxArgs
+	if err := json.Unmarshal(input, &args.TxID); err == nil {
+		return nil
+	}
+	return json.Unmarshal(input, (*txArgs)(args))
+}
+
+// RPCAddressArgs address args
+type RPCAddressArgs struct {
+	PairID  string `json:"pairid"`
+	Address string `json:"address"`
+}
+
+// UnmarshalJSON also accept a single address string of the default pair
+func (args *RPCAddressArgs) UnmarshalJSON(input []byte) error {
+	type addressArgs RPCAddressArgs
+	if err := json.Unmarshal(input, &args.Address); err == nil {
+		return nil
+	}
+	return json.Unmarshal(input, (*addressArgs)(args))
+}
+
+// RPCLatestScanInfoArgs latest scan info args
+type RPCLatestScanInfoArgs struct {
+	PairID string `json:"pairid"`
+	IsSrc  bool   `json:"isSrc"`
+}
+
+// UnmarshalJSON also accept a single isSrc bool of the default pair
+func (args *RPCLatestScanInfoArgs) UnmarshalJSON(input []byte) error {
+	type latestScanInfoArgs RPCLatestScanInfoArgs
+	if err := json.Unmarshal(input, &args.IsSrc); err == nil {
+		return nil
+	}
+	return json.Unmarshal(input, (*latestScanInfoArgs)(args))
+}
+
 // GetVersionInfo api
 func (s *RPCAPI) GetVersionInfo(r *http.Request, args *RPCNullArgs, result *string) error {
 	version := params.VersionWithMeta
@@ -33,8 +84,8 @@ func (s *RPCAPI) GetServerInfo(r *http.Request, args *RPCNullArgs, result *swapa
 }
 
 // GetSwapStatistics api
-func (s *RPCAPI) GetSwapStatistics(r *http.Request, args *RPCNullArgs, result *swapapi.SwapStatistics) error {
-	res, err := swapapi.GetSwapStatistics()
+func (s *RPCAPI) GetSwapStatistics(r *http.Request, args *RPCPairArgs, result *swapapi.SwapStatistics) error {
+	res, err := swapapi.GetSwapStatistics(args.PairID)
 	if err == nil && res != nil {
 		*result = *res
 	}
@@ -42,8 +93,8 @@ func (s *RPCAPI) GetSwapStatistics(r *http.Request, args *RPCNullArgs, result *s
 }
 
 // GetRawSwapin api
-func (s *RPCAPI) GetRawSwapin(r *http.Request, txid *string, result *swapapi.Swap) error {
-	res, err := swapapi.GetRawSwapin(txid)
+func (s *RPCAPI) GetRawSwapin(r *http.Request, args *RPCTxArgs, result *swapapi.Swap) error {
+	res, err := swapapi.GetRawSwapin(args.PairID, &args.TxID)
 	if err == nil && res != nil {
 		*result = *res
 	}
@@ -51,8 +102,8 @@ func (s *RPCAPI) GetRawSwapin(r *http.Request, txid *string, result *swapapi.Swa
 }
 
 // GetRawSwapinResult api
-func (s *RPCAPI) GetRawSwapinResult(r *http.Request, txid *string, result *swapapi.SwapResult) error {
-	res, err := swapapi.GetRawSwapinResult(txid)
+func (s *RPCAPI) GetRawSwapinResult(r *http.Request, args *RPCTxArgs, result *swapapi.SwapResult) error {
+	res, err := swapapi.GetRawSwapinResult(args.PairID, &args.TxID)
 	if err == nil && res != nil {
 		*result = *res
 	}
@@ -60,8 +111,8 @@ func (s *RPCAPI) GetRawSwapinResult(r *http.Request, txid *string, result *swapa
 }
 
 // GetSwapin api
-func (s *RPCAPI) GetSwapin(r *http.Request, txid *string, result *swapapi.SwapInfo) error {
-	res, err := swapapi.GetSwapin(txid)
+func (s *RPCAPI) GetSwapin(r *http.Request, args *RPCTxArgs, result *swapapi.SwapInfo) error {
+	res, err := swapapi.GetSwapin(args.PairID, &args.TxID)
 	if err == nil && res != nil {
 		*result = *res
 	}
@@ -69,8 +120,8 @@ func (s *RPCAPI) GetSwapin(r *http.Request, txid *string, result *swapapi.SwapIn
 }
 
 // GetRawSwapout api
-func (s *RPCAPI) GetRawSwapout(r *http.Request, txid *string, result *swapapi.Swap) error {
-	res, err := swapapi.GetRawSwapout(txid)
+func (s *RPCAPI) GetRawSwapout(r *http.Request, args *RPCTxArgs, result *swapapi.Swap) error {
+	res, err := swapapi.GetRawSwapout(args.PairID, &args.TxID)
 	if err == nil && res != nil {
 		*result = *res
 	}
@@ -78,8 +129,8 @@ func (s *RPCAPI) GetRawSwapout(r *http.Request, txid *string, result *swapapi.Sw
 }
 
 // GetRawSwapoutResult api
-func (s *RPCAPI) GetRawSwapoutResult(r *http.Request, txid *string, result *swapapi.SwapResult) error {
-	res, err := swapapi.GetRawSwapoutResult(txid)
+func (s *RPCAPI) GetRawSwapoutResult(r *http.Request, args *RPCTxArgs, result *swapapi.SwapResult) error {
+	res, err := swapapi.GetRawSwapoutResult(args.PairID, &args.TxID)
 	if err == nil && res != nil {
 		*result = *res
 	}
@@ -87,8 +138,8 @@ func (s *RPCAPI) GetRawSwapoutResult(r *http.Request, txid *string, result *swap
 }
 
 // GetSwapout api
-func (s *RPCAPI) GetSwapout(r *http.Request, txid *string, result *swapapi.SwapInfo) error {
-	res, err := swapapi.GetSwapout(txid)
+func (s *RPCAPI) GetSwapout(r *http.Request, args *RPCTxArgs, result *swapapi.SwapInfo) error {
+	res, err := swapapi.GetSwapout(args.PairID, &args.TxID)
 	if err == nil && res != nil {
 		*result = *res
 	}
@@ -97,6 +148,7 @@ func (s *RPCAPI) GetSwapout(r *http.Request, txid *string, result *swapapi.SwapI
 
 // RPCQueryHistoryArgs args
 type RPCQueryHistoryArgs struct {
+	PairID  string `json:"pairid"`
 	Address string `json:"address"`
 	Offset  int    `json:"offset"`
 	Limit   int    `json:"limit"`
@@ -104,7 +156,7 @@ type RPCQueryHistoryArgs struct {
 
 // GetSwapinHistory api
 func (s *RPCAPI) GetSwapinHistory(r *http.Request, args *RPCQueryHistoryArgs, result *[]*swapapi.SwapInfo) error {
-	res, err := swapapi.GetSwapinHistory(args.Address, args.Offset, args.Limit)
+	res, err := swapapi.GetSwapinHistory(args.PairID, args.Address, args.Offset, args.Limit)
 	if err == nil && res != nil {
 		*result = res
 	}
@@ -113,7 +165,7 @@ func (s *RPCAPI) GetSwapinHistory(r *http.Request, args *RPCQueryHistoryArgs, re
 
 // GetSwapoutHistory api
 func (s *RPCAPI) GetSwapoutHistory(r *http.Request, args *RPCQueryHistoryArgs, result *[]*swapapi.SwapInfo) error {
-	res, err := swapapi.GetSwapoutHistory(args.Address, args.Offset, args.Limit)
+	res, err := swapapi.GetSwapoutHistory(args.PairID, args.Address, args.Offset, args.Limit)
 	if err == nil && res != nil {
 		*result = res
 	}
@@ -121,8 +173,8 @@ func (s *RPCAPI) GetSwapoutHistory(r *http.Request, args *RPCQueryHistoryArgs, r
 }
 
 // Swapin api
-func (s *RPCAPI) Swapin(r *http.Request, txid *string, result *swapapi.PostResult) error {
-	res, err := swapapi.Swapin(txid)
+func (s *RPCAPI) Swapin(r *http.Request, args *RPCTxArgs, result *swapapi.PostResult) error {
+	res, err := swapapi.Swapin(args.PairID, &args.TxID)
 	if err == nil && res != nil {
 		*result = *res
 	}
@@ -131,13 +183,14 @@ func (s *RPCAPI) Swapin(r *http.Request, txid *string, result *swapapi.PostResul
 
 // RPCP2shSwapinArgs args
 type RPCP2shSwapinArgs struct {
-	TxID string `json:"txid"`
-	Bind string `json:"bind"`
+	PairID string `json:"pairid"`
+	TxID   string `json:"txid"`
+	Bind   string `json:"bind"`
 }
 
 // P2shSwapin api
 func (s *RPCAPI) P2shSwapin(r *http.Request, args *RPCP2shSwapinArgs, result *swapapi.PostResult) error {
-	res, err := swapapi.P2shSwapin(&args.TxID, &args.Bind)
+	res, err := swapapi.P2shSwapin(args.PairID, &args.TxID, &args.Bind)
 	if err == nil && res != nil {
 		*result = *res
 	}
@@ -145,8 +198,8 @@ func (s *RPCAPI) P2shSwapin(r *http.Request, args *RPCP2shSwapinArgs, result *sw
 }
 
 // Swapout api
-func (s *RPCAPI) Swapout(r *http.Request, txid *string, result *swapapi.PostResult) error {
-	res, err := swapapi.Swapout(txid)
+func (s *RPCAPI) Swapout(r *http.Request, args *RPCTxArgs, result *swapapi.PostResult) error {
+	res, err := swapapi.Swapout(args.PairID, &args.TxID)
 	if err == nil && res != nil {
 		*result = *res
 	}
@@ -154,8 +207,8 @@ func (s *RPCAPI) Swapout(r *http.Request, txid *string, result *swapapi.PostResu
 }
 
 // RecallSwapin api
-func (s *RPCAPI) RecallSwapin(r *http.Request, txid *string, result *swapapi.PostResult) error {
-	res, err := swapapi.RecallSwapin(txid)
+func (s *RPCAPI) RecallSwapin(r *http.Request, args *RPCTxArgs, result *swapapi.PostResult) error {
+	res, err := swapapi.RecallSwapin(args.PairID, &args.TxID)
 	if err == nil && res != nil {
 		*result = *res
 	}
@@ -163,20 +216,20 @@ func (s *RPCAPI) RecallSwapin(r *http.Request, txid *string, result *swapapi.Pos
 }
 
 // IsValidSwapinBindAddress api
-func (s *RPCAPI) IsValidSwapinBindAddress(r *http.Request, address *string, result *bool) error {
-	*result = swapapi.IsValidSwapinBindAddress(address)
+func (s *RPCAPI) IsValidSwapinBindAddress(r *http.Request, args *RPCAddressArgs, result *bool) error {
+	*result = swapapi.IsValidSwapinBindAddress(args.PairID, &args.Address)
 	return nil
 }
 
 // IsValidSwapoutBindAddress api
-func (s *RPCAPI) IsValidSwapoutBindAddress(r *http.Request, address *string, result *bool) error {
-	*result = swapapi.IsValidSwapoutBindAddress(address)
+func (s *RPCAPI) IsValidSwapoutBindAddress(r *http.Request, args *RPCAddressArgs, result *bool) error {
+	*result = swapapi.IsValidSwapoutBindAddress(args.PairID, &args.Address)
 	return nil
 }
 
 // RegisterP2shAddress api
-func (s *RPCAPI) RegisterP2shAddress(r *http.Request, bindAddress *string, result *tokens.P2shAddressInfo) error {
-	res, err := swapapi.RegisterP2shAddress(*bindAddress)
+func (s *RPCAPI) RegisterP2shAddress(r *http.Request, args *RPCAddressArgs, result *tokens.P2shAddressInfo) error {
+	res, err := swapapi.RegisterP2shAddress(args.PairID, args.Address)
 	if err == nil && res != nil {
 		*result = *res
 	}
@@ -184,8 +237,8 @@ func (s *RPCAPI) RegisterP2shAddress(r *http.Request, bindAddress *string, resul
 }
 
 // GetP2shAddressInfo api
-func (s *RPCAPI) GetP2shAddressInfo(r *http.Request, p2shAddress *string, result *tokens.P2shAddressInfo) error {
-	res, err := swapapi.GetP2shAddressInfo(*p2shAddress)
+func (s *RPCAPI) GetP2shAddressInfo(r *http.Request, args *RPCAddressArgs, result *tokens.P2shAddressInfo) error {
+	res, err := swapapi.GetP2shAddressInfo(args.PairID, args.Address)
 	if err == nil && res != nil {
 		*result = *res
 	}
@@ -193,10 +246,26 @@ func (s *RPCAPI) GetP2shAddressInfo(r *http.Request, p2shAddress *string, result
 }
 
 // GetLatestScanInfo api
-func (s *RPCAPI) GetLatestScanInfo(r *http.Request, isSrc *bool, result *swapapi.LatestScanInfo) error {
-	res, err := swapapi.GetLatestScanInfo(*isSrc)
+func (s *RPCAPI) GetLatestScanInfo(r *http.Request, args *RPCLatestScanInfoArgs, result *swapapi.LatestScanInfo) error {
+	res, err := swapapi.GetLatestScanInfo(args.PairID, args.IsSrc)
 	if err == nil && res != nil {
 		*result = *res
 	}
 	return err
 }
+
+// RPCQueryReorgEventsArgs args
+type RPCQueryReorgEventsArgs struct {
+	PairID string `json:"pairid"`
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit"`
+}
+
+// GetReorgEvents api
+func (s *RPCAPI) GetReorgEvents(r *http.Request, args *RPCQueryReorgEventsArgs, result *[]*swapapi.ReorgEvent) error {
+	res, err := swapapi.GetReorgEvents(args.PairID, args.Offset, args.Limit)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
@@ -1,17 +1,19 @@
 package rpcapi
 
 import (
+	"fmt"
+	"math/big"
 	"net/http"
 
 	"github.com/fsn-dev/crossChain-Bridge/common"
 	"github.com/fsn-dev/crossChain-Bridge/common/hexutil"
 	"github.com/fsn-dev/crossChain-Bridge/tokens"
-	"github.com/fsn-dev/crossChain-Bridge/tokens/eth"
 	"github.com/fsn-dev/crossChain-Bridge/types"
 )
 
 // BuildSwapoutTxArgs build swapout tx args
 type BuildSwapoutTxArgs struct {
+	PairID   string          `json:"pairid"`
 	From     common.Address  `json:"from"`
 	Value    *hexutil.Big    `json:"value"`
 	Bind     string          `json:"bind"`
@@ -20,10 +22,26 @@ type BuildSwapoutTxArgs struct {
 	Nonce    *hexutil.Uint64 `json:"nonce"`
 }
 
+type swapoutTxBuilder interface {
+	BuildSwapoutTx(from, contract string, extraArgs *tokens.EthExtraArgs, swapoutVal *big.Int, bindAddr string) (*types.Transaction, error)
+}
+
 // BuildSwapoutTx build swapout tx
 func (s *RPCAPI) BuildSwapoutTx(r *http.Request, args *BuildSwapoutTxArgs, result *types.Transaction) error {
+	pairID := args.PairID
+	if pairID == "" {
+		pairID = tokens.GetDefaultPairID()
+	}
+	pair := tokens.GetBridgePair(pairID)
+	if pair == nil {
+		return fmt.Errorf("bridge pair %v not found", pairID)
+	}
+	builder, ok := pair.DstBridge.(swapoutTxBuilder)
+	if !ok {
+		return fmt.Errorf("bridge pair %v does not support building swapout tx", pairID)
+	}
 	from := args.From.String()
-	token, gateway := tokens.DstBridge.GetTokenAndGateway()
+	token, _ := pair.DstBridge.GetTokenAndGateway()
 	contract := token.ContractAddress
 	extraArgs := &tokens.EthExtraArgs{
 		Gas:      (*uint64)(args.Gas),
@@ -33,10 +51,7 @@ func (s *RPCAPI) BuildSwapoutTx(r *http.Request, args *BuildSwapoutTxArgs, resul
 	swapoutVal := args.Value.ToInt()
 	bindAddr := args.Bind
 
-	ethBridge := eth.NewCrossChainBridge(false)
-	ethBridge.TokenConfig = token
-	ethBridge.GatewayConfig = gateway
-	tx, err := ethBridge.BuildSwapoutTx(from, contract, extraArgs, swapoutVal, bindAddr)
+	tx, err := builder.BuildSwapoutTx(from, contract, extraArgs, swapoutVal, bindAddr)
 	if err != nil {
 		return err
 	}
@@ -54,10 +54,12 @@ func initRouter() *mux.Router {
 	rpcserver := rpc.NewServer()
 	rpcserver.RegisterCodec(rpcjson.NewCodec(), "application/json")
 	_ = rpcserver.RegisterService(new(rpcapi.RPCAPI), "swap")
+	_ = rpcserver.RegisterService(new(rpcapi.AdminAPI), "admin")
 
 	r.Handle("/rpc", rpcserver)
 	r.HandleFunc("/serverinfo", restapi.SeverInfoHandler).Methods("GET")
 	r.HandleFunc("/statistics", restapi.StatisticsHandler).Methods("GET")
+	r.HandleFunc("/reorgs", restapi.ReorgEventsHandler).Methods("GET")
 	r.HandleFunc("/swapin/post/{txid}", restapi.PostSwapinHandler).Methods("POST")
 	r.HandleFunc("/swapin/post/{txid}/{bind}", restapi.PostP2shSwapinHandler).Methods("POST")
 	r.HandleFunc("/swapout/post/{txid}", restapi.PostSwapoutHandler).Methods("POST")
@@ -79,6 +81,7 @@ func initRouter() *mux.Router {
 
 	r.HandleFunc("/serverinfo", warnHandler).Methods(methodsExcluesGet...)
 	r.HandleFunc("/statistics", warnHandler).Methods(methodsExcluesGet...)
+	r.HandleFunc("/reorgs", warnHandler).Methods(methodsExcluesGet...)
 	r.HandleFunc("/swapin/post/{txid}", warnHandler).Methods(methodsExcluesPost...)
 	r.HandleFunc("/swapin/post/{txid}/{bind}", warnHandler).Methods(methodsExcluesPost...)
 	r.HandleFunc("/swapout/post/{txid}", warnHandler).Methods(methodsExcluesPost...)
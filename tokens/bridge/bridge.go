@@ -1,7 +1,9 @@
 package bridge
 
 import (
+	"bytes"
 	"fmt"
+	"math/big"
 	"strings"
 	"time"
 
@@ -13,55 +15,89 @@ import (
 	"github.com/fsn-dev/crossChain-Bridge/tokens"
 	"github.com/fsn-dev/crossChain-Bridge/tokens/btc"
 	"github.com/fsn-dev/crossChain-Bridge/tokens/eth"
+	"github.com/fsn-dev/crossChain-Bridge/tokens/evm"
 	"github.com/fsn-dev/crossChain-Bridge/tokens/fsn"
 )
 
 // NewCrossChainBridge new bridge according to chain name
-func NewCrossChainBridge(id string, isSrc bool) tokens.CrossChainBridge {
+func NewCrossChainBridge(id, pairID string, isSrc bool) tokens.CrossChainBridge {
 	switch strings.ToUpper(id) {
 	case "BITCOIN":
-		return btc.NewCrossChainBridge(isSrc)
+		return btc.NewCrossChainBridge(pairID, isSrc)
 	case "ETHEREUM":
-		return eth.NewCrossChainBridge(isSrc)
+		return eth.NewCrossChainBridge(pairID, isSrc)
 	case "FUSION":
-		return fsn.NewCrossChainBridge(isSrc)
+		return fsn.NewCrossChainBridge(pairID, isSrc)
+	case tokens.EvmBlockChain:
+		return evm.NewCrossChainBridge(pairID, isSrc)
 	default:
 		panic("Unsupported block chain " + id)
 	}
 }
 
-// InitCrossChainBridge init bridge
+// InitCrossChainBridge init bridges of all token pairs
 func InitCrossChainBridge(isServer bool) {
 	cfg := params.GetConfig()
-	srcToken := cfg.SrcToken
-	dstToken := cfg.DestToken
-	srcGateway := cfg.SrcGateway
-	dstGateway := cfg.DestGateway
+
+	for _, pairCfg := range cfg.GetPairConfigs() {
+		initBridgePair(pairCfg)
+	}
+
+	initBtcExtra(cfg.BtcExtra)
+
+	initEthExtra(cfg.EthExtra)
+
+	initDcrm(cfg.Dcrm, isServer)
+}
+
+func initBridgePair(pairCfg *params.PairConfig) {
+	pairID := pairCfg.Identifier
+	switch pairID {
+	case btc.AggregateIdentifier, btc.CpfpIdentifier:
+		log.Fatal("pair identifier is reserved", "identifier", pairID)
+	}
+
+	srcToken := pairCfg.SrcToken
+	dstToken := pairCfg.DestToken
+	srcGateway := pairCfg.SrcGateway
+	dstGateway := pairCfg.DestGateway
 
 	srcID := srcToken.BlockChain
 	dstID := dstToken.BlockChain
 	srcNet := srcToken.NetID
 	dstNet := dstToken.NetID
 
-	tokens.SrcBridge = NewCrossChainBridge(srcID, true)
-	tokens.DstBridge = NewCrossChainBridge(dstID, false)
-	log.Info("New bridge finished", "source", srcID, "sourceNet", srcNet, "dest", dstID, "destNet", dstNet)
-
-	tokens.SrcBridge.SetTokenAndGateway(srcToken, srcGateway)
-	log.Info("Init bridge source", "token", srcToken.Symbol, "gateway", srcGateway)
-
-	tokens.DstBridge.SetTokenAndGateway(dstToken, dstGateway)
-	log.Info("Init bridge destation", "token", dstToken.Symbol, "gateway", dstGateway)
+	pair := &tokens.BridgePair{
+		PairID:    pairID,
+		SrcBridge: NewCrossChainBridge(srcID, pairID, true),
+		DstBridge: NewCrossChainBridge(dstID, pairID, false),
+	}
+	// add before setting token and gateway, dest bridge lookup source by pair
+	tokens.AddBridgePair(pair)
+	log.Info("New bridge finished", "pairID", pairID, "source", srcID, "sourceNet", srcNet, "dest", dstID, "destNet", dstNet)
 
-	initBtcExtra(cfg.BtcExtra)
+	pair.SrcBridge.SetTokenAndGateway(srcToken, srcGateway)
+	log.Info("Init bridge source", "pairID", pairID, "token", srcToken.Symbol, "gateway", srcGateway)
 
-	initDcrm(cfg.Dcrm, isServer)
+	pair.DstBridge.SetTokenAndGateway(dstToken, dstGateway)
+	log.Info("Init bridge destation", "pairID", pairID, "token", dstToken.Symbol, "gateway", dstGateway)
+}
 
-	eth.InitExtCodeParts()
+// getBtcBridges get btc bridges of all token pairs
+func getBtcBridges() (bridges []*btc.Bridge) {
+	for _, pairID := range tokens.GetAllPairIDs() {
+		for _, isSrc := range []bool{true, false} {
+			if b := btc.GetBridgeOfPair(pairID, isSrc); b != nil {
+				bridges = append(bridges, b)
+			}
+		}
+	}
+	return bridges
 }
 
 func initBtcExtra(btcExtra *tokens.BtcExtraConfig) {
-	if btc.BridgeInstance == nil || btcExtra == nil {
+	btcBridges := getBtcBridges()
+	if len(btcBridges) == 0 || btcExtra == nil {
 		return
 	}
 
@@ -74,27 +110,48 @@ func initBtcExtra(btcExtra *tokens.BtcExtraConfig) {
 		}
 	}
 
+	if btcExtra.MaxRelayFeePerKb > 0 {
+		tokens.BtcMaxRelayFeePerKb = btcExtra.MaxRelayFeePerKb
+	}
+
+	if btcExtra.MinRelayFeePerKb > 0 {
+		tokens.BtcMinRelayFeePerKb = btcExtra.MinRelayFeePerKb
+	}
+
+	if tokens.BtcMinRelayFeePerKb > tokens.BtcMaxRelayFeePerKb {
+		log.Fatal("BtcMinRelayFeePerKb is larger than BtcMaxRelayFeePerKb", "min", tokens.BtcMinRelayFeePerKb, "max", tokens.BtcMaxRelayFeePerKb)
+	}
+
 	if btcExtra.RelayFeePerKb > 0 {
 		tokens.BtcRelayFeePerKb = btcExtra.RelayFeePerKb
-		maxRelayFeePerKb, _ := btcutil.NewAmount(0.001)
+		maxRelayFeePerKb := btcutil.Amount(tokens.BtcMaxRelayFeePerKb)
 		relayFeePerKb := btcutil.Amount(tokens.BtcRelayFeePerKb)
 		if relayFeePerKb > maxRelayFeePerKb {
 			log.Fatal("BtcRelayFeePerKb is too large", "value", relayFeePerKb, "max", maxRelayFeePerKb)
 		}
 	}
 
+	if btcExtra.EstimateFeeBlocks != 0 {
+		tokens.BtcEstimateFeeBlocks = btcExtra.EstimateFeeBlocks
+	}
+
 	log.Info("Init Btc extra", "MinRelayFee", tokens.BtcMinRelayFee, "RelayFeePerKb", tokens.BtcRelayFeePerKb)
+	log.Info("Init Btc extra", "EstimateFeeBlocks", tokens.BtcEstimateFeeBlocks, "MinRelayFeePerKb", tokens.BtcMinRelayFeePerKb, "MaxRelayFeePerKb", tokens.BtcMaxRelayFeePerKb)
 
 	if btcExtra.FromPublicKey != "" {
 		tokens.BtcFromPublicKey = btcExtra.FromPublicKey
 		pk := common.FromHex(tokens.BtcFromPublicKey)
-		address, _ := btcutil.NewAddressPubKeyHash(btcutil.Hash160(pk), btc.BridgeInstance.GetChainConfig())
-		pubkeyAddress := address.EncodeAddress()
-		log.Info("Init Btc extra", "FromPublicKey", tokens.BtcFromPublicKey, "address", pubkeyAddress)
-
-		btcDcrmAddress := btc.BridgeInstance.TokenConfig.DcrmAddress
-		if pubkeyAddress != btcDcrmAddress {
-			log.Fatal("BtcFromPublicKey's address mismatch dcrm address", "pubkeyAddress", pubkeyAddress, "dcrmAddress", btcDcrmAddress)
+		for _, b := range btcBridges {
+			address, _ := btcutil.NewAddressPubKeyHash(btcutil.Hash160(pk), b.GetChainConfig())
+			pubkeyAddress := address.EncodeAddress()
+			log.Info("Init Btc extra", "pairID", b.PairID, "FromPublicKey", tokens.BtcFromPublicKey, "address", pubkeyAddress)
+
+			// dcrm address may be p2pkh or p2wpkh address of the same pubkey hash
+			btcDcrmAddress := b.TokenConfig.DcrmAddress
+			dcrmAddress, err := btcutil.DecodeAddress(btcDcrmAddress, b.GetChainConfig())
+			if err != nil || !bytes.Equal(dcrmAddress.ScriptAddress(), address.ScriptAddress()) {
+				log.Fatal("BtcFromPublicKey's address mismatch dcrm address", "pairID", b.PairID, "pubkeyAddress", pubkeyAddress, "dcrmAddress", btcDcrmAddress)
+			}
 		}
 	}
 
@@ -107,6 +164,79 @@ func initBtcExtra(btcExtra *tokens.BtcExtraConfig) {
 	}
 
 	log.Info("Init Btc extra", "UtxoAggregateMinCount", tokens.BtcUtxoAggregateMinCount, "UtxoAggregateMinValue", tokens.BtcUtxoAggregateMinValue)
+
+	if btcExtra.ReplacePendingAge != 0 {
+		tokens.BtcReplacePendingAge = btcExtra.ReplacePendingAge
+	}
+
+	if btcExtra.RelayFeeBumpPercent > 0 {
+		tokens.BtcRelayFeeBumpPercent = btcExtra.RelayFeeBumpPercent
+	}
+
+	if btcExtra.MaxReplaceCount > 0 {
+		tokens.BtcMaxReplaceCount = btcExtra.MaxReplaceCount
+	}
+
+	log.Info("Init Btc extra", "ReplacePendingAge", tokens.BtcReplacePendingAge, "RelayFeeBumpPercent", tokens.BtcRelayFeeBumpPercent, "MaxReplaceCount", tokens.BtcMaxReplaceCount)
+
+	if btcExtra.CoinSelection != "" {
+		if btc.GetCoinSelector(btcExtra.CoinSelection) == nil {
+			log.Fatal("unknown BtcCoinSelection", "value", btcExtra.CoinSelection)
+		}
+		tokens.BtcCoinSelection = btcExtra.CoinSelection
+	}
+
+	if btcExtra.UtxoReserveTime > 0 {
+		tokens.BtcUtxoReserveTime = btcExtra.UtxoReserveTime
+	}
+
+	log.Info("Init Btc extra", "CoinSelection", tokens.BtcCoinSelection, "UtxoReserveTime", tokens.BtcUtxoReserveTime)
+}
+
+func initEthExtra(ethExtra *tokens.EthExtraConfig) {
+	if ethExtra == nil {
+		return
+	}
+
+	if ethExtra.ReplacePendingAge != 0 {
+		tokens.EthReplacePendingAge = ethExtra.ReplacePendingAge
+	}
+
+	if ethExtra.GasPriceBumpPercent > 0 {
+		tokens.EthGasPriceBumpPercent = ethExtra.GasPriceBumpPercent
+		if tokens.EthGasPriceBumpPercent < tokens.EthMinGasPriceBumpPercent {
+			log.Fatal("EthGasPriceBumpPercent is too small", "value", tokens.EthGasPriceBumpPercent, "min", tokens.EthMinGasPriceBumpPercent)
+		}
+	}
+
+	if ethExtra.MaxReplaceCount > 0 {
+		tokens.EthMaxReplaceCount = ethExtra.MaxReplaceCount
+	}
+
+	if ethExtra.MaxGasPriceInGwei > 0 {
+		tokens.EthMaxGasPrice = new(big.Int).SetUint64(ethExtra.MaxGasPriceInGwei * 1e9)
+	}
+
+	log.Info("Init Eth extra", "ReplacePendingAge", tokens.EthReplacePendingAge, "GasPriceBumpPercent", tokens.EthGasPriceBumpPercent, "MaxReplaceCount", tokens.EthMaxReplaceCount, "MaxGasPrice", tokens.EthMaxGasPrice)
+
+	tokens.EthEnableDynamicFeeTx = ethExtra.EnableDynamicFeeTx
+
+	if ethExtra.MaxGasTipCapInGwei > 0 {
+		tokens.EthMaxGasTipCap = new(big.Int).SetUint64(ethExtra.MaxGasTipCapInGwei * 1e9)
+	}
+
+	if ethExtra.FeeHistoryBlocks > 0 {
+		tokens.EthFeeHistoryBlocks = ethExtra.FeeHistoryBlocks
+	}
+
+	if ethExtra.FeeHistoryPercentile > 0 {
+		tokens.EthFeeHistoryPercentile = ethExtra.FeeHistoryPercentile
+		if tokens.EthFeeHistoryPercentile > 100 {
+			log.Fatal("EthFeeHistoryPercentile is too large", "value", tokens.EthFeeHistoryPercentile, "max", 100)
+		}
+	}
+
+	log.Info("Init Eth extra", "EnableDynamicFeeTx", tokens.EthEnableDynamicFeeTx, "MaxGasTipCap", tokens.EthMaxGasTipCap, "FeeHistoryBlocks", tokens.EthFeeHistoryBlocks, "FeeHistoryPercentile", tokens.EthFeeHistoryPercentile)
 }
 
 func initDcrm(dcrmConfig *params.DcrmConfig, isServer bool) {
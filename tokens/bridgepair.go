@@ -0,0 +1,83 @@
+package tokens
+
+// BridgePair source and destination bridges of a token pair, identified by pair id.
+// pair id is the 'Identifier' of the pair in config, it distinguishes the pair in dcrm accept.
+type BridgePair struct {
+	PairID    string
+	SrcBridge CrossChainBridge
+	DstBridge CrossChainBridge
+
+	SrcLatestBlockHeight uint64
+	DstLatestBlockHeight uint64
+}
+
+var (
+	bridgePairs   = make(map[string]*BridgePair)
+	bridgePairIDs []string
+)
+
+// AddBridgePair add bridge pair, the first added pair is the default pair
+func AddBridgePair(pair *BridgePair) {
+	if _, exist := bridgePairs[pair.PairID]; !exist {
+		bridgePairIDs = append(bridgePairIDs, pair.PairID)
+	}
+	bridgePairs[pair.PairID] = pair
+}
+
+// GetBridgePair get bridge pair of pair id (nil if not exist)
+func GetBridgePair(pairID string) *BridgePair {
+	return bridgePairs[pairID]
+}
+
+// GetAllPairIDs get all pair ids in the order of adding
+func GetAllPairIDs() []string {
+	return bridgePairIDs
+}
+
+// GetDefaultPairID get pair id of the default pair
+func GetDefaultPairID() string {
+	if len(bridgePairIDs) == 0 {
+		return ""
+	}
+	return bridgePairIDs[0]
+}
+
+// GetCrossChainBridge get bridge of specified endpoint
+func (p *BridgePair) GetCrossChainBridge(isSrc bool) CrossChainBridge {
+	if isSrc {
+		return p.SrcBridge
+	}
+	return p.DstBridge
+}
+
+// GetLatestBlockHeight get latest block height of specified endpoint
+func (p *BridgePair) GetLatestBlockHeight(isSrc bool) uint64 {
+	if isSrc {
+		return p.SrcLatestBlockHeight
+	}
+	return p.DstLatestBlockHeight
+}
+
+// GetCrossChainBridge get bridge of specified endpoint of pair
+func GetCrossChainBridge(pairID string, isSrc bool) CrossChainBridge {
+	return GetBridgePair(pairID).GetCrossChainBridge(isSrc)
+}
+
+// GetTokenConfig get token config of specified endpoint of pair
+func GetTokenConfig(pairID string, isSrc bool) *TokenConfig {
+	token, _ := GetCrossChainBridge(pairID, isSrc).GetTokenAndGateway()
+	return token
+}
+
+// SetLatestBlockHeight set latest block height of specified endpoint of pair
+func SetLatestBlockHeight(pairID string, latest uint64, isSrc bool) {
+	pair := GetBridgePair(pairID)
+	if pair == nil {
+		return
+	}
+	if isSrc {
+		pair.SrcLatestBlockHeight = latest
+	} else {
+		pair.DstLatestBlockHeight = latest
+	}
+}
@@ -7,10 +7,10 @@ import (
 	"github.com/btcsuite/btcutil"
 )
 
-// IsValidAddress check address
+// IsValidAddress check address (taproot address is valid)
 func (b *Bridge) IsValidAddress(addr string) bool {
 	chainConfig := b.GetChainConfig()
-	address, err := btcutil.DecodeAddress(addr, chainConfig)
+	address, err := DecodeAddress(addr, chainConfig)
 	if err != nil {
 		return false
 	}
@@ -45,6 +45,48 @@ func (b *Bridge) IsP2shAddress(addr string) bool {
 	return ok
 }
 
+// IsP2wpkhAddress check p2wpkh (native segwit) addrss
+func (b *Bridge) IsP2wpkhAddress(addr string) bool {
+	chainConfig := b.GetChainConfig()
+	address, err := btcutil.DecodeAddress(addr, chainConfig)
+	if err != nil {
+		return false
+	}
+	if !address.IsForNet(chainConfig) {
+		return false
+	}
+	_, ok := address.(*btcutil.AddressWitnessPubKeyHash)
+	return ok
+}
+
+// IsP2wshAddress check p2wsh (native segwit) addrss
+func (b *Bridge) IsP2wshAddress(addr string) bool {
+	chainConfig := b.GetChainConfig()
+	address, err := btcutil.DecodeAddress(addr, chainConfig)
+	if err != nil {
+		return false
+	}
+	if !address.IsForNet(chainConfig) {
+		return false
+	}
+	_, ok := address.(*btcutil.AddressWitnessScriptHash)
+	return ok
+}
+
+// IsP2trAddress check p2tr (taproot) addrss
+func (b *Bridge) IsP2trAddress(addr string) bool {
+	chainConfig := b.GetChainConfig()
+	address, err := DecodeAddress(addr, chainConfig)
+	if err != nil {
+		return false
+	}
+	if !address.IsForNet(chainConfig) {
+		return false
+	}
+	_, ok := address.(*AddressTaproot)
+	return ok
+}
+
 // GetChainConfig get chain config (net params)
 func (b *Bridge) GetChainConfig() *chaincfg.Params {
 	token := b.TokenConfig
@@ -14,18 +14,22 @@ const (
 
 // AggregateUtxos aggregate uxtos
 func (b *Bridge) AggregateUtxos(addrs []string, utxos []*electrs.ElectUtxo) (string, error) {
-	authoredTx, err := b.BuildAggregateTransaction(addrs, utxos)
+	relayFeePerKb := b.getRelayFeePerKb()
+	authoredTx, err := b.BuildAggregateTransaction(addrs, utxos, relayFeePerKb)
 	if err != nil {
 		return "", err
 	}
 
 	args := &tokens.BuildTxArgs{
 		Extra: &tokens.AllExtras{
-			BtcExtra: &tokens.BtcExtraArgs{},
+			BtcExtra: &tokens.BtcExtraArgs{
+				RelayFeePerKb: &relayFeePerKb,
+			},
 		},
 	}
 
 	args.Identifier = AggregateIdentifier
+	args.PairID = b.PairID
 	extra := args.Extra.BtcExtra
 	extra.PreviousOutPoints = make([]*tokens.BtcOutPoint, len(authoredTx.Tx.TxIn))
 	for i, txin := range authoredTx.Tx.TxIn {
@@ -35,6 +39,9 @@ func (b *Bridge) AggregateUtxos(addrs []string, utxos []*electrs.ElectUtxo) (str
 			Index: point.Index,
 		}
 	}
+	b.utxoIndex.Lock()
+	b.utxoIndex.lockUtxos(extra.PreviousOutPoints, AggregateIdentifier)
+	b.utxoIndex.Unlock()
 
 	signedTx, txHash, err := b.DcrmSignTransaction(authoredTx, args)
 	if err != nil {
@@ -47,14 +54,15 @@ func (b *Bridge) AggregateUtxos(addrs []string, utxos []*electrs.ElectUtxo) (str
 	return txHash, nil
 }
 
-// VerifyAggregateMsgHash verify aggregate msgHash
-func (b *Bridge) VerifyAggregateMsgHash(msgHash []string, args *tokens.BuildTxArgs) error {
+// VerifyAggregateMsgHash verify aggregate msgHash, return the rebuilt msgHash
+func (b *Bridge) VerifyAggregateMsgHash(msgHash []string, args *tokens.BuildTxArgs) (rebuiltMsgHash []string, err error) {
 	if args == nil || args.Extra == nil || args.Extra.BtcExtra == nil || len(args.Extra.BtcExtra.PreviousOutPoints) == 0 {
-		return errors.New("empty btc extra")
+		return nil, errors.New("empty btc extra")
 	}
-	rawTx, err := b.rebuildAggregateTransaction(args.Extra.BtcExtra.PreviousOutPoints)
+	rawTx, err := b.rebuildAggregateTransaction(args.Extra.BtcExtra)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return b.VerifyMsgHash(rawTx, msgHash, args.Extra)
+	rebuiltMsgHash, _ = b.CalcMsgHash(rawTx)
+	return rebuiltMsgHash, b.VerifyMsgHash(rawTx, msgHash, args.Extra)
 }
@@ -0,0 +1,188 @@
+package bitcoind
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fsn-dev/crossChain-Bridge/rpc/client"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+	"github.com/fsn-dev/crossChain-Bridge/tokens/btc/electrs"
+)
+
+const (
+	// scantxoutset walks through the whole utxo set, it may take minutes
+	scanTxOutSetTimeout = 600 // seconds
+	defaultRequestID    = 1
+)
+
+var errNotSupported = errors.New("not supported by bitcoind gateway")
+
+func rpcPost(b tokens.CrossChainBridge, result interface{}, method string, params ...interface{}) error {
+	_, gateway := b.GetTokenAndGateway()
+	return gateway.Call(func(url string) error {
+		return client.RPCPost(result, url, method, params...)
+	})
+}
+
+// GetLatestBlockNumber call getblockcount
+func GetLatestBlockNumber(b tokens.CrossChainBridge) (uint64, error) {
+	var result uint64
+	err := rpcPost(b, &result, "getblockcount")
+	return result, err
+}
+
+// GetTransactionByHash call getrawtransaction (need txindex for confirmed tx)
+func GetTransactionByHash(b tokens.CrossChainBridge, txHash string) (*electrs.ElectTx, error) {
+	var result RawTransaction
+	err := rpcPost(b, &result, "getrawtransaction", txHash, 2)
+	if err != nil {
+		return nil, err
+	}
+	return convertTransaction(b, &result)
+}
+
+// GetElectTransactionStatus call getrawtransaction
+func GetElectTransactionStatus(b tokens.CrossChainBridge, txHash string) (*electrs.ElectTxStatus, error) {
+	var result RawTransaction
+	err := rpcPost(b, &result, "getrawtransaction", txHash, true)
+	if err != nil {
+		return nil, err
+	}
+	return getTransactionStatus(b, &result)
+}
+
+// FindUtxos call scantxoutset (confirmed only, then big value first)
+func FindUtxos(b tokens.CrossChainBridge, addr string) ([]*electrs.ElectUtxo, error) {
+	_, gateway := b.GetTokenAndGateway()
+	var result ScanTxOutSetResult
+	scanObjects := []string{"addr(" + addr + ")"}
+	err := gateway.Call(func(url string) error {
+		return client.RPCPostWithTimeoutAndID(&result, scanTxOutSetTimeout, defaultRequestID, url, "scantxoutset", "start", scanObjects)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return nil, fmt.Errorf("scantxoutset of %v is not success", addr)
+	}
+	utxos := make([]*electrs.ElectUtxo, 0, len(result.Unspents))
+	for _, unspent := range result.Unspents {
+		value, err := convertAmount(unspent.Amount)
+		if err != nil {
+			return nil, err
+		}
+		utxos = append(utxos, &electrs.ElectUtxo{
+			Txid:   newString(unspent.Txid),
+			Vout:   newUint32(unspent.Vout),
+			Value:  newUint64(value),
+			Status: newConfirmedStatus(unspent.Height, "", 0),
+		})
+	}
+	sort.Sort(electrs.SortableElectUtxoSlice(utxos))
+	return utxos, nil
+}
+
+// GetPoolTxidList call getrawmempool
+func GetPoolTxidList(b tokens.CrossChainBridge) ([]string, error) {
+	var result []string
+	err := rpcPost(b, &result, "getrawmempool")
+	return result, err
+}
+
+// GetPoolTransactions is not supported (bitcoind has no address index)
+func GetPoolTransactions(b tokens.CrossChainBridge, addr string) ([]*electrs.ElectTx, error) {
+	return nil, errNotSupported
+}
+
+// GetTransactionHistory return empty list as bitcoind has no address index,
+// deposits are found by scanning blocks and txpool instead.
+func GetTransactionHistory(b tokens.CrossChainBridge, addr, lastSeenTxid string) ([]*electrs.ElectTx, error) {
+	return nil, nil
+}
+
+// GetOutspend call gettxout, and gettxspendingprevout to find spending tx in txpool
+func GetOutspend(b tokens.CrossChainBridge, txHash string, vout uint32) (*electrs.ElectOutspend, error) {
+	var txout *TxOut
+	err := rpcPost(b, &txout, "gettxout", txHash, vout, true)
+	if err != nil {
+		return nil, err
+	}
+	if txout != nil {
+		return &electrs.ElectOutspend{Spent: newBool(false)}, nil
+	}
+	// not in utxo set (including txpool), check whether it is spent in txpool
+	var confirmedTxout *TxOut
+	err = rpcPost(b, &confirmedTxout, "gettxout", txHash, vout, false)
+	if err != nil {
+		return nil, err
+	}
+	if confirmedTxout == nil {
+		return &electrs.ElectOutspend{
+			Spent:  newBool(true),
+			Status: &electrs.ElectTxStatus{Confirmed: newBool(true)},
+		}, nil
+	}
+	outspend := &electrs.ElectOutspend{
+		Spent:  newBool(true),
+		Status: &electrs.ElectTxStatus{Confirmed: newBool(false)},
+	}
+	var spendings []*TxSpendingPrevout
+	outpoints := []map[string]interface{}{{"txid": txHash, "vout": vout}}
+	// gettxspendingprevout exist since bitcoin core 24.0, ignore error for older versions
+	if err = rpcPost(b, &spendings, "gettxspendingprevout", outpoints); err == nil {
+		for _, spending := range spendings {
+			if spending.SpendingTxid != "" {
+				outspend.Txid = newString(spending.SpendingTxid)
+				break
+			}
+		}
+	}
+	return outspend, nil
+}
+
+// PostTransaction call sendrawtransaction
+func PostTransaction(b tokens.CrossChainBridge, txHex string) (txHash string, err error) {
+	_, gateway := b.GetTokenAndGateway()
+	return gateway.Broadcast(func(url string) (result string, err error) {
+		err = client.RPCPost(&result, url, "sendrawtransaction", txHex)
+		return result, err
+	})
+}
+
+// GetBlockHash call getblockhash
+func GetBlockHash(b tokens.CrossChainBridge, height uint64) (string, error) {
+	var result string
+	err := rpcPost(b, &result, "getblockhash", height)
+	return result, err
+}
+
+// GetBlockTxids call getblock
+func GetBlockTxids(b tokens.CrossChainBridge, blockHash string) ([]string, error) {
+	var result Block
+	err := rpcPost(b, &result, "getblock", blockHash, 1)
+	if err != nil {
+		return nil, err
+	}
+	return result.Tx, nil
+}
+
+// GetFeeEstimates call estimatesmartfee of configed target blocks (confirmation target blocks => fee rate in sat/vB)
+func GetFeeEstimates(b tokens.CrossChainBridge) (map[string]float64, error) {
+	estimates := make(map[string]float64)
+	if tokens.BtcEstimateFeeBlocks <= 0 {
+		return estimates, nil
+	}
+	var result SmartFeeResult
+	err := rpcPost(b, &result, "estimatesmartfee", tokens.BtcEstimateFeeBlocks)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Errors) > 0 || result.FeeRate <= 0 {
+		return nil, fmt.Errorf("estimatesmartfee failed: %v", strings.Join(result.Errors, "; "))
+	}
+	// BTC/kvB => sat/vB
+	estimates[fmt.Sprintf("%d", result.Blocks)] = result.FeeRate * 1e5
+	return estimates, nil
+}
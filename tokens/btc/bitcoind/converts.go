@@ -0,0 +1,257 @@
+package bitcoind
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+	"github.com/fsn-dev/crossChain-Bridge/tokens/btc/electrs"
+)
+
+// script types of bitcoind => script types of electrs
+var scriptTypes = map[string]string{
+	"pubkey":                "p2pk",
+	"pubkeyhash":            "p2pkh",
+	"scripthash":            "p2sh",
+	"witness_v0_keyhash":    "v0_p2wpkh",
+	"witness_v0_scripthash": "v0_p2wsh",
+	"witness_v1_taproot":    "v1_p2tr",
+	"multisig":              "multisig",
+	"nulldata":              "op_return",
+	"nonstandard":           "unknown",
+	"witness_unknown":       "unknown",
+}
+
+func newBool(val bool) *bool {
+	return &val
+}
+
+func newString(val string) *string {
+	return &val
+}
+
+func newUint32(val uint32) *uint32 {
+	return &val
+}
+
+func newUint64(val uint64) *uint64 {
+	return &val
+}
+
+func newConfirmedStatus(height uint64, blockHash string, blockTime uint64) *electrs.ElectTxStatus {
+	status := &electrs.ElectTxStatus{
+		Confirmed:   newBool(true),
+		BlockHeight: newUint64(height),
+	}
+	if blockHash != "" {
+		status.BlockHash = newString(blockHash)
+		status.BlockTime = newUint64(blockTime)
+	}
+	return status
+}
+
+// convertAmount convert BTC to satoshi
+func convertAmount(value float64) (uint64, error) {
+	amount, err := btcutil.NewAmount(value)
+	if err != nil {
+		return 0, err
+	}
+	if amount < 0 {
+		return 0, fmt.Errorf("negative amount %v", value)
+	}
+	return uint64(amount), nil
+}
+
+func getTransactionStatus(b tokens.CrossChainBridge, tx *RawTransaction) (*electrs.ElectTxStatus, error) {
+	if tx.BlockHash == "" || tx.Confirmations == 0 {
+		return &electrs.ElectTxStatus{Confirmed: newBool(false)}, nil
+	}
+	var header BlockHeader
+	err := rpcPost(b, &header, "getblockheader", tx.BlockHash, true)
+	if err != nil {
+		return nil, err
+	}
+	return newConfirmedStatus(header.Height, tx.BlockHash, tx.BlockTime), nil
+}
+
+// convertTransaction convert to electrs tx, prevouts are queried if they are absent (before bitcoin core 25.0)
+func convertTransaction(b tokens.CrossChainBridge, tx *RawTransaction) (*electrs.ElectTx, error) {
+	status, err := getTransactionStatus(b, tx)
+	if err != nil {
+		return nil, err
+	}
+	result := &electrs.ElectTx{
+		Txid:     newString(tx.Txid),
+		Version:  newUint32(tx.Version),
+		Locktime: newUint32(tx.Locktime),
+		Size:     newUint32(tx.Size),
+		Weight:   newUint32(tx.Weight),
+		Vin:      make([]*electrs.ElectTxin, len(tx.Vin)),
+		Vout:     make([]*electrs.ElectTxOut, len(tx.Vout)),
+		Status:   status,
+	}
+
+	var totalIn, totalOut uint64
+	isCoinbaseTx := false
+	prevTxs := make(map[string]*RawTransaction)
+	for i, input := range tx.Vin {
+		txin := &electrs.ElectTxin{
+			Sequence: newUint32(input.Sequence),
+		}
+		result.Vin[i] = txin
+		if input.Coinbase != "" {
+			isCoinbaseTx = true
+			txin.Txid = newString(strings.Repeat("0", 64))
+			txin.Vout = newUint32(0xffffffff)
+			txin.IsCoinbase = newBool(true)
+			txin.Scriptsig = newString(input.Coinbase)
+			continue
+		}
+		txin.Txid = newString(input.Txid)
+		txin.Vout = newUint32(input.Vout)
+		txin.IsCoinbase = newBool(false)
+		if input.ScriptSig != nil {
+			txin.Scriptsig = newString(input.ScriptSig.Hex)
+			if asm, errf := disasmScript(input.ScriptSig.Hex); errf == nil {
+				txin.ScriptsigAsm = newString(asm)
+			}
+		}
+		var prevout *electrs.ElectTxOut
+		if input.Prevout != nil {
+			prevout, err = convertTxOut(input.Prevout.Value, input.Prevout.ScriptPubKey)
+		} else {
+			prevout, err = getPrevout(b, prevTxs, input.Txid, input.Vout)
+		}
+		if err != nil {
+			return nil, err
+		}
+		txin.Prevout = prevout
+		totalIn += *prevout.Value
+	}
+
+	for i, output := range tx.Vout {
+		txout, errf := convertTxOut(output.Value, output.ScriptPubKey)
+		if errf != nil {
+			return nil, errf
+		}
+		result.Vout[i] = txout
+		totalOut += *txout.Value
+	}
+
+	switch {
+	case isCoinbaseTx:
+		result.Fee = newUint64(0)
+	case totalIn >= totalOut:
+		result.Fee = newUint64(totalIn - totalOut)
+	default:
+		return nil, fmt.Errorf("tx %v has input value %v less than output value %v", tx.Txid, totalIn, totalOut)
+	}
+	return result, nil
+}
+
+func getPrevout(b tokens.CrossChainBridge, prevTxs map[string]*RawTransaction, txid string, vout uint32) (*electrs.ElectTxOut, error) {
+	prevTx, exist := prevTxs[txid]
+	if !exist {
+		prevTx = &RawTransaction{}
+		err := rpcPost(b, prevTx, "getrawtransaction", txid, true)
+		if err != nil {
+			return nil, err
+		}
+		prevTxs[txid] = prevTx
+	}
+	if int(vout) >= len(prevTx.Vout) {
+		return nil, fmt.Errorf("prevout %v:%v not found", txid, vout)
+	}
+	output := prevTx.Vout[vout]
+	return convertTxOut(output.Value, output.ScriptPubKey)
+}
+
+func convertTxOut(value float64, scriptPubKey *ScriptPubKey) (*electrs.ElectTxOut, error) {
+	if scriptPubKey == nil {
+		return nil, fmt.Errorf("tx output without scriptPubKey")
+	}
+	amount, err := convertAmount(value)
+	if err != nil {
+		return nil, err
+	}
+	asm, err := disasmScript(scriptPubKey.Hex)
+	if err != nil {
+		return nil, err
+	}
+	txout := &electrs.ElectTxOut{
+		Scriptpubkey:    newString(scriptPubKey.Hex),
+		ScriptpubkeyAsm: newString(asm),
+		Value:           newUint64(amount),
+	}
+	if scriptType, exist := scriptTypes[scriptPubKey.Type]; exist {
+		txout.ScriptpubkeyType = newString(scriptType)
+	} else {
+		txout.ScriptpubkeyType = newString(scriptPubKey.Type)
+	}
+	switch {
+	case scriptPubKey.Address != "":
+		txout.ScriptpubkeyAddress = newString(scriptPubKey.Address)
+	case len(scriptPubKey.Addresses) == 1:
+		txout.ScriptpubkeyAddress = newString(scriptPubKey.Addresses[0])
+	}
+	return txout, nil
+}
+
+// disasmScript disassemble script in electrs asm format (eg. OP_RETURN OP_PUSHBYTES_4 01020304)
+func disasmScript(scriptHex string) (string, error) {
+	script, err := hex.DecodeString(scriptHex)
+	if err != nil {
+		return "", err
+	}
+	var items []string
+	for len(script) > 0 {
+		op := script[0]
+		script = script[1:]
+		var dataLen int
+		var name string
+		switch {
+		case op == txscript.OP_0:
+			items = append(items, "OP_0")
+			continue
+		case op <= txscript.OP_DATA_75:
+			dataLen = int(op)
+			name = fmt.Sprintf("OP_PUSHBYTES_%d", op)
+		case op == txscript.OP_PUSHDATA1, op == txscript.OP_PUSHDATA2, op == txscript.OP_PUSHDATA4:
+			sizeLen := 1 << (op - txscript.OP_PUSHDATA1) // 1, 2, 4 bytes little endian length
+			if len(script) < sizeLen {
+				return "", fmt.Errorf("malformed script %v", scriptHex)
+			}
+			for i := sizeLen - 1; i >= 0; i-- {
+				dataLen = dataLen<<8 | int(script[i])
+			}
+			script = script[sizeLen:]
+			name = fmt.Sprintf("OP_PUSHDATA%d", sizeLen)
+		case op == txscript.OP_1NEGATE:
+			items = append(items, "OP_PUSHNUM_NEG1")
+			continue
+		case op >= txscript.OP_1 && op <= txscript.OP_16:
+			items = append(items, fmt.Sprintf("OP_PUSHNUM_%d", op-txscript.OP_1+1))
+			continue
+		default:
+			items = append(items, opcodeName(op))
+			continue
+		}
+		if dataLen < 0 || len(script) < dataLen {
+			return "", fmt.Errorf("malformed script %v", scriptHex)
+		}
+		items = append(items, name, hex.EncodeToString(script[:dataLen]))
+		script = script[dataLen:]
+	}
+	return strings.Join(items, " "), nil
+}
+
+func opcodeName(op byte) string {
+	name, _ := txscript.DisasmString([]byte{op})
+	if strings.HasPrefix(name, "OP_") {
+		return name
+	}
+	return fmt.Sprintf("OP_UNKNOWN_%d", op)
+}
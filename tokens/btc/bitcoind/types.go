@@ -0,0 +1,113 @@
+package bitcoind
+
+// RawTransaction result of getrawtransaction (verbose)
+type RawTransaction struct {
+	Txid          string      `json:"txid"`
+	Hash          string      `json:"hash"`
+	Version       uint32      `json:"version"`
+	Size          uint32      `json:"size"`
+	Vsize         uint32      `json:"vsize"`
+	Weight        uint32      `json:"weight"`
+	Locktime      uint32      `json:"locktime"`
+	Vin           []*RawTxIn  `json:"vin"`
+	Vout          []*RawTxOut `json:"vout"`
+	BlockHash     string      `json:"blockhash"`
+	Confirmations uint64      `json:"confirmations"`
+	BlockTime     uint64      `json:"blocktime"`
+}
+
+// RawTxIn tx input of RawTransaction
+type RawTxIn struct {
+	Txid        string      `json:"txid"`
+	Vout        uint32      `json:"vout"`
+	Coinbase    string      `json:"coinbase"`
+	ScriptSig   *ScriptSig  `json:"scriptSig"`
+	TxinWitness []string    `json:"txinwitness"`
+	Sequence    uint32      `json:"sequence"`
+	Prevout     *RawPrevout `json:"prevout"` // exist since bitcoin core 25.0 (verbosity 2)
+}
+
+// RawPrevout spent output of RawTxIn
+type RawPrevout struct {
+	Height       uint64        `json:"height"`
+	Value        float64       `json:"value"`
+	ScriptPubKey *ScriptPubKey `json:"scriptPubKey"`
+}
+
+// RawTxOut tx output of RawTransaction
+type RawTxOut struct {
+	Value        float64       `json:"value"`
+	N            uint32        `json:"n"`
+	ScriptPubKey *ScriptPubKey `json:"scriptPubKey"`
+}
+
+// ScriptSig struct
+type ScriptSig struct {
+	Asm string `json:"asm"`
+	Hex string `json:"hex"`
+}
+
+// ScriptPubKey struct
+type ScriptPubKey struct {
+	Asm       string   `json:"asm"`
+	Hex       string   `json:"hex"`
+	Type      string   `json:"type"`
+	Address   string   `json:"address"`   // since bitcoin core 22.0
+	Addresses []string `json:"addresses"` // before bitcoin core 22.0
+}
+
+// BlockHeader result of getblockheader (verbose)
+type BlockHeader struct {
+	Hash   string `json:"hash"`
+	Height uint64 `json:"height"`
+	Time   uint64 `json:"time"`
+}
+
+// Block result of getblock (verbosity 1)
+type Block struct {
+	Hash   string   `json:"hash"`
+	Height uint64   `json:"height"`
+	Time   uint64   `json:"time"`
+	Tx     []string `json:"tx"`
+}
+
+// TxOut result of gettxout
+type TxOut struct {
+	BestBlock     string        `json:"bestblock"`
+	Confirmations uint64        `json:"confirmations"`
+	Value         float64       `json:"value"`
+	ScriptPubKey  *ScriptPubKey `json:"scriptPubKey"`
+	Coinbase      bool          `json:"coinbase"`
+}
+
+// TxSpendingPrevout result item of gettxspendingprevout
+type TxSpendingPrevout struct {
+	Txid         string `json:"txid"`
+	Vout         uint32 `json:"vout"`
+	SpendingTxid string `json:"spendingtxid"`
+}
+
+// ScanTxOutSetResult result of scantxoutset
+type ScanTxOutSetResult struct {
+	Success   bool                `json:"success"`
+	Height    uint64              `json:"height"`
+	BestBlock string              `json:"bestblock"`
+	Unspents  []*ScanTxOutSetUtxo `json:"unspents"`
+}
+
+// ScanTxOutSetUtxo unspent of ScanTxOutSetResult
+type ScanTxOutSetUtxo struct {
+	Txid         string  `json:"txid"`
+	Vout         uint32  `json:"vout"`
+	ScriptPubKey string  `json:"scriptPubKey"`
+	Desc         string  `json:"desc"`
+	Amount       float64 `json:"amount"`
+	Height       uint64  `json:"height"`
+}
+
+// SmartFeeResult result of estimatesmartfee
+type SmartFeeResult struct {
+	FeeRate float64  `json:"feerate"` // BTC/kvB
+	Errors  []string `json:"errors"`
+	Blocks  int      `json:"blocks"`
+}
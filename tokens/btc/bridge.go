@@ -6,6 +6,7 @@ import (
 
 	"github.com/fsn-dev/crossChain-Bridge/log"
 	"github.com/fsn-dev/crossChain-Bridge/tokens"
+	"github.com/fsn-dev/crossChain-Bridge/tokens/tools"
 )
 
 const (
@@ -14,27 +15,45 @@ const (
 	netCustom   = "custom"
 )
 
-// BridgeInstance btc bridge instance
-var BridgeInstance *Bridge
-
 // Bridge btc bridge
 type Bridge struct {
 	*tokens.CrossChainBridgeBase
+
+	scannedTxs    *tools.CachedScannedTxs
+	scannedBlocks *tools.CachedScannedBlocks
+	utxoIndex     *utxoIndex
+	gateway       Gateway
 }
 
 // NewCrossChainBridge new btc bridge
-func NewCrossChainBridge(isSrc bool) *Bridge {
-	if !isSrc {
-		panic(tokens.ErrBridgeDestinationNotSupported)
+func NewCrossChainBridge(pairID string, isSrc bool) *Bridge {
+	return &Bridge{
+		CrossChainBridgeBase: tokens.NewCrossChainBridgeBase(pairID, isSrc),
+		scannedTxs:           tools.NewCachedScannedTxs(100),
+		scannedBlocks:        tools.NewCachedScannedBlocks(13),
+		utxoIndex:            newUtxoIndex(),
 	}
-	BridgeInstance = &Bridge{tokens.NewCrossChainBridgeBase(isSrc)}
-	return BridgeInstance
+}
+
+// GetBridgeOfPair get btc bridge of specified endpoint of bridge pair (nil if it's not btc)
+func GetBridgeOfPair(pairID string, isSrc bool) *Bridge {
+	pair := tokens.GetBridgePair(pairID)
+	if pair == nil {
+		return nil
+	}
+	b, _ := pair.GetCrossChainBridge(isSrc).(*Bridge)
+	return b
 }
 
 // SetTokenAndGateway set token and gateway config
 func (b *Bridge) SetTokenAndGateway(tokenCfg *tokens.TokenConfig, gatewayCfg *tokens.GatewayConfig) {
 	b.CrossChainBridgeBase.SetTokenAndGateway(tokenCfg, gatewayCfg)
 
+	if !IsValidGatewayType(gatewayCfg.APIType) {
+		log.Fatal("unsupported btc gateway api type", "apiType", gatewayCfg.APIType)
+	}
+	b.gateway = newGateway(b, gatewayCfg.APIType)
+
 	networkID := strings.ToLower(tokenCfg.NetID)
 	switch networkID {
 	case netMainnet, netTestnet3:
@@ -44,8 +63,8 @@ func (b *Bridge) SetTokenAndGateway(tokenCfg *tokens.TokenConfig, gatewayCfg *to
 		log.Fatal("unsupported bitcoin network", "netID", tokenCfg.NetID)
 	}
 
-	if !b.IsP2pkhAddress(tokenCfg.DcrmAddress) {
-		log.Fatal("invalid dcrm address (not p2pkh)", "address", tokenCfg.DcrmAddress)
+	if !b.IsP2pkhAddress(tokenCfg.DcrmAddress) && !b.IsP2wpkhAddress(tokenCfg.DcrmAddress) {
+		log.Fatal("invalid dcrm address (not p2pkh or p2wpkh)", "address", tokenCfg.DcrmAddress)
 	}
 
 	if strings.EqualFold(tokenCfg.Symbol, "BTC") && *tokenCfg.Decimals != 8 {
@@ -57,7 +76,7 @@ func (b *Bridge) SetTokenAndGateway(tokenCfg *tokens.TokenConfig, gatewayCfg *to
 	for {
 		latest, err = b.GetLatestBlockNumber()
 		if err == nil {
-			tokens.SetLatestBlockHeight(latest, b.IsSrc)
+			tokens.SetLatestBlockHeight(b.PairID, latest, b.IsSrc)
 			log.Info("get latst block number succeed.", "number", latest, "BlockChain", tokenCfg.BlockChain, "NetID", tokenCfg.NetID)
 			break
 		}
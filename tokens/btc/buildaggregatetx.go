@@ -11,11 +11,10 @@ import (
 	"github.com/fsn-dev/crossChain-Bridge/log"
 	"github.com/fsn-dev/crossChain-Bridge/tokens"
 	"github.com/fsn-dev/crossChain-Bridge/tokens/btc/electrs"
-	"github.com/fsn-dev/crossChain-Bridge/tokens/tools"
 )
 
-// BuildAggregateTransaction build aggregate tx (spend p2sh utxo)
-func (b *Bridge) BuildAggregateTransaction(addrs []string, utxos []*electrs.ElectUtxo) (rawTx *txauthor.AuthoredTx, err error) {
+// BuildAggregateTransaction build aggregate tx (spend p2sh and p2wsh utxo)
+func (b *Bridge) BuildAggregateTransaction(addrs []string, utxos []*electrs.ElectUtxo, relayFeePerKb int64) (rawTx *txauthor.AuthoredTx, err error) {
 	if len(addrs) != len(utxos) {
 		return nil, fmt.Errorf("call BuildAggregateTransaction: count of addrs (%v) is not equal to count of utxos (%v)", len(addrs), len(utxos))
 	}
@@ -28,17 +27,23 @@ func (b *Bridge) BuildAggregateTransaction(addrs []string, utxos []*electrs.Elec
 		return b.getPayToAddrScript(b.TokenConfig.DcrmAddress)
 	}
 
-	relayFeePerKb := btcutil.Amount(tokens.BtcRelayFeePerKb)
+	if relayFeePerKb > tokens.BtcMaxRelayFeePerKb {
+		return nil, tokens.ErrRelayFeeExceedLimit
+	}
 
-	return NewUnsignedTransaction(nil, relayFeePerKb, inputSource, changeSource)
+	return NewUnsignedTransaction(nil, btcutil.Amount(relayFeePerKb), inputSource, changeSource)
 }
 
-func (b *Bridge) rebuildAggregateTransaction(prevOutPoints []*tokens.BtcOutPoint) (rawTx *txauthor.AuthoredTx, err error) {
-	addrs, utxos, err := b.getUtxosFromOutPoints(prevOutPoints)
+func (b *Bridge) rebuildAggregateTransaction(extra *tokens.BtcExtraArgs) (rawTx *txauthor.AuthoredTx, err error) {
+	addrs, utxos, err := b.getUtxosFromOutPoints(extra.PreviousOutPoints)
 	if err != nil {
 		return nil, err
 	}
-	return b.BuildAggregateTransaction(addrs, utxos)
+	relayFeePerKb := tokens.BtcRelayFeePerKb
+	if extra.RelayFeePerKb != nil {
+		relayFeePerKb = *extra.RelayFeePerKb
+	}
+	return b.BuildAggregateTransaction(addrs, utxos, relayFeePerKb)
 }
 
 func (b *Bridge) getUtxosFromElectUtxos(target btcutil.Amount, addrs []string, utxos []*electrs.ElectUtxo) (total btcutil.Amount, inputs []*wire.TxIn, inputValues []btcutil.Amount, scripts [][]byte, err error) {
@@ -57,12 +62,17 @@ func (b *Bridge) getUtxosFromElectUtxos(target btcutil.Amount, addrs []string, u
 		}
 
 		address := addrs[i]
-		if b.IsP2shAddress(address) {
-			bindAddr := tools.GetP2shBindAddress(address)
+		isP2sh, isP2wsh := b.IsP2shAddress(address), b.IsP2wshAddress(address)
+		if isP2sh || isP2wsh {
+			bindAddr := b.getP2shBindAddress(address)
 			if bindAddr == "" {
 				continue
 			}
-			p2shAddr, _, _ = b.GetP2shAddress(bindAddr)
+			if isP2wsh {
+				p2shAddr, _, _ = b.GetP2wshAddress(bindAddr)
+			} else {
+				p2shAddr, _, _ = b.GetP2shAddress(bindAddr)
+			}
 			if p2shAddr != address {
 				log.Warn("wrong registered p2sh address", "have", address, "bind", bindAddr, "want", p2shAddr)
 				continue
@@ -6,6 +6,7 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/btcsuite/btcd/blockchain"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
@@ -14,7 +15,6 @@ import (
 	"github.com/btcsuite/btcwallet/wallet/txrules"
 	"github.com/btcsuite/btcwallet/wallet/txsizes"
 	"github.com/fsn-dev/crossChain-Bridge/log"
-	"github.com/fsn-dev/crossChain-Bridge/params"
 	"github.com/fsn-dev/crossChain-Bridge/tokens"
 	"github.com/fsn-dev/crossChain-Bridge/tokens/btc/electrs"
 )
@@ -22,7 +22,12 @@ import (
 const (
 	p2pkhType    = "p2pkh"
 	p2shType     = "p2sh"
+	p2wpkhType   = "v0_p2wpkh"
+	p2wshType    = "v0_p2wsh"
 	opReturnType = "op_return"
+
+	// signal opt-in replace-by-fee (BIP125)
+	rbfTxInSequence = wire.MaxTxInSequenceNum - 2
 )
 
 // BuildRawTransaction build raw tx
@@ -39,10 +44,23 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 
 	switch args.SwapType {
 	case tokens.SwapinType:
-		return nil, tokens.ErrSwapTypeNotSupported
-	case tokens.SwapoutType, tokens.SwapRecallType:
-		from = token.DcrmAddress                        // from
-		amount = tokens.CalcSwappedValue(amount, false) // amount
+		if b.IsSrc {
+			return nil, tokens.ErrBuildSwapTxInWrongEndpoint
+		}
+		from = token.DcrmAddress                                 // from
+		amount = tokens.CalcSwappedValue(b.PairID, amount, true) // amount
+	case tokens.SwapoutType:
+		if !b.IsSrc {
+			return nil, tokens.ErrBuildSwapTxInWrongEndpoint
+		}
+		from = token.DcrmAddress                                  // from
+		amount = tokens.CalcSwappedValue(b.PairID, amount, false) // amount
+	case tokens.SwapRecallType:
+		if !b.IsSrc {
+			return nil, tokens.ErrBuildSwapTxInWrongEndpoint
+		}
+		from = token.DcrmAddress                          // from
+		amount = tokens.CalcRecallValue(b.PairID, amount) // amount
 	}
 
 	if from == "" {
@@ -64,9 +82,14 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 	}
 
 	if extra.RelayFeePerKb != nil {
+		if *extra.RelayFeePerKb > tokens.BtcMaxRelayFeePerKb {
+			return nil, tokens.ErrRelayFeeExceedLimit
+		}
 		relayFeePerKb = btcutil.Amount(*extra.RelayFeePerKb)
 	} else {
-		relayFeePerKb = btcutil.Amount(tokens.BtcRelayFeePerKb)
+		feePerKb := b.getRelayFeePerKb()
+		extra.RelayFeePerKb = &feePerKb
+		relayFeePerKb = btcutil.Amount(feePerKb)
 	}
 
 	txOuts, err := b.getTxOutputs(to, amount, memo)
@@ -74,11 +97,18 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 		return nil, err
 	}
 
+	selectInputs := len(extra.PreviousOutPoints) == 0
+	if selectInputs {
+		// prevent concurrent swap txs from selecting the same utxos
+		b.utxoIndex.Lock()
+		defer b.utxoIndex.Unlock()
+	}
+
 	inputSource := func(target btcutil.Amount) (total btcutil.Amount, inputs []*wire.TxIn, inputValues []btcutil.Amount, scripts [][]byte, err error) {
-		if len(extra.PreviousOutPoints) != 0 {
-			return b.getUtxos(from, target, extra.PreviousOutPoints)
+		if !selectInputs {
+			return b.getUtxos(from, target, extra.PreviousOutPoints, memo)
 		}
-		return b.selectUtxos(from, target)
+		return b.selectUtxos(from, target, relayFeePerKb)
 	}
 
 	changeSource := func() ([]byte, error) {
@@ -90,7 +120,7 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 		return nil, err
 	}
 
-	if len(extra.PreviousOutPoints) == 0 {
+	if selectInputs {
 		extra.PreviousOutPoints = make([]*tokens.BtcOutPoint, len(authoredTx.Tx.TxIn))
 		for i, txin := range authoredTx.Tx.TxIn {
 			point := txin.PreviousOutPoint
@@ -99,10 +129,11 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 				Index: point.Index,
 			}
 		}
+		b.utxoIndex.lockUtxos(extra.PreviousOutPoints, swapLockPrefix+args.SwapID)
 	}
 
 	if args.SwapType != tokens.NoSwapType {
-		args.Identifier = params.GetIdentifier()
+		args.Identifier = b.PairID
 	}
 
 	return authoredTx, nil
@@ -128,11 +159,11 @@ func (b *Bridge) getTxOutputs(to string, amount *big.Int, memo string) (txOuts [
 
 func (b *Bridge) getPayToAddrScript(address string) ([]byte, error) {
 	chainConfig := b.GetChainConfig()
-	toAddr, err := btcutil.DecodeAddress(address, chainConfig)
+	toAddr, err := DecodeAddress(address, chainConfig)
 	if err != nil {
 		return nil, err
 	}
-	return txscript.PayToAddrScript(toAddr)
+	return PayToAddrScript(toAddr)
 }
 
 func (b *Bridge) findUxtosWithRetry(from string) (utxos []*electrs.ElectUtxo, err error) {
@@ -168,72 +199,73 @@ func (b *Bridge) getOutspendWithRetry(point *tokens.BtcOutPoint) (outspend *elec
 	return outspend, err
 }
 
-func (b *Bridge) selectUtxos(from string, target btcutil.Amount) (total btcutil.Amount, inputs []*wire.TxIn, inputValues []btcutil.Amount, scripts [][]byte, err error) {
-	p2pkhScript, err := b.getPayToAddrScript(from)
+// isSpendablePkhType only p2pkh and p2wpkh outputs of sender are spent directly
+func isSpendablePkhType(scriptType string) bool {
+	return scriptType == p2pkhType || scriptType == p2wpkhType
+}
+
+// selectUtxos select utxos of sender from local utxo index with the configed coin selection strategy.
+// confirmed utxos are selected first, unconfirmed utxos are selected only if confirmed ones are not enough.
+func (b *Bridge) selectUtxos(from string, target, relayFeePerKb btcutil.Amount) (total btcutil.Amount, inputs []*wire.TxIn, inputValues []btcutil.Amount, scripts [][]byte, err error) {
+	pkScript, err := b.getPayToAddrScript(from)
 	if err != nil {
 		return 0, nil, nil, nil, err
 	}
 
-	utxos, err := b.findUxtosWithRetry(from)
+	utxos, err := b.utxoIndex.loadUtxos(from)
 	if err != nil {
 		return 0, nil, nil, nil, err
 	}
 
-	var (
-		tx      *electrs.ElectTx
-		success bool
-	)
-
-	for _, utxo := range utxos {
-		value := btcutil.Amount(*utxo.Value)
-		if value <= 0 {
-			continue
-		}
-		if value > btcutil.MaxSatoshi {
-			continue
-		}
-		tx, err = b.getTransactionByHashWithRetry(*utxo.Txid)
-		if err != nil {
-			continue
-		}
-		if *utxo.Vout >= uint32(len(tx.Vout)) {
-			continue
-		}
-		output := tx.Vout[*utxo.Vout]
-		if *output.ScriptpubkeyType != p2pkhType {
-			continue
-		}
-		if *output.ScriptpubkeyAddress != from {
-			continue
-		}
-		txHash, err2 := chainhash.NewHashFromStr(*utxo.Txid)
-		if err2 != nil {
-			continue
-		}
-		preOut := wire.NewOutPoint(txHash, *utxo.Vout)
-		txIn := wire.NewTxIn(preOut, p2pkhScript, nil)
+	selector := GetCoinSelector(tokens.BtcCoinSelection)
+	if selector == nil {
+		return 0, nil, nil, nil, fmt.Errorf("unknown coin selection %v", tokens.BtcCoinSelection)
+	}
 
-		total += value
-		inputs = append(inputs, txIn)
-		inputValues = append(inputValues, value)
-		scripts = append(scripts, p2pkhScript)
+	// target has counted the fee of tx without inputs
+	costPerInput := txrules.FeeForSerializeSize(relayFeePerKb, estimateVirtualSize([][]byte{pkScript}, nil, nil)-estimateVirtualSize(nil, nil, nil))
+	costOfChange := txrules.GetDustThreshold(len(pkScript), txrules.DefaultRelayFeePerKb) - 1
 
-		if total >= target {
-			success = true
-			break
+	confirmed := make([]*Utxo, 0, len(utxos))
+	for _, utxo := range utxos {
+		if utxo.Height > 0 {
+			confirmed = append(confirmed, utxo)
 		}
 	}
 
-	if !success {
+	selected := selector(confirmed, target, costPerInput, costOfChange)
+	if selected == nil && len(confirmed) < len(utxos) {
+		selected = selector(utxos, target, costPerInput, costOfChange)
+	}
+	if selected == nil {
+		for _, utxo := range utxos {
+			total += utxo.Value
+		}
 		err = fmt.Errorf("not enough balance, total %v < target %v", total, target)
 		return 0, nil, nil, nil, err
 	}
 
+	for _, utxo := range selected {
+		txHash, errh := chainhash.NewHashFromStr(utxo.Hash)
+		if errh != nil {
+			return 0, nil, nil, nil, errh
+		}
+		preOut := wire.NewOutPoint(txHash, utxo.Index)
+		txIn := wire.NewTxIn(preOut, pkScript, nil)
+
+		total += utxo.Value
+		inputs = append(inputs, txIn)
+		inputValues = append(inputValues, utxo.Value)
+		scripts = append(scripts, pkScript)
+	}
+
+	log.Debug("select utxos", "from", from, "strategy", tokens.BtcCoinSelection, "target", target, "total", total, "inputs", len(inputs))
 	return total, inputs, inputValues, scripts, nil
 }
 
-func (b *Bridge) getUtxos(from string, target btcutil.Amount, prevOutPoints []*tokens.BtcOutPoint) (total btcutil.Amount, inputs []*wire.TxIn, inputValues []btcutil.Amount, scripts [][]byte, err error) {
-	p2pkhScript, err := b.getPayToAddrScript(from)
+// getUtxos get utxos of out points, out points spent in txpool by replaceable tx with the same memo are allowed
+func (b *Bridge) getUtxos(from string, target btcutil.Amount, prevOutPoints []*tokens.BtcOutPoint, replaceMemo string) (total btcutil.Amount, inputs []*wire.TxIn, inputValues []btcutil.Amount, scripts [][]byte, err error) {
+	pkScript, err := b.getPayToAddrScript(from)
 	if err != nil {
 		return 0, nil, nil, nil, err
 	}
@@ -249,7 +281,7 @@ func (b *Bridge) getUtxos(from string, target btcutil.Amount, prevOutPoints []*t
 		if err != nil {
 			return 0, nil, nil, nil, err
 		}
-		if *outspend.Spent {
+		if *outspend.Spent && !b.isSpentByReplaceableTx(outspend, replaceMemo) {
 			if outspend.Status != nil && outspend.Status.BlockHeight != nil {
 				spentHeight := *outspend.Status.BlockHeight
 				err = fmt.Errorf("out point (%v, %v) is spent at %v", point.Hash, point.Index, spentHeight)
@@ -267,8 +299,8 @@ func (b *Bridge) getUtxos(from string, target btcutil.Amount, prevOutPoints []*t
 			return 0, nil, nil, nil, err
 		}
 		output := tx.Vout[point.Index]
-		if *output.ScriptpubkeyType != p2pkhType {
-			err = fmt.Errorf("out point (%v, %v) script pubkey type %v is not p2pkh", point.Hash, point.Index, *output.ScriptpubkeyType)
+		if !isSpendablePkhType(*output.ScriptpubkeyType) {
+			err = fmt.Errorf("out point (%v, %v) script pubkey type %v is not p2pkh or p2wpkh", point.Hash, point.Index, *output.ScriptpubkeyType)
 			return 0, nil, nil, nil, err
 		}
 		if *output.ScriptpubkeyAddress != from {
@@ -283,12 +315,12 @@ func (b *Bridge) getUtxos(from string, target btcutil.Amount, prevOutPoints []*t
 
 		txHash, _ = chainhash.NewHashFromStr(point.Hash)
 		prevOutPoint := wire.NewOutPoint(txHash, point.Index)
-		txIn := wire.NewTxIn(prevOutPoint, p2pkhScript, nil)
+		txIn := wire.NewTxIn(prevOutPoint, pkScript, nil)
 
 		total += value
 		inputs = append(inputs, txIn)
 		inputValues = append(inputValues, value)
-		scripts = append(scripts, p2pkhScript)
+		scripts = append(scripts, pkScript)
 	}
 	if total < target {
 		err = fmt.Errorf("not enough balance, total %v < target %v", total, target)
@@ -304,12 +336,73 @@ func (insufficientFundsError) Error() string {
 	return "insufficient funds available to construct transaction"
 }
 
+const (
+	// bind memo of p2sh/p2wsh deposit address is a 20 bytes address usually, the redeem script is
+	// <20 bytes memo> OP_DROP OP_DUP OP_HASH160 <20 bytes pubkey hash> OP_EQUALVERIFY OP_CHECKSIG
+	memoRedeemScriptSize = 21 + 1 + 1 + 1 + 21 + 1 + 1
+
+	// outpoint 36 + sigscript varint 1 + sigscript (sig 1+73, pubkey 1+33, redeem script 1+47) + sequence 4
+	redeemP2shMemoInputSize = 36 + 1 + 74 + 34 + 1 + memoRedeemScriptSize + 4
+
+	// witness items count 1 + sig 1+73 + pubkey 1+33 + witness script 1+47
+	redeemP2wshMemoInputWitnessWeight = 1 + 74 + 34 + 1 + memoRedeemScriptSize
+)
+
+// estimateVirtualSize estimate vsize of signed tx which spends outputs of pkScripts,
+// every output (including taproot output and the optional change output) is counted by its real size.
+func estimateVirtualSize(pkScripts [][]byte, txOuts []*wire.TxOut, changeScript []byte) int {
+	outputCount := len(txOuts)
+	baseSize := 0
+	for _, txOut := range txOuts {
+		baseSize += txOut.SerializeSize()
+	}
+	if len(changeScript) != 0 {
+		baseSize += wire.NewTxOut(0, changeScript).SerializeSize()
+		outputCount++
+	}
+
+	// version 4 + locktime 4 + varint of inputs and outputs count
+	baseSize += 8 + wire.VarIntSerializeSize(uint64(len(pkScripts))) + wire.VarIntSerializeSize(uint64(outputCount))
+
+	var witnessWeight, witnessInputs int
+	for _, pkScript := range pkScripts {
+		switch {
+		case txscript.IsPayToScriptHash(pkScript):
+			baseSize += redeemP2shMemoInputSize
+		case txscript.IsPayToWitnessPubKeyHash(pkScript):
+			baseSize += txsizes.RedeemP2WPKHInputSize
+			witnessWeight += txsizes.RedeemP2WPKHInputWitnessWeight
+			witnessInputs++
+		case txscript.IsPayToWitnessScriptHash(pkScript):
+			baseSize += txsizes.RedeemP2WPKHInputSize
+			witnessWeight += redeemP2wshMemoInputWitnessWeight
+			witnessInputs++
+		default:
+			baseSize += txsizes.RedeemP2PKHInputSize
+		}
+	}
+
+	if witnessInputs > 0 {
+		// segwit marker and flag 2, and empty witness of every non witness input 1
+		witnessWeight += 2 + len(pkScripts) - witnessInputs
+	}
+
+	// round up
+	return baseSize + (witnessWeight+3)/blockchain.WitnessScaleFactor
+}
+
 // NewUnsignedTransaction ref btcwallet
 // ref. https://github.com/btcsuite/btcwallet/blob/b07494fc2d662fdda2b8a9db2a3eacde3e1ef347/wallet/txauthor/author.go
-// we only modify it to support P2PKH change script (the origin only support P2WPKH change script)
+// we modify it to support P2PKH change script (the origin only support P2WPKH change script),
+// and to estimate size with the real input and output types (P2SH, P2WSH, taproot etc.)
 func NewUnsignedTransaction(outputs []*wire.TxOut, relayFeePerKb btcutil.Amount, fetchInputs txauthor.InputSource, fetchChange txauthor.ChangeSource) (*txauthor.AuthoredTx, error) {
+	changeScript, err := fetchChange()
+	if err != nil {
+		return nil, err
+	}
+
 	targetAmount := txauthor.SumOutputValues(outputs)
-	estimatedSize := txsizes.EstimateVirtualSize(0, 1, 0, outputs, true)
+	estimatedSize := estimateVirtualSize(nil, outputs, changeScript)
 	targetFee := txrules.FeeForSerializeSize(relayFeePerKb, estimatedSize)
 
 	for {
@@ -321,23 +414,7 @@ func NewUnsignedTransaction(outputs []*wire.TxOut, relayFeePerKb btcutil.Amount,
 			return nil, insufficientFundsError{}
 		}
 
-		// We count the types of inputs, which we'll use to estimate
-		// the vsize of the transaction.
-		var nested, p2wpkh, p2pkh int
-		for _, pkScript := range scripts {
-			switch {
-			// If this is a p2sh output, we assume this is a
-			// nested P2WKH.
-			case txscript.IsPayToScriptHash(pkScript):
-				nested++
-			case txscript.IsPayToWitnessPubKeyHash(pkScript):
-				p2wpkh++
-			default:
-				p2pkh++
-			}
-		}
-
-		maxSignedSize := txsizes.EstimateVirtualSize(p2pkh, p2wpkh, nested, outputs, true)
+		maxSignedSize := estimateVirtualSize(scripts, outputs, changeScript)
 		maxRequiredFee := txrules.FeeForSerializeSize(relayFeePerKb, maxSignedSize)
 		if maxRequiredFee < btcutil.Amount(tokens.BtcMinRelayFee) {
 			maxRequiredFee = btcutil.Amount(tokens.BtcMinRelayFee)
@@ -348,6 +425,10 @@ func NewUnsignedTransaction(outputs []*wire.TxOut, relayFeePerKb btcutil.Amount,
 			continue
 		}
 
+		for _, txin := range inputs {
+			txin.Sequence = rbfTxInSequence
+		}
+
 		unsignedTransaction := &wire.MsgTx{
 			Version:  wire.TxVersion,
 			TxIn:     inputs,
@@ -357,10 +438,6 @@ func NewUnsignedTransaction(outputs []*wire.TxOut, relayFeePerKb btcutil.Amount,
 		changeIndex := -1
 		changeAmount := inputAmount - targetAmount - maxRequiredFee
 		if changeAmount != 0 {
-			changeScript, err := fetchChange()
-			if err != nil {
-				return nil, err
-			}
 			// commont this to support P2PKH change script
 			// if len(changeScript) > txsizes.P2WPKHPkScriptSize {
 			//	return nil, errors.New("fee estimation requires change " +
@@ -1,60 +1,69 @@
 package btc
 
 import (
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
 	"github.com/fsn-dev/crossChain-Bridge/tokens/btc/electrs"
 )
 
 // GetLatestBlockNumber impl
 func (b *Bridge) GetLatestBlockNumber() (uint64, error) {
-	return electrs.GetLatestBlockNumber(b)
+	if b.GatewayConfig.IsQuorumEnabled() {
+		return tokens.QuorumGetLatestBlockNumber(b)
+	}
+	return b.gateway.GetLatestBlockNumber()
 }
 
 // GetTransactionByHash impl
 func (b *Bridge) GetTransactionByHash(txHash string) (*electrs.ElectTx, error) {
-	return electrs.GetTransactionByHash(b, txHash)
+	return b.gateway.GetTransactionByHash(txHash)
 }
 
 // GetElectTransactionStatus impl
 func (b *Bridge) GetElectTransactionStatus(txHash string) (*electrs.ElectTxStatus, error) {
-	return electrs.GetElectTransactionStatus(b, txHash)
+	return b.gateway.GetElectTransactionStatus(txHash)
 }
 
 // FindUtxos impl
 func (b *Bridge) FindUtxos(addr string) ([]*electrs.ElectUtxo, error) {
-	return electrs.FindUtxos(b, addr)
+	return b.gateway.FindUtxos(addr)
 }
 
 // GetPoolTxidList impl
 func (b *Bridge) GetPoolTxidList() ([]string, error) {
-	return electrs.GetPoolTxidList(b)
+	return b.gateway.GetPoolTxidList()
 }
 
 // GetPoolTransactions impl
 func (b *Bridge) GetPoolTransactions(addr string) ([]*electrs.ElectTx, error) {
-	return electrs.GetPoolTransactions(b, addr)
+	return b.gateway.GetPoolTransactions(addr)
 }
 
 // GetTransactionHistory impl
 func (b *Bridge) GetTransactionHistory(addr, lastSeenTxid string) ([]*electrs.ElectTx, error) {
-	return electrs.GetTransactionHistory(b, addr, lastSeenTxid)
+	return b.gateway.GetTransactionHistory(addr, lastSeenTxid)
 }
 
 // GetOutspend impl
 func (b *Bridge) GetOutspend(txHash string, vout uint32) (*electrs.ElectOutspend, error) {
-	return electrs.GetOutspend(b, txHash, vout)
+	return b.gateway.GetOutspend(txHash, vout)
 }
 
 // PostTransaction impl
 func (b *Bridge) PostTransaction(txHex string) (txHash string, err error) {
-	return electrs.PostTransaction(b, txHex)
+	return b.gateway.PostTransaction(txHex)
 }
 
 // GetBlockHash impl
 func (b *Bridge) GetBlockHash(height uint64) (string, error) {
-	return electrs.GetBlockHash(b, height)
+	return b.gateway.GetBlockHash(height)
 }
 
 // GetBlockTxids impl
 func (b *Bridge) GetBlockTxids(blockHash string) ([]string, error) {
-	return electrs.GetBlockTxids(b, blockHash)
+	return b.gateway.GetBlockTxids(blockHash)
+}
+
+// GetFeeEstimates impl
+func (b *Bridge) GetFeeEstimates() (map[string]float64, error) {
+	return b.gateway.GetFeeEstimates()
 }
@@ -0,0 +1,151 @@
+package btc
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/btcsuite/btcutil"
+)
+
+// coin selection strategies
+const (
+	CoinSelectBranchAndBound = "branchandbound" // find inputs without change, fallback to largest first
+	CoinSelectLargestFirst   = "largestfirst"   // less inputs
+	CoinSelectOldestFirst    = "oldestfirst"    // consolidate old utxos
+
+	// max tries of branch and bound search (same as bitcoin core)
+	bnbMaxTries = 100000
+)
+
+// Utxo unspent output in local utxo index
+type Utxo struct {
+	Hash   string
+	Index  uint32
+	Value  btcutil.Amount
+	Height uint64 // 0 means unconfirmed
+}
+
+// CoinSelector select utxos to cover target value.
+// costPerInput is the fee of spending one input, which is added to target for every selected input.
+// costOfChange is the max value which can be given up as fee instead of making a change output.
+// return nil if utxos are not enough.
+type CoinSelector func(utxos []*Utxo, target, costPerInput, costOfChange btcutil.Amount) []*Utxo
+
+var coinSelectors = map[string]CoinSelector{
+	CoinSelectBranchAndBound: selectBranchAndBound,
+	CoinSelectLargestFirst:   selectLargestFirst,
+	CoinSelectOldestFirst:    selectOldestFirst,
+}
+
+// RegisterCoinSelector register custom coin selection strategy
+func RegisterCoinSelector(name string, selector CoinSelector) {
+	coinSelectors[strings.ToLower(name)] = selector
+}
+
+// GetCoinSelector get coin selection strategy by name (nil if not exist)
+func GetCoinSelector(name string) CoinSelector {
+	return coinSelectors[strings.ToLower(name)]
+}
+
+// selectInOrder select utxos in order until total value covers target
+func selectInOrder(utxos []*Utxo, target, costPerInput btcutil.Amount) []*Utxo {
+	var total btcutil.Amount
+	for i, utxo := range utxos {
+		total += utxo.Value - costPerInput
+		if total >= target {
+			return utxos[:i+1]
+		}
+	}
+	return nil
+}
+
+func selectLargestFirst(utxos []*Utxo, target, costPerInput, costOfChange btcutil.Amount) []*Utxo {
+	sorted := append([]*Utxo{}, utxos...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Value > sorted[j].Value
+	})
+	return selectInOrder(sorted, target, costPerInput)
+}
+
+func selectOldestFirst(utxos []*Utxo, target, costPerInput, costOfChange btcutil.Amount) []*Utxo {
+	sorted := append([]*Utxo{}, utxos...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		hi, hj := sorted[i].Height, sorted[j].Height
+		if hi == 0 || hj == 0 {
+			return hj == 0 && hi != 0 // unconfirmed at last
+		}
+		return hi < hj
+	})
+	return selectInOrder(sorted, target, costPerInput)
+}
+
+// selectBranchAndBound search inputs whose effective value is in [target, target+costOfChange],
+// so that no change output is needed (ref. bitcoin core SelectCoinsBnB).
+// fallback to largest first if no such inputs is found.
+func selectBranchAndBound(utxos []*Utxo, target, costPerInput, costOfChange btcutil.Amount) []*Utxo {
+	candidates := make([]*Utxo, 0, len(utxos))
+	for _, utxo := range utxos {
+		if utxo.Value > costPerInput {
+			candidates = append(candidates, utxo)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Value > candidates[j].Value
+	})
+
+	effValue := func(i int) btcutil.Amount {
+		return candidates[i].Value - costPerInput
+	}
+
+	// remains[i] is sum of effective values of candidates[i:]
+	remains := make([]btcutil.Amount, len(candidates)+1)
+	for i := len(candidates) - 1; i >= 0; i-- {
+		remains[i] = remains[i+1] + effValue(i)
+	}
+	if remains[0] < target {
+		return nil
+	}
+
+	var (
+		selected  = make([]bool, len(candidates))
+		best      []bool
+		bestWaste btcutil.Amount
+		tries     int
+	)
+
+	var search func(depth int, sum btcutil.Amount)
+	search = func(depth int, sum btcutil.Amount) {
+		tries++
+		switch {
+		case tries > bnbMaxTries, bestWaste == 0 && best != nil:
+			return
+		case sum > target+costOfChange:
+			return
+		case sum >= target:
+			if waste := sum - target; best == nil || waste < bestWaste {
+				best = append([]bool{}, selected...)
+				bestWaste = waste
+			}
+			return
+		case depth == len(candidates), sum+remains[depth] < target:
+			return
+		}
+		// try include the current candidate first, then exclude it
+		selected[depth] = true
+		search(depth+1, sum+effValue(depth))
+		selected[depth] = false
+		search(depth+1, sum)
+	}
+	search(0, 0)
+
+	if best == nil {
+		return selectLargestFirst(utxos, target, costPerInput, costOfChange)
+	}
+	result := make([]*Utxo, 0, len(best))
+	for i, isSelected := range best {
+		if isSelected {
+			result = append(result, candidates[i])
+		}
+	}
+	return result
+}
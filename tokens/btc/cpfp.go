@@ -0,0 +1,170 @@
+package btc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
+	"github.com/fsn-dev/crossChain-Bridge/mongodb"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+	"github.com/fsn-dev/crossChain-Bridge/tokens/btc/electrs"
+)
+
+const (
+	// CpfpIdentifier used in accepting
+	CpfpIdentifier = "cpfp"
+)
+
+var (
+	errNoChangeOutput   = errors.New("no dcrm change output to do cpfp")
+	errCpfpDustChange   = errors.New("cpfp change output is dust")
+	errCpfpWrongExtra   = errors.New("wrong cpfp extra")
+	errCpfpParentNoSwap = errors.New("cpfp parent is not swap tx")
+	errCpfpChangeLocked = errors.New("cpfp change output is locked")
+	errCpfpChangeSpent  = errors.New("cpfp change output is already spent")
+)
+
+// BuildAccelerateTransaction build child tx to accelerate pending swap tx by child-pays-for-parent
+// (spend the dcrm change output). args carries the swap info of the pending swap tx.
+// the change output is locked by the child tx, call UnlockUtxos if the child tx is never sent.
+func (b *Bridge) BuildAccelerateTransaction(args *tokens.BuildTxArgs, pendingTxHash string) (rawTx interface{}, err error) {
+	parent, err := b.GetTransactionByHash(pendingTxHash)
+	if err != nil {
+		return nil, err
+	}
+	if isTxConfirmed(parent) {
+		return nil, tokens.ErrTxIsNotPending
+	}
+	point, err := b.getDcrmChangeOutPoint(parent)
+	if err != nil {
+		return nil, err
+	}
+	relayFeePerKb := getCpfpFeePerKb(parent, b.getCpfpChildVsize())
+
+	args.Extra = &tokens.AllExtras{
+		BtcExtra: &tokens.BtcExtraArgs{
+			RelayFeePerKb:     &relayFeePerKb,
+			PreviousOutPoints: []*tokens.BtcOutPoint{point},
+		},
+	}
+	args.Identifier = CpfpIdentifier
+	args.PairID = b.PairID
+
+	// check and lock the change output in the same critical section as swap txs selecting utxos
+	b.utxoIndex.Lock()
+	defer b.utxoIndex.Unlock()
+
+	if mu, errf := mongodb.FindUtxo(outPointKey(point.Hash, point.Index)); errf == nil && mu.LockedBy != "" {
+		return nil, fmt.Errorf("%w, locked by %v", errCpfpChangeLocked, mu.LockedBy)
+	}
+	outspend, err := b.getOutspendWithRetry(point)
+	if err != nil {
+		return nil, err
+	}
+	if *outspend.Spent {
+		return nil, errCpfpChangeSpent
+	}
+
+	authoredTx, err := b.buildCpfpTransaction(args.Extra.BtcExtra)
+	if err != nil {
+		return nil, err
+	}
+	// the change output should not be selected by swap txs meanwhile
+	b.utxoIndex.lockUtxos(args.Extra.BtcExtra.PreviousOutPoints, cpfpLockPrefix+args.SwapID)
+	return authoredTx, nil
+}
+
+// VerifyCpfpMsgHash verify cpfp msgHash, return the rebuilt msgHash
+func (b *Bridge) VerifyCpfpMsgHash(msgHash []string, args *tokens.BuildTxArgs) (rebuiltMsgHash []string, err error) {
+	if args == nil || args.Extra == nil || args.Extra.BtcExtra == nil {
+		return nil, errCpfpWrongExtra
+	}
+	extra := args.Extra.BtcExtra
+	if len(extra.PreviousOutPoints) != 1 || extra.RelayFeePerKb == nil {
+		return nil, errCpfpWrongExtra
+	}
+	parent, err := b.getTransactionByHashWithRetry(extra.PreviousOutPoints[0].Hash)
+	if err != nil {
+		return nil, err
+	}
+	if isTxConfirmed(parent) {
+		return nil, tokens.ErrTxIsNotPending
+	}
+	memo := getTxMemo(parent)
+	if !strings.HasPrefix(memo, tokens.UnlockMemoPrefix) && !strings.HasPrefix(memo, tokens.RecallMemoPrefix) {
+		return nil, errCpfpParentNoSwap
+	}
+	rawTx, err := b.buildCpfpTransaction(extra)
+	if err != nil {
+		return nil, err
+	}
+	rebuiltMsgHash, _ = b.CalcMsgHash(rawTx)
+	return rebuiltMsgHash, b.VerifyMsgHash(rawTx, msgHash, args.Extra)
+}
+
+// buildCpfpTransaction build child tx which spends dcrm change output back to dcrm address
+func (b *Bridge) buildCpfpTransaction(extra *tokens.BtcExtraArgs) (*txauthor.AuthoredTx, error) {
+	if *extra.RelayFeePerKb > tokens.BtcMaxRelayFeePerKb {
+		return nil, tokens.ErrRelayFeeExceedLimit
+	}
+	dcrmAddress := b.TokenConfig.DcrmAddress
+
+	inputSource := func(target btcutil.Amount) (total btcutil.Amount, inputs []*wire.TxIn, inputValues []btcutil.Amount, scripts [][]byte, err error) {
+		return b.getUtxos(dcrmAddress, target, extra.PreviousOutPoints, "")
+	}
+
+	changeSource := func() ([]byte, error) {
+		return b.getPayToAddrScript(dcrmAddress)
+	}
+
+	relayFeePerKb := btcutil.Amount(*extra.RelayFeePerKb)
+	authoredTx, err := NewUnsignedTransaction(nil, relayFeePerKb, inputSource, changeSource)
+	if err != nil {
+		return nil, err
+	}
+	if len(authoredTx.Tx.TxOut) == 0 {
+		return nil, errCpfpDustChange
+	}
+	return authoredTx, nil
+}
+
+func (b *Bridge) getDcrmChangeOutPoint(tx *electrs.ElectTx) (*tokens.BtcOutPoint, error) {
+	dcrmAddress := b.TokenConfig.DcrmAddress
+	for i, output := range tx.Vout {
+		if !isSpendablePkhType(*output.ScriptpubkeyType) {
+			continue
+		}
+		if output.ScriptpubkeyAddress == nil || *output.ScriptpubkeyAddress != dcrmAddress {
+			continue
+		}
+		return &tokens.BtcOutPoint{
+			Hash:  *tx.Txid,
+			Index: uint32(i),
+		}, nil
+	}
+	return nil, errNoChangeOutput
+}
+
+// getCpfpChildVsize estimate vsize of child tx which spends one dcrm output to dcrm address
+func (b *Bridge) getCpfpChildVsize() int64 {
+	dcrmScript, _ := b.getPayToAddrScript(b.TokenConfig.DcrmAddress)
+	return int64(estimateVirtualSize([][]byte{dcrmScript}, nil, dcrmScript))
+}
+
+// getCpfpFeePerKb calc fee rate of child tx to let parent and child reach the bumped fee rate
+func getCpfpFeePerKb(parent *electrs.ElectTx, childVsize int64) int64 {
+	targetFeePerKb := getBumpedFeePerKb(getTxFeePerKb(parent))
+	parentVsize := getTxVsize(parent)
+	childFee := targetFeePerKb*(parentVsize+childVsize)/1000 - int64(*parent.Fee)
+	feePerKb := childFee*1000/childVsize + 1
+	if feePerKb < tokens.BtcRelayFeePerKb {
+		feePerKb = tokens.BtcRelayFeePerKb
+	}
+	if feePerKb > tokens.BtcMaxRelayFeePerKb {
+		feePerKb = tokens.BtcMaxRelayFeePerKb
+	}
+	return feePerKb
+}
@@ -0,0 +1,51 @@
+package btc
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/fsn-dev/crossChain-Bridge/log"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+)
+
+// getRelayFeePerKb get relay fee per kb from fee estimates of the configed confirmation target,
+// fallback to the configed RelayFeePerKb if estimation is disabled or failed.
+func (b *Bridge) getRelayFeePerKb() int64 {
+	relayFeePerKb := tokens.BtcRelayFeePerKb
+	if tokens.BtcEstimateFeeBlocks > 0 {
+		estimates, err := b.GetFeeEstimates()
+		if err != nil {
+			log.Warn(b.TokenConfig.BlockChain+" get fee estimates failed", "err", err)
+		} else if feeRate, ok := chooseFeeEstimate(estimates, tokens.BtcEstimateFeeBlocks); ok {
+			relayFeePerKb = int64(math.Ceil(feeRate * 1000)) // sat/vB to sat/kB
+		} else {
+			log.Warn(b.TokenConfig.BlockChain+" no fee estimate available", "blocks", tokens.BtcEstimateFeeBlocks)
+		}
+	}
+	if relayFeePerKb < tokens.BtcMinRelayFeePerKb {
+		relayFeePerKb = tokens.BtcMinRelayFeePerKb
+	}
+	if relayFeePerKb > tokens.BtcMaxRelayFeePerKb {
+		relayFeePerKb = tokens.BtcMaxRelayFeePerKb
+	}
+	return relayFeePerKb
+}
+
+// chooseFeeEstimate choose fee rate of the largest target not greater than blocks,
+// or of the smallest target if all targets are greater than blocks.
+func chooseFeeEstimate(estimates map[string]float64, blocks int) (feeRate float64, ok bool) {
+	chosen := 0
+	for key, rate := range estimates {
+		target, err := strconv.Atoi(key)
+		if err != nil || target <= 0 || rate <= 0 {
+			continue
+		}
+		switch {
+		case chosen == 0,
+			target <= blocks && (chosen > blocks || target > chosen),
+			target > blocks && chosen > blocks && target < chosen:
+			chosen, feeRate = target, rate
+		}
+	}
+	return feeRate, chosen != 0
+}
@@ -0,0 +1,83 @@
+package btc
+
+import (
+	"fmt"
+
+	"github.com/fsn-dev/crossChain-Bridge/log"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+	"github.com/fsn-dev/crossChain-Bridge/tokens/btc/electrs"
+)
+
+// FindSwapTx find swap tx of swap by the spending txs of the utxos in args extra,
+// swap tx has the memo of swap (eg. `SWAPTX:` memo output). as every swap tx spends these utxos,
+// the spending txs are enough and 'fromHeight' only limits the search of address history.
+func (b *Bridge) FindSwapTx(args *tokens.BuildTxArgs, fromHeight uint64) (txHash string, err error) {
+	if args.Extra == nil || args.Extra.BtcExtra == nil || len(args.Extra.BtcExtra.PreviousOutPoints) == 0 {
+		return "", nil
+	}
+	points := args.Extra.BtcExtra.PreviousOutPoints
+	for _, point := range points {
+		outspend, err := b.getOutspendWithRetry(point)
+		if err != nil {
+			return "", err
+		}
+		if !*outspend.Spent {
+			continue
+		}
+		if outspend.Txid == nil {
+			// spending tx is unknown (eg. bitcoind backend), search it in history of dcrm address
+			return b.findSwapTxInHistory(args, fromHeight)
+		}
+		tx, err := b.getTransactionByHashWithRetry(*outspend.Txid)
+		if err != nil {
+			return "", err
+		}
+		if getTxMemo(tx) != args.Memo {
+			log.Warn("[findswaptx] utxo is spent by another tx", "pairID", b.PairID, "swapID", args.SwapID, "utxo", fmt.Sprintf("%v:%v", point.Hash, point.Index), "txHash", *outspend.Txid)
+			return "", tokens.ErrSwapTxConflicted
+		}
+		log.Info("[findswaptx] found swap tx by utxo", "pairID", b.PairID, "swapID", args.SwapID, "utxo", fmt.Sprintf("%v:%v", point.Hash, point.Index), "txHash", *outspend.Txid)
+		return *outspend.Txid, nil
+	}
+	return "", nil
+}
+
+// findSwapTxInHistory find tx with the memo of swap in txpool and history of dcrm address.
+// utxos in args extra are known to be spent, so it's an error if the swap tx is not found.
+func (b *Bridge) findSwapTxInHistory(args *tokens.BuildTxArgs, fromHeight uint64) (string, error) {
+	dcrmAddress := b.TokenConfig.DcrmAddress
+	if poolTxs, err := b.GetPoolTransactions(dcrmAddress); err == nil {
+		if txHash := findTxWithMemo(poolTxs, dcrmAddress, args.Memo); txHash != "" {
+			return txHash, nil
+		}
+	}
+	lastSeenTxid := ""
+	for {
+		txHistory, err := b.GetTransactionHistory(dcrmAddress, lastSeenTxid)
+		if err != nil {
+			return "", err
+		}
+		if txHash := findTxWithMemo(txHistory, dcrmAddress, args.Memo); txHash != "" {
+			return txHash, nil
+		}
+		if len(txHistory) == 0 {
+			break
+		}
+		lastTx := txHistory[len(txHistory)-1]
+		if lastTx.Status != nil && lastTx.Status.BlockHeight != nil && *lastTx.Status.BlockHeight < fromHeight {
+			break
+		}
+		lastSeenTxid = *lastTx.Txid
+	}
+	return "", fmt.Errorf("utxos of swap %v are spent but swap tx is not found", args.SwapID)
+}
+
+// findTxWithMemo find tx sent from address with memo (deposits to address may have any memo)
+func findTxWithMemo(txs []*electrs.ElectTx, from, memo string) string {
+	for _, tx := range txs {
+		if tx.Txid != nil && getTxFrom(tx.Vin) == from && getTxMemo(tx) == memo {
+			return *tx.Txid
+		}
+	}
+	return ""
+}
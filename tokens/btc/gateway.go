@@ -0,0 +1,165 @@
+package btc
+
+import (
+	"strings"
+
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+	"github.com/fsn-dev/crossChain-Bridge/tokens/btc/bitcoind"
+	"github.com/fsn-dev/crossChain-Bridge/tokens/btc/electrs"
+)
+
+// gateway api types
+const (
+	GatewayElectrs  = "electrs"  // esplora REST API (default)
+	GatewayBitcoind = "bitcoind" // bitcoin core JSON-RPC (txindex is required)
+)
+
+// Gateway blockchain data source of btc bridge, results are in electrs format
+type Gateway interface {
+	GetLatestBlockNumber() (uint64, error)
+	GetTransactionByHash(txHash string) (*electrs.ElectTx, error)
+	GetElectTransactionStatus(txHash string) (*electrs.ElectTxStatus, error)
+	FindUtxos(addr string) ([]*electrs.ElectUtxo, error)
+	GetPoolTxidList() ([]string, error)
+	GetPoolTransactions(addr string) ([]*electrs.ElectTx, error)
+	GetTransactionHistory(addr, lastSeenTxid string) ([]*electrs.ElectTx, error)
+	GetOutspend(txHash string, vout uint32) (*electrs.ElectOutspend, error)
+	PostTransaction(txHex string) (txHash string, err error)
+	GetBlockHash(height uint64) (string, error)
+	GetBlockTxids(blockHash string) ([]string, error)
+	GetFeeEstimates() (map[string]float64, error)
+}
+
+// IsValidGatewayType is valid gateway api type (empty means electrs)
+func IsValidGatewayType(apiType string) bool {
+	switch strings.ToLower(apiType) {
+	case "", GatewayElectrs, GatewayBitcoind:
+		return true
+	default:
+		return false
+	}
+}
+
+// PinEndpoint clone bridge which only calls the specified gateway endpoint
+func (b *Bridge) PinEndpoint(url string) tokens.CrossChainBridge {
+	base := *b.CrossChainBridgeBase
+	base.GatewayConfig = b.GatewayConfig.PinEndpoint(url)
+	pinned := *b
+	pinned.CrossChainBridgeBase = &base
+	pinned.gateway = newGateway(&pinned, base.GatewayConfig.APIType)
+	return &pinned
+}
+
+// newGateway new gateway of api type
+func newGateway(b tokens.CrossChainBridge, apiType string) Gateway {
+	if strings.EqualFold(apiType, GatewayBitcoind) {
+		return &bitcoindGateway{b: b}
+	}
+	return &electrsGateway{b: b}
+}
+
+// electrsGateway call electrs (esplora) REST API
+type electrsGateway struct {
+	b tokens.CrossChainBridge
+}
+
+func (g *electrsGateway) GetLatestBlockNumber() (uint64, error) {
+	return electrs.GetLatestBlockNumber(g.b)
+}
+
+func (g *electrsGateway) GetTransactionByHash(txHash string) (*electrs.ElectTx, error) {
+	return electrs.GetTransactionByHash(g.b, txHash)
+}
+
+func (g *electrsGateway) GetElectTransactionStatus(txHash string) (*electrs.ElectTxStatus, error) {
+	return electrs.GetElectTransactionStatus(g.b, txHash)
+}
+
+func (g *electrsGateway) FindUtxos(addr string) ([]*electrs.ElectUtxo, error) {
+	return electrs.FindUtxos(g.b, addr)
+}
+
+func (g *electrsGateway) GetPoolTxidList() ([]string, error) {
+	return electrs.GetPoolTxidList(g.b)
+}
+
+func (g *electrsGateway) GetPoolTransactions(addr string) ([]*electrs.ElectTx, error) {
+	return electrs.GetPoolTransactions(g.b, addr)
+}
+
+func (g *electrsGateway) GetTransactionHistory(addr, lastSeenTxid string) ([]*electrs.ElectTx, error) {
+	return electrs.GetTransactionHistory(g.b, addr, lastSeenTxid)
+}
+
+func (g *electrsGateway) GetOutspend(txHash string, vout uint32) (*electrs.ElectOutspend, error) {
+	return electrs.GetOutspend(g.b, txHash, vout)
+}
+
+func (g *electrsGateway) PostTransaction(txHex string) (txHash string, err error) {
+	return electrs.PostTransaction(g.b, txHex)
+}
+
+func (g *electrsGateway) GetBlockHash(height uint64) (string, error) {
+	return electrs.GetBlockHash(g.b, height)
+}
+
+func (g *electrsGateway) GetBlockTxids(blockHash string) ([]string, error) {
+	return electrs.GetBlockTxids(g.b, blockHash)
+}
+
+func (g *electrsGateway) GetFeeEstimates() (map[string]float64, error) {
+	return electrs.GetFeeEstimates(g.b)
+}
+
+// bitcoindGateway call bitcoin core JSON-RPC
+type bitcoindGateway struct {
+	b tokens.CrossChainBridge
+}
+
+func (g *bitcoindGateway) GetLatestBlockNumber() (uint64, error) {
+	return bitcoind.GetLatestBlockNumber(g.b)
+}
+
+func (g *bitcoindGateway) GetTransactionByHash(txHash string) (*electrs.ElectTx, error) {
+	return bitcoind.GetTransactionByHash(g.b, txHash)
+}
+
+func (g *bitcoindGateway) GetElectTransactionStatus(txHash string) (*electrs.ElectTxStatus, error) {
+	return bitcoind.GetElectTransactionStatus(g.b, txHash)
+}
+
+func (g *bitcoindGateway) FindUtxos(addr string) ([]*electrs.ElectUtxo, error) {
+	return bitcoind.FindUtxos(g.b, addr)
+}
+
+func (g *bitcoindGateway) GetPoolTxidList() ([]string, error) {
+	return bitcoind.GetPoolTxidList(g.b)
+}
+
+func (g *bitcoindGateway) GetPoolTransactions(addr string) ([]*electrs.ElectTx, error) {
+	return bitcoind.GetPoolTransactions(g.b, addr)
+}
+
+func (g *bitcoindGateway) GetTransactionHistory(addr, lastSeenTxid string) ([]*electrs.ElectTx, error) {
+	return bitcoind.GetTransactionHistory(g.b, addr, lastSeenTxid)
+}
+
+func (g *bitcoindGateway) GetOutspend(txHash string, vout uint32) (*electrs.ElectOutspend, error) {
+	return bitcoind.GetOutspend(g.b, txHash, vout)
+}
+
+func (g *bitcoindGateway) PostTransaction(txHex string) (txHash string, err error) {
+	return bitcoind.PostTransaction(g.b, txHex)
+}
+
+func (g *bitcoindGateway) GetBlockHash(height uint64) (string, error) {
+	return bitcoind.GetBlockHash(g.b, height)
+}
+
+func (g *bitcoindGateway) GetBlockTxids(blockHash string) ([]string, error) {
+	return bitcoind.GetBlockTxids(g.b, blockHash)
+}
+
+func (g *bitcoindGateway) GetFeeEstimates() (map[string]float64, error) {
+	return bitcoind.GetFeeEstimates(g.b)
+}
@@ -1,23 +1,31 @@
 package btc
 
 import (
+	"crypto/sha256"
 	"fmt"
 
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcutil"
 	"github.com/fsn-dev/crossChain-Bridge/common"
+	"github.com/fsn-dev/crossChain-Bridge/dcrm"
+	"github.com/fsn-dev/crossChain-Bridge/log"
 	"github.com/fsn-dev/crossChain-Bridge/tokens"
 	"github.com/fsn-dev/crossChain-Bridge/tokens/tools"
 )
 
-// GetP2shAddressWithMemo common
-func GetP2shAddressWithMemo(memo, pubKeyHash []byte, net *chaincfg.Params) (p2shAddress string, redeemScript []byte, err error) {
-	redeemScript, err = txscript.NewScriptBuilder().
+// GetMemoRedeemScript get the memo-DROP redeem script (also used as p2wsh witness script)
+func GetMemoRedeemScript(memo, pubKeyHash []byte) ([]byte, error) {
+	return txscript.NewScriptBuilder().
 		AddData(memo).AddOp(txscript.OP_DROP).
 		AddOp(txscript.OP_DUP).AddOp(txscript.OP_HASH160).AddData(pubKeyHash).
 		AddOp(txscript.OP_EQUALVERIFY).AddOp(txscript.OP_CHECKSIG).
 		Script()
+}
+
+// GetP2shAddressWithMemo common
+func GetP2shAddressWithMemo(memo, pubKeyHash []byte, net *chaincfg.Params) (p2shAddress string, redeemScript []byte, err error) {
+	redeemScript, err = GetMemoRedeemScript(memo, pubKeyHash)
 	if err != nil {
 		return
 	}
@@ -30,19 +38,82 @@ func GetP2shAddressWithMemo(memo, pubKeyHash []byte, net *chaincfg.Params) (p2sh
 	return
 }
 
+// GetP2wshAddressWithMemo common
+func GetP2wshAddressWithMemo(memo, pubKeyHash []byte, net *chaincfg.Params) (p2wshAddress string, witnessScript []byte, err error) {
+	witnessScript, err = GetMemoRedeemScript(memo, pubKeyHash)
+	if err != nil {
+		return
+	}
+	scriptHash := sha256.Sum256(witnessScript)
+	var addressScriptHash *btcutil.AddressWitnessScriptHash
+	addressScriptHash, err = btcutil.NewAddressWitnessScriptHash(scriptHash[:], net)
+	if err != nil {
+		return
+	}
+	p2wshAddress = addressScriptHash.EncodeAddress()
+	return
+}
+
 // GetP2shAddress get p2sh address from bind address
 func (b *Bridge) GetP2shAddress(bindAddr string) (p2shAddress string, redeemScript []byte, err error) {
-	if !tokens.GetCrossChainBridge(!b.IsSrc).IsValidAddress(bindAddr) {
-		return "", nil, fmt.Errorf("invalid bind address %v", bindAddr)
+	memo, pubKeyHash, err := b.getBindMemoAndPubKeyHash(bindAddr)
+	if err != nil {
+		return "", nil, err
 	}
-	memo := common.FromHex(bindAddr)
-	net := b.GetChainConfig()
+	return GetP2shAddressWithMemo(memo, pubKeyHash, b.GetChainConfig())
+}
+
+// GetP2wshAddress get p2wsh address from bind address
+func (b *Bridge) GetP2wshAddress(bindAddr string) (p2wshAddress string, witnessScript []byte, err error) {
+	memo, pubKeyHash, err := b.getBindMemoAndPubKeyHash(bindAddr)
+	if err != nil {
+		return "", nil, err
+	}
+	return GetP2wshAddressWithMemo(memo, pubKeyHash, b.GetChainConfig())
+}
+
+// getP2shBindAddress get bind address of p2sh (or p2wsh) address. oracle resolves it from its own index,
+// the bind address queried from swap server is indexed only if the oracle derives the same address from it.
+func (b *Bridge) getP2shBindAddress(p2shAddress string) string {
+	bindAddr := tools.GetP2shBindAddress(b.PairID, p2shAddress)
+	if bindAddr != "" || dcrm.IsSwapServer() {
+		return bindAddr
+	}
+	bindAddr = tools.QueryP2shBindAddress(b.PairID, p2shAddress)
+	if bindAddr == "" {
+		return ""
+	}
+	p2shAddr, _, _ := b.GetP2shAddress(bindAddr)
+	p2wshAddr, _, _ := b.GetP2wshAddress(bindAddr)
+	if p2shAddress != p2shAddr && p2shAddress != p2wshAddr {
+		log.Warn("p2sh bind address from swap server mismatch", "pairID", b.PairID, "p2shAddress", p2shAddress, "bindAddress", bindAddr)
+		return ""
+	}
+	if err := tools.AddOracleP2shBindAddress(b.PairID, p2shAddress, bindAddr); err != nil {
+		log.Warn("add p2sh bind address to oracle index failed", "pairID", b.PairID, "p2shAddress", p2shAddress, "err", err)
+	}
+	return bindAddr
+}
+
+func (b *Bridge) getBindMemoAndPubKeyHash(bindAddr string) (memo, pubKeyHash []byte, err error) {
+	if !b.IsSrc {
+		return nil, nil, tokens.ErrBridgeDestinationNotSupported
+	}
+	if !tokens.GetCrossChainBridge(b.PairID, !b.IsSrc).IsValidAddress(bindAddr) {
+		return nil, nil, fmt.Errorf("invalid bind address %v", bindAddr)
+	}
+	memo = common.FromHex(bindAddr)
 	dcrmAddress := b.TokenConfig.DcrmAddress
-	address, _ := btcutil.DecodeAddress(dcrmAddress, net)
-	pubKeyHash := address.ScriptAddress()
-	return GetP2shAddressWithMemo(memo, pubKeyHash, net)
+	address, err := btcutil.DecodeAddress(dcrmAddress, b.GetChainConfig())
+	if err != nil {
+		return nil, nil, err
+	}
+	// p2pkh and p2wpkh dcrm address have the same pubkey hash
+	pubKeyHash = address.ScriptAddress()
+	return memo, pubKeyHash, nil
 }
 
+// getRedeemScriptByOutputScrpit get redeem script of p2sh output or witness script of p2wsh output
 func (b *Bridge) getRedeemScriptByOutputScrpit(preScript []byte) ([]byte, error) {
 	pkScript, err := txscript.ParsePkScript(preScript)
 	if err != nil {
@@ -52,13 +123,20 @@ func (b *Bridge) getRedeemScriptByOutputScrpit(preScript []byte) ([]byte, error)
 	if err != nil {
 		return nil, err
 	}
-	p2shAddr := p2shAddress.String()
-	bindAddr := tools.GetP2shBindAddress(p2shAddr)
+	p2shAddr := p2shAddress.EncodeAddress()
+	bindAddr := b.getP2shBindAddress(p2shAddr)
 	if bindAddr == "" {
-		return nil, fmt.Errorf("ps2h address %v is registered", p2shAddr)
+		return nil, fmt.Errorf("ps2h address %v is not registered", p2shAddr)
+	}
+	var (
+		address      string
+		redeemScript []byte
+	)
+	if txscript.IsPayToWitnessScriptHash(preScript) {
+		address, redeemScript, _ = b.GetP2wshAddress(bindAddr)
+	} else {
+		address, redeemScript, _ = b.GetP2shAddress(bindAddr)
 	}
-	var address string
-	address, redeemScript, _ := b.GetP2shAddress(bindAddr)
 	if address != p2shAddr {
 		return nil, fmt.Errorf("ps2h address mismatch for bind address %v, have %v want %v", bindAddr, p2shAddr, address)
 	}
@@ -7,34 +7,64 @@ import (
 )
 
 func (b *Bridge) processTransaction(txid string) {
-	_ = b.processSwapin(txid)
-	_ = b.processP2shSwapin(txid)
+	if isUtxoTrackerEnabled() {
+		b.trackTransaction(txid)
+	}
+	if b.IsSrc {
+		_ = b.processSwapin(txid)
+		_ = b.processP2shSwapin(txid)
+	} else {
+		_ = b.processSwapout(txid)
+	}
+}
+
+func (b *Bridge) processDeposit(txid string) {
+	if b.IsSrc {
+		_ = b.processSwapin(txid)
+	} else {
+		_ = b.processSwapout(txid)
+	}
 }
 
 func (b *Bridge) processSwapin(txid string) error {
-	if tools.IsSwapinExist(txid) {
+	if tools.IsSwapinExist(b.PairID, txid) {
 		return nil
 	}
 	swapInfo, err := b.VerifyTransaction(txid, true)
 	if !tokens.ShouldRegisterSwapForError(err) {
 		return err
 	}
-	err = tools.RegisterSwapin(txid, swapInfo.Bind)
+	err = tools.RegisterSwapin(b.PairID, txid, swapInfo.Bind)
 	if err != nil {
 		log.Trace("[scan] processSwapin", "txid", txid, "err", err)
 	}
 	return err
 }
 
+func (b *Bridge) processSwapout(txid string) error {
+	if tools.IsSwapoutExist(b.PairID, txid) {
+		return nil
+	}
+	swapInfo, err := b.VerifyTransaction(txid, true)
+	if !tokens.ShouldRegisterSwapForError(err) {
+		return err
+	}
+	err = tools.RegisterSwapout(b.PairID, txid, swapInfo.Bind)
+	if err != nil {
+		log.Trace("[scan] processSwapout", "txid", txid, "err", err)
+	}
+	return err
+}
+
 func (b *Bridge) processP2shSwapin(txid string) error {
-	if tools.IsSwapinExist(txid) {
+	if tools.IsSwapinExist(b.PairID, txid) {
 		return nil
 	}
 	swapInfo, err := b.checkP2shTransaction(txid, true)
 	if !tokens.ShouldRegisterSwapForError(err) {
 		return err
 	}
-	err = tools.RegisterP2shSwapin(txid, swapInfo.Bind)
+	err = tools.RegisterP2shSwapin(b.PairID, txid, swapInfo.Bind)
 	if err != nil {
 		log.Trace("[scan] processP2shSwapin", "txid", txid, "err", err)
 	}
@@ -49,9 +79,9 @@ func (b *Bridge) checkP2shTransaction(txHash string, allowUnstable bool) (*token
 	}
 	var bindAddress, p2shAddress string
 	for _, output := range tx.Vout {
-		if *output.ScriptpubkeyType == p2shType {
+		if scriptType := *output.ScriptpubkeyType; scriptType == p2shType || scriptType == p2wshType {
 			p2shAddress = *output.ScriptpubkeyAddress
-			bindAddress = tools.GetP2shBindAddress(p2shAddress)
+			bindAddress = b.getP2shBindAddress(p2shAddress)
 			if bindAddress != "" {
 				break
 			}
@@ -0,0 +1,93 @@
+package btc
+
+import (
+	"github.com/btcsuite/btcd/wire"
+	"github.com/fsn-dev/crossChain-Bridge/log"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+	"github.com/fsn-dev/crossChain-Bridge/tokens/btc/electrs"
+)
+
+const (
+	// replacement must pay for its own bandwidth (BIP125 rule 4)
+	incrementalRelayFeePerKb = 1000
+)
+
+// GetReplaceConfig get pending age to replace tx and max replace count
+func (b *Bridge) GetReplaceConfig() (pendingAge int64, maxReplaceCount int) {
+	return tokens.BtcReplacePendingAge, tokens.BtcMaxReplaceCount
+}
+
+// GetReplaceTxExtra get extra args to replace pending swap tx (same inputs, bumped relay fee)
+func (b *Bridge) GetReplaceTxExtra(pendingTxHash string) (*tokens.AllExtras, error) {
+	tx, err := b.GetTransactionByHash(pendingTxHash)
+	if err != nil {
+		return nil, err
+	}
+	if isTxConfirmed(tx) {
+		return nil, tokens.ErrTxIsNotPending
+	}
+	if !isRbfSignaled(tx) {
+		return nil, tokens.ErrTxNotReplaceable
+	}
+
+	oldFeePerKb := getTxFeePerKb(tx)
+	relayFeePerKb := getBumpedFeePerKb(oldFeePerKb)
+	if relayFeePerKb > tokens.BtcMaxRelayFeePerKb {
+		return nil, tokens.ErrRelayFeeExceedLimit
+	}
+
+	prevOutPoints := make([]*tokens.BtcOutPoint, len(tx.Vin))
+	for i, input := range tx.Vin {
+		prevOutPoints[i] = &tokens.BtcOutPoint{
+			Hash:  *input.Txid,
+			Index: *input.Vout,
+		}
+	}
+	log.Info(b.TokenConfig.BlockChain+" GetReplaceTxExtra", "txHash", pendingTxHash, "oldFeePerKb", oldFeePerKb, "newFeePerKb", relayFeePerKb)
+	return &tokens.AllExtras{
+		BtcExtra: &tokens.BtcExtraArgs{
+			RelayFeePerKb:     &relayFeePerKb,
+			PreviousOutPoints: prevOutPoints,
+		},
+	}, nil
+}
+
+// isSpentByReplaceableTx is out point spent in txpool by replaceable tx with the specified memo
+func (b *Bridge) isSpentByReplaceableTx(outspend *electrs.ElectOutspend, replaceMemo string) bool {
+	if replaceMemo == "" || outspend.Txid == nil {
+		return false
+	}
+	if outspend.Status != nil && outspend.Status.Confirmed != nil && *outspend.Status.Confirmed {
+		return false
+	}
+	tx, err := b.getTransactionByHashWithRetry(*outspend.Txid)
+	if err != nil || isTxConfirmed(tx) {
+		return false
+	}
+	return isRbfSignaled(tx) && getTxMemo(tx) == replaceMemo
+}
+
+func isRbfSignaled(tx *electrs.ElectTx) bool {
+	for _, input := range tx.Vin {
+		if input.Sequence != nil && *input.Sequence < wire.MaxTxInSequenceNum-1 {
+			return true
+		}
+	}
+	return false
+}
+
+func getTxVsize(tx *electrs.ElectTx) int64 {
+	return (int64(*tx.Weight) + 3) / 4
+}
+
+func getTxFeePerKb(tx *electrs.ElectTx) int64 {
+	return int64(*tx.Fee) * 1000 / getTxVsize(tx)
+}
+
+func getBumpedFeePerKb(oldFeePerKb int64) int64 {
+	feePerKb := oldFeePerKb*int64(100+tokens.BtcRelayFeeBumpPercent)/100 + incrementalRelayFeePerKb
+	if feePerKb < tokens.BtcRelayFeePerKb {
+		feePerKb = tokens.BtcRelayFeePerKb
+	}
+	return feePerKb
+}
@@ -7,15 +7,11 @@ import (
 	"github.com/fsn-dev/crossChain-Bridge/tokens/tools"
 )
 
-var (
-	scannedBlocks = tools.NewCachedScannedBlocks(13)
-)
-
 // StartChainTransactionScanJob scan job
 func (b *Bridge) StartChainTransactionScanJob() {
-	log.Info("[scanchain] start scan chain tx job", "isSrc", b.IsSrc)
+	log.Info("[scanchain] start scan chain tx job", "pairID", b.PairID, "isSrc", b.IsSrc)
 
-	startHeight := tools.GetLatestScanHeight(b.IsSrc)
+	startHeight := tools.GetLatestScanHeight(b.PairID, b.IsSrc)
 	confirmations := *b.TokenConfig.Confirmations
 	initialHeight := b.TokenConfig.InitialHeight
 
@@ -34,7 +30,7 @@ func (b *Bridge) StartChainTransactionScanJob() {
 	if height < initialHeight {
 		height = initialHeight
 	}
-	_ = tools.UpdateLatestScanInfo(b.IsSrc, height)
+	_ = tools.UpdateLatestScanInfo(b.PairID, b.IsSrc, height)
 	log.Info("[scanchain] start scan tx history loop", "isSrc", b.IsSrc, "start", height)
 
 	for {
@@ -46,7 +42,7 @@ func (b *Bridge) StartChainTransactionScanJob() {
 				time.Sleep(retryIntervalInScanJob)
 				continue
 			}
-			if scannedBlocks.IsBlockScanned(blockHash) {
+			if b.scannedBlocks.IsBlockScanned(blockHash) {
 				h++
 				continue
 			}
@@ -59,7 +55,7 @@ func (b *Bridge) StartChainTransactionScanJob() {
 			for _, txid := range txids {
 				b.processTransaction(txid)
 			}
-			scannedBlocks.CacheScannedBlock(blockHash, h)
+			b.scannedBlocks.CacheScannedBlock(blockHash, h)
 			log.Info("[scanchain] scanned tx history", "isSrc", b.IsSrc, "blockHash", blockHash, "height", h, "txs", len(txids))
 			h++
 		}
@@ -67,7 +63,7 @@ func (b *Bridge) StartChainTransactionScanJob() {
 			latestStable := latest - confirmations
 			if height < latestStable {
 				height = latestStable
-				_ = tools.UpdateLatestScanInfo(b.IsSrc, height)
+				_ = tools.UpdateLatestScanInfo(b.PairID, b.IsSrc, height)
 			}
 		}
 		time.Sleep(restIntervalInScanJob)
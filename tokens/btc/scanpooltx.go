@@ -4,16 +4,11 @@ import (
 	"time"
 
 	"github.com/fsn-dev/crossChain-Bridge/log"
-	"github.com/fsn-dev/crossChain-Bridge/tokens/tools"
-)
-
-var (
-	scannedTxs = tools.NewCachedScannedTxs(100)
 )
 
 // StartPoolTransactionScanJob scan job
 func (b *Bridge) StartPoolTransactionScanJob() {
-	log.Info("[scanpool] start scan pool tx job", "isSrc", b.IsSrc)
+	log.Info("[scanpool] start scan pool tx job", "pairID", b.PairID, "isSrc", b.IsSrc)
 	for {
 		txids, err := b.GetPoolTxidList()
 		if err != nil {
@@ -23,11 +18,11 @@ func (b *Bridge) StartPoolTransactionScanJob() {
 		}
 		log.Info("[scanpool] scan pool tx", "isSrc", b.IsSrc, "txs", len(txids))
 		for _, txid := range txids {
-			if scannedTxs.IsTxScanned(txid) {
+			if b.scannedTxs.IsTxScanned(txid) {
 				continue
 			}
 			b.processTransaction(txid)
-			scannedTxs.CacheScannedTx(txid)
+			b.scannedTxs.CacheScannedTx(txid)
 		}
 		time.Sleep(restIntervalInScanJob)
 	}
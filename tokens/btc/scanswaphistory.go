@@ -19,9 +19,9 @@ func (b *Bridge) StartSwapHistoryScanJob() {
 
 	isProcessed := func(txid string) bool {
 		if b.IsSrc {
-			return tools.IsSwapinExist(txid)
+			return tools.IsSwapinExist(b.PairID, txid)
 		}
-		return tools.IsSwapoutExist(txid)
+		return tools.IsSwapoutExist(b.PairID, txid)
 	}
 
 	go b.scanFirstLoop(isProcessed)
@@ -61,7 +61,7 @@ FIRST_LOOP:
 			}
 			txid := *tx.Txid
 			if !isProcessed(txid) {
-				_ = b.processSwapin(txid)
+				b.processDeposit(txid)
 			}
 		}
 		lastSeenTxid = *txHistory[len(txHistory)-1].Txid
@@ -101,7 +101,7 @@ func (b *Bridge) scanTransactionHistory(isProcessed func(string) bool) {
 				rescan = true
 				break // rescan if already processed
 			}
-			_ = b.processSwapin(txid)
+			b.processDeposit(txid)
 		}
 		if rescan {
 			lastSeenTxid = ""
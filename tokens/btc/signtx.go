@@ -1,6 +1,7 @@
 package btc
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
 	"github.com/btcsuite/btcwallet/wallet/txauthor"
 	"github.com/fsn-dev/crossChain-Bridge/common"
@@ -22,65 +24,116 @@ import (
 var (
 	retryCount    = 15
 	retryInterval = 10 * time.Second
-	waitInterval  = 10 * time.Second
 
 	hashType = txscript.SigHashAll
 )
 
 // DcrmSignTransaction dcrm sign raw tx
 func (b *Bridge) DcrmSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signedTx interface{}, txHash string, err error) {
+	msgHashes, msgContext, err := b.GetDcrmSignMsgHash(rawTx, args)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rsvs, err := b.dcrmSignMsgHash(msgHashes, msgContext, args)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return b.MakeDcrmSignedTransaction(rawTx, rsvs, args)
+}
+
+// GetDcrmSignMsgHash get msgHashes of raw tx (to be signed by dcrm) and its context
+func (b *Bridge) GetDcrmSignMsgHash(rawTx interface{}, args *tokens.BuildTxArgs) (msgHash, msgContext []string, err error) {
+	authoredTx, ok := rawTx.(*txauthor.AuthoredTx)
+	if !ok {
+		return nil, nil, tokens.ErrWrongRawTx
+	}
+	if args.Extra == nil || args.Extra.BtcExtra == nil {
+		return nil, nil, tokens.ErrWrongExtraArgs
+	}
+	msgHash, _, err = b.calcSignatureHashes(authoredTx)
+	if err != nil {
+		return nil, nil, err
+	}
+	jsondata, _ := json.Marshal(args)
+	return msgHash, []string{string(jsondata)}, nil
+}
+
+// MakeDcrmSignedTransaction make signed tx with rsvs signed by dcrm
+func (b *Bridge) MakeDcrmSignedTransaction(rawTx interface{}, rsvs []string, args *tokens.BuildTxArgs) (signedTx interface{}, txHash string, err error) {
 	authoredTx, ok := rawTx.(*txauthor.AuthoredTx)
 	if !ok {
 		return nil, "", tokens.ErrWrongRawTx
 	}
+	msgHashes, sigScripts, err := b.calcSignatureHashes(authoredTx)
+	if err != nil {
+		return nil, "", err
+	}
+	return b.MakeSignedTransaction(authoredTx, msgHashes, rsvs, sigScripts, args)
+}
 
+// calcSignatureHashes calc sighash of every input (BIP143 sighash for segwit inputs).
+// sigScripts are the redeem scripts (or witness scripts) of inputs, it is nil if no p2sh or p2wsh input.
+func (b *Bridge) calcSignatureHashes(authoredTx *txauthor.AuthoredTx) (msgHashes []string, sigScripts [][]byte, err error) {
 	var (
-		msgHashes    []string
-		rsvs         []string
-		sigScripts   [][]byte
-		hasP2shInput bool
-		sigHash      []byte
+		sigHashes      *txscript.TxSigHashes
+		hasScriptInput bool
+		sigHash        []byte
 	)
 
 	for i, preScript := range authoredTx.PrevScripts {
 		sigScript := preScript
-		if txscript.IsPayToScriptHash(preScript) {
+		if txscript.IsPayToScriptHash(preScript) || txscript.IsPayToWitnessScriptHash(preScript) {
 			sigScript, err = b.getRedeemScriptByOutputScrpit(preScript)
 			if err != nil {
-				return nil, "", err
+				return nil, nil, err
 			}
-			hasP2shInput = true
+			hasScriptInput = true
 		}
 
-		sigHash, err = txscript.CalcSignatureHash(sigScript, hashType, authoredTx.Tx, i)
+		if txscript.IsPayToWitnessPubKeyHash(preScript) || txscript.IsPayToWitnessScriptHash(preScript) {
+			if i >= len(authoredTx.PrevInputValues) {
+				return nil, nil, errors.New("missing input value to calc witness sighash")
+			}
+			if sigHashes == nil {
+				sigHashes = txscript.NewTxSigHashes(authoredTx.Tx)
+			}
+			inputValue := int64(authoredTx.PrevInputValues[i])
+			sigHash, err = txscript.CalcWitnessSigHash(sigScript, sigHashes, hashType, authoredTx.Tx, i, inputValue)
+		} else {
+			sigHash, err = txscript.CalcSignatureHash(sigScript, hashType, authoredTx.Tx, i)
+		}
 		if err != nil {
-			return nil, "", err
+			return nil, nil, err
 		}
 		msgHash := hex.EncodeToString(sigHash)
 		msgHashes = append(msgHashes, msgHash)
 		sigScripts = append(sigScripts, sigScript)
 	}
-	if !hasP2shInput {
+	if !hasScriptInput {
 		sigScripts = nil
 	}
-
-	rsvs, err = b.DcrmSignMsgHash(msgHashes, args)
-	if err != nil {
-		return nil, "", err
-	}
-
-	return b.MakeSignedTransaction(authoredTx, msgHashes, rsvs, sigScripts, args)
+	return msgHashes, sigScripts, nil
 }
 
 func (b *Bridge) verifyPublickeyData(pkData []byte, swapType tokens.SwapType) error {
 	switch swapType {
 	case tokens.SwapinType:
-		return tokens.ErrSwapTypeNotSupported
+		// swapin pays out from dcrm address of destination chain
+		if b.IsSrc {
+			return tokens.ErrSwapTypeNotSupported
+		}
+		fallthrough
 	case tokens.SwapoutType, tokens.SwapRecallType:
+		// compare pubkey hash to support both p2pkh and p2wpkh dcrm address
 		dcrmAddress := b.TokenConfig.DcrmAddress
-		address, _ := btcutil.NewAddressPubKeyHash(btcutil.Hash160(pkData), b.GetChainConfig())
-		if address.EncodeAddress() != b.TokenConfig.DcrmAddress {
-			return fmt.Errorf("sign public key %v is not the configed dcrm address %v", address, dcrmAddress)
+		address, err := btcutil.DecodeAddress(dcrmAddress, b.GetChainConfig())
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(address.ScriptAddress(), btcutil.Hash160(pkData)) {
+			return fmt.Errorf("sign public key %x is not of the configed dcrm address %v", pkData, dcrmAddress)
 		}
 	}
 	return nil
@@ -119,6 +172,7 @@ func (b *Bridge) MakeSignedTransaction(authoredTx *txauthor.AuthoredTx, msgHash,
 			}
 		}
 
+		var witness wire.TxWitness
 		prevScript := authoredTx.PrevScripts[i]
 		scriptClass := txscript.GetScriptClass(prevScript)
 		switch scriptClass {
@@ -130,6 +184,16 @@ func (b *Bridge) MakeSignedTransaction(authoredTx *txauthor.AuthoredTx, msgHash,
 			} else {
 				sigScript, err = txscript.NewScriptBuilder().AddData(signData).AddData(cPkData).AddData(sigScripts[i]).Script()
 			}
+		case txscript.WitnessV0PubKeyHashTy:
+			sigScript = nil
+			witness = wire.TxWitness{signData, cPkData}
+		case txscript.WitnessV0ScriptHashTy:
+			if sigScripts == nil {
+				err = fmt.Errorf("call MakeSignedTransaction spend p2wsh without witness scripts")
+			} else {
+				sigScript = nil
+				witness = wire.TxWitness{signData, cPkData, sigScripts[i]}
+			}
 		default:
 			err = fmt.Errorf("unsupport to spend '%v' output", scriptClass.String())
 		}
@@ -137,6 +201,7 @@ func (b *Bridge) MakeSignedTransaction(authoredTx *txauthor.AuthoredTx, msgHash,
 			return nil, "", err
 		}
 		txin.SignatureScript = sigScript
+		txin.Witness = witness
 	}
 	txHash = authoredTx.Tx.TxHash().String()
 	log.Info(b.TokenConfig.BlockChain+" MakeSignedTransaction success", "txhash", txHash)
@@ -213,35 +278,19 @@ func (b *Bridge) DcrmSignMsgHash(msgHash []string, args *tokens.BuildTxArgs) (rs
 		return nil, tokens.ErrWrongExtraArgs
 	}
 	jsondata, _ := json.Marshal(args)
-	msgContext := []string{string(jsondata)}
+	return b.dcrmSignMsgHash(msgHash, []string{string(jsondata)}, args)
+}
+
+func (b *Bridge) dcrmSignMsgHash(msgHash, msgContext []string, args *tokens.BuildTxArgs) (rsv []string, err error) {
 	keyID, err := dcrm.DoSign(msgHash, msgContext)
 	if err != nil {
 		return nil, err
 	}
 	log.Info(b.TokenConfig.BlockChain+" DcrmSignTransaction start", "keyID", keyID, "msghash", msgHash, "txid", args.SwapID)
-	time.Sleep(waitInterval)
-
-	i := 0
-	for ; i < retryCount; i++ {
-		signStatus, err := dcrm.GetSignStatus(keyID)
-		if err == nil {
-			if len(signStatus.Rsv) != len(msgHash) {
-				return nil, fmt.Errorf("get sign status require %v rsv but have %v (keyID = %v)", len(msgHash), len(signStatus.Rsv), keyID)
-			}
-			rsv = signStatus.Rsv
-			break
-		}
-		switch err {
-		case dcrm.ErrGetSignStatusFailed, dcrm.ErrGetSignStatusTimeout:
-			return nil, err
-		}
-		log.Warn("retry get sign status as error", "err", err, "txid", args.SwapID)
-		time.Sleep(retryInterval)
-	}
-	if i == retryCount || len(rsv) == 0 {
-		return nil, errors.New("get sign status failed")
+	rsv, err = dcrm.WaitSignResult(keyID, len(msgHash))
+	if err != nil {
+		return nil, err
 	}
-
 	log.Trace(b.TokenConfig.BlockChain+" DcrmSignTransaction get rsv success", "keyID", keyID, "rsv", rsv)
 	return rsv, nil
 }
@@ -258,35 +307,13 @@ func (b *Bridge) SignTransaction(rawTx interface{}, wif string) (signedTx interf
 	}
 	privateKey := pkwif.PrivKey
 
-	var (
-		msgHashes    []string
-		rsvs         []string
-		sigScripts   [][]byte
-		hasP2shInput bool
-	)
-
-	for i, preScript := range authoredTx.PrevScripts {
-		sigScript := preScript
-		if txscript.IsPayToScriptHash(preScript) {
-			sigScript, err = b.getRedeemScriptByOutputScrpit(preScript)
-			if err != nil {
-				return nil, "", err
-			}
-			hasP2shInput = true
-		}
-
-		sigHash, err := txscript.CalcSignatureHash(sigScript, hashType, authoredTx.Tx, i)
-		if err != nil {
-			return nil, "", err
-		}
-		msgHash := hex.EncodeToString(sigHash)
-		msgHashes = append(msgHashes, msgHash)
-		sigScripts = append(sigScripts, sigScript)
-	}
-	if !hasP2shInput {
-		sigScripts = nil
+	msgHashes, sigScripts, err := b.calcSignatureHashes(authoredTx)
+	if err != nil {
+		return nil, "", err
 	}
 
+	var rsvs []string
+
 	for _, msgHash := range msgHashes {
 		signature, err := privateKey.Sign(common.FromHex(msgHash))
 		if err != nil {
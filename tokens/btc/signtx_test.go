@@ -0,0 +1,234 @@
+package btc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+	"github.com/fsn-dev/crossChain-Bridge/tools/crypto"
+)
+
+const testPrivateKey = "289c2857d4598e37fb9647507e47a309d6133539bf21a8b9cb6df88fd5232032"
+
+func newTestBridge(t *testing.T, isSrc, isSegwit bool) (*Bridge, []byte) {
+	key, err := crypto.HexToECDSA(testPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkData := crypto.CompressPubkey(&key.PublicKey)
+	var address btcutil.Address
+	if isSegwit {
+		address, err = btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(pkData), &chaincfg.TestNet3Params)
+	} else {
+		address, err = btcutil.NewAddressPubKeyHash(btcutil.Hash160(pkData), &chaincfg.TestNet3Params)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := NewCrossChainBridge("test", isSrc)
+	b.TokenConfig = &tokens.TokenConfig{
+		BlockChain:  "Bitcoin",
+		NetID:       netTestnet3,
+		DcrmAddress: address.EncodeAddress(),
+	}
+	return b, pkData
+}
+
+// newTestAuthoredTx tx spending an output of the dcrm address
+func newTestAuthoredTx(t *testing.T, b *Bridge, value btcutil.Amount) *txauthor.AuthoredTx {
+	address, err := btcutil.DecodeAddress(b.TokenConfig.DcrmAddress, b.GetChainConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevScript, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := wire.NewMsgTx(wire.TxVersion)
+	prevHash, _ := chainhash.NewHashFromStr("1c3bd8c2d4b5b1a5f1a58a0e6ab6d4e8bde2a50e3b3b2e7a6c8f1c9d3e4a5b6c")
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(prevHash, 1), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(int64(value)-1000, prevScript))
+	return &txauthor.AuthoredTx{
+		Tx:              tx,
+		PrevScripts:     [][]byte{prevScript},
+		PrevInputValues: []btcutil.Amount{value},
+	}
+}
+
+// signMsgHashes sign like dcrm, rsv is hex of r || s || v
+func signMsgHashes(t *testing.T, msgHashes []string) []string {
+	key, _ := crypto.HexToECDSA(testPrivateKey)
+	rsvs := make([]string, 0, len(msgHashes))
+	for _, msgHash := range msgHashes {
+		hash, _ := hex.DecodeString(msgHash)
+		sig, err := crypto.Sign(hash, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsvs = append(rsvs, hex.EncodeToString(sig))
+	}
+	return rsvs
+}
+
+func signAndVerifyTestTx(t *testing.T, b *Bridge, swapType tokens.SwapType) error {
+	value := btcutil.Amount(100000)
+	authoredTx := newTestAuthoredTx(t, b, value)
+	msgHashes, sigScripts, err := b.calcSignatureHashes(authoredTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &tokens.BuildTxArgs{SwapInfo: tokens.SwapInfo{SwapType: swapType}}
+	_, _, err = b.MakeSignedTransaction(authoredTx, msgHashes, signMsgHashes(t, msgHashes), sigScripts, args)
+	if err != nil {
+		return err
+	}
+	vm, err := txscript.NewEngine(authoredTx.PrevScripts[0], authoredTx.Tx, 0,
+		txscript.StandardVerifyFlags, nil, nil, int64(value))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = vm.Execute(); err != nil {
+		t.Fatalf("signed tx is not valid: %v", err)
+	}
+	return nil
+}
+
+func TestSignSwapinTx(t *testing.T) {
+	b, _ := newTestBridge(t, false, false)
+	if err := signAndVerifyTestTx(t, b, tokens.SwapinType); err != nil {
+		t.Fatalf("sign swapin tx on destination chain failed: %v", err)
+	}
+
+	b, _ = newTestBridge(t, true, false)
+	if err := signAndVerifyTestTx(t, b, tokens.SwapinType); err != tokens.ErrSwapTypeNotSupported {
+		t.Fatalf("sign swapin tx on source chain, want %v, have %v", tokens.ErrSwapTypeNotSupported, err)
+	}
+	if err := signAndVerifyTestTx(t, b, tokens.SwapoutType); err != nil {
+		t.Fatalf("sign swapout tx failed: %v", err)
+	}
+}
+
+func TestSignWithWrongDcrmAddress(t *testing.T) {
+	b, _ := newTestBridge(t, false, false)
+	other, _ := newTestBridge(t, false, true)
+	authoredTx := newTestAuthoredTx(t, other, btcutil.Amount(100000))
+	msgHashes, sigScripts, err := b.calcSignatureHashes(authoredTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// sign with key of dcrm address, but the configed dcrm address is another one
+	b.TokenConfig.DcrmAddress = "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn"
+	args := &tokens.BuildTxArgs{SwapInfo: tokens.SwapInfo{SwapType: tokens.SwapinType}}
+	_, _, err = b.MakeSignedTransaction(authoredTx, msgHashes, signMsgHashes(t, msgHashes), sigScripts, args)
+	if err == nil {
+		t.Fatal("sign with public key which is not of dcrm address should fail")
+	}
+}
+
+// TestBIP143NativeP2WPKH check witness sighash and witness against the native P2WPKH example of BIP143
+func TestBIP143NativeP2WPKH(t *testing.T) {
+	var (
+		unsignedTx = "0100000002fff7f7881a8099afa6940d42d1e7f6362bec38171ea3edf433541db4e4ad969f0000000000eeffffffef51e1b804cc89d182d279655c3aa89e815b1b309fe287d9b2b55d57b90ec68a0100000000ffffffff02202cb206000000001976a9148280b37df378db99f66f85c95a783a76ac7a6d5988ac9093510d000000001976a9143bde42dbee7e4dbe6a21b2d50ce2f0167faa815988ac11000000"
+		privateKey = "619c335025c7f4012e556c2a58b2506e30b8511b53ade95ea316fd8c3286feb9"
+		publicKey  = "025476c2e83188368da1ff3e292e7acafcdb3566bb0ad253f62fc70f07aeee6357"
+		prevScript = "00141d0f172a0ecb48aee1be1f2687d2963ae33f71a1"
+		inputValue = btcutil.Amount(600000000)
+		sigHash    = "c37af31116d1b27caf68aae9e3ac82f1477929014d5b917657d0eb49478cb670"
+		signature  = "304402203609e17b84f6a7d30c80bfa610b5b4542f32a8a0d5447a12fb1366d7f01cc44a0220573a954c4518331561406f90300e8f3358f51928d43c212a8caed02de67eebee01"
+	)
+
+	pkData, _ := hex.DecodeString(publicKey)
+	address, err := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(pkData), &chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := newTestBridge(t, true, true)
+	b.TokenConfig.DcrmAddress = address.EncodeAddress()
+
+	txData, _ := hex.DecodeString(unsignedTx)
+	tx := wire.NewMsgTx(wire.TxVersion)
+	if err = tx.Deserialize(bytes.NewReader(txData)); err != nil {
+		t.Fatal(err)
+	}
+	pkScript, _ := hex.DecodeString(prevScript)
+	// the first input of the example is p2pk, which is not spent by bridge.
+	// replace it with p2wpkh input, BIP143 sighash of the second input does not depend on it.
+	authoredTx := &txauthor.AuthoredTx{
+		Tx:              tx,
+		PrevScripts:     [][]byte{pkScript, pkScript},
+		PrevInputValues: []btcutil.Amount{inputValue, inputValue},
+	}
+	msgHashes, sigScripts, err := b.calcSignatureHashes(authoredTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sigScripts != nil {
+		t.Fatal("sig scripts should be nil if there is no p2sh or p2wsh input")
+	}
+	if msgHashes[1] != sigHash {
+		t.Fatalf("wrong BIP143 sighash, want %v, have %v", sigHash, msgHashes[1])
+	}
+
+	key, _ := crypto.HexToECDSA(privateKey)
+	rsvs := make([]string, 0, len(msgHashes))
+	for _, msgHash := range msgHashes {
+		hash, _ := hex.DecodeString(msgHash)
+		sig, errs := crypto.Sign(hash, key)
+		if errs != nil {
+			t.Fatal(errs)
+		}
+		rsvs = append(rsvs, hex.EncodeToString(sig))
+	}
+	args := &tokens.BuildTxArgs{SwapInfo: tokens.SwapInfo{SwapType: tokens.SwapoutType}}
+	if _, _, err = b.MakeSignedTransaction(authoredTx, msgHashes, rsvs, sigScripts, args); err != nil {
+		t.Fatalf("make signed tx failed: %v", err)
+	}
+
+	txIn := tx.TxIn[1]
+	if len(txIn.SignatureScript) != 0 {
+		t.Fatalf("p2wpkh input should have empty signature script, have %x", txIn.SignatureScript)
+	}
+	if len(txIn.Witness) != 2 || hex.EncodeToString(txIn.Witness[0]) != signature || hex.EncodeToString(txIn.Witness[1]) != publicKey {
+		t.Fatalf("wrong witness, want [%v %v], have %x", signature, publicKey, txIn.Witness)
+	}
+	for i := range tx.TxIn {
+		vm, errv := txscript.NewEngine(pkScript, tx, i, txscript.StandardVerifyFlags, nil, nil, int64(inputValue))
+		if errv != nil {
+			t.Fatal(errv)
+		}
+		if errv = vm.Execute(); errv != nil {
+			t.Fatalf("signed input %v is not valid: %v", i, errv)
+		}
+	}
+}
+
+func TestVerifyPublickeyDataOfP2wpkhDcrmAddress(t *testing.T) {
+	b, pkData := newTestBridge(t, true, true)
+	if err := b.verifyPublickeyData(pkData, tokens.SwapoutType); err != nil {
+		t.Fatalf("public key of p2wpkh dcrm address is not accepted: %v", err)
+	}
+	key, _ := crypto.HexToECDSA(testPrivateKey)
+	if err := b.verifyPublickeyData(crypto.FromECDSAPub(&key.PublicKey), tokens.SwapoutType); err == nil {
+		t.Fatal("uncompressed public key should not match p2wpkh dcrm address")
+	}
+	other, _ := hex.DecodeString("025476c2e83188368da1ff3e292e7acafcdb3566bb0ad253f62fc70f07aeee6357")
+	if err := b.verifyPublickeyData(other, tokens.SwapRecallType); err == nil {
+		t.Fatal("public key of another address should not match p2wpkh dcrm address")
+	}
+
+	// signing with the key of p2wpkh dcrm address
+	if err := signAndVerifyTestTx(t, b, tokens.SwapoutType); err != nil {
+		t.Fatalf("sign swapout tx with p2wpkh dcrm address failed: %v", err)
+	}
+	b, _ = newTestBridge(t, false, true)
+	if err := signAndVerifyTestTx(t, b, tokens.SwapinType); err != nil {
+		t.Fatalf("sign swapin tx with p2wpkh dcrm address failed: %v", err)
+	}
+}
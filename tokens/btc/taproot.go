@@ -0,0 +1,168 @@
+package btc
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/bech32"
+)
+
+// taproot (segwit version 1) addresses are encoded with bech32m (BIP350),
+// which is not supported by the btcutil version we are using.
+
+const (
+	bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	bech32mConst  = 0x2bc830a3
+
+	taprootWitnessVersion = 1
+	taprootProgramSize    = 32
+)
+
+var errInvalidTaprootAddress = errors.New("invalid taproot address")
+
+// AddressTaproot pay-to-taproot address (implements btcutil.Address)
+type AddressTaproot struct {
+	hrp            string
+	witnessProgram [taprootProgramSize]byte
+}
+
+// NewAddressTaproot new taproot address from witness program (the tweaked public key)
+func NewAddressTaproot(witnessProg []byte, net *chaincfg.Params) (*AddressTaproot, error) {
+	if len(witnessProg) != taprootProgramSize {
+		return nil, errInvalidTaprootAddress
+	}
+	addr := &AddressTaproot{hrp: strings.ToLower(net.Bech32HRPSegwit)}
+	copy(addr.witnessProgram[:], witnessProg)
+	return addr, nil
+}
+
+// EncodeAddress impl btcutil.Address
+func (a *AddressTaproot) EncodeAddress() string {
+	converted, err := bech32.ConvertBits(a.witnessProgram[:], 8, 5, true)
+	if err != nil {
+		return ""
+	}
+	data := append([]byte{taprootWitnessVersion}, converted...)
+	checksum := bech32mChecksum(a.hrp, data)
+	var sb strings.Builder
+	sb.WriteString(a.hrp)
+	sb.WriteByte('1')
+	for _, b := range append(data, checksum...) {
+		sb.WriteByte(bech32Charset[b])
+	}
+	return sb.String()
+}
+
+// ScriptAddress impl btcutil.Address
+func (a *AddressTaproot) ScriptAddress() []byte {
+	return a.witnessProgram[:]
+}
+
+// IsForNet impl btcutil.Address
+func (a *AddressTaproot) IsForNet(net *chaincfg.Params) bool {
+	return a.hrp == net.Bech32HRPSegwit
+}
+
+// String impl btcutil.Address
+func (a *AddressTaproot) String() string {
+	return a.EncodeAddress()
+}
+
+// PayToAddrScript pay-to-taproot script (OP_1 <32 bytes witness program>)
+func (a *AddressTaproot) PayToAddrScript() ([]byte, error) {
+	return txscript.NewScriptBuilder().AddOp(txscript.OP_1).AddData(a.witnessProgram[:]).Script()
+}
+
+// DecodeAddress decode address, support taproot address besides what btcutil supports
+func DecodeAddress(addr string, net *chaincfg.Params) (btcutil.Address, error) {
+	address, err := btcutil.DecodeAddress(addr, net)
+	if err == nil {
+		return address, nil
+	}
+	if taprootAddress, errt := decodeTaprootAddress(addr, net); errt == nil {
+		return taprootAddress, nil
+	}
+	return nil, err
+}
+
+// PayToAddrScript pay to address script, support taproot address besides what txscript supports
+func PayToAddrScript(address btcutil.Address) ([]byte, error) {
+	if taprootAddress, ok := address.(*AddressTaproot); ok {
+		return taprootAddress.PayToAddrScript()
+	}
+	return txscript.PayToAddrScript(address)
+}
+
+func decodeTaprootAddress(addr string, net *chaincfg.Params) (*AddressTaproot, error) {
+	if len(addr) > 90 || (strings.ToLower(addr) != addr && strings.ToUpper(addr) != addr) {
+		return nil, errInvalidTaprootAddress
+	}
+	addr = strings.ToLower(addr)
+	sep := strings.LastIndexByte(addr, '1')
+	if sep < 1 || sep+7 > len(addr) {
+		return nil, errInvalidTaprootAddress
+	}
+	hrp := addr[:sep]
+	if hrp != net.Bech32HRPSegwit {
+		return nil, errInvalidTaprootAddress
+	}
+	data := make([]byte, 0, len(addr)-sep-1)
+	for _, c := range addr[sep+1:] {
+		idx := strings.IndexRune(bech32Charset, c)
+		if idx < 0 {
+			return nil, errInvalidTaprootAddress
+		}
+		data = append(data, byte(idx))
+	}
+	if bech32Polymod(hrp, data) != bech32mConst {
+		return nil, errInvalidTaprootAddress
+	}
+	data = data[:len(data)-6]
+	if len(data) == 0 || data[0] != taprootWitnessVersion {
+		return nil, errInvalidTaprootAddress
+	}
+	program, err := bech32.ConvertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return nil, errInvalidTaprootAddress
+	}
+	return NewAddressTaproot(program, net)
+}
+
+func bech32Polymod(hrp string, data []byte) int {
+	gen := []int{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	values := make([]int, 0, len(hrp)*2+1+len(data))
+	for _, c := range hrp {
+		values = append(values, int(c>>5))
+	}
+	values = append(values, 0)
+	for _, c := range hrp {
+		values = append(values, int(c&31))
+	}
+	for _, d := range data {
+		values = append(values, int(d))
+	}
+	chk := 1
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ v
+		for i := 0; i < 5; i++ {
+			if (b>>uint(i))&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32mChecksum(hrp string, data []byte) []byte {
+	values := append(append([]byte{}, data...), 0, 0, 0, 0, 0, 0)
+	polymod := bech32Polymod(hrp, values) ^ bech32mConst
+	checksum := make([]byte, 6)
+	for i := 0; i < 6; i++ {
+		checksum[i] = byte((polymod >> uint(5*(5-i))) & 31)
+	}
+	return checksum
+}
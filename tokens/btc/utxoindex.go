@@ -0,0 +1,147 @@
+package btc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
+	"github.com/fsn-dev/crossChain-Bridge/log"
+	"github.com/fsn-dev/crossChain-Bridge/mongodb"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+)
+
+var errUtxoIndexNotReady = errors.New("local utxo index is not ready")
+
+// lock prefixes of utxos selected by in-flight txs which are not sent yet (followed by swap ID),
+// utxos spent by txs in txpool are locked by the spending txid.
+const (
+	swapLockPrefix = "swap:"
+	cpfpLockPrefix = "cpfp:"
+)
+
+// utxoIndex local index of utxos of tracked addresses (the dcrm address and registered p2sh addresses).
+// it is persisted in storage and followed by the utxo tracker from scanned blocks and txpool.
+// utxos spent by txs in txpool or selected by in-flight swap txs are locked,
+// and are not selected again until they are spent or the lock expired.
+type utxoIndex struct {
+	sync.Mutex
+
+	ready bool // initial sync is finished
+}
+
+func newUtxoIndex() *utxoIndex {
+	return &utxoIndex{}
+}
+
+func outPointKey(hash string, index uint32) string {
+	return fmt.Sprintf("%v:%v", hash, index)
+}
+
+func (idx *utxoIndex) setReady() {
+	idx.Lock()
+	defer idx.Unlock()
+	idx.ready = true
+}
+
+func (idx *utxoIndex) isReady() bool {
+	idx.Lock()
+	defer idx.Unlock()
+	return idx.ready
+}
+
+// loadUtxos load spendable utxos of address, locked utxos are excluded.
+// caller should hold the lock.
+func (idx *utxoIndex) loadUtxos(address string) ([]*Utxo, error) {
+	if !idx.ready {
+		return nil, errUtxoIndexNotReady
+	}
+	trackedUtxos, err := mongodb.FindUtxos(address)
+	if err != nil {
+		return nil, err
+	}
+	utxos := make([]*Utxo, 0, len(trackedUtxos))
+	for _, mu := range trackedUtxos {
+		value := btcutil.Amount(mu.Value)
+		if value <= 0 || value > btcutil.MaxSatoshi {
+			continue
+		}
+		if mu.LockedBy != "" {
+			continue
+		}
+		utxos = append(utxos, &Utxo{
+			Hash:   mu.TxID,
+			Index:  mu.Vout,
+			Value:  value,
+			Height: mu.Height,
+		})
+	}
+	return utxos, nil
+}
+
+// lockUtxos lock out points selected by in-flight tx, untracked out points are ignored.
+// caller should hold the lock.
+func (idx *utxoIndex) lockUtxos(points []*tokens.BtcOutPoint, lockedBy string) {
+	now := time.Now().Unix()
+	for _, point := range points {
+		mu, err := mongodb.FindUtxo(outPointKey(point.Hash, point.Index))
+		if err != nil {
+			continue
+		}
+		mu.LockedBy = lockedBy
+		mu.LockTime = now
+		mu.Timestamp = now
+		_ = mongodb.UpdateUtxo(mu)
+	}
+	log.Debug("lock utxos", "outpoints", len(points), "lockedBy", lockedBy)
+}
+
+// unlockUtxos unlock out points locked by in-flight tx which is never sent,
+// out points locked by txs in txpool (or by others) are kept.
+// caller should hold the lock.
+func (idx *utxoIndex) unlockUtxos(points []*tokens.BtcOutPoint, lockedBy string) {
+	now := time.Now().Unix()
+	for _, point := range points {
+		mu, err := mongodb.FindUtxo(outPointKey(point.Hash, point.Index))
+		if err != nil || mu.LockedBy != lockedBy {
+			continue
+		}
+		mu.LockedBy = ""
+		mu.LockTime = 0
+		mu.Timestamp = now
+		_ = mongodb.UpdateUtxo(mu)
+	}
+	log.Debug("unlock utxos", "outpoints", len(points), "lockedBy", lockedBy)
+}
+
+// getInFlightSwapID get swap ID of utxo lock of in-flight tx which is not sent yet
+func getInFlightSwapID(lockedBy string) (swapID string, ok bool) {
+	for _, prefix := range []string{swapLockPrefix, cpfpLockPrefix} {
+		if strings.HasPrefix(lockedBy, prefix) {
+			return lockedBy[len(prefix):], true
+		}
+	}
+	return "", false
+}
+
+// UnlockUtxos unlock utxos locked by raw tx of swap (or its cpfp child), only called if raw tx is never broadcast
+func (b *Bridge) UnlockUtxos(rawTx interface{}, swapID string) {
+	authoredTx, ok := rawTx.(*txauthor.AuthoredTx)
+	if !ok || swapID == "" {
+		return
+	}
+	points := make([]*tokens.BtcOutPoint, len(authoredTx.Tx.TxIn))
+	for i, txin := range authoredTx.Tx.TxIn {
+		points[i] = &tokens.BtcOutPoint{
+			Hash:  txin.PreviousOutPoint.Hash.String(),
+			Index: txin.PreviousOutPoint.Index,
+		}
+	}
+	b.utxoIndex.Lock()
+	defer b.utxoIndex.Unlock()
+	b.utxoIndex.unlockUtxos(points, swapLockPrefix+swapID)
+	b.utxoIndex.unlockUtxos(points, cpfpLockPrefix+swapID)
+}
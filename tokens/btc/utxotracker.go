@@ -0,0 +1,291 @@
+package btc
+
+import (
+	"strings"
+	"time"
+
+	"github.com/fsn-dev/crossChain-Bridge/dcrm"
+	"github.com/fsn-dev/crossChain-Bridge/log"
+	"github.com/fsn-dev/crossChain-Bridge/mongodb"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+	"github.com/fsn-dev/crossChain-Bridge/tokens/btc/electrs"
+)
+
+var (
+	utxoLockCheckInterval = 60 * time.Second
+	p2shAddressPageLimit  = 100
+)
+
+// isUtxoTrackerEnabled utxos are tracked by swap server which has storage
+func isUtxoTrackerEnabled() bool {
+	return dcrm.IsSwapServer() && mongodb.GetStore() != nil
+}
+
+// StartUtxoTrackerJob sync utxos of tracked addresses at start,
+// then utxos are followed from scanned txs, and expired locks are checked periodically.
+func (b *Bridge) StartUtxoTrackerJob() {
+	if !isUtxoTrackerEnabled() {
+		return
+	}
+	log.Info("[utxotracker] start utxo tracker job", "pairID", b.PairID, "isSrc", b.IsSrc)
+
+	for {
+		err := b.syncTrackedUtxos()
+		if err == nil {
+			break
+		}
+		log.Error("[utxotracker] sync utxos failed", "pairID", b.PairID, "isSrc", b.IsSrc, "err", err)
+		time.Sleep(retryIntervalInScanJob)
+	}
+	b.utxoIndex.setReady()
+	log.Info("[utxotracker] sync utxos finished", "pairID", b.PairID, "isSrc", b.IsSrc)
+
+	for {
+		time.Sleep(utxoLockCheckInterval)
+		b.checkExpiredUtxoLocks()
+	}
+}
+
+// isTrackedAddress the dcrm address, and registered p2sh (p2wsh) addresses of source endpoint
+func (b *Bridge) isTrackedAddress(address string) bool {
+	if address == b.TokenConfig.DcrmAddress {
+		return true
+	}
+	if b.IsSrc && (b.IsP2shAddress(address) || b.IsP2wshAddress(address)) {
+		return b.getP2shBindAddress(address) != ""
+	}
+	return false
+}
+
+func (b *Bridge) syncTrackedUtxos() error {
+	if err := b.syncAddressUtxos(b.TokenConfig.DcrmAddress); err != nil {
+		return err
+	}
+	if !b.IsSrc {
+		return nil
+	}
+	for offset := 0; ; offset += p2shAddressPageLimit {
+		p2shAddrs, err := mongodb.FindP2shAddresses(b.PairID, offset, p2shAddressPageLimit)
+		if err != nil {
+			return err
+		}
+		for _, p2shAddr := range p2shAddrs {
+			for _, address := range []string{p2shAddr.P2shAddress, p2shAddr.P2wshAddress} {
+				if address == "" {
+					continue
+				}
+				if err = b.syncAddressUtxos(address); err != nil {
+					return err
+				}
+			}
+		}
+		if len(p2shAddrs) < p2shAddressPageLimit {
+			return nil
+		}
+	}
+}
+
+// syncAddressUtxos sync utxos of address from gateway, lock state of existing utxos is kept
+func (b *Bridge) syncAddressUtxos(address string) error {
+	electUtxos, err := b.findUxtosWithRetry(address)
+	if err != nil {
+		return err
+	}
+	unspents := make(map[string]struct{}, len(electUtxos))
+	for _, electUtxo := range electUtxos {
+		if electUtxo.Txid == nil || electUtxo.Vout == nil || electUtxo.Value == nil {
+			continue
+		}
+		var height uint64
+		if status := electUtxo.Status; status != nil && status.Confirmed != nil && *status.Confirmed && status.BlockHeight != nil {
+			height = *status.BlockHeight
+		}
+		unspents[outPointKey(*electUtxo.Txid, *electUtxo.Vout)] = struct{}{}
+		b.addTrackedUtxo(*electUtxo.Txid, *electUtxo.Vout, address, *electUtxo.Value, height)
+	}
+	// remove utxos spent when we are offline
+	trackedUtxos, err := mongodb.FindUtxos(address)
+	if err != nil {
+		return err
+	}
+	for _, mu := range trackedUtxos {
+		if _, exist := unspents[mu.Key]; !exist {
+			_ = mongodb.RemoveUtxo(mu.Key)
+		}
+	}
+	log.Info("[utxotracker] sync address utxos", "pairID", b.PairID, "address", address, "utxos", len(unspents))
+	return nil
+}
+
+func (b *Bridge) addTrackedUtxo(txid string, vout uint32, address string, value, height uint64) {
+	key := outPointKey(txid, vout)
+	mu, err := mongodb.FindUtxo(key)
+	if err == nil {
+		if mu.Height == height {
+			return
+		}
+		mu.Height = height
+	} else {
+		mu = &mongodb.MgoUtxo{
+			Key:     key,
+			TxID:    txid,
+			Vout:    vout,
+			Address: address,
+			Value:   value,
+			Height:  height,
+		}
+	}
+	mu.Timestamp = time.Now().Unix()
+	_ = mongodb.UpdateUtxo(mu)
+}
+
+// trackTransaction follow utxos of tracked addresses spent or received by tx
+func (b *Bridge) trackTransaction(txid string) {
+	tx, err := b.GetTransactionByHash(txid)
+	if err != nil {
+		log.Debug("[utxotracker] get tx failed", "txid", txid, "err", err)
+		return
+	}
+	b.trackElectTransaction(tx)
+}
+
+func (b *Bridge) trackElectTransaction(tx *electrs.ElectTx) {
+	if tx.Txid == nil {
+		return
+	}
+	txid := *tx.Txid
+	var height uint64
+	if isTxConfirmed(tx) && tx.Status.BlockHeight != nil {
+		height = *tx.Status.BlockHeight
+	}
+	now := time.Now().Unix()
+
+	for _, input := range tx.Vin {
+		if input.Txid == nil || input.Vout == nil || (input.IsCoinbase != nil && *input.IsCoinbase) {
+			continue
+		}
+		mu, errf := mongodb.FindUtxo(outPointKey(*input.Txid, *input.Vout))
+		if errf != nil {
+			continue
+		}
+		if height > 0 {
+			_ = mongodb.RemoveUtxo(mu.Key)
+			continue
+		}
+		if mu.LockedBy != txid {
+			mu.LockedBy = txid
+			mu.LockTime = now
+			mu.Timestamp = now
+			_ = mongodb.UpdateUtxo(mu)
+		}
+	}
+
+	for i, output := range tx.Vout {
+		if output.ScriptpubkeyAddress == nil || output.Value == nil || *output.Value == 0 {
+			continue
+		}
+		address := *output.ScriptpubkeyAddress
+		if !b.isTrackedAddress(address) {
+			continue
+		}
+		b.addTrackedUtxo(txid, uint32(i), address, *output.Value, height)
+	}
+}
+
+// checkExpiredUtxoLocks check utxos locked longer than BtcUtxoReserveTime,
+// unlock it if it is not spent (eg. the swap tx is not sent or is dropped from txpool)
+func (b *Bridge) checkExpiredUtxoLocks() {
+	trackedUtxos, err := mongodb.FindUtxos("all")
+	if err != nil {
+		return
+	}
+	signRequests, err := mongodb.FindSignRequests()
+	if err != nil {
+		return
+	}
+	now := time.Now().Unix()
+	for _, mu := range trackedUtxos {
+		if mu.LockedBy == "" || mu.LockTime+tokens.BtcUtxoReserveTime > now {
+			continue
+		}
+		if !b.isTrackedAddress(mu.Address) {
+			continue
+		}
+		if b.isLockedBySigningSwap(mu.LockedBy, signRequests) {
+			continue
+		}
+		outspend, errs := b.getOutspendWithRetry(&tokens.BtcOutPoint{Hash: mu.TxID, Index: mu.Vout})
+		if errs != nil {
+			continue
+		}
+		switch {
+		case !*outspend.Spent:
+			log.Info("[utxotracker] unlock expired utxo", "key", mu.Key, "lockedBy", mu.LockedBy, "lockTime", mu.LockTime)
+			mu.LockedBy = ""
+			mu.LockTime = 0
+		case outspend.Status != nil && outspend.Status.Confirmed != nil && *outspend.Status.Confirmed:
+			_ = mongodb.RemoveUtxo(mu.Key)
+			continue
+		case outspend.Txid != nil:
+			mu.LockedBy = *outspend.Txid
+			mu.LockTime = now
+		}
+		mu.Timestamp = now
+		_ = mongodb.UpdateUtxo(mu)
+	}
+}
+
+// isLockedBySigningSwap is utxo locked by in-flight tx of swap which is still being signed
+// (its sign intent or sign request exists), the lock is kept until the tx is sent or aborted.
+func (b *Bridge) isLockedBySigningSwap(lockedBy string, signRequests []*mongodb.MgoSignRequest) bool {
+	swapID, ok := getInFlightSwapID(lockedBy)
+	if !ok {
+		return false
+	}
+	for _, req := range signRequests {
+		if req.PairID == b.PairID && strings.EqualFold(req.SwapID, swapID) {
+			return true
+		}
+	}
+	for _, swapType := range []tokens.SwapType{tokens.SwapinType, tokens.SwapoutType, tokens.SwapRecallType} {
+		_, err := mongodb.FindSignIntent(b.PairID, mongodb.GetSignIntentKey(swapID, swapType))
+		if err != mongodb.ErrItemNotFound {
+			return true
+		}
+	}
+	return false
+}
+
+// FindTrackedUtxos find unlocked utxos of tracked address in local utxo index
+func (b *Bridge) FindTrackedUtxos(address string) ([]*electrs.ElectUtxo, error) {
+	b.utxoIndex.Lock()
+	defer b.utxoIndex.Unlock()
+	utxos, err := b.utxoIndex.loadUtxos(address)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*electrs.ElectUtxo, len(utxos))
+	for i, utxo := range utxos {
+		txid, vout, value := utxo.Hash, utxo.Index, uint64(utxo.Value)
+		confirmed := utxo.Height > 0
+		result[i] = &electrs.ElectUtxo{
+			Txid:   &txid,
+			Vout:   &vout,
+			Value:  &value,
+			Status: &electrs.ElectTxStatus{Confirmed: &confirmed},
+		}
+		if confirmed {
+			height := utxo.Height
+			result[i].Status.BlockHeight = &height
+		}
+	}
+	return result, nil
+}
+
+// GetDcrmUtxos get tracked utxos of dcrm address (locked utxos included)
+func (b *Bridge) GetDcrmUtxos() ([]*mongodb.MgoUtxo, error) {
+	if !b.utxoIndex.isReady() {
+		return nil, errUtxoIndexNotReady
+	}
+	return mongodb.FindUtxos(b.TokenConfig.DcrmAddress)
+}
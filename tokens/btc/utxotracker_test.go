@@ -0,0 +1,42 @@
+package btc
+
+import (
+	"testing"
+
+	"github.com/fsn-dev/crossChain-Bridge/mongodb"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+)
+
+func TestIsLockedBySigningSwap(t *testing.T) {
+	mongodb.SetStore(mongodb.NewMemStore())
+	defer mongodb.SetStore(nil)
+
+	pairID := "testIsLockedBySigningSwap"
+	b := &Bridge{CrossChainBridgeBase: &tokens.CrossChainBridgeBase{PairID: pairID}}
+
+	_ = mongodb.UpdateSignIntent(pairID, &mongodb.MgoSignIntent{
+		Key:      mongodb.GetSignIntentKey("0x01", tokens.SwapinType),
+		TxID:     "0x01",
+		SwapType: uint32(tokens.SwapinType),
+	})
+	signRequests := []*mongodb.MgoSignRequest{
+		{Key: "keyID1", PairID: pairID, SwapID: "0x02", SwapType: uint32(tokens.SwapoutType)},
+		{Key: "keyID2", PairID: "otherPair", SwapID: "0x03", SwapType: uint32(tokens.SwapoutType)},
+	}
+
+	tests := []struct {
+		lockedBy string
+		want     bool
+	}{
+		{swapLockPrefix + "0x01", true},  // has sign intent
+		{cpfpLockPrefix + "0x02", true},  // has sign request
+		{swapLockPrefix + "0x03", false}, // sign request of another pair
+		{swapLockPrefix + "0x04", false},
+		{"0x01", false}, // locked by tx in txpool
+	}
+	for _, test := range tests {
+		if have := b.isLockedBySigningSwap(test.lockedBy, signRequests); have != test.want {
+			t.Errorf("isLockedBySigningSwap(%v) = %v, want %v", test.lockedBy, have, test.want)
+		}
+	}
+}
@@ -8,8 +8,17 @@ import (
 	"github.com/fsn-dev/crossChain-Bridge/tokens"
 )
 
-// VerifyP2shTransaction verify p2sh tx
+// VerifyP2shTransaction verify p2sh tx (deposit to p2sh or p2wsh address of bind address)
 func (b *Bridge) VerifyP2shTransaction(txHash, bindAddress string, allowUnstable bool) (*tokens.TxSwapInfo, error) {
+	if b.GatewayConfig.IsQuorumEnabled() {
+		results, err := b.GatewayConfig.QuorumCall(func(url string) (interface{}, error) {
+			return b.PinEndpoint(url).(*Bridge).VerifyP2shTransaction(txHash, bindAddress, allowUnstable)
+		}, nil)
+		if err == tokens.ErrGatewayQuorumNotReached {
+			return &tokens.TxSwapInfo{Hash: txHash}, err
+		}
+		return results[0].(*tokens.TxSwapInfo), err
+	}
 	swapInfo := &tokens.TxSwapInfo{}
 	swapInfo.Hash = txHash // Hash
 	if !b.IsSrc {
@@ -19,6 +28,10 @@ func (b *Bridge) VerifyP2shTransaction(txHash, bindAddress string, allowUnstable
 	if err != nil {
 		return swapInfo, fmt.Errorf("verify p2sh tx, wrong bind address %v", bindAddress)
 	}
+	p2wshAddress, _, err := b.GetP2wshAddress(bindAddress)
+	if err != nil {
+		return swapInfo, fmt.Errorf("verify p2sh tx, wrong bind address %v", bindAddress)
+	}
 	if !allowUnstable && !b.checkStable(txHash) {
 		return swapInfo, tokens.ErrTxNotStable
 	}
@@ -34,11 +47,16 @@ func (b *Bridge) VerifyP2shTransaction(txHash, bindAddress string, allowUnstable
 	if txStatus.BlockTime != nil {
 		swapInfo.Timestamp = *txStatus.BlockTime // Timestamp
 	}
+	depositAddress := p2shAddress
 	value, _, rightReceiver := b.getReceivedValue(tx.Vout, p2shAddress)
+	if !rightReceiver {
+		depositAddress = p2wshAddress
+		value, _, rightReceiver = b.getReceivedValue(tx.Vout, p2wshAddress)
+	}
 	if !rightReceiver {
 		return swapInfo, tokens.ErrTxWithWrongReceiver
 	}
-	swapInfo.To = p2shAddress                    // To
+	swapInfo.To = depositAddress                 // To
 	swapInfo.Bind = bindAddress                  // Bind
 	swapInfo.Value = common.BigFromUint64(value) // Value
 
@@ -49,7 +67,7 @@ func (b *Bridge) VerifyP2shTransaction(txHash, bindAddress string, allowUnstable
 		return swapInfo, tokens.ErrTxWithWrongSender
 	}
 
-	if !tokens.CheckSwapValue(swapInfo.Value, b.IsSrc) {
+	if !tokens.CheckSwapValue(b.PairID, swapInfo.Value, b.IsSrc) {
 		return swapInfo, tokens.ErrTxWithWrongValue
 	}
 
@@ -1,11 +1,9 @@
 package btc
 
 import (
-	"encoding/hex"
 	"regexp"
 	"strings"
 
-	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcwallet/wallet/txauthor"
 	"github.com/fsn-dev/crossChain-Bridge/common"
 	"github.com/fsn-dev/crossChain-Bridge/log"
@@ -20,11 +18,27 @@ func (b *Bridge) GetTransaction(txHash string) (interface{}, error) {
 
 // GetTransactionStatus impl
 func (b *Bridge) GetTransactionStatus(txHash string) *tokens.TxStatus {
+	if b.GatewayConfig.IsQuorumEnabled() {
+		txStatus, err := tokens.QuorumGetTransactionStatus(b, txHash)
+		if err != nil {
+			log.Debug(b.TokenConfig.BlockChain+" Bridge::QuorumGetTransactionStatus fail", "tx", txHash, "err", err)
+			return &tokens.TxStatus{}
+		}
+		return txStatus
+	}
+	txStatus, err := b.QueryTransactionStatus(txHash)
+	if err != nil {
+		log.Debug(b.TokenConfig.BlockChain+" Bridge::GetTransactionStatus fail", "tx", txHash, "err", err)
+	}
+	return txStatus
+}
+
+// QueryTransactionStatus impl
+func (b *Bridge) QueryTransactionStatus(txHash string) (*tokens.TxStatus, error) {
 	txStatus := &tokens.TxStatus{}
 	elcstStatus, err := b.GetElectTransactionStatus(txHash)
 	if err != nil {
-		log.Debug(b.TokenConfig.BlockChain+" Bridge::GetElectTransactionStatus fail", "tx", txHash, "err", err)
-		return txStatus
+		return txStatus, err
 	}
 	if elcstStatus.BlockHash != nil {
 		txStatus.BlockHash = *elcstStatus.BlockHash
@@ -36,14 +50,23 @@ func (b *Bridge) GetTransactionStatus(txHash string) *tokens.TxStatus {
 		txStatus.BlockHeight = *elcstStatus.BlockHeight
 		latest, err := b.GetLatestBlockNumber()
 		if err != nil {
-			log.Debug(b.TokenConfig.BlockChain+" Bridge::GetLatestBlockNumber fail", "err", err)
-			return txStatus
+			return txStatus, err
 		}
 		if latest > txStatus.BlockHeight {
 			txStatus.Confirmations = latest - txStatus.BlockHeight
 		}
 	}
-	return txStatus
+	return txStatus, nil
+}
+
+// CalcMsgHash calc msg hash (signature hash of every input) of raw tx
+func (b *Bridge) CalcMsgHash(rawTx interface{}) ([]string, error) {
+	authoredTx, ok := rawTx.(*txauthor.AuthoredTx)
+	if !ok {
+		return nil, tokens.ErrWrongRawTx
+	}
+	msgHashes, _, err := b.calcSignatureHashes(authoredTx)
+	return msgHashes, err
 }
 
 // VerifyMsgHash verify msg hash
@@ -52,19 +75,15 @@ func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHash []string, extra interf
 	if !ok {
 		return tokens.ErrWrongRawTx
 	}
-	for i, preScript := range authoredTx.PrevScripts {
-		sigScript := preScript
-		if txscript.IsPayToScriptHash(sigScript) {
-			sigScript, err = b.getRedeemScriptByOutputScrpit(preScript)
-			if err != nil {
-				return err
-			}
-		}
-		sigHash, err := txscript.CalcSignatureHash(sigScript, hashType, authoredTx.Tx, i)
-		if err != nil {
-			return err
-		}
-		if hex.EncodeToString(sigHash) != msgHash[i] {
+	sigHashes, _, err := b.calcSignatureHashes(authoredTx)
+	if err != nil {
+		return err
+	}
+	if len(sigHashes) != len(msgHash) {
+		return tokens.ErrMsgHashMismatch
+	}
+	for i, sigHash := range sigHashes {
+		if sigHash != msgHash[i] {
 			return tokens.ErrMsgHashMismatch
 		}
 	}
@@ -72,14 +91,15 @@ func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHash []string, extra interf
 }
 
 // VerifyTransaction impl
+// deposit to dcrm address is swapin if btc is source chain, otherwise it is swapout
 func (b *Bridge) VerifyTransaction(txHash string, allowUnstable bool) (*tokens.TxSwapInfo, error) {
-	if b.IsSrc {
-		return b.verifySwapinTx(txHash, allowUnstable)
+	if b.GatewayConfig.IsQuorumEnabled() {
+		return tokens.QuorumVerifyTransaction(b, txHash, allowUnstable)
 	}
-	return nil, tokens.ErrBridgeDestinationNotSupported
+	return b.verifyDepositTx(txHash, allowUnstable)
 }
 
-func (b *Bridge) verifySwapinTx(txHash string, allowUnstable bool) (*tokens.TxSwapInfo, error) {
+func (b *Bridge) verifyDepositTx(txHash string, allowUnstable bool) (*tokens.TxSwapInfo, error) {
 	swapInfo := &tokens.TxSwapInfo{}
 	swapInfo.Hash = txHash // Hash
 	if !allowUnstable && !b.checkStable(txHash) {
@@ -115,7 +135,7 @@ func (b *Bridge) verifySwapinTx(txHash string, allowUnstable bool) (*tokens.TxSw
 		return swapInfo, tokens.ErrTxWithWrongSender
 	}
 
-	if !tokens.CheckSwapValue(swapInfo.Value, b.IsSrc) {
+	if !tokens.CheckSwapValue(b.PairID, swapInfo.Value, b.IsSrc) {
 		return swapInfo, tokens.ErrTxWithWrongValue
 	}
 
@@ -123,13 +143,17 @@ func (b *Bridge) verifySwapinTx(txHash string, allowUnstable bool) (*tokens.TxSw
 	if !bindOk {
 		log.Debug("wrong memo", "memo", memoScript)
 		return swapInfo, tokens.ErrTxWithWrongMemo
-	} else if !tokens.DstBridge.IsValidAddress(swapInfo.Bind) {
+	} else if !tokens.GetCrossChainBridge(b.PairID, !b.IsSrc).IsValidAddress(swapInfo.Bind) {
 		log.Debug("wrong bind address in memo", "bind", swapInfo.Bind)
 		return swapInfo, tokens.ErrTxWithWrongMemo
 	}
 
 	if !allowUnstable {
-		log.Debug("verify swapin pass", "from", swapInfo.From, "to", swapInfo.To, "bind", swapInfo.Bind, "value", swapInfo.Value, "txid", swapInfo.Hash, "height", swapInfo.Height, "timestamp", swapInfo.Timestamp)
+		swapType := "swapin"
+		if !b.IsSrc {
+			swapType = "swapout"
+		}
+		log.Debug("verify "+swapType+" pass", "from", swapInfo.From, "to", swapInfo.To, "bind", swapInfo.Bind, "value", swapInfo.Value, "txid", swapInfo.Hash, "height", swapInfo.Height, "timestamp", swapInfo.Timestamp)
 	}
 	return swapInfo, nil
 }
@@ -145,8 +169,8 @@ func (b *Bridge) getReceivedValue(vout []*electrs.ElectTxOut, receiver string) (
 		case opReturnType:
 			memoScript = *output.ScriptpubkeyAsm
 			continue
-		case p2pkhType:
-			if *output.ScriptpubkeyAddress != receiver {
+		case p2pkhType, p2wpkhType, p2shType, p2wshType:
+			if output.ScriptpubkeyAddress == nil || *output.ScriptpubkeyAddress != receiver {
 				continue
 			}
 			rightReceiver = true
@@ -167,14 +191,35 @@ func getTxFrom(vin []*electrs.ElectTxin) string {
 	return ""
 }
 
-func getBindAddressFromMemoScipt(memoScript string) (bind string, ok bool) {
+func getMemoFromMemoScript(memoScript string) (memo []byte, ok bool) {
 	re := regexp.MustCompile("^OP_RETURN OP_PUSHBYTES_[0-9]* ")
 	parts := re.Split(memoScript, -1)
 	if len(parts) != 2 {
-		return "", false
+		return nil, false
 	}
 	memoHex := strings.TrimSpace(parts[1])
-	memo := common.FromHex(memoHex)
+	return common.FromHex(memoHex), true
+}
+
+func getTxMemo(tx *electrs.ElectTx) string {
+	for _, output := range tx.Vout {
+		if *output.ScriptpubkeyType == opReturnType {
+			memo, _ := getMemoFromMemoScript(*output.ScriptpubkeyAsm)
+			return string(memo)
+		}
+	}
+	return ""
+}
+
+func isTxConfirmed(tx *electrs.ElectTx) bool {
+	return tx.Status != nil && tx.Status.Confirmed != nil && *tx.Status.Confirmed
+}
+
+func getBindAddressFromMemoScipt(memoScript string) (bind string, ok bool) {
+	memo, ok := getMemoFromMemoScript(memoScript)
+	if !ok {
+		return "", false
+	}
 	if len(memo) <= len(tokens.LockMemoPrefix) {
 		return "", false
 	}
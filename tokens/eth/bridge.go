@@ -8,6 +8,7 @@ import (
 
 	"github.com/fsn-dev/crossChain-Bridge/log"
 	"github.com/fsn-dev/crossChain-Bridge/tokens"
+	"github.com/fsn-dev/crossChain-Bridge/tokens/tools"
 	"github.com/fsn-dev/crossChain-Bridge/types"
 )
 
@@ -21,11 +22,24 @@ const (
 type Bridge struct {
 	*tokens.CrossChainBridgeBase
 	Signer types.Signer
+
+	// ExtCodeParts extended func hashes and log topics of dest contract
+	ExtCodeParts      map[string][]byte
+	isMbtcContractABI bool
+
+	scannedTxs    *tools.CachedScannedTxs
+	scannedBlocks *tools.CachedScannedBlocks
+
+	subscriber *subscriber // push mode of scan jobs (nil if not configed)
 }
 
 // NewCrossChainBridge new bridge
-func NewCrossChainBridge(isSrc bool) *Bridge {
-	return &Bridge{CrossChainBridgeBase: tokens.NewCrossChainBridgeBase(isSrc)}
+func NewCrossChainBridge(pairID string, isSrc bool) *Bridge {
+	return &Bridge{
+		CrossChainBridgeBase: tokens.NewCrossChainBridgeBase(pairID, isSrc),
+		scannedTxs:           tools.NewCachedScannedTxs(300),
+		scannedBlocks:        tools.NewCachedScannedBlocks(67),
+	}
 }
 
 // SetTokenAndGateway set token and gateway config
@@ -34,6 +48,7 @@ func (b *Bridge) SetTokenAndGateway(tokenCfg *tokens.TokenConfig, gatewayCfg *to
 	b.VerifyChainID()
 	b.VerifyTokenCofig()
 	b.InitLatestBlockNumber()
+	b.InitSubscriber()
 }
 
 // VerifyChainID verify chain id
@@ -83,14 +98,23 @@ func (b *Bridge) VerifyChainID() {
 		panic("unsupported ethereum network")
 	}
 
-	b.Signer = types.MakeSigner("EIP155", chainID)
+	b.Signer = types.MakeSigner("London", chainID)
 
 	log.Info("VerifyChainID succeed", "networkID", networkID, "chainID", chainID)
 }
 
+// IsDynamicFeeTxSupported is dynamic fee tx (EIP-1559) supported by the signer
+func (b *Bridge) IsDynamicFeeTxSupported() bool {
+	_, ok := b.Signer.(types.LondonSigner)
+	return ok
+}
+
 // VerifyTokenCofig verify token config
 func (b *Bridge) VerifyTokenCofig() {
 	tokenCfg := b.TokenConfig
+	if !b.IsSrc {
+		b.InitExtCodeParts(tokenCfg.ContractABI)
+	}
 	if !b.IsValidAddress(tokenCfg.DcrmAddress) {
 		log.Fatal("invalid dcrm address", "address", tokenCfg.DcrmAddress)
 	}
@@ -151,7 +175,7 @@ func (b *Bridge) InitLatestBlockNumber() {
 	for {
 		latest, err = b.GetLatestBlockNumber()
 		if err == nil {
-			tokens.SetLatestBlockHeight(latest, b.IsSrc)
+			tokens.SetLatestBlockHeight(b.PairID, latest, b.IsSrc)
 			log.Info("get latst block number succeed.", "number", latest, "BlockChain", tokenCfg.BlockChain, "NetID", tokenCfg.NetID)
 			break
 		}
@@ -22,16 +22,16 @@ func (b *Bridge) BuildSwapoutTx(from, contract string, extraArgs *tokens.EthExtr
 		return nil, fmt.Errorf("not enough balance, %v < %v", balance, swapoutVal)
 	}
 	token := b.TokenConfig
-	if token != nil && !tokens.CheckSwapValue(swapoutVal, b.IsSrc) {
+	if token != nil && !tokens.CheckSwapValue(b.PairID, swapoutVal, b.IsSrc) {
 		decimals := *token.Decimals
 		minValue := tokens.ToBits(*token.MinimumSwap, decimals)
 		maxValue := tokens.ToBits(*token.MaximumSwap, decimals)
 		return nil, fmt.Errorf("wrong swapout value, not in range [%v, %v]", minValue, maxValue)
 	}
-	if tokens.SrcBridge != nil && !tokens.SrcBridge.IsValidAddress(bindAddr) {
+	if srcBridge := tokens.GetCrossChainBridge(b.PairID, true); !srcBridge.IsValidAddress(bindAddr) {
 		return nil, fmt.Errorf("wrong swapout bind address %v", bindAddr)
 	}
-	input, err := BuildSwapoutTxInput(swapoutVal, bindAddr)
+	input, err := b.BuildSwapoutTxInput(swapoutVal, bindAddr)
 	if err != nil {
 		return nil, err
 	}
@@ -58,11 +58,11 @@ func (b *Bridge) BuildSwapoutTx(from, contract string, extraArgs *tokens.EthExtr
 }
 
 // BuildSwapoutTxInput build swapout tx input
-func BuildSwapoutTxInput(swapoutVal *big.Int, bindAddr string) ([]byte, error) {
-	input := PackDataWithFuncHash(getSwapoutFuncHash(), swapoutVal, bindAddr)
+func (b *Bridge) BuildSwapoutTxInput(swapoutVal *big.Int, bindAddr string) ([]byte, error) {
+	input := PackDataWithFuncHash(b.getSwapoutFuncHash(), swapoutVal, bindAddr)
 
 	// verify input
-	bindAddress, swapoutvalue, err := parseSwapoutTxInput(&input)
+	bindAddress, swapoutvalue, err := b.parseSwapoutTxInput(&input)
 	if err != nil {
 		log.Error("parseSwapoutTxInput error", "err", err)
 		return nil, err
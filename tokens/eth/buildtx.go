@@ -1,21 +1,21 @@
 package eth
 
 import (
+	"errors"
 	"math/big"
 	"time"
 
 	"github.com/fsn-dev/crossChain-Bridge/common"
-	"github.com/fsn-dev/crossChain-Bridge/params"
 	"github.com/fsn-dev/crossChain-Bridge/tokens"
 	"github.com/fsn-dev/crossChain-Bridge/types"
 )
 
 var (
-	swapinNonce  uint64
-	swapoutNonce uint64
-
 	retryRPCCount    = 3
 	retryRPCInterval = 1 * time.Second
+
+	errWrongDynamicFeeArgs = errors.New("dynamic fee tx require gasTipCap and gasFeeCap but no gasPrice")
+	errTipAboveFeeCap      = errors.New("gasTipCap is greater than gasFeeCap")
 )
 
 // BuildRawTransaction build raw tx
@@ -47,6 +47,11 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 		input = *args.Input
 	}
 
+	if args.SwapType != tokens.NoSwapType {
+		// swap txs are paid out from dcrm address, its nonces are reserved by nonce manager
+		args.From = b.TokenConfig.DcrmAddress
+	}
+
 	extra, err := b.setDefaults(args)
 	if err != nil {
 		return nil, err
@@ -56,21 +61,26 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 		value    = args.Value
 		nonce    = *extra.Nonce
 		gasLimit = *extra.Gas
-		gasPrice = extra.GasPrice
 	)
 
-	switch args.SwapType {
-	case tokens.SwapoutType, tokens.SwapRecallType:
-		if !b.TokenConfig.IsErc20() {
-			value = tokens.CalcSwappedValue(value, false)
+	if !b.TokenConfig.IsErc20() {
+		switch args.SwapType {
+		case tokens.SwapoutType:
+			value = tokens.CalcSwappedValue(b.PairID, value, false)
+		case tokens.SwapRecallType:
+			value = tokens.CalcRecallValue(b.PairID, value)
 		}
 	}
 
 	if args.SwapType != tokens.NoSwapType {
-		args.Identifier = params.GetIdentifier()
+		args.Identifier = b.PairID
 	}
 
-	return types.NewTransaction(nonce, to, value, gasLimit, gasPrice, input), nil
+	if extra.IsDynamicFeeTx() {
+		chainID := b.Signer.ChainID()
+		return types.NewDynamicFeeTransaction(chainID, nonce, &to, value, gasLimit, extra.GasTipCap, extra.GasFeeCap, input, nil), nil
+	}
+	return types.NewTransaction(nonce, to, value, gasLimit, extra.GasPrice, input), nil
 }
 
 func (b *Bridge) setDefaults(args *tokens.BuildTxArgs) (extra *tokens.EthExtraArgs, err error) {
@@ -83,14 +93,30 @@ func (b *Bridge) setDefaults(args *tokens.BuildTxArgs) (extra *tokens.EthExtraAr
 	} else {
 		extra = args.Extra.EthExtra
 	}
-	if extra.GasPrice == nil {
+	switch {
+	case extra.IsDynamicFeeTx():
+		if !b.IsDynamicFeeTxSupported() {
+			return nil, types.ErrTxTypeNotSupported
+		}
+		if extra.GasPrice != nil || extra.GasTipCap == nil || extra.GasFeeCap == nil {
+			return nil, errWrongDynamicFeeArgs
+		}
+		if extra.GasTipCap.Cmp(extra.GasFeeCap) > 0 {
+			return nil, errTipAboveFeeCap
+		}
+	case extra.GasPrice == nil && tokens.EthEnableDynamicFeeTx && b.IsDynamicFeeTxSupported():
+		extra.GasTipCap, extra.GasFeeCap, err = b.getDynamicFee()
+		if err != nil {
+			return nil, err
+		}
+	case extra.GasPrice == nil:
 		extra.GasPrice, err = b.getGasPrice()
 		if err != nil {
 			return nil, err
 		}
 	}
 	if extra.Nonce == nil {
-		extra.Nonce, err = b.getAccountNonce(args.From, args.SwapType)
+		extra.Nonce, err = b.getAccountNonce(args.From)
 		if err != nil {
 			return nil, err
 		}
@@ -113,45 +139,63 @@ func (b *Bridge) getGasPrice() (price *big.Int, err error) {
 	return nil, err
 }
 
-func (b *Bridge) getAccountNonce(from string, swapType tokens.SwapType) (nonceptr *uint64, err error) {
-	var nonce uint64
+func (b *Bridge) getDynamicFee() (gasTipCap, gasFeeCap *big.Int, err error) {
+	var baseFee *big.Int
 	for i := 0; i < retryRPCCount; i++ {
-		nonce, err = b.GetPoolNonce(from)
+		gasTipCap, baseFee, err = b.SuggestDynamicFee()
 		if err == nil {
 			break
 		}
 		time.Sleep(retryRPCInterval)
 	}
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	if from == b.TokenConfig.DcrmAddress {
-		switch swapType {
-		case tokens.SwapinType:
-			if swapinNonce >= nonce && swapinNonce != 0 {
-				swapinNonce++
-				nonce = swapinNonce
-			} else {
-				swapinNonce = nonce
-			}
-		case tokens.SwapoutType, tokens.SwapRecallType:
-			if swapoutNonce >= nonce && swapoutNonce != 0 {
-				swapoutNonce++
-				nonce = swapoutNonce
-			} else {
-				swapoutNonce = nonce
-			}
+	if tokens.EthMaxGasTipCap != nil && gasTipCap.Cmp(tokens.EthMaxGasTipCap) > 0 {
+		gasTipCap = new(big.Int).Set(tokens.EthMaxGasTipCap)
+	}
+	// leave room for base fee increasing in the following blocks
+	gasFeeCap = new(big.Int).Mul(baseFee, big.NewInt(2))
+	gasFeeCap.Add(gasFeeCap, gasTipCap)
+	if tokens.EthMaxGasPrice != nil && gasFeeCap.Cmp(tokens.EthMaxGasPrice) > 0 {
+		gasFeeCap = new(big.Int).Set(tokens.EthMaxGasPrice)
+		if gasFeeCap.Cmp(gasTipCap) < 0 {
+			return nil, nil, tokens.ErrGasPriceExceedLimit
+		}
+	}
+	return gasTipCap, gasFeeCap, nil
+}
+
+func (b *Bridge) getPoolNonce(address string) (nonce uint64, err error) {
+	for i := 0; i < retryRPCCount; i++ {
+		nonce, err = b.GetPoolNonce(address)
+		if err == nil {
+			return nonce, nil
 		}
+		time.Sleep(retryRPCInterval)
+	}
+	return 0, err
+}
+
+func (b *Bridge) getAccountNonce(from string) (nonceptr *uint64, err error) {
+	var nonce uint64
+	if from == b.TokenConfig.DcrmAddress {
+		nonce, err = b.reserveNonce(from)
+	} else {
+		nonce, err = b.getPoolNonce(from)
+	}
+	if err != nil {
+		return nil, err
 	}
 	return &nonce, nil
 }
 
 // build input for calling `Swapin(bytes32 txhash, address account, uint256 amount)`
 func (b *Bridge) buildSwapinTxInput(args *tokens.BuildTxArgs) {
-	funcHash := getSwapinFuncHash()
+	funcHash := b.getSwapinFuncHash()
 	txHash := common.HexToHash(args.SwapID)
 	address := common.HexToAddress(args.To)
-	amount := tokens.CalcSwappedValue(args.Value, true)
+	amount := tokens.CalcSwappedValue(b.PairID, args.Value, true)
 
 	input := PackDataWithFuncHash(funcHash, txHash, address, amount)
 	args.Input = &input // input
@@ -165,7 +209,12 @@ func (b *Bridge) buildSwapinTxInput(args *tokens.BuildTxArgs) {
 func (b *Bridge) buildErc20SwapoutTxInput(args *tokens.BuildTxArgs) {
 	funcHash := erc20CodeParts["transfer"]
 	address := common.HexToAddress(args.To)
-	amount := tokens.CalcSwappedValue(args.Value, false)
+	var amount *big.Int
+	if args.SwapType == tokens.SwapRecallType {
+		amount = tokens.CalcRecallValue(b.PairID, args.Value)
+	} else {
+		amount = tokens.CalcSwappedValue(b.PairID, args.Value, false)
+	}
 
 	input := PackDataWithFuncHash(funcHash, address, amount)
 	args.Input = &input // input
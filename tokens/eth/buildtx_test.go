@@ -0,0 +1,87 @@
+package eth
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+	"github.com/fsn-dev/crossChain-Bridge/types"
+)
+
+// newTestRPCServer serve json rpc calls with fixed results of methods
+func newTestRPCServer(t *testing.T, results map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode rpc request failed: %v", err)
+			return
+		}
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		if result, exist := results[req.Method]; exist {
+			resp["result"] = result
+		} else {
+			resp["error"] = map[string]interface{}{"code": -32601, "message": "method not found: " + req.Method}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func newTestBridge(pairID string, isSrc bool, dcrmAddress, apiAddress string) *Bridge {
+	decimals := uint8(18)
+	swapFeeRate := 0.001
+	b := NewCrossChainBridge(pairID, isSrc)
+	b.TokenConfig = &tokens.TokenConfig{
+		BlockChain:  "Ethereum",
+		NetID:       "Rinkeby",
+		Symbol:      "ETH",
+		Decimals:    &decimals,
+		DcrmAddress: dcrmAddress,
+		SwapFeeRate: &swapFeeRate,
+	}
+	b.GatewayConfig = &tokens.GatewayConfig{APIAddress: apiAddress}
+	b.Signer = types.MakeSigner("London", big.NewInt(4))
+	return b
+}
+
+func TestNativeSwapoutReserveNonces(t *testing.T) {
+	server := newTestRPCServer(t, map[string]interface{}{
+		"eth_getTransactionCount": "0x5",
+		"eth_gasPrice":            "0x3b9aca00",
+	})
+	defer server.Close()
+
+	pairID := "testNativeSwapoutNonce"
+	dcrmAddress := "0x6B1a4Bc7C1e0e8F3d1D4c3Ae1A36E5C3a9c6c1Fa"
+	srcBridge := newTestBridge(pairID, true, dcrmAddress, server.URL)
+	dstBridge := newTestBridge(pairID, false, "0x2b3c2D3f0Ae13e4F9E8a2dC2e1cE1C4d4b6E3a2F", server.URL)
+	tokens.AddBridgePair(&tokens.BridgePair{PairID: pairID, SrcBridge: srcBridge, DstBridge: dstBridge})
+
+	for i, swapType := range []tokens.SwapType{tokens.SwapoutType, tokens.SwapoutType, tokens.SwapRecallType} {
+		args := &tokens.BuildTxArgs{
+			SwapInfo: tokens.SwapInfo{
+				SwapID:   "0x1b2f5e8c3c8d6a4e0f9d7b5a3c1e2f4d6b8a0c2e4f6d8b0a2c4e6f8d0b2a4c6e",
+				SwapType: swapType,
+				PairID:   pairID,
+			},
+			To:    "0x7C1e2D3f4a5B6c7D8e9F0a1B2c3D4e5F6a7B8c9D",
+			Value: big.NewInt(1e18),
+		}
+		rawTx, err := srcBridge.BuildRawTransaction(args)
+		if err != nil {
+			t.Fatalf("build swap tx %v failed: %v", i, err)
+		}
+		if args.From != dcrmAddress {
+			t.Errorf("swap tx %v is not from dcrm address, have %v", i, args.From)
+		}
+		wantNonce := uint64(5 + i)
+		if nonce := rawTx.(*types.Transaction).Nonce(); nonce != wantNonce {
+			t.Errorf("swap tx %v has nonce %v, want %v", i, nonce, wantNonce)
+		}
+	}
+}
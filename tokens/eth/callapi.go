@@ -2,21 +2,48 @@ package eth
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/fsn-dev/crossChain-Bridge/common"
 	"github.com/fsn-dev/crossChain-Bridge/common/hexutil"
 	"github.com/fsn-dev/crossChain-Bridge/rpc/client"
-	"github.com/fsn-dev/crossChain-Bridge/tools/rlp"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
 	"github.com/fsn-dev/crossChain-Bridge/types"
 )
 
+// rpcMethod get rpc method name, which can be overridden by gateway config
+func (b *Bridge) rpcMethod(method string) string {
+	if override, exist := b.GatewayConfig.RPCMethods[method]; exist && override != "" {
+		return override
+	}
+	return method
+}
+
+// callRPC call rpc method, fail over to other gateway endpoints if current one is unreachable
+func (b *Bridge) callRPC(result interface{}, method string, params ...interface{}) error {
+	return b.GatewayConfig.Call(func(url string) error {
+		return client.RPCPost(result, url, b.rpcMethod(method), params...)
+	})
+}
+
+// PinEndpoint clone bridge which only calls the specified gateway endpoint
+func (b *Bridge) PinEndpoint(url string) tokens.CrossChainBridge {
+	base := *b.CrossChainBridgeBase
+	base.GatewayConfig = b.GatewayConfig.PinEndpoint(url)
+	pinned := *b
+	pinned.CrossChainBridgeBase = &base
+	return &pinned
+}
+
 // GetLatestBlockNumber call eth_blockNumber
 func (b *Bridge) GetLatestBlockNumber() (uint64, error) {
-	gateway := b.GatewayConfig
-	url := gateway.APIAddress
+	if b.GatewayConfig.IsQuorumEnabled() {
+		return tokens.QuorumGetLatestBlockNumber(b)
+	}
 	var result string
-	err := client.RPCPost(&result, url, "eth_blockNumber")
+	err := b.callRPC(&result, "eth_blockNumber")
 	if err != nil {
 		return 0, err
 	}
@@ -25,10 +52,8 @@ func (b *Bridge) GetLatestBlockNumber() (uint64, error) {
 
 // GetBlockByHash call eth_getBlockByHash
 func (b *Bridge) GetBlockByHash(blockHash string) (*types.RPCBlock, error) {
-	gateway := b.GatewayConfig
-	url := gateway.APIAddress
 	var result *types.RPCBlock
-	err := client.RPCPost(&result, url, "eth_getBlockByHash", blockHash, false)
+	err := b.callRPC(&result, "eth_getBlockByHash", blockHash, false)
 	if err != nil {
 		return nil, err
 	}
@@ -40,10 +65,8 @@ func (b *Bridge) GetBlockByHash(blockHash string) (*types.RPCBlock, error) {
 
 // GetBlockByNumber call eth_getBlockByNumber
 func (b *Bridge) GetBlockByNumber(number *big.Int) (*types.RPCBlock, error) {
-	gateway := b.GatewayConfig
-	url := gateway.APIAddress
 	var result *types.RPCBlock
-	err := client.RPCPost(&result, url, "eth_getBlockByNumber", types.ToBlockNumArg(number), false)
+	err := b.callRPC(&result, "eth_getBlockByNumber", types.ToBlockNumArg(number), false)
 	if err != nil {
 		return nil, err
 	}
@@ -53,12 +76,32 @@ func (b *Bridge) GetBlockByNumber(number *big.Int) (*types.RPCBlock, error) {
 	return result, nil
 }
 
+// GetBlockHash get block hash of height
+func (b *Bridge) GetBlockHash(height uint64) (string, error) {
+	block, err := b.GetBlockByNumber(new(big.Int).SetUint64(height))
+	if err != nil {
+		return "", err
+	}
+	return block.Hash.String(), nil
+}
+
+// GetBlockNumberByTag call eth_getBlockByNumber with block tag (eg. safe, finalized)
+func (b *Bridge) GetBlockNumberByTag(tag string) (uint64, error) {
+	var result *types.RPCBlock
+	err := b.callRPC(&result, "eth_getBlockByNumber", tag, false)
+	if err != nil {
+		return 0, err
+	}
+	if result == nil || result.Number == nil {
+		return 0, errors.New("block not found")
+	}
+	return result.Number.ToInt().Uint64(), nil
+}
+
 // GetTransactionByHash call eth_getTransactionByHash
 func (b *Bridge) GetTransactionByHash(txHash string) (*types.RPCTransaction, error) {
-	gateway := b.GatewayConfig
-	url := gateway.APIAddress
 	var result *types.RPCTransaction
-	err := client.RPCPost(&result, url, "eth_getTransactionByHash", txHash)
+	err := b.callRPC(&result, "eth_getTransactionByHash", txHash)
 	if err != nil {
 		return nil, err
 	}
@@ -70,10 +113,8 @@ func (b *Bridge) GetTransactionByHash(txHash string) (*types.RPCTransaction, err
 
 // GetPendingTransactions call eth_pendingTransactions
 func (b *Bridge) GetPendingTransactions() ([]*types.RPCTransaction, error) {
-	gateway := b.GatewayConfig
-	url := gateway.APIAddress
 	var result []*types.RPCTransaction
-	err := client.RPCPost(&result, url, "eth_pendingTransactions")
+	err := b.callRPC(&result, "eth_pendingTransactions")
 	if err != nil {
 		return nil, err
 	}
@@ -82,10 +123,8 @@ func (b *Bridge) GetPendingTransactions() ([]*types.RPCTransaction, error) {
 
 // GetTransactionReceipt call eth_getTransactionReceipt
 func (b *Bridge) GetTransactionReceipt(txHash string) (*types.RPCTxReceipt, error) {
-	gateway := b.GatewayConfig
-	url := gateway.APIAddress
 	var result *types.RPCTxReceipt
-	err := client.RPCPost(&result, url, "eth_getTransactionReceipt", txHash)
+	err := b.callRPC(&result, "eth_getTransactionReceipt", txHash)
 	if err != nil {
 		return nil, err
 	}
@@ -97,13 +136,17 @@ func (b *Bridge) GetTransactionReceipt(txHash string) (*types.RPCTxReceipt, erro
 
 // GetContractLogs get contract logs
 func (b *Bridge) GetContractLogs(contractAddress, logTopic string, blockHeight uint64) ([]*types.RPCLog, error) {
+	return b.GetContractLogsInRange(contractAddress, logTopic, blockHeight, blockHeight)
+}
+
+// GetContractLogsInRange get contract logs in block range [fromHeight, toHeight]
+func (b *Bridge) GetContractLogsInRange(contractAddress, logTopic string, fromHeight, toHeight uint64) ([]*types.RPCLog, error) {
 	addresses := []common.Address{common.HexToAddress(contractAddress)}
 	topics := []common.Hash{common.HexToHash(logTopic)}
-	height := new(big.Int).SetUint64(blockHeight)
 
 	filter := &types.FilterQuery{
-		FromBlock: height,
-		ToBlock:   height,
+		FromBlock: new(big.Int).SetUint64(fromHeight),
+		ToBlock:   new(big.Int).SetUint64(toHeight),
 		Addresses: addresses,
 		Topics:    [][]common.Hash{topics},
 	}
@@ -112,14 +155,12 @@ func (b *Bridge) GetContractLogs(contractAddress, logTopic string, blockHeight u
 
 // GetLogs call eth_getLogs
 func (b *Bridge) GetLogs(filterQuery *types.FilterQuery) ([]*types.RPCLog, error) {
-	gateway := b.GatewayConfig
-	url := gateway.APIAddress
 	args, err := types.ToFilterArg(filterQuery)
 	if err != nil {
 		return nil, err
 	}
 	var result []*types.RPCLog
-	err = client.RPCPost(&result, url, "eth_getLogs", args)
+	err = b.callRPC(&result, "eth_getLogs", args)
 	if err != nil {
 		return nil, err
 	}
@@ -128,69 +169,127 @@ func (b *Bridge) GetLogs(filterQuery *types.FilterQuery) ([]*types.RPCLog, error
 
 // GetPoolNonce call eth_getTransactionCount
 func (b *Bridge) GetPoolNonce(address string) (uint64, error) {
-	gateway := b.GatewayConfig
-	url := gateway.APIAddress
 	account := common.HexToAddress(address)
 	var result hexutil.Uint64
-	err := client.RPCPost(&result, url, "eth_getTransactionCount", account, "pending")
+	err := b.callRPC(&result, "eth_getTransactionCount", account, "pending")
+	return uint64(result), err
+}
+
+// GetAccountNonceAt call eth_getTransactionCount at block number (nil means latest)
+func (b *Bridge) GetAccountNonceAt(address string, number *big.Int) (uint64, error) {
+	account := common.HexToAddress(address)
+	var result hexutil.Uint64
+	err := b.callRPC(&result, "eth_getTransactionCount", account, types.ToBlockNumArg(number))
 	return uint64(result), err
 }
 
+// GetBlockTransactions call eth_getBlockByNumber with full transactions
+func (b *Bridge) GetBlockTransactions(number *big.Int) ([]*types.RPCTransaction, error) {
+	var result *struct {
+		Transactions []*types.RPCTransaction `json:"transactions"`
+	}
+	err := b.callRPC(&result, "eth_getBlockByNumber", types.ToBlockNumArg(number), true)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("block not found")
+	}
+	return result.Transactions, nil
+}
+
 // SuggestPrice call eth_gasPrice
 func (b *Bridge) SuggestPrice() (*big.Int, error) {
-	gateway := b.GatewayConfig
-	url := gateway.APIAddress
 	var result hexutil.Big
-	err := client.RPCPost(&result, url, "eth_gasPrice")
+	err := b.callRPC(&result, "eth_gasPrice")
 	if err != nil {
 		return nil, err
 	}
 	return result.ToInt(), nil
 }
 
+// FeeHistory call eth_feeHistory
+func (b *Bridge) FeeHistory(blockCount int, rewardPercentiles []float64) (*types.RPCFeeHistory, error) {
+	var result types.RPCFeeHistory
+	err := b.callRPC(&result, "eth_feeHistory", hexutil.Uint(blockCount), "latest", rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SuggestDynamicFee suggest gas tip cap and base fee of pending block by eth_feeHistory
+// gas tip cap is the median of the configed reward percentile of recent blocks
+func (b *Bridge) SuggestDynamicFee() (gasTipCap, baseFee *big.Int, err error) {
+	feeHistory, err := b.FeeHistory(tokens.EthFeeHistoryBlocks, []float64{tokens.EthFeeHistoryPercentile})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(feeHistory.BaseFee) == 0 {
+		return nil, nil, errors.New("fee history without base fee")
+	}
+	// the last base fee is of the next block after the newest of the returned range
+	baseFee = feeHistory.BaseFee[len(feeHistory.BaseFee)-1].ToInt()
+
+	tips := make([]*big.Int, 0, len(feeHistory.Reward))
+	for _, reward := range feeHistory.Reward {
+		if len(reward) != 0 && reward[0] != nil {
+			tips = append(tips, reward[0].ToInt())
+		}
+	}
+	if len(tips) == 0 {
+		return nil, nil, errors.New("fee history without reward")
+	}
+	sort.Slice(tips, func(i, j int) bool { return tips[i].Cmp(tips[j]) < 0 })
+	gasTipCap = tips[len(tips)/2]
+	return gasTipCap, baseFee, nil
+}
+
 // SendSignedTransaction call eth_sendRawTransaction
 func (b *Bridge) SendSignedTransaction(tx *types.Transaction) error {
-	data, err := rlp.EncodeToBytes(tx)
+	data, err := tx.MarshalBinary()
 	if err != nil {
 		return err
 	}
-	gateway := b.GatewayConfig
-	url := gateway.APIAddress
-	var result interface{}
-	return client.RPCPost(&result, url, "eth_sendRawTransaction", common.ToHex(data))
+	rawTx := common.ToHex(data)
+	_, err = b.GatewayConfig.Broadcast(func(url string) (string, error) {
+		var result string
+		err := client.RPCPost(&result, url, b.rpcMethod("eth_sendRawTransaction"), rawTx)
+		return result, err
+	})
+	return err
 }
 
-// ChainID call eth_chainId
+// ChainID call eth_chainId (or its overriding method, eg. net_version)
 func (b *Bridge) ChainID() (*big.Int, error) {
-	gateway := b.GatewayConfig
-	url := gateway.APIAddress
-	var result hexutil.Big
-	err := client.RPCPost(&result, url, "eth_chainId")
+	var result string
+	err := b.callRPC(&result, "eth_chainId")
 	if err != nil {
 		return nil, err
 	}
-	return result.ToInt(), nil
+	// eth_chainId returns hex string, net_version returns decimal string
+	chainID, ok := new(big.Int).SetString(result, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid chain id %q", result)
+	}
+	return chainID, nil
 }
 
 // GetCode call eth_getCode
 func (b *Bridge) GetCode(contract string) ([]byte, error) {
-	gateway := b.GatewayConfig
-	url := gateway.APIAddress
 	var result hexutil.Bytes
-	err := client.RPCPost(&result, url, "eth_getCode", contract, "latest")
+	err := b.callRPC(&result, "eth_getCode", contract, "latest")
 	return []byte(result), err
 }
 
 // CallContract call eth_call
 func (b *Bridge) CallContract(contract string, data hexutil.Bytes, blockNumber string) (string, error) {
-	gateway := b.GatewayConfig
-	url := gateway.APIAddress
 	reqArgs := map[string]interface{}{
 		"to":   contract,
 		"data": data,
 	}
 	var result string
-	err := client.RPCPost(&result, url, "eth_call", reqArgs, blockNumber)
+	err := b.callRPC(&result, "eth_call", reqArgs, blockNumber)
 	if err != nil {
 		return "", err
 	}
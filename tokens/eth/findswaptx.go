@@ -0,0 +1,133 @@
+package eth
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/fsn-dev/crossChain-Bridge/common"
+	"github.com/fsn-dev/crossChain-Bridge/log"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+	"github.com/fsn-dev/crossChain-Bridge/types"
+)
+
+// FindSwapTx find swap tx of swap since block height 'fromHeight'.
+// swapin tx is found by its `Swapin(bytes32 txhash,...)` log, otherwise
+// by the tx of dcrm address which spends the nonce in args extra.
+func (b *Bridge) FindSwapTx(args *tokens.BuildTxArgs, fromHeight uint64) (txHash string, err error) {
+	latest, err := b.GetLatestBlockNumber()
+	if err != nil {
+		return "", err
+	}
+	if fromHeight > latest {
+		fromHeight = latest
+	}
+	if args.SwapType == tokens.SwapinType {
+		txHash, err = b.findSwapinTxByLog(args.SwapID, fromHeight, latest)
+		if err != nil || txHash != "" {
+			return txHash, err
+		}
+	}
+	if args.Extra == nil || args.Extra.EthExtra == nil || args.Extra.EthExtra.Nonce == nil {
+		return "", nil
+	}
+	return b.findSwapTxByNonce(args, *args.Extra.EthExtra.Nonce, fromHeight, latest)
+}
+
+func (b *Bridge) findSwapinTxByLog(swapID string, fromHeight, toHeight uint64) (string, error) {
+	filter := &types.FilterQuery{
+		Addresses: []common.Address{common.HexToAddress(b.TokenConfig.ContractAddress)},
+		Topics:    [][]common.Hash{{common.BytesToHash(b.getLogSwapinTopic())}, {common.HexToHash(swapID)}},
+	}
+	for start := fromHeight; start <= toHeight; start += maxScanLogsRange {
+		end := start + maxScanLogsRange - 1
+		if end > toHeight {
+			end = toHeight
+		}
+		filter.FromBlock = new(big.Int).SetUint64(start)
+		filter.ToBlock = new(big.Int).SetUint64(end)
+		logs, err := b.GetLogs(filter)
+		if err != nil {
+			return "", err
+		}
+		for _, rlog := range logs {
+			if rlog.Removed != nil && *rlog.Removed {
+				continue
+			}
+			log.Info("[findswaptx] found swapin log", "pairID", b.PairID, "swapID", swapID, "txHash", rlog.TxHash.String(), "from", start, "to", end)
+			return rlog.TxHash.String(), nil
+		}
+	}
+	return "", nil
+}
+
+func (b *Bridge) findSwapTxByNonce(args *tokens.BuildTxArgs, nonce, fromHeight, toHeight uint64) (string, error) {
+	dcrmAddress := b.TokenConfig.DcrmAddress
+	latestNonce, err := b.GetAccountNonceAt(dcrmAddress, nil)
+	if err != nil {
+		return "", err
+	}
+	if latestNonce <= nonce {
+		return "", nil // nonce is not spent yet
+	}
+	// find the lowest height whose account nonce is greater than 'nonce'
+	var errs error
+	count := int(toHeight - fromHeight + 1)
+	index := sort.Search(count, func(i int) bool {
+		if errs != nil {
+			return true
+		}
+		nonceAt, errf := b.GetAccountNonceAt(dcrmAddress, new(big.Int).SetUint64(fromHeight+uint64(i)))
+		if errf != nil {
+			errs = errf
+			return true
+		}
+		return nonceAt > nonce
+	})
+	if errs != nil {
+		return "", errs
+	}
+	if index == count {
+		return "", fmt.Errorf("nonce %v of %v is spent after height %v", nonce, dcrmAddress, toHeight)
+	}
+	height := fromHeight + uint64(index)
+	txs, err := b.GetBlockTransactions(new(big.Int).SetUint64(height))
+	if err != nil {
+		return "", err
+	}
+	for _, tx := range txs {
+		if tx.From == nil || tx.AccountNonce == nil || uint64(*tx.AccountNonce) != nonce ||
+			!common.IsEqualIgnoreCase(tx.From.String(), dcrmAddress) {
+			continue
+		}
+		if !b.isSwapTxOf(tx, args) {
+			log.Warn("[findswaptx] nonce is spent by another tx", "pairID", b.PairID, "swapID", args.SwapID, "nonce", nonce, "txHash", tx.Hash.String(), "blockHeight", height)
+			return "", tokens.ErrSwapTxConflicted
+		}
+		log.Info("[findswaptx] found swap tx by nonce", "pairID", b.PairID, "swapID", args.SwapID, "nonce", nonce, "txHash", tx.Hash.String(), "blockHeight", height)
+		return tx.Hash.String(), nil
+	}
+	// the nonce is spent before the swap tx is signed
+	log.Warn("[findswaptx] nonce is spent before height", "pairID", b.PairID, "swapID", args.SwapID, "nonce", nonce, "blockHeight", height)
+	return "", tokens.ErrSwapTxConflicted
+}
+
+// isSwapTxOf is tx the same as the swap tx built with args
+func (b *Bridge) isSwapTxOf(tx *types.RPCTransaction, args *tokens.BuildTxArgs) bool {
+	cpy := *args
+	cpy.Input = nil
+	rawTx, err := b.BuildRawTransaction(&cpy)
+	if err != nil {
+		return false
+	}
+	swapTx := rawTx.(*types.Transaction)
+	if tx.Recipient == nil || swapTx.To() == nil || *tx.Recipient != *swapTx.To() {
+		return false
+	}
+	var input []byte
+	if tx.Payload != nil {
+		input = *tx.Payload
+	}
+	return bytes.Equal(input, swapTx.Data())
+}
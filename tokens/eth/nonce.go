@@ -0,0 +1,305 @@
+package eth
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsn-dev/crossChain-Bridge/log"
+	"github.com/fsn-dev/crossChain-Bridge/mongodb"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+	"github.com/fsn-dev/crossChain-Bridge/types"
+)
+
+var (
+	nonceManagers     = make(map[string]*nonceManager)
+	nonceManagersLock sync.Mutex
+
+	// reserved nonce which is not in tx pool after this duration is treated as a gap
+	nonceGapTimeout = int64(600)
+)
+
+// nonceManager keeps reserved nonces of one (chain, address) account.
+// reserved nonces are persisted in storage, so that swapin and swapout which
+// spend from the same dcrm address never reuse a nonce, even after restart.
+// nonces held by unresolved sign intents and pending swap txs are never reset, released or reserved again.
+type nonceManager struct {
+	lock        sync.Mutex
+	bridge      *Bridge
+	address     string
+	info        *mongodb.MgoNonceInfo
+	held        map[uint64]struct{} // nonces of sign intents and pending swap txs (refreshed in reconcile)
+	initialized bool
+}
+
+func getNonceKey(blockChain, netID, address string) string {
+	return strings.ToLower(fmt.Sprintf("%s:%s:%s", blockChain, netID, address))
+}
+
+func (b *Bridge) getNonceManager(address string) *nonceManager {
+	token := b.TokenConfig
+	key := getNonceKey(token.BlockChain, token.NetID, address)
+
+	nonceManagersLock.Lock()
+	defer nonceManagersLock.Unlock()
+
+	m, exist := nonceManagers[key]
+	if !exist {
+		m = &nonceManager{
+			bridge:  b,
+			address: address,
+			info:    &mongodb.MgoNonceInfo{Key: key},
+		}
+		nonceManagers[key] = m
+	}
+	return m
+}
+
+// InitNonces load reserved nonces of dcrm address and reconcile with pool nonce
+func (b *Bridge) InitNonces() error {
+	m := b.getNonceManager(b.TokenConfig.DcrmAddress)
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	_, err := m.reconcile()
+	return err
+}
+
+// ReleaseNonce release nonce of raw tx which is never broadcast. only the highest reserved nonce
+// is released, a lower one is kept reserved (and filled by replacing or the nonce gap check),
+// otherwise it may be reused while the txs of higher nonces are pending.
+func (b *Bridge) ReleaseNonce(rawTx interface{}) {
+	tx, ok := rawTx.(*types.Transaction)
+	if !ok {
+		return
+	}
+	m := b.getNonceManager(b.TokenConfig.DcrmAddress)
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, err := m.reconcile(); err != nil {
+		return
+	}
+	nonce := tx.Nonce()
+	if nonce+1 != m.info.NextNonce {
+		log.Info("nonce is not the highest reserved one, do not release it", "key", m.info.Key, "nonce", nonce, "nextNonce", m.info.NextNonce)
+		return
+	}
+	m.release(nonce)
+}
+
+// CheckNonceGap detect reserved nonce which never reaches tx pool and stalls the account.
+// the gap nonce is released to let the next swap fill it, unless it is held by a swap
+// (the replace job fills it with the swap tx or its replacement).
+func (b *Bridge) CheckNonceGap() {
+	m := b.getNonceManager(b.TokenConfig.DcrmAddress)
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	poolNonce, err := m.reconcile()
+	if err != nil {
+		return
+	}
+	info := m.info
+	if poolNonce >= info.NextNonce || m.isReleased(poolNonce) || m.isHeld(poolNonce) {
+		return
+	}
+	if time.Now().Unix()-info.Timestamp < nonceGapTimeout {
+		return
+	}
+	log.Warn("detect nonce gap of account", "key", info.Key, "poolNonce", poolNonce, "nextNonce", info.NextNonce, "released", info.Released)
+	m.release(poolNonce)
+}
+
+// reserveNonce reserve a nonce of address, released nonce is reused first
+func (b *Bridge) reserveNonce(address string) (uint64, error) {
+	m := b.getNonceManager(address)
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, err := m.reconcile(); err != nil {
+		return 0, err
+	}
+	info := m.info
+	var nonce uint64
+	if len(info.Released) > 0 {
+		nonce = info.Released[0]
+		info.Released = info.Released[1:]
+	} else {
+		nonce = info.NextNonce
+		info.NextNonce++
+	}
+	info.Timestamp = time.Now().Unix()
+	m.save()
+	log.Info("reserve nonce", "key", info.Key, "nonce", nonce, "nextNonce", info.NextNonce, "released", info.Released)
+	return nonce, nil
+}
+
+// reconcile load nonce info from storage at the first time,
+// and adjust it with the pool nonce of the account and nonces held by sign intents and pending swap txs
+func (m *nonceManager) reconcile() (poolNonce uint64, err error) {
+	poolNonce, err = m.bridge.getPoolNonce(m.address)
+	if err != nil {
+		log.Warn("get pool nonce failed", "key", m.info.Key, "err", err)
+		return 0, err
+	}
+	held, err := m.loadHeldNonces()
+	if err != nil {
+		log.Warn("load held nonces failed", "key", m.info.Key, "err", err)
+		return 0, err
+	}
+	m.held = held
+	info := m.info
+	if !m.initialized {
+		if mongodb.GetStore() != nil {
+			if stored, errf := mongodb.FindNonceInfo(info.Key); errf == nil {
+				m.info, info = stored, stored
+			}
+		}
+		if info.NextNonce > poolNonce && time.Now().Unix()-info.Timestamp >= nonceGapTimeout {
+			// reserved nonces which are not held are never sent before restart
+			log.Warn("reset nonce to pool nonce", "key", info.Key, "nextNonce", info.NextNonce, "poolNonce", poolNonce)
+			info.NextNonce = poolNonce
+		}
+		m.initialized = true
+	}
+	if info.NextNonce < poolNonce {
+		info.NextNonce = poolNonce
+	}
+	// remove used and held nonces
+	released := info.Released[:0]
+	for _, nonce := range info.Released {
+		if nonce >= poolNonce && nonce < info.NextNonce && !m.isHeld(nonce) {
+			released = append(released, nonce)
+		}
+	}
+	info.Released = released
+	m.seedHeldNonces()
+	m.save()
+	return poolNonce, nil
+}
+
+func (m *nonceManager) release(nonce uint64) {
+	info := m.info
+	if nonce >= info.NextNonce || m.isReleased(nonce) {
+		return
+	}
+	if m.isHeld(nonce) {
+		log.Info("nonce is held by swap, do not release it", "key", info.Key, "nonce", nonce)
+		return
+	}
+	if nonce+1 == info.NextNonce {
+		info.NextNonce--
+		// released nonces on the top are not reserved any more
+		for len(info.Released) > 0 && info.Released[len(info.Released)-1]+1 == info.NextNonce {
+			info.Released = info.Released[:len(info.Released)-1]
+			info.NextNonce--
+		}
+	} else {
+		info.Released = append(info.Released, nonce)
+		sort.Slice(info.Released, func(i, j int) bool { return info.Released[i] < info.Released[j] })
+	}
+	m.save()
+	log.Info("release nonce", "key", info.Key, "nonce", nonce, "nextNonce", info.NextNonce, "released", info.Released)
+}
+
+func (m *nonceManager) isReleased(nonce uint64) bool {
+	for _, released := range m.info.Released {
+		if released == nonce {
+			return true
+		}
+	}
+	return false
+}
+
+// seedHeldNonces reserve held nonces which are beyond the next nonce (eg. after reset),
+// the skipped nonces below them are released to fill the gap.
+func (m *nonceManager) seedHeldNonces() {
+	info := m.info
+	held := make([]uint64, 0, len(m.held))
+	for nonce := range m.held {
+		held = append(held, nonce)
+	}
+	sort.Slice(held, func(i, j int) bool { return held[i] < held[j] })
+	for _, nonce := range held {
+		if nonce < info.NextNonce {
+			continue
+		}
+		for gap := info.NextNonce; gap < nonce; gap++ {
+			if !m.isHeld(gap) {
+				info.Released = append(info.Released, gap)
+			}
+		}
+		log.Info("reserve nonce held by swap", "key", info.Key, "nonce", nonce, "nextNonce", info.NextNonce)
+		info.NextNonce = nonce + 1
+	}
+	sort.Slice(info.Released, func(i, j int) bool { return info.Released[i] < info.Released[j] })
+}
+
+func (m *nonceManager) isHeld(nonce uint64) bool {
+	_, exist := m.held[nonce]
+	return exist
+}
+
+// loadHeldNonces load nonces of unresolved sign intents and pending swap txs of swaps paid out from the account.
+// the swap tx of an intent may be signed and sent before restart, and it's signed again
+// with the same nonce. a pending swap tx may be dropped from tx pool, and it's sent again
+// or replaced with the same nonce. so those nonces must not be given to another swap.
+func (m *nonceManager) loadHeldNonces() (map[uint64]struct{}, error) {
+	held := make(map[uint64]struct{})
+	if mongodb.GetStore() == nil {
+		return held, nil
+	}
+	for _, pairID := range tokens.GetAllPairIDs() {
+		intents, err := mongodb.FindSignIntents(pairID)
+		if err != nil {
+			return nil, err
+		}
+		for _, intent := range intents {
+			if !m.isPaidOutFrom(pairID, tokens.SwapType(intent.SwapType)) {
+				continue
+			}
+			var extra tokens.AllExtras
+			if err = json.Unmarshal([]byte(intent.Extra), &extra); err != nil {
+				continue
+			}
+			if extra.EthExtra != nil && extra.EthExtra.Nonce != nil {
+				held[*extra.EthExtra.Nonce] = struct{}{}
+			}
+		}
+		for _, isSwapin := range []bool{true, false} {
+			var results []*mongodb.MgoSwapResult
+			if isSwapin {
+				results, err = mongodb.FindSwapinResultsWithStatus(pairID, mongodb.MatchTxNotStable, 0)
+			} else {
+				results, err = mongodb.FindSwapoutResultsWithStatus(pairID, mongodb.MatchTxNotStable, 0)
+			}
+			if err != nil {
+				return nil, err
+			}
+			for _, res := range results {
+				if res.SwapTx == "" || res.SwapHeight != 0 || res.SwapNonce == nil {
+					continue
+				}
+				if m.isPaidOutFrom(pairID, tokens.SwapType(res.SwapType)) {
+					held[*res.SwapNonce] = struct{}{}
+				}
+			}
+		}
+	}
+	return held, nil
+}
+
+// isPaidOutFrom is swap paid out from the account.
+// swapin is paid out from destination chain, swapout and recall from source chain
+func (m *nonceManager) isPaidOutFrom(pairID string, swapType tokens.SwapType) bool {
+	isSrc := swapType != tokens.SwapinType
+	token := tokens.GetTokenConfig(pairID, isSrc)
+	return token != nil && getNonceKey(token.BlockChain, token.NetID, token.DcrmAddress) == m.info.Key
+}
+
+func (m *nonceManager) save() {
+	if mongodb.GetStore() == nil {
+		return
+	}
+	_ = mongodb.UpdateNonceInfo(m.info)
+}
@@ -0,0 +1,144 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/fsn-dev/crossChain-Bridge/common"
+	"github.com/fsn-dev/crossChain-Bridge/mongodb"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+	"github.com/fsn-dev/crossChain-Bridge/types"
+)
+
+func TestNonceHeldBySignIntent(t *testing.T) {
+	server := newTestRPCServer(t, map[string]interface{}{
+		"eth_getTransactionCount": "0x5",
+	})
+	defer server.Close()
+
+	mongodb.SetStore(mongodb.NewMemStore())
+	defer mongodb.SetStore(nil)
+
+	pairID := "testNonceHeldBySignIntent"
+	dcrmAddress := "0x3F4a5B6c7D8e9F0a1B2c3D4e5F6a7B8c9D0e1F2a"
+	srcBridge := newTestBridge(pairID, true, dcrmAddress, server.URL)
+	dstBridge := newTestBridge(pairID, false, "0x4A5b6C7d8E9f0A1b2C3d4E5f6A7b8C9d0E1f2A3b", server.URL)
+	tokens.AddBridgePair(&tokens.BridgePair{PairID: pairID, SrcBridge: srcBridge, DstBridge: dstBridge})
+
+	// reserved nonces before restart are out of date, except nonce 6 which is held by sign intent
+	token := srcBridge.TokenConfig
+	key := getNonceKey(token.BlockChain, token.NetID, token.DcrmAddress)
+	_ = mongodb.UpdateNonceInfo(&mongodb.MgoNonceInfo{Key: key, NextNonce: 9, Timestamp: 1})
+	_ = mongodb.UpdateSignIntent(pairID, &mongodb.MgoSignIntent{
+		Key:      mongodb.GetSignIntentKey("0x01", tokens.SwapoutType),
+		TxID:     "0x01",
+		SwapType: uint32(tokens.SwapoutType),
+		Extra:    `{"ethExtra":{"nonce":6}}`,
+	})
+	// sign intent of swapin is paid out from another account
+	_ = mongodb.UpdateSignIntent(pairID, &mongodb.MgoSignIntent{
+		Key:      mongodb.GetSignIntentKey("0x02", tokens.SwapinType),
+		TxID:     "0x02",
+		SwapType: uint32(tokens.SwapinType),
+		Extra:    `{"ethExtra":{"nonce":5}}`,
+	})
+
+	var reserved []uint64
+	for i := 0; i < 3; i++ {
+		nonce, err := srcBridge.reserveNonce(dcrmAddress)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reserved = append(reserved, nonce)
+	}
+	if reserved[0] != 5 || reserved[1] != 7 || reserved[2] != 8 {
+		t.Fatalf("reserve nonces %v, want [5 7 8]", reserved)
+	}
+
+	m := srcBridge.getNonceManager(dcrmAddress)
+	release := func(nonce, wantNextNonce uint64) {
+		srcBridge.ReleaseNonce(types.NewTransaction(nonce, common.Address{}, nil, 0, nil, nil))
+		if m.isReleased(nonce) || m.info.NextNonce != wantNextNonce {
+			t.Fatalf("release nonce %v, have next nonce %v released %v, want next nonce %v", nonce, m.info.NextNonce, m.info.Released, wantNextNonce)
+		}
+	}
+	release(6, 9) // held by sign intent
+	release(7, 9) // not the highest reserved one
+	release(8, 8)
+	release(7, 7)
+	release(6, 7) // still held by sign intent
+
+	// the swap tx of intent is resolved, its nonce can be released then
+	_ = mongodb.RemoveSignIntent(pairID, mongodb.GetSignIntentKey("0x01", tokens.SwapoutType))
+	release(6, 6)
+}
+
+func TestNonceHeldByPendingSwapTx(t *testing.T) {
+	server := newTestRPCServer(t, map[string]interface{}{
+		"eth_getTransactionCount": "0x5",
+	})
+	defer server.Close()
+
+	mongodb.SetStore(mongodb.NewMemStore())
+	defer mongodb.SetStore(nil)
+
+	pairID := "testNonceHeldByPendingSwapTx"
+	dcrmAddress := "0x5B6c7D8e9F0a1B2c3D4e5F6a7B8c9D0e1F2a3B4c"
+	srcBridge := newTestBridge(pairID, true, dcrmAddress, server.URL)
+	dstBridge := newTestBridge(pairID, false, "0x6C7d8E9f0A1b2C3d4E5f6A7b8C9d0E1f2A3b4C5d", server.URL)
+	tokens.AddBridgePair(&tokens.BridgePair{PairID: pairID, SrcBridge: srcBridge, DstBridge: dstBridge})
+
+	// swap tx of nonce 5 is recorded before restart, but it's dropped from tx pool
+	token := srcBridge.TokenConfig
+	key := getNonceKey(token.BlockChain, token.NetID, token.DcrmAddress)
+	_ = mongodb.UpdateNonceInfo(&mongodb.MgoNonceInfo{Key: key, NextNonce: 8, Timestamp: 1})
+	addSwapResult := func(txid string, swapType tokens.SwapType, nonce, swapHeight uint64) {
+		res := &mongodb.MgoSwapResult{
+			Key:        txid,
+			TxID:       txid,
+			SwapTx:     "0xaa" + txid[2:],
+			SwapHeight: swapHeight,
+			SwapNonce:  &nonce,
+			SwapType:   uint32(swapType),
+			Status:     mongodb.MatchTxNotStable,
+			Timestamp:  1,
+		}
+		var err error
+		if swapType == tokens.SwapoutType {
+			err = mongodb.AddSwapoutResult(pairID, res)
+		} else {
+			err = mongodb.AddSwapinResult(pairID, res)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	addSwapResult("0x11", tokens.SwapoutType, 5, 0)
+	addSwapResult("0x12", tokens.SwapoutType, 6, 100) // mined
+	addSwapResult("0x13", tokens.SwapinType, 6, 0)    // paid out from another account
+
+	nonce, err := srcBridge.reserveNonce(dcrmAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nonce != 6 {
+		t.Fatalf("reserve nonce %v, want 6", nonce)
+	}
+
+	m := srcBridge.getNonceManager(dcrmAddress)
+	m.info.Timestamp = 1
+	srcBridge.CheckNonceGap()
+	if m.isReleased(5) || m.info.NextNonce != 7 {
+		t.Fatalf("nonce of pending swap tx is released as a gap, next nonce %v released %v", m.info.NextNonce, m.info.Released)
+	}
+	srcBridge.ReleaseNonce(types.NewTransaction(5, common.Address{}, nil, 0, nil, nil))
+	if m.isReleased(5) {
+		t.Fatal("nonce of pending swap tx is released")
+	}
+
+	// the swap tx is mined (in the test, pool nonce is unchanged), it's a gap then
+	_ = mongodb.UpdateSwapoutResult(pairID, "0x11", &mongodb.SwapResultUpdateItems{SwapHeight: 100, Status: mongodb.MatchTxNotStable, Timestamp: 1})
+	srcBridge.CheckNonceGap()
+	if !m.isReleased(5) {
+		t.Fatalf("nonce gap is not released, next nonce %v released %v", m.info.NextNonce, m.info.Released)
+	}
+}
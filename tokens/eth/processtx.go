@@ -5,32 +5,39 @@ import (
 	"github.com/fsn-dev/crossChain-Bridge/tokens/tools"
 )
 
-func (b *Bridge) processTransaction(txid string) {
+// processTransaction verify and register swap of tx, txs which are not swaps are ignored.
+// error is returned if the tx is not verified or the swap is not registered, it should be processed again.
+func (b *Bridge) processTransaction(txid string) error {
 	if b.IsSrc {
-		_ = b.processSwapin(txid)
-	} else {
-		_ = b.processSwapout(txid)
+		return b.processSwapin(txid)
 	}
+	return b.processSwapout(txid)
 }
 
 func (b *Bridge) processSwapin(txid string) error {
-	if tools.IsSwapinExist(txid) {
+	if tools.IsSwapinExist(b.PairID, txid) {
 		return nil
 	}
 	swapInfo, err := b.VerifyTransaction(txid, true)
 	if !tokens.ShouldRegisterSwapForError(err) {
-		return err
+		if tokens.ShouldRetryVerifyForError(err) {
+			return err
+		}
+		return nil
 	}
-	return tools.RegisterSwapin(txid, swapInfo.Bind)
+	return tools.RegisterSwapin(b.PairID, txid, swapInfo.Bind)
 }
 
 func (b *Bridge) processSwapout(txid string) error {
-	if tools.IsSwapoutExist(txid) {
+	if tools.IsSwapoutExist(b.PairID, txid) {
 		return nil
 	}
 	swapInfo, err := b.VerifyTransaction(txid, true)
 	if !tokens.ShouldRegisterSwapForError(err) {
-		return err
+		if tokens.ShouldRetryVerifyForError(err) {
+			return err
+		}
+		return nil
 	}
-	return tools.RegisterSwapout(txid, swapInfo.Bind)
+	return tools.RegisterSwapout(b.PairID, txid, swapInfo.Bind)
 }
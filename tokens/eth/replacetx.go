@@ -0,0 +1,83 @@
+package eth
+
+import (
+	"math/big"
+
+	"github.com/fsn-dev/crossChain-Bridge/common"
+	"github.com/fsn-dev/crossChain-Bridge/log"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+	"github.com/fsn-dev/crossChain-Bridge/types"
+)
+
+// GetReplaceConfig get pending age to replace tx and max replace count
+func (b *Bridge) GetReplaceConfig() (pendingAge int64, maxReplaceCount int) {
+	return tokens.EthReplacePendingAge, tokens.EthMaxReplaceCount
+}
+
+// GetReplaceTxExtra get extra args to replace pending swap tx (same nonce and gas, bumped gas price)
+// dynamic fee tx is replaced by dynamic fee tx with bumped gas tip cap and gas fee cap
+func (b *Bridge) GetReplaceTxExtra(pendingTxHash string) (*tokens.AllExtras, error) {
+	tx, err := b.GetTransactionByHash(pendingTxHash)
+	if err != nil {
+		return nil, err
+	}
+	if tx.BlockNumber != nil {
+		return nil, tokens.ErrTxIsNotPending
+	}
+	if tx.From == nil || !common.IsEqualIgnoreCase(tx.From.String(), b.TokenConfig.DcrmAddress) {
+		return nil, tokens.ErrTxWithWrongSender
+	}
+
+	nonce := uint64(*tx.AccountNonce)
+	gas := uint64(*tx.GasLimit)
+	extra := &tokens.EthExtraArgs{
+		Gas:   &gas,
+		Nonce: &nonce,
+	}
+
+	if tx.Type != nil && uint64(*tx.Type) == types.DynamicFeeTxType {
+		oldGasTipCap := tx.GasTipCap.ToInt()
+		oldGasFeeCap := tx.GasFeeCap.ToInt()
+		gasTipCap := bumpGasPrice(oldGasTipCap)
+		gasFeeCap := bumpGasPrice(oldGasFeeCap)
+		if suggestTipCap, suggestFeeCap, errf := b.getDynamicFee(); errf == nil {
+			if suggestTipCap.Cmp(gasTipCap) > 0 {
+				gasTipCap = suggestTipCap
+			}
+			if suggestFeeCap.Cmp(gasFeeCap) > 0 {
+				gasFeeCap = suggestFeeCap
+			}
+		}
+		if gasTipCap.Cmp(gasFeeCap) > 0 {
+			gasFeeCap = new(big.Int).Set(gasTipCap)
+		}
+		if tokens.EthMaxGasPrice != nil && gasFeeCap.Cmp(tokens.EthMaxGasPrice) > 0 {
+			return nil, tokens.ErrGasPriceExceedLimit
+		}
+		if tokens.EthMaxGasTipCap != nil && gasTipCap.Cmp(tokens.EthMaxGasTipCap) > 0 {
+			return nil, tokens.ErrGasPriceExceedLimit
+		}
+		log.Info(b.TokenConfig.BlockChain+" GetReplaceTxExtra", "txHash", pendingTxHash, "nonce", nonce, "oldGasTipCap", oldGasTipCap, "newGasTipCap", gasTipCap, "oldGasFeeCap", oldGasFeeCap, "newGasFeeCap", gasFeeCap)
+		extra.GasTipCap = gasTipCap
+		extra.GasFeeCap = gasFeeCap
+		return &tokens.AllExtras{EthExtra: extra}, nil
+	}
+
+	oldGasPrice := tx.Price.ToInt()
+	gasPrice := bumpGasPrice(oldGasPrice)
+	if suggestPrice, errp := b.getGasPrice(); errp == nil && suggestPrice.Cmp(gasPrice) > 0 {
+		gasPrice = suggestPrice
+	}
+	if tokens.EthMaxGasPrice != nil && gasPrice.Cmp(tokens.EthMaxGasPrice) > 0 {
+		return nil, tokens.ErrGasPriceExceedLimit
+	}
+
+	log.Info(b.TokenConfig.BlockChain+" GetReplaceTxExtra", "txHash", pendingTxHash, "nonce", nonce, "oldGasPrice", oldGasPrice, "newGasPrice", gasPrice)
+	extra.GasPrice = gasPrice
+	return &tokens.AllExtras{EthExtra: extra}, nil
+}
+
+func bumpGasPrice(oldGasPrice *big.Int) *big.Int {
+	gasPrice := new(big.Int).Mul(oldGasPrice, new(big.Int).SetUint64(100+tokens.EthGasPriceBumpPercent))
+	return gasPrice.Div(gasPrice, big.NewInt(100))
+}
@@ -8,15 +8,12 @@ import (
 	"github.com/fsn-dev/crossChain-Bridge/tokens/tools"
 )
 
-var (
-	scannedBlocks = tools.NewCachedScannedBlocks(67)
-)
-
 // StartChainTransactionScanJob scan job
 func (b *Bridge) StartChainTransactionScanJob() {
-	log.Info("[scanchain] start scan chain job", "isSrc", b.IsSrc)
+	log.Info("[scanchain] start scan chain job", "pairID", b.PairID, "isSrc", b.IsSrc)
+	b.startSubscriber()
 
-	startHeight := tools.GetLatestScanHeight(b.IsSrc)
+	startHeight := tools.GetLatestScanHeight(b.PairID, b.IsSrc)
 	confirmations := *b.TokenConfig.Confirmations
 	initialHeight := b.TokenConfig.InitialHeight
 
@@ -35,11 +32,11 @@ func (b *Bridge) StartChainTransactionScanJob() {
 	if height < initialHeight {
 		height = initialHeight
 	}
-	_ = tools.UpdateLatestScanInfo(b.IsSrc, height)
+	_ = tools.UpdateLatestScanInfo(b.PairID, b.IsSrc, height)
 	log.Info("[scanchain] start scan chain loop", "isSrc", b.IsSrc, "start", height)
 
 	for {
-		latest := tools.LoopGetLatestBlockNumber(b)
+		latest := b.getLatestBlockNumberForScan()
 		for h := height + 1; h <= latest; {
 			block, err := b.GetBlockByNumber(new(big.Int).SetUint64(h))
 			if err != nil {
@@ -48,14 +45,14 @@ func (b *Bridge) StartChainTransactionScanJob() {
 				continue
 			}
 			blockHash := block.Hash.String()
-			if scannedBlocks.IsBlockScanned(blockHash) {
+			if b.scannedBlocks.IsBlockScanned(blockHash) {
 				h++
 				continue
 			}
 			for _, tx := range block.Transactions {
-				b.processTransaction(tx.String())
+				_ = b.processTransaction(tx.String())
 			}
-			scannedBlocks.CacheScannedBlock(blockHash, h)
+			b.scannedBlocks.CacheScannedBlock(blockHash, h)
 			log.Info("[scanchain] scanned chain", "isSrc", b.IsSrc, "blockHash", blockHash, "height", h, "txs", len(block.Transactions))
 			h++
 		}
@@ -63,9 +60,9 @@ func (b *Bridge) StartChainTransactionScanJob() {
 			latestStable := latest - confirmations
 			if height < latestStable {
 				height = latestStable
-				_ = tools.UpdateLatestScanInfo(b.IsSrc, height)
+				_ = tools.UpdateLatestScanInfo(b.PairID, b.IsSrc, height)
 			}
 		}
-		time.Sleep(restIntervalInScanJob)
+		b.waitForNewHead()
 	}
 }
@@ -4,17 +4,17 @@ import (
 	"time"
 
 	"github.com/fsn-dev/crossChain-Bridge/log"
-	"github.com/fsn-dev/crossChain-Bridge/tokens/tools"
-)
-
-var (
-	scannedTxs = tools.NewCachedScannedTxs(300)
 )
 
 // StartPoolTransactionScanJob scan job
 func (b *Bridge) StartPoolTransactionScanJob() {
-	log.Info("[scanpool] start scan tx pool loop", "isSrc", b.IsSrc)
+	log.Info("[scanpool] start scan tx pool loop", "pairID", b.PairID, "isSrc", b.IsSrc)
+	b.startSubscriber()
 	for {
+		if b.isPendingTxsPushed() {
+			b.processPushedPendingTxs()
+			continue
+		}
 		txs, err := b.GetPendingTransactions()
 		if err != nil {
 			log.Error("[scanpool] get pool txs error", "isSrc", b.IsSrc, "err", err)
@@ -24,12 +24,27 @@ func (b *Bridge) StartPoolTransactionScanJob() {
 		log.Info("[scanpool] scan pool tx", "isSrc", b.IsSrc, "txs", len(txs))
 		for _, tx := range txs {
 			txid := tx.Hash.String()
-			if scannedTxs.IsTxScanned(txid) {
+			if b.scannedTxs.IsTxScanned(txid) {
 				continue
 			}
-			b.processTransaction(txid)
-			scannedTxs.CacheScannedTx(txid)
+			_ = b.processTransaction(txid)
+			b.scannedTxs.CacheScannedTx(txid)
 		}
 		time.Sleep(restIntervalInScanJob)
 	}
 }
+
+// processPushedPendingTxs process pushed pending txs until the subscription drops
+func (b *Bridge) processPushedPendingTxs() {
+	for b.isPendingTxsPushed() {
+		select {
+		case txid := <-b.subscriber.pendingTxs:
+			if b.scannedTxs.IsTxScanned(txid) {
+				continue
+			}
+			_ = b.processTransaction(txid)
+			b.scannedTxs.CacheScannedTx(txid)
+		case <-time.After(restIntervalInScanJob):
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package eth
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/fsn-dev/crossChain-Bridge/common"
@@ -13,6 +16,24 @@ var (
 	maxScanHeight          = uint64(15000)
 	retryIntervalInScanJob = 3 * time.Second
 	restIntervalInScanJob  = 3 * time.Second
+
+	// block range of eth_getLogs is adjusted in [1, maxScanLogsRange]
+	maxScanLogsRange = uint64(2000)
+	// consecutive successes to double the shrinked block range
+	successesToGrowLogsRange = 10
+
+	// error messages of gateways when eth_getLogs returns too many results (or range is too wide)
+	tooManyLogsErrors = []string{
+		"more than",
+		"too many",
+		"too large",
+		"too wide",
+		"limit exceeded",
+		"size exceeded",
+		"range is too",
+		"exceed maximum block range",
+		"query timeout",
+	}
 )
 
 // StartSwapHistoryScanJob scan job
@@ -22,88 +43,180 @@ func (b *Bridge) StartSwapHistoryScanJob() {
 	}
 	log.Info("[swaphistory] start scan swap history job", "isSrc", b.IsSrc)
 
-	isProcessed := func(txid string) bool {
-		if b.IsSrc {
-			return tools.IsSwapinExist(txid)
-		}
-		return tools.IsSwapoutExist(txid)
-	}
+	b.startSubscriber()
+
+	b.scanTransactionHistory()
+}
 
-	go b.scanFirstLoop(isProcessed)
+func (b *Bridge) isSwapProcessed(txid string) bool {
+	if b.IsSrc {
+		return tools.IsSwapinExist(b.PairID, txid)
+	}
+	return tools.IsSwapoutExist(b.PairID, txid)
+}
 
-	b.scanTransactionHistory(isProcessed)
+func (b *Bridge) getSwapLogTopic() []byte {
+	if b.IsSrc {
+		return b.getLogSwapinTopic()
+	}
+	return b.getLogSwapoutTopic()
 }
 
-func (b *Bridge) getSwapLogs(blockHeight uint64) ([]*types.RPCLog, error) {
+func (b *Bridge) getSwapLogsInRange(fromHeight, toHeight uint64) ([]*types.RPCLog, error) {
 	token := b.TokenConfig
 	contractAddress := token.ContractAddress
-	var logTopic string
-	if b.IsSrc {
-		logTopic = common.ToHex(getLogSwapinTopic())
-	} else {
-		logTopic = common.ToHex(getLogSwapoutTopic())
+	logTopic := common.ToHex(b.getSwapLogTopic())
+	return b.GetContractLogsInRange(contractAddress, logTopic, fromHeight, toHeight)
+}
+
+func isTooManyLogsError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, pattern := range tooManyLogsErrors {
+		if strings.Contains(msg, pattern) {
+			return true
+		}
 	}
-	return b.GetContractLogs(contractAddress, logTopic, blockHeight)
+	return false
 }
 
-func (b *Bridge) scanFirstLoop(isProcessed func(string) bool) {
-	// first loop process all tx history no matter whether processed before
-	log.Info("[scanhistory] start first scan loop", "isSrc", b.IsSrc)
-	initialHeight := b.TokenConfig.InitialHeight
-	latest := tools.LoopGetLatestBlockNumber(b)
-	for height := latest; height+maxScanHeight > latest && height >= initialHeight; {
-		logs, err := b.getSwapLogs(height)
+// swapLogsScanner scan swap logs over adaptive block ranges,
+// the range is halved if too many results are returned, and doubled after some consecutive successes.
+type swapLogsScanner struct {
+	b          *Bridge
+	blockRange uint64
+	successes  int
+}
+
+func (b *Bridge) newSwapLogsScanner() *swapLogsScanner {
+	return &swapLogsScanner{b: b, blockRange: maxScanLogsRange}
+}
+
+// scanRange scan the first range from start (at most to end), return the next height to scan.
+// if the tx of a swap log is failed to process, the range stops before the block of the log,
+// so the checkpoint does not skip the unregistered swap.
+func (s *swapLogsScanner) scanRange(start, end uint64) (uint64, error) {
+	b := s.b
+	for {
+		to := end
+		if to-start >= s.blockRange {
+			to = start + s.blockRange - 1
+		}
+		logs, err := b.getSwapLogsInRange(start, to)
 		if err != nil {
-			log.Error("[scanhistory] get swap logs error", "isSrc", b.IsSrc, "height", height, "err", err)
-			time.Sleep(retryIntervalInScanJob)
-			continue
+			if s.blockRange > 1 && isTooManyLogsError(err) {
+				s.blockRange /= 2
+				s.successes = 0
+				log.Info("[scanhistory] shrink block range of get logs", "isSrc", b.IsSrc, "from", start, "to", to, "range", s.blockRange, "err", err)
+				continue
+			}
+			return start, err
 		}
-		for _, log := range logs {
-			txid := log.TxHash.String()
-			if !isProcessed(txid) {
-				b.processTransaction(txid)
+		for _, rlog := range logs {
+			txid := rlog.TxHash.String()
+			if b.isSwapProcessed(txid) {
+				continue
+			}
+			if err = b.processTransaction(txid); err != nil {
+				next := start
+				if rlog.BlockNumber != nil && uint64(*rlog.BlockNumber) > start {
+					next = uint64(*rlog.BlockNumber)
+				}
+				log.Warn("[scanhistory] process swap log failed", "isSrc", b.IsSrc, "txid", txid, "height", next, "err", err)
+				return next, err
 			}
 		}
-		if height > 0 {
-			height--
-		} else {
-			break
+		log.Info("[scanhistory] scan swap history", "isSrc", b.IsSrc, "from", start, "to", to, "count", len(logs))
+		s.successes++
+		if s.blockRange < maxScanLogsRange && s.successes >= successesToGrowLogsRange {
+			s.blockRange *= 2
+			if s.blockRange > maxScanLogsRange {
+				s.blockRange = maxScanLogsRange
+			}
+			s.successes = 0
 		}
+		return to + 1, nil
 	}
+}
 
-	log.Info("[scanhistory] finish first scan loop", "isSrc", b.IsSrc)
+// getHistoryScanStartHeight resume from checkpoint,
+// or start from at most 'maxScanHeight' blocks before latest if no checkpoint.
+func (b *Bridge) getHistoryScanStartHeight() uint64 {
+	initialHeight := b.TokenConfig.InitialHeight
+	start := tools.GetHistoryScanHeight(b.PairID, b.IsSrc) + 1
+	if start == 1 {
+		latest := tools.LoopGetLatestBlockNumber(b)
+		if latest > maxScanHeight {
+			start = latest - maxScanHeight + 1
+		}
+	}
+	if start < initialHeight {
+		start = initialHeight
+	}
+	return start
 }
 
-func (b *Bridge) scanTransactionHistory(isProcessed func(string) bool) {
-	log.Info("[scanhistory] start scan swap history loop")
-	var (
-		height        uint64
-		rescan        = true
-		initialHeight = b.TokenConfig.InitialHeight
-	)
+func (b *Bridge) scanTransactionHistory() {
+	height := b.getHistoryScanStartHeight()
+	log.Info("[scanhistory] start scan swap history loop", "isSrc", b.IsSrc, "start", height)
+	scanner := b.newSwapLogsScanner()
 	for {
-		if rescan || height < initialHeight || height == 0 {
-			height = tools.LoopGetLatestBlockNumber(b)
+		latest := b.getLatestBlockNumberForScan()
+		if height > latest {
+			if b.isSwapLogsPushed() {
+				b.processPushedSwapLogs()
+			} else {
+				b.waitForNewHead()
+			}
+			continue
+		}
+		next, err := scanner.scanRange(height, latest)
+		if next > height {
+			_ = tools.UpdateHistoryScanInfo(b.PairID, b.IsSrc, next-1)
+			height = next
 		}
-		logs, err := b.getSwapLogs(height)
 		if err != nil {
-			log.Error("[swaphistory] get swap logs error", "isSrc", b.IsSrc, "height", height, "err", err)
+			log.Error("[scanhistory] scan swap history error", "isSrc", b.IsSrc, "from", height, "err", err)
 			time.Sleep(retryIntervalInScanJob)
-			continue
 		}
-		log.Info("[scanhistory] scan swap history", "isSrc", b.IsSrc, "height", height, "count", len(logs))
-		for _, log := range logs {
-			txid := log.TxHash.String()
-			if isProcessed(txid) {
-				rescan = true
-				break // rescan if already processed
+	}
+}
+
+// processPushedSwapLogs process txs of pushed swap logs until the subscription drops,
+// or a while later to advance the checkpoint by scanning logs in range.
+func (b *Bridge) processPushedSwapLogs() {
+	timeout := time.After(maxWaitIntervalInPushMode)
+	for b.isSwapLogsPushed() {
+		select {
+		case txid := <-b.subscriber.swapLogTxs:
+			if !b.isSwapProcessed(txid) {
+				// the failed one is processed again by scanning logs in range
+				_ = b.processTransaction(txid)
 			}
-			b.processTransaction(txid)
+		case <-timeout:
+			return
+		case <-time.After(restIntervalInScanJob):
 		}
-		if rescan {
-			time.Sleep(restIntervalInScanJob)
-		} else if height > 0 {
-			height--
+	}
+}
+
+// BackfillSwapHistory scan swap logs in block range [start, end] and register the unprocessed swaps,
+// the history scan checkpoint is not changed.
+func (b *Bridge) BackfillSwapHistory(start, end uint64) error {
+	if b.TokenConfig.ContractAddress == "" {
+		return errors.New("backfill swap history need contract address")
+	}
+	if start > end {
+		return fmt.Errorf("backfill start height %v is greater than end height %v", start, end)
+	}
+	log.Info("[scanhistory] start backfill swap history", "pairID", b.PairID, "isSrc", b.IsSrc, "start", start, "end", end)
+	scanner := b.newSwapLogsScanner()
+	for height := start; height <= end; {
+		next, err := scanner.scanRange(height, end)
+		if err != nil {
+			return err
 		}
+		height = next
 	}
+	log.Info("[scanhistory] finish backfill swap history", "pairID", b.PairID, "isSrc", b.IsSrc, "start", start, "end", end)
+	return nil
 }
@@ -0,0 +1,45 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/fsn-dev/crossChain-Bridge/mongodb"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+)
+
+func TestScanRangeStopsBeforeFailedLog(t *testing.T) {
+	// txs of swap logs are not found, so they are failed to process
+	server := newTestRPCServer(t, map[string]interface{}{
+		"eth_getLogs": []map[string]interface{}{
+			{
+				"blockNumber":     "0xa",
+				"transactionHash": "0x1111111111111111111111111111111111111111111111111111111111111111",
+			},
+			{
+				"blockNumber":     "0xc",
+				"transactionHash": "0x2222222222222222222222222222222222222222222222222222222222222222",
+			},
+		},
+	})
+	defer server.Close()
+
+	mongodb.SetStore(mongodb.NewMemStore())
+	defer mongodb.SetStore(nil)
+
+	pairID := "testScanRangeStopsBeforeFailedLog"
+	srcBridge := newTestBridge(pairID, true, "0x7D8e9F0a1B2c3D4e5F6a7B8c9D0e1F2a3B4c5D6e", server.URL)
+	dstBridge := newTestBridge(pairID, false, "0x8E9f0A1b2C3d4E5f6A7b8C9d0E1f2A3b4C5d6E7f", server.URL)
+	dstBridge.TokenConfig.ContractAddress = "0x9F0a1B2c3D4e5F6a7B8c9D0e1F2a3B4c5D6e7F80"
+	tokens.AddBridgePair(&tokens.BridgePair{PairID: pairID, SrcBridge: srcBridge, DstBridge: dstBridge})
+
+	scanner := dstBridge.newSwapLogsScanner()
+	next, err := scanner.scanRange(5, 20)
+	if err != tokens.ErrTxNotFound || next != 10 {
+		t.Fatalf("scan range [5, 20] returns next height %v err %v, want 10 and %v", next, err, tokens.ErrTxNotFound)
+	}
+	// the failed log is in the start block, no block is scanned
+	next, err = scanner.scanRange(10, 20)
+	if err == nil || next != 10 {
+		t.Fatalf("scan range [10, 20] returns next height %v err %v, want 10 and an error", next, err)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/fsn-dev/crossChain-Bridge/common"
 	"github.com/fsn-dev/crossChain-Bridge/dcrm"
@@ -14,62 +13,54 @@ import (
 	"github.com/fsn-dev/crossChain-Bridge/types"
 )
 
-var (
-	retryCount    = 15
-	retryInterval = 10 * time.Second
-	waitInterval  = 10 * time.Second
-)
-
 // DcrmSignTransaction dcrm sign raw tx
 func (b *Bridge) DcrmSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signTx interface{}, txHash string, err error) {
-	swapinNonce--
-	tx, ok := rawTx.(*types.Transaction)
-	if !ok {
-		return nil, "", errors.New("wrong raw tx param")
+	msgHash, msgContext, err := b.GetDcrmSignMsgHash(rawTx, args)
+	if err != nil {
+		return nil, "", err
 	}
-	signer := b.Signer
-	msgHash := signer.Hash(tx)
-	jsondata, _ := json.Marshal(args)
-	msgContext := string(jsondata)
-	keyID, err := dcrm.DoSignOne(msgHash.String(), msgContext)
+	keyID, err := dcrm.DoSign(msgHash, msgContext)
+	if err != nil {
+		return nil, "", err
+	}
+	log.Info(b.TokenConfig.BlockChain+" DcrmSignTransaction start", "keyID", keyID, "msghash", msgHash, "txid", args.SwapID)
+	rsv, err := dcrm.WaitSignResult(keyID, len(msgHash))
 	if err != nil {
 		return nil, "", err
 	}
-	log.Info(b.TokenConfig.BlockChain+" DcrmSignTransaction start", "keyID", keyID, "msghash", msgHash.String(), "txid", args.SwapID)
-	time.Sleep(waitInterval)
+	return b.MakeDcrmSignedTransaction(rawTx, rsv, args)
+}
 
-	var rsv string
-	i := 0
-	for ; i < retryCount; i++ {
-		signStatus, err2 := dcrm.GetSignStatus(keyID)
-		if err2 == nil {
-			if len(signStatus.Rsv) != 1 {
-				return nil, "", fmt.Errorf("get sign status require one rsv but have %v (keyID = %v)", len(signStatus.Rsv), keyID)
-			}
+// GetDcrmSignMsgHash get msgHash of raw tx (to be signed by dcrm) and its context
+func (b *Bridge) GetDcrmSignMsgHash(rawTx interface{}, args *tokens.BuildTxArgs) (msgHash, msgContext []string, err error) {
+	tx, ok := rawTx.(*types.Transaction)
+	if !ok {
+		return nil, nil, errors.New("wrong raw tx param")
+	}
+	jsondata, _ := json.Marshal(args)
+	return []string{b.Signer.Hash(tx).String()}, []string{string(jsondata)}, nil
+}
 
-			rsv = signStatus.Rsv[0]
-			break
-		}
-		switch err2 {
-		case dcrm.ErrGetSignStatusFailed, dcrm.ErrGetSignStatusTimeout:
-			return nil, "", err2
-		}
-		log.Warn("retry get sign status as error", "err", err2, "txid", args.SwapID)
-		time.Sleep(retryInterval)
+// MakeDcrmSignedTransaction make signed tx with rsv signed by dcrm
+func (b *Bridge) MakeDcrmSignedTransaction(rawTx interface{}, rsvs []string, args *tokens.BuildTxArgs) (signTx interface{}, txHash string, err error) {
+	tx, ok := rawTx.(*types.Transaction)
+	if !ok {
+		return nil, "", errors.New("wrong raw tx param")
 	}
-	if i == retryCount || rsv == "" {
-		return nil, "", errors.New("get sign status failed")
+	if len(rsvs) != 1 {
+		return nil, "", fmt.Errorf("require one rsv but have %v", len(rsvs))
 	}
-
-	log.Trace(b.TokenConfig.BlockChain+" DcrmSignTransaction get rsv success", "keyID", keyID, "rsv", rsv)
+	rsv := rsvs[0]
+	log.Trace(b.TokenConfig.BlockChain+" DcrmSignTransaction get rsv success", "rsv", rsv, "txid", args.SwapID)
 
 	signature := common.FromHex(rsv)
 
 	if len(signature) != crypto.SignatureLength {
 		log.Error("DcrmSignTransaction wrong length of signature")
-		return nil, "", errors.New("wrong signature of keyID " + keyID)
+		return nil, "", errors.New("wrong signature " + rsv)
 	}
 
+	signer := b.Signer
 	signedTx, err := tx.WithSignature(signer, signature)
 	if err != nil {
 		return nil, "", err
@@ -86,7 +77,6 @@ func (b *Bridge) DcrmSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs
 		return nil, "", errors.New("wrong sender address")
 	}
 	txHash = signedTx.Hash().String()
-	swapinNonce++
-	log.Info(b.TokenConfig.BlockChain+" DcrmSignTransaction success", "keyID", keyID, "txhash", txHash, "nonce", swapinNonce)
+	log.Info(b.TokenConfig.BlockChain+" DcrmSignTransaction success", "txhash", txHash, "nonce", signedTx.Nonce(), "txid", args.SwapID)
 	return signedTx, txHash, err
 }
@@ -0,0 +1,222 @@
+package eth
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsn-dev/crossChain-Bridge/common"
+	"github.com/fsn-dev/crossChain-Bridge/log"
+	"github.com/fsn-dev/crossChain-Bridge/rpc/client"
+	"github.com/fsn-dev/crossChain-Bridge/tokens/tools"
+	"github.com/fsn-dev/crossChain-Bridge/types"
+)
+
+var (
+	resubscribeInterval = 10 * time.Second
+	// scan jobs still check the chain if no push is received in this interval
+	maxWaitIntervalInPushMode = 60 * time.Second
+
+	subscribeChanSize = 1000
+)
+
+// subscriber push mode of scan jobs, which subscribes new heads, swap logs and pending txs through websocket.
+// every subscription has its active flag, scan jobs fall back to polling if the flag is not set.
+type subscriber struct {
+	latestHead uint64 // atomic
+
+	url           string
+	headsActive   int32 // atomic
+	logsActive    int32 // atomic
+	pendingActive int32 // atomic
+
+	headNotify chan struct{}
+	swapLogTxs chan string
+	pendingTxs chan string
+
+	startOnce sync.Once
+}
+
+func newSubscriber(url string) *subscriber {
+	return &subscriber{
+		url:        url,
+		headNotify: make(chan struct{}, 1),
+		swapLogTxs: make(chan string, subscribeChanSize),
+		pendingTxs: make(chan string, subscribeChanSize),
+	}
+}
+
+func isFlagSet(flag *int32) bool {
+	return atomic.LoadInt32(flag) != 0
+}
+
+func setFlag(flag *int32, value bool) {
+	if value {
+		atomic.StoreInt32(flag, 1)
+	} else {
+		atomic.StoreInt32(flag, 0)
+	}
+}
+
+func pushTxid(ch chan string, txid string) {
+	select {
+	case ch <- txid:
+	default:
+		// the chain scan job will find it in block
+		log.Debug("[subscribe] drop pushed tx as channel is full", "txid", txid)
+	}
+}
+
+func notifyNewHead(s *subscriber) {
+	select {
+	case s.headNotify <- struct{}{}:
+	default:
+	}
+}
+
+// InitSubscriber init push mode of scan jobs if 'WebsocketAddress' is configed
+func (b *Bridge) InitSubscriber() {
+	if url := b.GatewayConfig.WebsocketAddress; url != "" {
+		b.subscriber = newSubscriber(url)
+	}
+}
+
+// startSubscriber start subscriber once (called by every scan job)
+func (b *Bridge) startSubscriber() {
+	s := b.subscriber
+	if s == nil {
+		return
+	}
+	s.startOnce.Do(func() {
+		go b.subscribeLoop()
+	})
+}
+
+func (b *Bridge) subscribeLoop() {
+	s := b.subscriber
+	for {
+		err := b.subscribe()
+		setFlag(&s.headsActive, false)
+		setFlag(&s.logsActive, false)
+		setFlag(&s.pendingActive, false)
+		atomic.StoreUint64(&s.latestHead, 0)
+		notifyNewHead(s) // wake up the waiting chain scan job to poll
+		log.Warn("[subscribe] subscription dropped, fall back to polling", "isSrc", b.IsSrc, "url", s.url, "err", err)
+		time.Sleep(resubscribeInterval)
+	}
+}
+
+// subscribe dial and subscribe, then dispatch notifications until the connection is broken
+func (b *Bridge) subscribe() error {
+	s := b.subscriber
+	c, err := client.DialWebsocket(s.url)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	method := b.rpcMethod("eth_subscribe")
+	heads := make(chan json.RawMessage, 16)
+	if _, err = c.Subscribe(heads, method, "newHeads"); err != nil {
+		return err
+	}
+	setFlag(&s.headsActive, true)
+
+	logs := make(chan json.RawMessage, subscribeChanSize)
+	if contractAddress := b.TokenConfig.ContractAddress; contractAddress != "" {
+		filter := map[string]interface{}{
+			"address": contractAddress,
+			"topics":  [][]string{{common.ToHex(b.getSwapLogTopic())}},
+		}
+		if _, err = c.Subscribe(logs, method, "logs", filter); err != nil {
+			log.Warn("[subscribe] subscribe swap logs failed", "isSrc", b.IsSrc, "err", err)
+		} else {
+			setFlag(&s.logsActive, true)
+		}
+	}
+
+	pendings := make(chan json.RawMessage, subscribeChanSize)
+	if _, err = c.Subscribe(pendings, method, "newPendingTransactions"); err != nil {
+		log.Warn("[subscribe] subscribe pending txs failed", "isSrc", b.IsSrc, "err", err)
+	} else {
+		setFlag(&s.pendingActive, true)
+	}
+
+	log.Info("[subscribe] subscribe success", "isSrc", b.IsSrc, "url", s.url,
+		"logs", isFlagSet(&s.logsActive), "pendingTxs", isFlagSet(&s.pendingActive))
+
+	for {
+		select {
+		case <-c.Done():
+			return c.Err()
+		case msg := <-heads:
+			var head types.RPCBlock
+			if err = json.Unmarshal(msg, &head); err != nil || head.Number == nil {
+				continue
+			}
+			atomic.StoreUint64(&s.latestHead, head.Number.ToInt().Uint64())
+			notifyNewHead(s)
+		case msg := <-logs:
+			var rlog types.RPCLog
+			if err = json.Unmarshal(msg, &rlog); err != nil || rlog.TxHash == nil {
+				continue
+			}
+			if rlog.Removed != nil && *rlog.Removed {
+				continue // removed by reorg
+			}
+			pushTxid(s.swapLogTxs, rlog.TxHash.String())
+		case msg := <-pendings:
+			if txid := parsePendingTxid(msg); txid != "" {
+				pushTxid(s.pendingTxs, txid)
+			}
+		}
+	}
+}
+
+// parsePendingTxid notification is tx hash, or full tx if the node is configed so
+func parsePendingTxid(msg json.RawMessage) string {
+	var txid string
+	if err := json.Unmarshal(msg, &txid); err == nil {
+		return txid
+	}
+	var tx types.RPCTransaction
+	if err := json.Unmarshal(msg, &tx); err == nil && tx.Hash != nil {
+		return tx.Hash.String()
+	}
+	return ""
+}
+
+func (b *Bridge) isHeadsPushed() bool {
+	return b.subscriber != nil && isFlagSet(&b.subscriber.headsActive)
+}
+
+func (b *Bridge) isSwapLogsPushed() bool {
+	return b.subscriber != nil && isFlagSet(&b.subscriber.logsActive)
+}
+
+func (b *Bridge) isPendingTxsPushed() bool {
+	return b.subscriber != nil && isFlagSet(&b.subscriber.pendingActive)
+}
+
+// getLatestBlockNumberForScan use pushed head if possible
+func (b *Bridge) getLatestBlockNumberForScan() uint64 {
+	if b.isHeadsPushed() {
+		if head := atomic.LoadUint64(&b.subscriber.latestHead); head != 0 {
+			return head
+		}
+	}
+	return tools.LoopGetLatestBlockNumber(b)
+}
+
+// waitForNewHead wait for pushed head, or rest a while in polling mode
+func (b *Bridge) waitForNewHead() {
+	if !b.isHeadsPushed() {
+		time.Sleep(restIntervalInScanJob)
+		return
+	}
+	select {
+	case <-b.subscriber.headNotify:
+	case <-time.After(maxWaitIntervalInPushMode):
+	}
+}
@@ -3,6 +3,7 @@ package eth
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/fsn-dev/crossChain-Bridge/common"
@@ -10,10 +11,13 @@ import (
 	"github.com/fsn-dev/crossChain-Bridge/tokens/btc"
 )
 
-var (
-	// ExtCodeParts extended func hashes and log topics
-	ExtCodeParts map[string][]byte
+// contract abi variants
+const (
+	ContractABIMbtc = "mBTC" // Swapout(uint256,string)
+	ContractABIMeth = "mETH" // Swapout(uint256)
+)
 
+var (
 	// first 4 bytes of `Keccak256Hash([]byte("Swapin(bytes32,address,uint256)"))`
 	swapinFuncHash = common.FromHex("0xec126c77")
 	logSwapinTopic = common.FromHex("0x05d0634fe981be85c22e2942a880821b70095d84e152c3ea3c17a4e4250d9d61")
@@ -87,36 +91,45 @@ func (b *Bridge) VerifyErc20ContractAddress(contract string) (err error) {
 
 // VerifyMbtcContractAddress verify mbtc contract
 func (b *Bridge) VerifyMbtcContractAddress(contract string) (err error) {
-	return b.VerifyContractCode(contract, ExtCodeParts, erc20CodeParts)
+	return b.VerifyContractCode(contract, b.ExtCodeParts, erc20CodeParts)
 }
 
 // InitExtCodeParts int extended code parts
-func InitExtCodeParts() {
+// contractABI is the abi variant of dest contract, default to mBTC if source of the pair is bitcoin, otherwise mETH
+func (b *Bridge) InitExtCodeParts(contractABI string) {
+	switch {
+	case strings.EqualFold(contractABI, ContractABIMbtc):
+		b.isMbtcContractABI = true
+	case strings.EqualFold(contractABI, ContractABIMeth):
+		b.isMbtcContractABI = false
+	default:
+		b.isMbtcContractABI = btc.GetBridgeOfPair(b.PairID, true) != nil
+	}
 	switch {
-	case isMbtcSwapout():
-		ExtCodeParts = mBTCExtCodeParts
+	case b.isMbtcSwapout():
+		b.ExtCodeParts = mBTCExtCodeParts
 	default:
-		ExtCodeParts = mETHExtCodeParts
+		b.ExtCodeParts = mETHExtCodeParts
 	}
-	log.Info("init extented code parts", "isMBTC", isMbtcSwapout())
+	log.Info("init extented code parts", "pairID", b.PairID, "contractABI", contractABI, "isMBTC", b.isMbtcSwapout())
 }
 
-func isMbtcSwapout() bool {
-	return btc.BridgeInstance != nil
+func (b *Bridge) isMbtcSwapout() bool {
+	return b.isMbtcContractABI
 }
 
-func getSwapinFuncHash() []byte {
-	return ExtCodeParts["SwapinFuncHash"]
+func (b *Bridge) getSwapinFuncHash() []byte {
+	return b.ExtCodeParts["SwapinFuncHash"]
 }
 
-func getLogSwapinTopic() []byte {
-	return ExtCodeParts["LogSwapinTopic"]
+func (b *Bridge) getLogSwapinTopic() []byte {
+	return b.ExtCodeParts["LogSwapinTopic"]
 }
 
-func getSwapoutFuncHash() []byte {
-	return ExtCodeParts["SwapoutFuncHash"]
+func (b *Bridge) getSwapoutFuncHash() []byte {
+	return b.ExtCodeParts["SwapoutFuncHash"]
 }
 
-func getLogSwapoutTopic() []byte {
-	return ExtCodeParts["LogSwapoutTopic"]
+func (b *Bridge) getLogSwapoutTopic() []byte {
+	return b.ExtCodeParts["LogSwapoutTopic"]
 }
@@ -52,9 +52,15 @@ func (b *Bridge) verifyErc20SwapinTxStable(txHash string) (*tokens.TxSwapInfo, e
 		log.Debug(b.TokenConfig.BlockChain+" parseErc20SwapinTxLogs failed", "err", err)
 		return swapInfo, tokens.ErrTxWithWrongInput
 	}
-	swapInfo.To = strings.ToLower(to)     // To
-	swapInfo.Value = value                // Value
-	swapInfo.Bind = strings.ToLower(from) // Bind
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		log.Debug(b.TokenConfig.BlockChain+" Bridge::GetTransaction fail", "tx", txHash, "err", err)
+		return swapInfo, tokens.ErrTxNotFound
+	}
+	input := (*[]byte)(tx.Payload)
+	swapInfo.To = strings.ToLower(to)                                       // To
+	swapInfo.Value = value                                                  // Value
+	swapInfo.Bind = getErc20SwapinBindAddress(input, strings.ToLower(from)) // Bind
 
 	if !common.IsEqualIgnoreCase(swapInfo.To, dcrmAddress) {
 		return swapInfo, tokens.ErrTxWithWrongReceiver
@@ -65,10 +71,16 @@ func (b *Bridge) verifyErc20SwapinTxStable(txHash string) (*tokens.TxSwapInfo, e
 		return swapInfo, tokens.ErrTxWithWrongSender
 	}
 
-	if !tokens.CheckSwapValue(swapInfo.Value, b.IsSrc) {
+	if !tokens.CheckSwapValue(b.PairID, swapInfo.Value, b.IsSrc) {
 		return swapInfo, tokens.ErrTxWithWrongValue
 	}
 
+	// NOTE: must verify memo at last step (as it can be recall)
+	if !tokens.GetCrossChainBridge(b.PairID, false).IsValidAddress(swapInfo.Bind) {
+		log.Debug("wrong bind address in swapin", "bind", swapInfo.Bind)
+		return swapInfo, tokens.ErrTxWithWrongMemo
+	}
+
 	log.Debug("verify erc20 swapin pass", "from", swapInfo.From, "to", swapInfo.To, "bind", swapInfo.Bind, "value", swapInfo.Value, "txid", txHash, "height", swapInfo.Height, "timestamp", swapInfo.Timestamp)
 	return swapInfo, nil
 }
@@ -101,9 +113,9 @@ func (b *Bridge) verifyErc20SwapinTxUnstable(txHash string) (*tokens.TxSwapInfo,
 	swapInfo.To = strings.ToLower(to) // To
 	swapInfo.Value = value            // Value
 	if from != "" {
-		swapInfo.Bind = strings.ToLower(from) // Bind
+		swapInfo.Bind = getErc20SwapinBindAddress(input, strings.ToLower(from)) // Bind
 	} else {
-		swapInfo.Bind = swapInfo.From // Bind
+		swapInfo.Bind = getErc20SwapinBindAddress(input, swapInfo.From) // Bind
 	}
 
 	dcrmAddress := token.DcrmAddress
@@ -116,10 +128,16 @@ func (b *Bridge) verifyErc20SwapinTxUnstable(txHash string) (*tokens.TxSwapInfo,
 		return swapInfo, tokens.ErrTxWithWrongSender
 	}
 
-	if !tokens.CheckSwapValue(swapInfo.Value, b.IsSrc) {
+	if !tokens.CheckSwapValue(b.PairID, swapInfo.Value, b.IsSrc) {
 		return swapInfo, tokens.ErrTxWithWrongValue
 	}
 
+	// NOTE: must verify memo at last step (as it can be recall)
+	if !tokens.GetCrossChainBridge(b.PairID, false).IsValidAddress(swapInfo.Bind) {
+		log.Debug("wrong bind address in swapin", "bind", swapInfo.Bind)
+		return swapInfo, tokens.ErrTxWithWrongMemo
+	}
+
 	return swapInfo, nil
 }
 
@@ -144,6 +162,24 @@ func parseErc20SwapinTxInput(input *[]byte) (from, to string, value *big.Int, er
 	return parseErc20EncodedData(encData, isTransferFrom)
 }
 
+// getErc20SwapinBindAddress get bind address from lock memo appended to the encoded
+// arguments of erc20 transfer input, default is the token sender
+func getErc20SwapinBindAddress(input *[]byte, from string) string {
+	if input == nil || len(*input) < 4 {
+		return from
+	}
+	data := *input
+	argsLen := 64
+	if bytes.Equal(data[:4], erc20CodeParts["transferFrom"]) {
+		argsLen = 96
+	}
+	if len(data) <= 4+argsLen {
+		return from
+	}
+	memo := data[4+argsLen:]
+	return getSwapinBindAddress(&memo, from)
+}
+
 func parseErc20SwapinTxLogs(logs []*types.RPCLog) (from, to string, value *big.Int, err error) {
 	for _, log := range logs {
 		if log.Removed != nil && *log.Removed {
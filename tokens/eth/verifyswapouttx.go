@@ -48,7 +48,7 @@ func (b *Bridge) verifySwapoutTxStable(txHash string) (*tokens.TxSwapInfo, error
 		return swapInfo, tokens.ErrTxWithWrongReceiver
 	}
 
-	bindAddress, value, err := parseSwapoutTxLogs(receipt.Logs)
+	bindAddress, value, err := b.parseSwapoutTxLogs(receipt.Logs)
 	if err != nil {
 		log.Debug(b.TokenConfig.BlockChain+" parseSwapoutTxLogs fail", "tx", txHash, "err", err)
 		return swapInfo, tokens.ErrTxWithWrongInput
@@ -65,12 +65,12 @@ func (b *Bridge) verifySwapoutTxStable(txHash string) (*tokens.TxSwapInfo, error
 	// 	return swapInfo, tokens.ErrTxWithWrongSender
 	// }
 
-	if !tokens.CheckSwapValue(swapInfo.Value, b.IsSrc) {
+	if !tokens.CheckSwapValue(b.PairID, swapInfo.Value, b.IsSrc) {
 		return swapInfo, tokens.ErrTxWithWrongValue
 	}
 
 	// NOTE: must verify memo at last step (as it can be recall)
-	if !tokens.SrcBridge.IsValidAddress(swapInfo.Bind) {
+	if !tokens.GetCrossChainBridge(b.PairID, true).IsValidAddress(swapInfo.Bind) {
 		log.Debug("wrong bind address in swapout", "bind", swapInfo.Bind)
 		return swapInfo, tokens.ErrTxWithWrongMemo
 	}
@@ -102,7 +102,7 @@ func (b *Bridge) verifySwapoutTxUnstable(txHash string) (*tokens.TxSwapInfo, err
 	}
 
 	input := (*[]byte)(tx.Payload)
-	bindAddress, value, err := parseSwapoutTxInput(input)
+	bindAddress, value, err := b.parseSwapoutTxInput(input)
 	if err != nil {
 		log.Debug(b.TokenConfig.BlockChain+" parseSwapoutTxInput fail", "tx", txHash, "err", err)
 		return swapInfo, tokens.ErrTxWithWrongInput
@@ -119,11 +119,11 @@ func (b *Bridge) verifySwapoutTxUnstable(txHash string) (*tokens.TxSwapInfo, err
 	// 	return swapInfo, tokens.ErrTxWithWrongSender
 	// }
 
-	if !tokens.CheckSwapValue(swapInfo.Value, b.IsSrc) {
+	if !tokens.CheckSwapValue(b.PairID, swapInfo.Value, b.IsSrc) {
 		return swapInfo, tokens.ErrTxWithWrongValue
 	}
 
-	if !tokens.SrcBridge.IsValidAddress(swapInfo.Bind) {
+	if !tokens.GetCrossChainBridge(b.PairID, true).IsValidAddress(swapInfo.Bind) {
 		log.Debug("wrong bind address in swapout", "bind", swapInfo.Bind)
 		return swapInfo, tokens.ErrTxWithWrongMemo
 	}
@@ -131,7 +131,7 @@ func (b *Bridge) verifySwapoutTxUnstable(txHash string) (*tokens.TxSwapInfo, err
 	return swapInfo, nil
 }
 
-func parseSwapoutTxInput(input *[]byte) (string, *big.Int, error) {
+func (b *Bridge) parseSwapoutTxInput(input *[]byte) (string, *big.Int, error) {
 	if input == nil {
 		return "", nil, fmt.Errorf("empty tx input")
 	}
@@ -140,16 +140,16 @@ func parseSwapoutTxInput(input *[]byte) (string, *big.Int, error) {
 		return "", nil, fmt.Errorf("wrong tx input %x", data)
 	}
 	funcHash := data[:4]
-	swapoutFuncHash := getSwapoutFuncHash()
+	swapoutFuncHash := b.getSwapoutFuncHash()
 	if !bytes.Equal(funcHash, swapoutFuncHash) {
 		return "", nil, fmt.Errorf("wrong func hash, have %x want %x", funcHash, swapoutFuncHash)
 	}
 	encData := data[4:]
-	return parseEncodedData(encData)
+	return b.parseEncodedData(encData)
 }
 
-func parseSwapoutTxLogs(logs []*types.RPCLog) (string, *big.Int, error) {
-	logSwapoutTopic := getLogSwapoutTopic()
+func (b *Bridge) parseSwapoutTxLogs(logs []*types.RPCLog) (string, *big.Int, error) {
+	logSwapoutTopic := b.getLogSwapoutTopic()
 	for _, log := range logs {
 		if log.Removed != nil && *log.Removed {
 			continue
@@ -160,13 +160,13 @@ func parseSwapoutTxLogs(logs []*types.RPCLog) (string, *big.Int, error) {
 		if !bytes.Equal(log.Topics[0].Bytes(), logSwapoutTopic) {
 			continue
 		}
-		return parseEncodedData(*log.Data)
+		return b.parseEncodedData(*log.Data)
 	}
 	return "", nil, fmt.Errorf("swapout log not found or removed")
 }
 
-func parseEncodedData(encData []byte) (string, *big.Int, error) {
-	isMbtc := isMbtcSwapout()
+func (b *Bridge) parseEncodedData(encData []byte) (string, *big.Int, error) {
+	isMbtc := b.isMbtcSwapout()
 	if isMbtc {
 		if len(encData) < 96 {
 			return "", nil, fmt.Errorf("wrong length of encoded data")
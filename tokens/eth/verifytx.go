@@ -17,11 +17,27 @@ func (b *Bridge) GetTransaction(txHash string) (interface{}, error) {
 
 // GetTransactionStatus impl
 func (b *Bridge) GetTransactionStatus(txHash string) *tokens.TxStatus {
+	if b.GatewayConfig.IsQuorumEnabled() {
+		txStatus, err := tokens.QuorumGetTransactionStatus(b, txHash)
+		if err != nil {
+			log.Debug("QuorumGetTransactionStatus fail", "hash", txHash, "err", err)
+			return &tokens.TxStatus{}
+		}
+		return txStatus
+	}
+	txStatus, err := b.QueryTransactionStatus(txHash)
+	if err != nil {
+		log.Debug("GetTransactionStatus fail", "hash", txHash, "err", err)
+	}
+	return txStatus
+}
+
+// QueryTransactionStatus impl
+func (b *Bridge) QueryTransactionStatus(txHash string) (*tokens.TxStatus, error) {
 	var txStatus tokens.TxStatus
 	txr, err := b.GetTransactionReceipt(txHash)
 	if err != nil {
-		log.Debug("GetTransactionReceipt fail", "hash", txHash, "err", err)
-		return &txStatus
+		return &txStatus, err
 	}
 	if *txr.Status != 1 {
 		log.Debug("transaction with wrong receipt status", "hash", txHash, "status", txr.Status)
@@ -31,19 +47,49 @@ func (b *Bridge) GetTransactionStatus(txHash string) *tokens.TxStatus {
 	block, err := b.GetBlockByHash(txStatus.BlockHash)
 	if err == nil {
 		txStatus.BlockTime = block.Time.ToInt().Uint64()
-	} else {
-		log.Debug("GetBlockByHash fail", "hash", txStatus.BlockHash, "err", err)
 	}
 	if *txr.Status == 1 {
-		latest, err := b.GetLatestBlockNumber()
-		if err == nil {
-			txStatus.Confirmations = latest - txStatus.BlockHeight
-		} else {
-			log.Debug("GetLatestBlockNumber fail", "err", err)
+		var errc error
+		txStatus.Confirmations, errc = b.getConfirmations(txStatus.BlockHeight)
+		if errc != nil {
+			err = errc
 		}
 	}
 	txStatus.Receipt = txr
-	return &txStatus
+	return &txStatus, err
+}
+
+// getConfirmations get confirmations of block height
+// if confirm block tag (eg. finalized) is configed, the tagged block is counted as the first confirmation
+func (b *Bridge) getConfirmations(blockHeight uint64) (uint64, error) {
+	switch tag := strings.ToLower(b.TokenConfig.ConfirmBlockTag); tag {
+	case "", "latest":
+		latest, err := b.GetLatestBlockNumber()
+		if err != nil {
+			return 0, err
+		}
+		if latest > blockHeight {
+			return latest - blockHeight, nil
+		}
+	default:
+		confirmedHeight, err := b.GetBlockNumberByTag(tag)
+		if err != nil {
+			return 0, err
+		}
+		if confirmedHeight >= blockHeight {
+			return confirmedHeight - blockHeight + 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// CalcMsgHash calc msg hash of raw tx
+func (b *Bridge) CalcMsgHash(rawTx interface{}) ([]string, error) {
+	tx, ok := rawTx.(*types.Transaction)
+	if !ok {
+		return nil, tokens.ErrWrongRawTx
+	}
+	return []string{b.Signer.Hash(tx).String()}, nil
 }
 
 // VerifyMsgHash verify msg hash
@@ -61,11 +107,26 @@ func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHashes []string, extra inte
 	if sigHash.String() != msgHash {
 		return tokens.ErrMsgHashMismatch
 	}
+	// gas price of dynamic fee tx is its gas fee cap
+	if tokens.EthMaxGasPrice != nil && tx.GasPrice().Cmp(tokens.EthMaxGasPrice) > 0 {
+		return tokens.ErrGasPriceExceedLimit
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		if tx.GasTipCap().Cmp(tx.GasFeeCap()) > 0 {
+			return errTipAboveFeeCap
+		}
+		if tokens.EthMaxGasTipCap != nil && tx.GasTipCap().Cmp(tokens.EthMaxGasTipCap) > 0 {
+			return tokens.ErrGasPriceExceedLimit
+		}
+	}
 	return nil
 }
 
 // VerifyTransaction impl
 func (b *Bridge) VerifyTransaction(txHash string, allowUnstable bool) (*tokens.TxSwapInfo, error) {
+	if b.GatewayConfig.IsQuorumEnabled() {
+		return tokens.QuorumVerifyTransaction(b, txHash, allowUnstable)
+	}
 	if !b.IsSrc {
 		return b.verifySwapoutTx(txHash, allowUnstable)
 	}
@@ -93,9 +154,9 @@ func (b *Bridge) verifySwapinTx(txHash string, allowUnstable bool) (*tokens.TxSw
 	if tx.Recipient != nil {
 		swapInfo.To = strings.ToLower(tx.Recipient.String()) // To
 	}
-	swapInfo.From = strings.ToLower(tx.From.String()) // From
-	swapInfo.Bind = swapInfo.From                     // Bind
-	swapInfo.Value = tx.Amount.ToInt()                // Value
+	swapInfo.From = strings.ToLower(tx.From.String())                          // From
+	swapInfo.Bind = getSwapinBindAddress((*[]byte)(tx.Payload), swapInfo.From) // Bind
+	swapInfo.Value = tx.Amount.ToInt()                                         // Value
 
 	if !allowUnstable {
 		txStatus := b.GetTransactionStatus(txHash)
@@ -123,10 +184,29 @@ func (b *Bridge) verifySwapinTx(txHash string, allowUnstable bool) (*tokens.TxSw
 		return swapInfo, tokens.ErrTxWithWrongSender
 	}
 
-	if !tokens.CheckSwapValue(swapInfo.Value, b.IsSrc) {
+	if !tokens.CheckSwapValue(b.PairID, swapInfo.Value, b.IsSrc) {
 		return swapInfo, tokens.ErrTxWithWrongValue
 	}
 
+	// NOTE: must verify memo at last step (as it can be recall)
+	if !tokens.GetCrossChainBridge(b.PairID, false).IsValidAddress(swapInfo.Bind) {
+		log.Debug("wrong bind address in swapin", "bind", swapInfo.Bind)
+		return swapInfo, tokens.ErrTxWithWrongMemo
+	}
+
 	log.Debug("verify swapin stable pass", "from", swapInfo.From, "to", swapInfo.To, "bind", swapInfo.Bind, "value", swapInfo.Value, "txid", txHash, "height", swapInfo.Height, "timestamp", swapInfo.Timestamp)
 	return swapInfo, nil
 }
+
+// getSwapinBindAddress get bind address from tx input with lock memo prefix
+// (eg. bitcoin address when btc is the destination chain), default is the sender
+func getSwapinBindAddress(input *[]byte, from string) string {
+	if input == nil {
+		return from
+	}
+	memo := string(*input)
+	if len(memo) > len(tokens.LockMemoPrefix) && strings.HasPrefix(memo, tokens.LockMemoPrefix) {
+		return memo[len(tokens.LockMemoPrefix):]
+	}
+	return from
+}
@@ -0,0 +1,48 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+)
+
+func TestQuorumGetTransactionStatus(t *testing.T) {
+	blockHash := "0x5b0d4f2e6a1c3b8d7e9f0a2c4b6d8e0f1a3c5e7b9d1f3a5c7e9b1d3f5a7c9e1b"
+	mined := map[string]interface{}{
+		"eth_getTransactionReceipt": map[string]interface{}{
+			"blockNumber": "0x10",
+			"blockHash":   blockHash,
+			"status":      "0x1",
+		},
+		"eth_getBlockByHash": map[string]interface{}{"hash": blockHash, "number": "0x10", "timestamp": "0x5f5e100"},
+		"eth_blockNumber":    "0x15",
+	}
+	minedServer1 := newTestRPCServer(t, mined)
+	defer minedServer1.Close()
+	minedServer2 := newTestRPCServer(t, mined)
+	defer minedServer2.Close()
+	failedServer := newTestRPCServer(t, nil) // responds error to every call
+	defer failedServer.Close()
+
+	b := newTestBridge("testQuorumTxStatus", true, "0x6B1a4Bc7C1e0e8F3d1D4c3Ae1A36E5C3a9c6c1Fa", minedServer1.URL)
+	b.GatewayConfig.APIAddresses = []string{minedServer2.URL, failedServer.URL}
+	txHash := "0x7d3c1e5f9a2b4c6d8e0f1a3b5c7d9e1f2a4b6c8d0e2f4a6b8c0d2e4f6a8b0c2d"
+
+	b.GatewayConfig.Quorum = 2
+	txStatus, err := tokens.QuorumGetTransactionStatus(b, txHash)
+	if err != nil {
+		t.Fatalf("quorum get tx status failed: %v", err)
+	}
+	if txStatus.BlockHeight != 0x10 || txStatus.BlockHash != blockHash || txStatus.Confirmations != 5 {
+		t.Fatalf("wrong tx status, height %v hash %v confirmations %v", txStatus.BlockHeight, txStatus.BlockHash, txStatus.Confirmations)
+	}
+
+	// the failed endpoint has no vote, only 2 endpoints respond
+	b.GatewayConfig.Quorum = 3
+	if _, err = tokens.QuorumGetTransactionStatus(b, txHash); err != tokens.ErrGatewayQuorumNotReached {
+		t.Fatalf("quorum get tx status, want %v, have %v", tokens.ErrGatewayQuorumNotReached, err)
+	}
+	if txStatus = b.GetTransactionStatus(txHash); txStatus.BlockHeight != 0 {
+		t.Fatalf("tx status should be empty if quorum is not reached, have height %v", txStatus.BlockHeight)
+	}
+}
@@ -0,0 +1,65 @@
+package evm
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/fsn-dev/crossChain-Bridge/log"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+	"github.com/fsn-dev/crossChain-Bridge/tokens/eth"
+	"github.com/fsn-dev/crossChain-Bridge/types"
+)
+
+// Bridge generic evm bridge inherit from eth bridge, the chain specifics are all from config
+type Bridge struct {
+	*eth.Bridge
+}
+
+// NewCrossChainBridge new generic evm bridge
+func NewCrossChainBridge(pairID string, isSrc bool) *Bridge {
+	return &Bridge{Bridge: eth.NewCrossChainBridge(pairID, isSrc)}
+}
+
+// SetTokenAndGateway set token and gateway config
+func (b *Bridge) SetTokenAndGateway(tokenCfg *tokens.TokenConfig, gatewayCfg *tokens.GatewayConfig) {
+	b.CrossChainBridgeBase.SetTokenAndGateway(tokenCfg, gatewayCfg)
+	b.VerifyChainID()
+	b.VerifyTokenCofig()
+	b.InitLatestBlockNumber()
+	b.InitSubscriber()
+}
+
+// VerifyChainID verify chain id (configed in token config)
+func (b *Bridge) VerifyChainID() {
+	tokenCfg := b.TokenConfig
+	gatewayCfg := b.GatewayConfig
+
+	configedChainID, ok := new(big.Int).SetString(tokenCfg.ChainID, 0)
+	if !ok {
+		panic(fmt.Sprintf("wrong chainID %q of %v", tokenCfg.ChainID, tokenCfg.BlockChain))
+	}
+
+	var (
+		chainID *big.Int
+		err     error
+	)
+
+	for {
+		chainID, err = b.ChainID()
+		if err == nil {
+			break
+		}
+		log.Errorf("can not get gateway chainID. %v", err)
+		log.Println("retry query gateway", gatewayCfg.APIAddress)
+		time.Sleep(3 * time.Second)
+	}
+
+	if chainID.Cmp(configedChainID) != 0 {
+		panic(fmt.Sprintf("gateway chainID %v is not %v", chainID, configedChainID))
+	}
+
+	b.Signer = types.MakeSigner("London", chainID)
+
+	log.Info("VerifyChainID succeed", "blockChain", tokenCfg.BlockChain, "networkID", tokenCfg.NetID, "chainID", chainID)
+}
@@ -25,8 +25,8 @@ type Bridge struct {
 }
 
 // NewCrossChainBridge new fsn bridge
-func NewCrossChainBridge(isSrc bool) *Bridge {
-	return &Bridge{Bridge: eth.NewCrossChainBridge(isSrc)}
+func NewCrossChainBridge(pairID string, isSrc bool) *Bridge {
+	return &Bridge{Bridge: eth.NewCrossChainBridge(pairID, isSrc)}
 }
 
 // SetTokenAndGateway set token and gateway config
@@ -35,6 +35,7 @@ func (b *Bridge) SetTokenAndGateway(tokenCfg *tokens.TokenConfig, gatewayCfg *to
 	b.VerifyChainID()
 	b.VerifyTokenCofig()
 	b.InitLatestBlockNumber()
+	b.InitSubscriber()
 }
 
 // VerifyChainID verify chain id
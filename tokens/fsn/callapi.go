@@ -11,10 +11,10 @@ import (
 
 // GetTransactionAndReceipt get tx and receipt (fsn special)
 func (b *Bridge) GetTransactionAndReceipt(txHash string) (*types.RPCTxAndReceipt, error) {
-	gateway := b.GatewayConfig
-	url := gateway.APIAddress
 	var result *types.RPCTxAndReceipt
-	err := client.RPCPost(&result, url, "fsn_getTransactionAndReceipt", txHash)
+	err := b.GatewayConfig.Call(func(url string) error {
+		return client.RPCPost(&result, url, "fsn_getTransactionAndReceipt", txHash)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -26,10 +26,10 @@ func (b *Bridge) GetTransactionAndReceipt(txHash string) (*types.RPCTxAndReceipt
 
 // ChainID get chain id use net_version (eth_chainId does not work)
 func (b *Bridge) ChainID() (*big.Int, error) {
-	gateway := b.GatewayConfig
-	url := gateway.APIAddress
 	var result string
-	err := client.RPCPost(&result, url, "net_version")
+	err := b.GatewayConfig.Call(func(url string) error {
+		return client.RPCPost(&result, url, "net_version")
+	})
 	if err != nil {
 		return nil, err
 	}
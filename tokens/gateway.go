@@ -0,0 +1,346 @@
+package tokens
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsn-dev/crossChain-Bridge/log"
+	"github.com/fsn-dev/crossChain-Bridge/rpc/client"
+)
+
+const (
+	// consecutive network failures to mark an endpoint unhealthy
+	maxEndpointFailures = 3
+	// default max blocks an endpoint can lag behind the highest endpoint
+	defaultMaxBlockLag = 5
+)
+
+// ErrGatewayQuorumNotReached quorum of gateway endpoints is not reached
+var ErrGatewayQuorumNotReached = errors.New("gateway endpoints quorum not reached")
+
+// errNoVote returned by quorum call of endpoint which fails to respond, it has no vote
+var errNoVote = errors.New("gateway endpoint has no vote")
+
+var gatewayEndpointsLock sync.Mutex
+
+// GatewayEndpoint gateway endpoint with health state
+type GatewayEndpoint struct {
+	URL         string
+	Healthy     bool
+	Failures    int // consecutive network failures
+	LatestBlock uint64
+	LastError   string
+	CheckTime   int64
+}
+
+// GatewayEndpoints endpoints of gateway, calls are sent to healthy endpoints first
+type GatewayEndpoints struct {
+	mu        sync.RWMutex
+	endpoints []*GatewayEndpoint
+}
+
+func newGatewayEndpoints(urls []string) *GatewayEndpoints {
+	endpoints := make([]*GatewayEndpoint, len(urls))
+	for i, url := range urls {
+		endpoints[i] = &GatewayEndpoint{URL: url, Healthy: true}
+	}
+	return &GatewayEndpoints{endpoints: endpoints}
+}
+
+// GetAPIAddresses get all endpoints, APIAddress is the first (preferred) one
+func (c *GatewayConfig) GetAPIAddresses() []string {
+	urls := make([]string, 0, len(c.APIAddresses)+1)
+	exist := make(map[string]struct{}, len(c.APIAddresses)+1)
+	for _, url := range append([]string{c.APIAddress}, c.APIAddresses...) {
+		if url == "" {
+			continue
+		}
+		if _, ok := exist[url]; ok {
+			continue
+		}
+		exist[url] = struct{}{}
+		urls = append(urls, url)
+	}
+	return urls
+}
+
+// CheckConfig check gateway config
+func (c *GatewayConfig) CheckConfig() error {
+	count := len(c.GetAPIAddresses())
+	if count == 0 {
+		return errors.New("gateway must config 'APIAddress'")
+	}
+	if c.Quorum < 0 || c.Quorum > count {
+		return fmt.Errorf("gateway 'Quorum' %v is out of range [0, %v]", c.Quorum, count)
+	}
+	if c.WebsocketAddress != "" &&
+		!strings.HasPrefix(c.WebsocketAddress, "ws://") &&
+		!strings.HasPrefix(c.WebsocketAddress, "wss://") {
+		return fmt.Errorf("gateway 'WebsocketAddress' %v should start with ws:// or wss://", c.WebsocketAddress)
+	}
+	return nil
+}
+
+// IsQuorumEnabled is quorum reads enabled (N-of-M endpoints should agree)
+func (c *GatewayConfig) IsQuorumEnabled() bool {
+	return c.Quorum > 1
+}
+
+// GetMaxBlockLag get max blocks an endpoint can lag behind the highest endpoint
+func (c *GatewayConfig) GetMaxBlockLag() uint64 {
+	if c.MaxBlockLag > 0 {
+		return c.MaxBlockLag
+	}
+	return defaultMaxBlockLag
+}
+
+// GetEndpoints get endpoints with health state
+func (c *GatewayConfig) GetEndpoints() *GatewayEndpoints {
+	gatewayEndpointsLock.Lock()
+	defer gatewayEndpointsLock.Unlock()
+	if c.endpoints == nil {
+		c.endpoints = newGatewayEndpoints(c.GetAPIAddresses())
+	}
+	return c.endpoints
+}
+
+// PinEndpoint copy config which only calls the specified endpoint (quorum disabled)
+func (c *GatewayConfig) PinEndpoint(url string) *GatewayConfig {
+	pinned := *c
+	pinned.APIAddress = url
+	pinned.APIAddresses = nil
+	pinned.Quorum = 0
+	pinned.endpoints = newGatewayEndpoints([]string{url})
+	return &pinned
+}
+
+// URLs get urls of all endpoints
+func (e *GatewayEndpoints) URLs() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	urls := make([]string, len(e.endpoints))
+	for i, endpoint := range e.endpoints {
+		urls[i] = endpoint.URL
+	}
+	return urls
+}
+
+// orderedURLs healthy endpoints first, keep the configed order otherwise
+func (e *GatewayEndpoints) orderedURLs() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	urls := make([]string, 0, len(e.endpoints))
+	for _, endpoint := range e.endpoints {
+		if endpoint.Healthy {
+			urls = append(urls, endpoint.URL)
+		}
+	}
+	for _, endpoint := range e.endpoints {
+		if !endpoint.Healthy {
+			urls = append(urls, endpoint.URL)
+		}
+	}
+	return urls
+}
+
+// GetStates get copy of health states of endpoints
+func (e *GatewayEndpoints) GetStates() []GatewayEndpoint {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	states := make([]GatewayEndpoint, len(e.endpoints))
+	for i, endpoint := range e.endpoints {
+		states[i] = *endpoint
+	}
+	return states
+}
+
+func (e *GatewayEndpoints) get(url string) *GatewayEndpoint {
+	for _, endpoint := range e.endpoints {
+		if endpoint.URL == url {
+			return endpoint
+		}
+	}
+	return nil
+}
+
+// markResult only network errors are counted as failures of endpoint
+func (e *GatewayEndpoints) markResult(url string, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	endpoint := e.get(url)
+	if endpoint == nil {
+		return
+	}
+	if err == nil || !client.IsNetworkError(err) {
+		endpoint.Failures = 0
+		return
+	}
+	endpoint.Failures++
+	endpoint.LastError = err.Error()
+	if endpoint.Healthy && endpoint.Failures >= maxEndpointFailures {
+		endpoint.Healthy = false
+		log.Warn("gateway endpoint is unhealthy", "url", url, "failures", endpoint.Failures, "err", err)
+	}
+}
+
+// CheckHealth get latest block of every endpoint,
+// endpoints which are unreachable or lag behind the highest one too much are marked unhealthy.
+func (e *GatewayEndpoints) CheckHealth(maxBlockLag uint64, getLatest func(url string) (uint64, error)) {
+	urls := e.URLs()
+	heights := make([]uint64, len(urls))
+	errs := make([]error, len(urls))
+	var wg sync.WaitGroup
+	for i, url := range urls {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			heights[i], errs[i] = getLatest(url)
+		}(i, url)
+	}
+	wg.Wait()
+
+	var highest uint64
+	for i := range urls {
+		if errs[i] == nil && heights[i] > highest {
+			highest = heights[i]
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := time.Now().Unix()
+	for i, url := range urls {
+		endpoint := e.get(url)
+		if endpoint == nil {
+			continue
+		}
+		endpoint.CheckTime = now
+		healthy := true
+		if errs[i] != nil {
+			healthy = false
+			endpoint.LastError = errs[i].Error()
+		} else {
+			endpoint.LatestBlock = heights[i]
+			endpoint.Failures = 0
+			if heights[i]+maxBlockLag < highest {
+				healthy = false
+				endpoint.LastError = fmt.Sprintf("lag behind highest block %v", highest)
+			}
+		}
+		if endpoint.Healthy != healthy {
+			log.Info("gateway endpoint health changed", "url", url, "healthy", healthy, "latest", heights[i], "highest", highest, "err", endpoint.LastError)
+		}
+		endpoint.Healthy = healthy
+	}
+}
+
+// Call call endpoints in order until one is reachable (fail over when network error happens)
+func (c *GatewayConfig) Call(call func(url string) error) (err error) {
+	endpoints := c.GetEndpoints()
+	for _, url := range endpoints.orderedURLs() {
+		err = call(url)
+		endpoints.markResult(url, err)
+		if err == nil || !client.IsNetworkError(err) {
+			return err
+		}
+		log.Debug("call gateway endpoint failed", "url", url, "err", err)
+	}
+	return err
+}
+
+// Broadcast call all endpoints concurrently (eg. send transaction),
+// return the result of the first succeeded endpoint, or the first error if all failed.
+func (c *GatewayConfig) Broadcast(call func(url string) (string, error)) (string, error) {
+	endpoints := c.GetEndpoints()
+	urls := endpoints.orderedURLs()
+	results := make([]string, len(urls))
+	errs := make([]error, len(urls))
+	var wg sync.WaitGroup
+	for i, url := range urls {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			results[i], errs[i] = call(url)
+			endpoints.markResult(url, errs[i])
+		}(i, url)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err == nil {
+			return results[i], nil
+		}
+		log.Debug("broadcast to gateway endpoint failed", "url", urls[i], "err", err)
+	}
+	return "", errs[0]
+}
+
+// QuorumCall call all endpoints concurrently, results (and error) are agreed by at least quorum endpoints.
+// unreachable endpoints (and calls returning errNoVote) have no vote. results are compared by equal (reflect.DeepEqual if nil).
+// return all agreed results, or ErrGatewayQuorumNotReached.
+func (c *GatewayConfig) QuorumCall(call func(url string) (interface{}, error), equal func(a, b interface{}) bool) ([]interface{}, error) {
+	if equal == nil {
+		equal = reflect.DeepEqual
+	}
+	endpoints := c.GetEndpoints()
+	urls := endpoints.URLs()
+	results := make([]interface{}, len(urls))
+	errs := make([]error, len(urls))
+	var wg sync.WaitGroup
+	for i, url := range urls {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			results[i], errs[i] = call(url)
+			if errs[i] != errNoVote {
+				endpoints.markResult(url, errs[i])
+			}
+		}(i, url)
+	}
+	wg.Wait()
+
+	type group struct {
+		err     error
+		results []interface{}
+	}
+	var groups []*group
+	for i, result := range results {
+		err := errs[i]
+		if err == errNoVote || client.IsNetworkError(err) {
+			continue
+		}
+		var matched *group
+		for _, g := range groups {
+			if isSameError(g.err, err) && equal(g.results[0], result) {
+				matched = g
+				break
+			}
+		}
+		if matched == nil {
+			matched = &group{err: err}
+			groups = append(groups, matched)
+		}
+		matched.results = append(matched.results, result)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].results) > len(groups[j].results)
+	})
+	// conflicting results both reaching quorum (quorum is not a majority) are not accepted
+	if len(groups) == 0 || len(groups[0].results) < c.Quorum ||
+		(len(groups) > 1 && len(groups[1].results) >= c.Quorum) {
+		log.Warn("gateway endpoints quorum not reached", "quorum", c.Quorum, "endpoints", len(urls), "groups", len(groups))
+		return nil, ErrGatewayQuorumNotReached
+	}
+	return groups[0].results, groups[0].err
+}
+
+func isSameError(err1, err2 error) bool {
+	if err1 == nil || err2 == nil {
+		return err1 == err2
+	}
+	return err1 == err2 || err1.Error() == err2.Error()
+}
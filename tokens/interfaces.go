@@ -13,15 +13,6 @@ const (
 	RecallMemoPrefix = "RECALL:"
 )
 
-// common variables
-var (
-	SrcBridge CrossChainBridge
-	DstBridge CrossChainBridge
-
-	SrcLatestBlockHeight uint64
-	DstLatestBlockHeight uint64
-)
-
 // common errors
 var (
 	ErrSwapTypeNotSupported          = errors.New("swap type not supported in this endpoint")
@@ -35,6 +26,13 @@ var (
 	ErrWrongSwapinTxType             = errors.New("wrong swapin tx type")
 	ErrBuildSwapTxInWrongEndpoint    = errors.New("build swap in/out tx in wrong endpoint")
 	ErrTxBeforeInitialHeight         = errors.New("transaction before initial block height")
+	ErrTxCanNotRecall                = errors.New("tx can not recall")
+	ErrRecallValueTooSmall           = errors.New("recall value is too small to pay recall fee")
+	ErrTxIsNotPending                = errors.New("tx is not pending")
+	ErrGasPriceExceedLimit           = errors.New("gas price exceed limit")
+	ErrRelayFeeExceedLimit           = errors.New("relay fee exceed limit")
+	ErrTxNotReplaceable              = errors.New("tx is not replaceable")
+	ErrSwapTxConflicted              = errors.New("nonce or utxos of swap tx are spent by another tx")
 
 	ErrTodo = errors.New("developing: TODO")
 
@@ -64,8 +62,31 @@ func ShouldRegisterSwapForError(err error) bool {
 	return false
 }
 
+// ShouldRetryVerifyForError return true if tx is not verified for this error (tx is not found or
+// gateways are not available), it should be verified again as the error is not a verdict of the tx
+func ShouldRetryVerifyForError(err error) bool {
+	switch err {
+	case ErrTxNotFound,
+		ErrTxNotStable,
+		ErrGatewayQuorumNotReached:
+		return true
+	}
+	return false
+}
+
+// IsRecallableError return true if swapin with this error can be recalled
+func IsRecallableError(err error) bool {
+	switch err {
+	case ErrTxWithWrongMemo,
+		ErrTxWithWrongValue:
+		return true
+	}
+	return false
+}
+
 // CrossChainBridge interface
 type CrossChainBridge interface {
+	GetPairID() string
 	IsSrcEndpoint() bool
 	GetTokenAndGateway() (*TokenConfig, *GatewayConfig)
 	SetTokenAndGateway(*TokenConfig, *GatewayConfig)
@@ -88,25 +109,75 @@ type CrossChainBridge interface {
 	StartSwapHistoryScanJob()
 }
 
-// SetLatestBlockHeight set latest block height
-func SetLatestBlockHeight(latest uint64, isSrc bool) {
-	if isSrc {
-		SrcLatestBlockHeight = latest
-	} else {
-		DstLatestBlockHeight = latest
-	}
+// NonceManager interface of account model bridge which manages nonces of dcrm address
+type NonceManager interface {
+	InitNonces() error
+	ReleaseNonce(rawTx interface{}) // only called if raw tx is never broadcast
+	CheckNonceGap()
+}
+
+// TxReplacer interface of bridge which can replace stuck swap tx
+type TxReplacer interface {
+	GetReplaceConfig() (pendingAge int64, maxReplaceCount int)
+	GetReplaceTxExtra(pendingTxHash string) (*AllExtras, error)
+}
+
+// BlockHashGetter interface of bridge which can get block hash of height (to detect chain reorg)
+type BlockHashGetter interface {
+	GetBlockHash(height uint64) (string, error)
+}
+
+// SwapHistoryBackfiller interface of bridge which can scan swap history in block range
+type SwapHistoryBackfiller interface {
+	BackfillSwapHistory(start, end uint64) error
+}
+
+// MsgHashCalculator interface of bridge which can calculate msgHash (to be signed by dcrm) of raw tx
+type MsgHashCalculator interface {
+	CalcMsgHash(rawTx interface{}) ([]string, error)
+}
+
+// TxAccelerator interface of bridge which can accelerate stuck tx by child-pays-for-parent.
+// the child tx is built from the pending swap tx, and is signed and sent as swap tx.
+type TxAccelerator interface {
+	BuildAccelerateTransaction(args *BuildTxArgs, pendingTxHash string) (rawTx interface{}, err error)
+}
+
+// UtxoLocker interface of utxo based bridge which locks utxos selected by in-flight txs of swap
+type UtxoLocker interface {
+	UnlockUtxos(rawTx interface{}, swapID string) // only called if raw tx is never broadcast
+}
+
+// SwapTxFinder interface of bridge which can find swap tx of swap on chain (to reconcile sign intent).
+// args extra is the one of the signed swap tx, empty tx hash is returned if the swap tx is not found,
+// and ErrSwapTxConflicted is returned if the nonce or utxos in args extra are spent by another tx.
+type SwapTxFinder interface {
+	FindSwapTx(args *BuildTxArgs, fromHeight uint64) (txHash string, err error)
+}
+
+// DcrmSignRequester interface of bridge whose swap tx can be signed by dcrm asynchronously,
+// the msgHash is submitted to dcrm, and the signed tx is made with the rsv of sign result.
+type DcrmSignRequester interface {
+	GetDcrmSignMsgHash(rawTx interface{}, args *BuildTxArgs) (msgHash, msgContext []string, err error)
+	MakeDcrmSignedTransaction(rawTx interface{}, rsv []string, args *BuildTxArgs) (signedTx interface{}, txHash string, err error)
 }
 
 // CrossChainBridgeBase base bridge
 type CrossChainBridgeBase struct {
+	PairID        string
 	TokenConfig   *TokenConfig
 	GatewayConfig *GatewayConfig
 	IsSrc         bool
 }
 
 // NewCrossChainBridgeBase new base bridge
-func NewCrossChainBridgeBase(isSrc bool) *CrossChainBridgeBase {
-	return &CrossChainBridgeBase{IsSrc: isSrc}
+func NewCrossChainBridgeBase(pairID string, isSrc bool) *CrossChainBridgeBase {
+	return &CrossChainBridgeBase{PairID: pairID, IsSrc: isSrc}
+}
+
+// GetPairID returns the identifier of bridge pair which the bridge belongs to
+func (b *CrossChainBridgeBase) GetPairID() string {
+	return b.PairID
 }
 
 // IsSrcEndpoint returns if bridge is at the source endpoint
@@ -129,25 +200,6 @@ func (b *CrossChainBridgeBase) SetTokenAndGateway(tokenCfg *TokenConfig, gateway
 	}
 }
 
-// GetCrossChainBridge get bridge of specified endpoint
-func GetCrossChainBridge(isSrc bool) CrossChainBridge {
-	if isSrc {
-		return SrcBridge
-	}
-	return DstBridge
-}
-
-// GetTokenConfig get token config of specified endpoint
-func GetTokenConfig(isSrc bool) *TokenConfig {
-	var token *TokenConfig
-	if isSrc {
-		token, _ = SrcBridge.GetTokenAndGateway()
-	} else {
-		token, _ = DstBridge.GetTokenAndGateway()
-	}
-	return token
-}
-
 // FromBits convert from bits
 func FromBits(value *big.Int, decimals uint8) float64 {
 	oneToken := math.Pow(10, float64(decimals))
@@ -171,8 +223,8 @@ func ToBits(value float64, decimals uint8) *big.Int {
 }
 
 // CheckSwapValue check swap value is in right range
-func CheckSwapValue(value *big.Int, isSrc bool) bool {
-	token := GetTokenConfig(isSrc)
+func CheckSwapValue(pairID string, value *big.Int, isSrc bool) bool {
+	token := GetTokenConfig(pairID, isSrc)
 	decimals := *token.Decimals
 	minValue := ToBits(*token.MinimumSwap, decimals)
 	toleranceBits := big.NewInt(100)
@@ -184,8 +236,8 @@ func CheckSwapValue(value *big.Int, isSrc bool) bool {
 }
 
 // CalcSwappedValue calc swapped value (get rid of fee)
-func CalcSwappedValue(value *big.Int, isSrc bool) *big.Int {
-	token := GetTokenConfig(isSrc)
+func CalcSwappedValue(pairID string, value *big.Int, isSrc bool) *big.Int {
+	token := GetTokenConfig(pairID, isSrc)
 
 	swapFeeRate := new(big.Float).SetFloat64(*token.SwapFeeRate)
 	swapValue := new(big.Float).SetInt(value)
@@ -197,3 +249,27 @@ func CalcSwappedValue(value *big.Int, isSrc bool) *big.Int {
 	swappedValue.Int(result)
 	return result
 }
+
+// CalcRecallValue calc recall value (get rid of recall fee)
+func CalcRecallValue(pairID string, value *big.Int) *big.Int {
+	token := GetTokenConfig(pairID, true)
+	if token.RecallFee == nil {
+		return new(big.Int).Set(value)
+	}
+	recallFee := ToBits(*token.RecallFee, *token.Decimals)
+	recallValue := new(big.Int).Sub(value, recallFee)
+	if recallValue.Sign() < 0 {
+		return big.NewInt(0)
+	}
+	return recallValue
+}
+
+// GetRecallAddress get the address to receive recalled value.
+// use bind address if it's valid in source chain (eg. erc20 token owner),
+// otherwise use the sender of swapin tx (eg. btc tx with wrong memo)
+func GetRecallAddress(pairID, from, bind string) string {
+	if bind != "" && GetCrossChainBridge(pairID, true).IsValidAddress(bind) {
+		return bind
+	}
+	return from
+}
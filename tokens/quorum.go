@@ -0,0 +1,87 @@
+package tokens
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/fsn-dev/crossChain-Bridge/log"
+	"github.com/fsn-dev/crossChain-Bridge/rpc/client"
+)
+
+// EndpointPinner interface of bridge which can be pinned to one gateway endpoint,
+// quorum reads are done by calling the pinned bridges of all endpoints.
+type EndpointPinner interface {
+	CrossChainBridge
+	PinEndpoint(url string) CrossChainBridge
+	// QueryTransactionStatus get tx status with the error of getting it (partial status is returned with error)
+	QueryTransactionStatus(txHash string) (*TxStatus, error)
+}
+
+// QuorumGetLatestBlockNumber get the highest block number which at least quorum endpoints have reached
+func QuorumGetLatestBlockNumber(b EndpointPinner) (uint64, error) {
+	_, gateway := b.GetTokenAndGateway()
+	results, err := gateway.QuorumCall(func(url string) (interface{}, error) {
+		return b.PinEndpoint(url).GetLatestBlockNumber()
+	}, func(a, b interface{}) bool {
+		return true // heights are sorted and chosen below
+	})
+	if err != nil {
+		return 0, err
+	}
+	heights := make([]uint64, len(results))
+	for i, result := range results {
+		heights[i] = result.(uint64)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] > heights[j] })
+	return heights[gateway.Quorum-1], nil
+}
+
+// QuorumGetTransactionStatus get tx status agreed by at least quorum endpoints,
+// confirmations is the one which at least quorum endpoints have reached.
+// endpoints which fail to get tx status have no vote, error is returned if quorum is not reached.
+func QuorumGetTransactionStatus(b EndpointPinner, txHash string) (*TxStatus, error) {
+	_, gateway := b.GetTokenAndGateway()
+	results, err := gateway.QuorumCall(func(url string) (interface{}, error) {
+		pinned, ok := b.PinEndpoint(url).(EndpointPinner)
+		if !ok {
+			return nil, errNoVote
+		}
+		txStatus, err := pinned.QueryTransactionStatus(txHash)
+		if err != nil {
+			if client.IsNetworkError(err) {
+				return nil, err // mark endpoint failure
+			}
+			log.Debug("get tx status from gateway endpoint failed", "url", url, "tx", txHash, "err", err)
+			return nil, errNoVote
+		}
+		return txStatus, nil
+	}, func(a, b interface{}) bool {
+		sa, sb := a.(*TxStatus), b.(*TxStatus)
+		return sa.BlockHeight == sb.BlockHeight &&
+			sa.BlockHash == sb.BlockHash &&
+			sa.BlockTime == sb.BlockTime &&
+			(sa.Confirmations == 0) == (sb.Confirmations == 0) &&
+			reflect.DeepEqual(sa.Receipt, sb.Receipt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]*TxStatus, len(results))
+	for i, result := range results {
+		statuses[i] = result.(*TxStatus)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Confirmations > statuses[j].Confirmations })
+	return statuses[gateway.Quorum-1], nil
+}
+
+// QuorumVerifyTransaction verify tx by every endpoint, the swap info and error should be agreed by at least quorum endpoints
+func QuorumVerifyTransaction(b EndpointPinner, txHash string, allowUnstable bool) (*TxSwapInfo, error) {
+	_, gateway := b.GetTokenAndGateway()
+	results, err := gateway.QuorumCall(func(url string) (interface{}, error) {
+		return b.PinEndpoint(url).VerifyTransaction(txHash, allowUnstable)
+	}, nil)
+	if err == ErrGatewayQuorumNotReached {
+		return &TxSwapInfo{Hash: txHash}, err
+	}
+	return results[0].(*TxSwapInfo), err
+}
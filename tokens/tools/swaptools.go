@@ -1,9 +1,11 @@
 package tools
 
 import (
+	"errors"
 	"time"
 
 	"github.com/fsn-dev/crossChain-Bridge/dcrm"
+	"github.com/fsn-dev/crossChain-Bridge/internal/oracleindex"
 	"github.com/fsn-dev/crossChain-Bridge/log"
 	"github.com/fsn-dev/crossChain-Bridge/mongodb"
 	"github.com/fsn-dev/crossChain-Bridge/params"
@@ -11,49 +13,43 @@ import (
 	"github.com/fsn-dev/crossChain-Bridge/tokens"
 )
 
+// ErrCodeSwapExist error code of swap server api if the registering swap already exists
+const ErrCodeSwapExist = -32097
+
 var (
 	retryRPCCount    = 3
 	retryRPCInterval = 1 * time.Second
 )
 
-// IsSwapinExist is swapin exist
-func IsSwapinExist(txid string) bool {
+func newTxArgs(pairID, txid string) map[string]interface{} {
+	return map[string]interface{}{
+		"pairid": pairID,
+		"txid":   txid,
+	}
+}
+
+// IsSwapinExist is swapin exist (in oracle's own index and registered on swap server if it's oracle)
+func IsSwapinExist(pairID, txid string) bool {
 	if dcrm.IsSwapServer() {
-		swap, _ := mongodb.FindSwapin(txid)
+		swap, _ := mongodb.FindSwapin(pairID, txid)
 		return swap != nil
 	}
-	var result interface{}
-	for i := 0; i < retryRPCCount; i++ {
-		err := client.RPCPost(&result, params.ServerAPIAddress, "swap.GetSwapin", txid)
-		if err == nil {
-			return result != nil
-		}
-		time.Sleep(retryRPCInterval)
-	}
-	return false
+	return oracleindex.IsDepositRegistered(pairID, true, txid)
 }
 
-// IsSwapoutExist is swapout exist
-func IsSwapoutExist(txid string) bool {
+// IsSwapoutExist is swapout exist (in oracle's own index and registered on swap server if it's oracle)
+func IsSwapoutExist(pairID, txid string) bool {
 	if dcrm.IsSwapServer() {
-		swap, _ := mongodb.FindSwapout(txid)
+		swap, _ := mongodb.FindSwapout(pairID, txid)
 		return swap != nil
 	}
-	var result interface{}
-	for i := 0; i < retryRPCCount; i++ {
-		err := client.RPCPost(&result, params.ServerAPIAddress, "swap.GetSwapout", txid)
-		if err == nil {
-			return result != nil
-		}
-		time.Sleep(retryRPCInterval)
-	}
-	return false
+	return oracleindex.IsDepositRegistered(pairID, false, txid)
 }
 
 // RegisterSwapin register swapin
-func RegisterSwapin(txid, bind string) error {
+func RegisterSwapin(pairID, txid, bind string) error {
 	isServer := dcrm.IsSwapServer()
-	log.Info("[scan] register swapin", "isServer", isServer, "tx", txid, "bind", bind)
+	log.Info("[scan] register swapin", "isServer", isServer, "pairID", pairID, "tx", txid, "bind", bind)
 	if isServer {
 		swap := &mongodb.MgoSwap{
 			Key:       txid,
@@ -63,16 +59,15 @@ func RegisterSwapin(txid, bind string) error {
 			Status:    mongodb.TxNotStable,
 			Timestamp: time.Now().Unix(),
 		}
-		return mongodb.AddSwapin(swap)
+		return mongodb.AddSwapin(pairID, swap)
 	}
-	var result interface{}
-	return client.RPCPost(&result, params.ServerAPIAddress, "swap.Swapin", txid)
+	return registerOracleDeposit(pairID, txid, bind, tokens.SwapinTx, "swap.Swapin", newTxArgs(pairID, txid))
 }
 
 // RegisterP2shSwapin register p2sh swapin
-func RegisterP2shSwapin(txid, bind string) error {
+func RegisterP2shSwapin(pairID, txid, bind string) error {
 	isServer := dcrm.IsSwapServer()
-	log.Info("[scan] register p2sh swapin", "isServer", isServer, "tx", txid, "bind", bind)
+	log.Info("[scan] register p2sh swapin", "isServer", isServer, "pairID", pairID, "tx", txid, "bind", bind)
 	if isServer {
 		swap := &mongodb.MgoSwap{
 			Key:       txid,
@@ -82,25 +77,75 @@ func RegisterP2shSwapin(txid, bind string) error {
 			Status:    mongodb.TxNotStable,
 			Timestamp: time.Now().Unix(),
 		}
-		return mongodb.AddSwapin(swap)
+		return mongodb.AddSwapin(pairID, swap)
 	}
 	args := map[string]interface{}{
-		"txid": txid,
-		"bind": bind,
+		"pairid": pairID,
+		"txid":   txid,
+		"bind":   bind,
+	}
+	return registerOracleDeposit(pairID, txid, bind, tokens.P2shSwapinTx, "swap.P2shSwapin", args)
+}
+
+// addOracleDeposit add deposit found by oracle's own scanning to its index
+func addOracleDeposit(pairID, txid, bind string, txType tokens.SwapTxType) error {
+	deposit := &oracleindex.Deposit{
+		PairID: pairID,
+		TxID:   txid,
+		TxType: uint32(txType),
+		Bind:   bind,
+	}
+	err := oracleindex.AddDeposit(txType != tokens.SwapoutTx, deposit)
+	if err != nil {
+		log.Warn("[scan] add deposit to oracle index failed", "pairID", pairID, "txid", txid, "err", err)
+	}
+	return err
+}
+
+// registerOracleDeposit add deposit to oracle's own index, then register it on swap server.
+// the deposit is marked registered only if registering succeeds, otherwise it's registered again when found again.
+func registerOracleDeposit(pairID, txid, bind string, txType tokens.SwapTxType, method string, args map[string]interface{}) error {
+	if err := addOracleDeposit(pairID, txid, bind, txType); err != nil {
+		return err
 	}
 	var result interface{}
-	return client.RPCPost(&result, params.ServerAPIAddress, "swap.P2shSwapin", args)
+	err := client.RPCPost(&result, params.ServerAPIAddress, method, args)
+	if err != nil && !isSwapExistError(err) {
+		log.Warn("[scan] register deposit on swap server failed", "pairID", pairID, "txid", txid, "err", err)
+		return err
+	}
+	return oracleindex.SetDepositRegistered(pairID, txType != tokens.SwapoutTx, txid)
 }
 
-// GetP2shBindAddress get p2sh bind address
-func GetP2shBindAddress(p2shAddress string) (bindAddress string) {
+func isSwapExistError(err error) bool {
+	var rpcErr *client.RPCError
+	return errors.As(err, &rpcErr) && rpcErr.Code == ErrCodeSwapExist
+}
+
+// GetP2shBindAddress get p2sh bind address (from oracle's own index if it's oracle)
+func GetP2shBindAddress(pairID, p2shAddress string) (bindAddress string) {
 	if dcrm.IsSwapServer() {
-		bindAddress, _ = mongodb.FindP2shBindAddress(p2shAddress)
+		bindAddress, _ = mongodb.FindP2shBindAddress(pairID, p2shAddress)
 		return bindAddress
 	}
+	return oracleindex.GetP2shBindAddress(pairID, p2shAddress)
+}
+
+// AddOracleP2shBindAddress add p2sh bind address verified by oracle to its own index
+func AddOracleP2shBindAddress(pairID, p2shAddress, bindAddress string) error {
+	return oracleindex.AddP2shBindAddress(pairID, p2shAddress, bindAddress)
+}
+
+// QueryP2shBindAddress query p2sh bind address registered on swap server,
+// oracle must verify it by deriving the p2sh address from it.
+func QueryP2shBindAddress(pairID, p2shAddress string) string {
+	args := map[string]interface{}{
+		"pairid":  pairID,
+		"address": p2shAddress,
+	}
 	var result tokens.P2shAddressInfo
 	for i := 0; i < retryRPCCount; i++ {
-		err := client.RPCPost(&result, params.ServerAPIAddress, "swap.GetP2shAddressInfo", p2shAddress)
+		err := client.RPCPost(&result, params.ServerAPIAddress, "swap.GetP2shAddressInfo", args)
 		if err == nil {
 			return result.BindAddress
 		}
@@ -110,9 +155,9 @@ func GetP2shBindAddress(p2shAddress string) (bindAddress string) {
 }
 
 // RegisterSwapout register swapout
-func RegisterSwapout(txid, bind string) error {
+func RegisterSwapout(pairID, txid, bind string) error {
 	isServer := dcrm.IsSwapServer()
-	log.Info("[scan] register swapout", "isServer", isServer, "txid", txid, "bind", bind)
+	log.Info("[scan] register swapout", "isServer", isServer, "pairID", pairID, "txid", txid, "bind", bind)
 	if isServer {
 		swap := &mongodb.MgoSwap{
 			Key:       txid,
@@ -122,31 +167,34 @@ func RegisterSwapout(txid, bind string) error {
 			Status:    mongodb.TxNotStable,
 			Timestamp: time.Now().Unix(),
 		}
-		return mongodb.AddSwapout(swap)
+		return mongodb.AddSwapout(pairID, swap)
 	}
-	var result interface{}
-	return client.RPCPost(&result, params.ServerAPIAddress, "swap.Swapout", txid)
+	return registerOracleDeposit(pairID, txid, bind, tokens.SwapoutTx, "swap.Swapout", newTxArgs(pairID, txid))
 }
 
 // GetLatestScanHeight get latest scanned block height
-func GetLatestScanHeight(isSrc bool) uint64 {
+func GetLatestScanHeight(pairID string, isSrc bool) uint64 {
 	if dcrm.IsSwapServer() {
 		for {
-			latestInfo, err := mongodb.FindLatestScanInfo(isSrc)
+			latestInfo, err := mongodb.FindLatestScanInfo(pairID, isSrc)
 			if err == nil {
 				height := latestInfo.BlockHeight
-				log.Info("GetLatestScanHeight", "isSrc", isSrc, "height", height)
+				log.Info("GetLatestScanHeight", "pairID", pairID, "isSrc", isSrc, "height", height)
 				return height
 			}
 			time.Sleep(1 * time.Second)
 		}
 	}
+	args := map[string]interface{}{
+		"pairid": pairID,
+		"isSrc":  isSrc,
+	}
 	var result mongodb.MgoLatestScanInfo
 	for {
-		err := client.RPCPost(&result, params.ServerAPIAddress, "swap.GetLatestScanInfo", isSrc)
+		err := client.RPCPost(&result, params.ServerAPIAddress, "swap.GetLatestScanInfo", args)
 		if err == nil {
 			height := result.BlockHeight
-			log.Info("GetLatestScanHeight", "isSrc", isSrc, "height", height)
+			log.Info("GetLatestScanHeight", "pairID", pairID, "isSrc", isSrc, "height", height)
 			return height
 		}
 		time.Sleep(1 * time.Second)
@@ -167,9 +215,33 @@ func LoopGetLatestBlockNumber(b tokens.CrossChainBridge) uint64 {
 }
 
 // UpdateLatestScanInfo update latest scan info
-func UpdateLatestScanInfo(isSrc bool, height uint64) error {
+func UpdateLatestScanInfo(pairID string, isSrc bool, height uint64) error {
+	if dcrm.IsSwapServer() {
+		return mongodb.UpdateLatestScanInfo(pairID, isSrc, height)
+	}
+	return nil
+}
+
+// GetHistoryScanHeight get history scan checkpoint (0 if not exist).
+// only the swap server persists checkpoint, oracle always return 0.
+func GetHistoryScanHeight(pairID string, isSrc bool) uint64 {
+	if !dcrm.IsSwapServer() {
+		return 0
+	}
+	for {
+		info, err := mongodb.FindHistoryScanInfo(pairID, isSrc)
+		if err == nil {
+			log.Info("GetHistoryScanHeight", "pairID", pairID, "isSrc", isSrc, "height", info.BlockHeight)
+			return info.BlockHeight
+		}
+		time.Sleep(1 * time.Second)
+	}
+}
+
+// UpdateHistoryScanInfo update history scan checkpoint
+func UpdateHistoryScanInfo(pairID string, isSrc bool, height uint64) error {
 	if dcrm.IsSwapServer() {
-		return mongodb.UpdateLatestScanInfo(isSrc, height)
+		return mongodb.UpdateHistoryScanInfo(pairID, isSrc, height)
 	}
 	return nil
 }
@@ -6,6 +6,9 @@ import (
 	"strings"
 )
 
+// EvmBlockChain blockchain name of generic evm chain
+const EvmBlockChain = "EVM"
+
 // btc extra default values
 var (
 	BtcMinRelayFee   int64 = 400
@@ -14,6 +17,33 @@ var (
 
 	BtcUtxoAggregateMinCount = 20
 	BtcUtxoAggregateMinValue = uint64(1000000)
+
+	BtcMaxRelayFeePerKb    int64  = 100000 // 0.001 BTC
+	BtcReplacePendingAge   int64  = 3600   // seconds
+	BtcRelayFeeBumpPercent uint64 = 25
+	BtcMaxReplaceCount            = 5
+
+	BtcEstimateFeeBlocks       = 6    // confirmation target in blocks
+	BtcMinRelayFeePerKb  int64 = 1000 // 1 sat/vB
+
+	BtcCoinSelection         = "branchandbound"
+	BtcUtxoReserveTime int64 = 1800 // seconds
+)
+
+// eth extra default values
+var (
+	EthReplacePendingAge   int64  = 900 // seconds
+	EthGasPriceBumpPercent uint64 = 10
+	EthMaxReplaceCount            = 5
+
+	EthMaxGasPrice  *big.Int // nil means no limit (gas fee cap of dynamic fee tx)
+	EthMaxGasTipCap *big.Int // nil means no limit
+
+	EthMinGasPriceBumpPercent uint64 = 10 // required by tx pool to replace tx
+
+	EthEnableDynamicFeeTx   bool
+	EthFeeHistoryBlocks             = 20
+	EthFeeHistoryPercentile float64 = 50
 )
 
 // TokenConfig struct
@@ -31,7 +61,13 @@ type TokenConfig struct {
 	MaximumSwap     *float64 // whole unit (eg. BTC, ETH, FSN), not Satoshi
 	MinimumSwap     *float64 // whole unit
 	SwapFeeRate     *float64
+	RecallFee       *float64 `json:",omitempty"` // whole unit, deducted when recall swapin (default 0)
 	InitialHeight   uint64
+
+	// evm chain configs
+	ChainID         string `json:",omitempty"` // required by generic EVM blockchain
+	ConfirmBlockTag string `json:",omitempty"` // count confirmations from block of this tag (latest, safe, finalized)
+	ContractABI     string `json:",omitempty"` // abi variant of dest contract (mBTC or mETH), default by source blockchain
 }
 
 // IsErc20 return is token is erc20
@@ -41,7 +77,22 @@ func (c *TokenConfig) IsErc20() bool {
 
 // GatewayConfig struct
 type GatewayConfig struct {
-	APIAddress string
+	APIAddress   string
+	APIAddresses []string          `json:",omitempty"` // more endpoints for failover and quorum reads
+	Quorum       int               `json:",omitempty"` // N of the endpoints should agree in verifying tx (0 or 1 means disabled)
+	MaxBlockLag  uint64            `json:",omitempty"` // endpoint lagging behind the highest one more than this is unhealthy (default 5)
+	RPCMethods   map[string]string `json:",omitempty"` // override rpc method names (eg. eth_chainId = "net_version")
+	APIType      string            `json:",omitempty"` // btc gateway api type: electrs (default) or bitcoind
+	// evm gateway websocket endpoint, scan jobs subscribe new heads, swap logs and pending txs through it,
+	// and fall back to polling when the subscription drops
+	WebsocketAddress string `json:",omitempty"`
+
+	endpoints *GatewayEndpoints
+}
+
+// IsEvmBlockChain is generic evm blockchain
+func (c *TokenConfig) IsEvmBlockChain() bool {
+	return strings.EqualFold(c.BlockChain, EvmBlockChain)
 }
 
 // SwapType type
@@ -92,6 +143,7 @@ type SwapInfo struct {
 	TxType     SwapTxType `json:"txtype,omitempty"`
 	Bind       string     `json:"bind,omitempty"`
 	Identifier string     `json:"identifier,omitempty"`
+	PairID     string     `json:"pairid,omitempty"`
 }
 
 // BuildTxArgs struct
@@ -121,9 +173,16 @@ type AllExtras struct {
 
 // EthExtraArgs struct
 type EthExtraArgs struct {
-	Gas      *uint64  `json:"gas,omitempty"`
-	GasPrice *big.Int `json:"gasPrice,omitempty"`
-	Nonce    *uint64  `json:"nonce,omitempty"`
+	Gas       *uint64  `json:"gas,omitempty"`
+	GasPrice  *big.Int `json:"gasPrice,omitempty"`
+	GasTipCap *big.Int `json:"gasTipCap,omitempty"` // max priority fee per gas of dynamic fee tx
+	GasFeeCap *big.Int `json:"gasFeeCap,omitempty"` // max fee per gas of dynamic fee tx
+	Nonce     *uint64  `json:"nonce,omitempty"`
+}
+
+// IsDynamicFeeTx is dynamic fee tx (EIP-1559) extra args
+func (e *EthExtraArgs) IsDynamicFeeTx() bool {
+	return e.GasTipCap != nil || e.GasFeeCap != nil
 }
 
 // BtcOutPoint struct
@@ -148,12 +207,33 @@ type BtcExtraConfig struct {
 	FromPublicKey         string
 	UtxoAggregateMinCount int
 	UtxoAggregateMinValue uint64
+	ReplacePendingAge     int64  // seconds, negative means disable replacing
+	RelayFeeBumpPercent   uint64 // bump percent of fee rate when replace or cpfp
+	MaxReplaceCount       int
+	EstimateFeeBlocks     int // confirmation target of fee estimation, negative means use RelayFeePerKb
+	MinRelayFeePerKb      int64
+	MaxRelayFeePerKb      int64
+	CoinSelection         string // branchandbound, largestfirst or oldestfirst
+	UtxoReserveTime       int64  // seconds, utxos selected by in-flight swap tx are not selected again in this time
+}
+
+// EthExtraConfig used to customize fees and replacing of eth swap tx
+type EthExtraConfig struct {
+	ReplacePendingAge    int64  // seconds, negative means disable replacing
+	GasPriceBumpPercent  uint64 // at least 10
+	MaxReplaceCount      int
+	MaxGasPriceInGwei    uint64 `toml:",omitempty"`
+	EnableDynamicFeeTx   bool   // build EIP-1559 dynamic fee tx
+	MaxGasTipCapInGwei   uint64 `toml:",omitempty"`
+	FeeHistoryBlocks     int
+	FeeHistoryPercentile float64
 }
 
 // P2shAddressInfo struct
 type P2shAddressInfo struct {
 	BindAddress        string
 	P2shAddress        string
+	P2wshAddress       string
 	RedeemScript       string
 	RedeemScriptDisasm string
 }
@@ -191,6 +271,9 @@ func (c *TokenConfig) CheckConfig(isSrc bool) error {
 	if *c.SwapFeeRate < 0 {
 		return errors.New("token 'SwapFeeRate' is negative")
 	}
+	if c.RecallFee != nil && *c.RecallFee < 0 {
+		return errors.New("token 'RecallFee' is negative")
+	}
 	if c.DcrmAddress == "" {
 		return errors.New("token must config 'DcrmAddress'")
 	}
@@ -200,5 +283,27 @@ func (c *TokenConfig) CheckConfig(isSrc bool) error {
 	if isSrc && c.IsErc20() && c.ContractAddress == "" {
 		return errors.New("token must config 'ContractAddress' for ERC20 in source chain")
 	}
+	if c.IsEvmBlockChain() && c.ChainID == "" {
+		return errors.New("token must config 'ChainID' for EVM blockchain")
+	}
+	if c.ChainID != "" {
+		if _, ok := new(big.Int).SetString(c.ChainID, 0); !ok {
+			return errors.New("token 'ChainID' is not a number")
+		}
+	}
+	switch strings.ToLower(c.ConfirmBlockTag) {
+	case "", "latest":
+	case "safe", "finalized":
+		if *c.Confirmations == 0 {
+			return errors.New("token 'Confirmations' must be at least 1 with 'ConfirmBlockTag'")
+		}
+	default:
+		return errors.New("token 'ConfirmBlockTag' is not one of latest, safe, finalized")
+	}
+	switch strings.ToLower(c.ContractABI) {
+	case "", "mbtc", "meth":
+	default:
+		return errors.New("token 'ContractABI' is not one of mBTC, mETH")
+	}
 	return nil
 }
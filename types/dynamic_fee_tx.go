@@ -0,0 +1,108 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/fsn-dev/crossChain-Bridge/common"
+)
+
+// AccessList is an EIP-2930 access list
+type AccessList []AccessTuple
+
+// AccessTuple is the element type of an access list
+type AccessTuple struct {
+	Address     common.Address `json:"address"`
+	StorageKeys []common.Hash  `json:"storageKeys"`
+}
+
+func (al AccessList) copy() AccessList {
+	if al == nil {
+		return nil
+	}
+	cpy := make(AccessList, len(al))
+	for i, tuple := range al {
+		cpy[i] = AccessTuple{
+			Address:     tuple.Address,
+			StorageKeys: append([]common.Hash(nil), tuple.StorageKeys...),
+		}
+	}
+	return cpy
+}
+
+// dynamicFeeTxdata is the data of EIP-1559 dynamic fee transaction
+type dynamicFeeTxdata struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasTipCap  *big.Int // max priority fee per gas
+	GasFeeCap  *big.Int // max fee per gas
+	Gas        uint64
+	To         *common.Address `rlp:"nil"` // nil means contract creation
+	Value      *big.Int
+	Data       []byte
+	AccessList AccessList
+
+	// Signature values
+	V *big.Int
+	R *big.Int
+	S *big.Int
+}
+
+func (tx *dynamicFeeTxdata) txType() byte { return DynamicFeeTxType }
+
+func (tx *dynamicFeeTxdata) copy() innerTx {
+	cpy := &dynamicFeeTxdata{
+		Nonce:      tx.Nonce,
+		To:         copyAddressPtr(tx.To),
+		Data:       common.CopyBytes(tx.Data),
+		Gas:        tx.Gas,
+		AccessList: tx.AccessList.copy(),
+		Value:      new(big.Int),
+		ChainID:    new(big.Int),
+		GasTipCap:  new(big.Int),
+		GasFeeCap:  new(big.Int),
+		V:          new(big.Int),
+		R:          new(big.Int),
+		S:          new(big.Int),
+	}
+	if tx.Value != nil {
+		cpy.Value.Set(tx.Value)
+	}
+	if tx.ChainID != nil {
+		cpy.ChainID.Set(tx.ChainID)
+	}
+	if tx.GasTipCap != nil {
+		cpy.GasTipCap.Set(tx.GasTipCap)
+	}
+	if tx.GasFeeCap != nil {
+		cpy.GasFeeCap.Set(tx.GasFeeCap)
+	}
+	if tx.V != nil {
+		cpy.V.Set(tx.V)
+	}
+	if tx.R != nil {
+		cpy.R.Set(tx.R)
+	}
+	if tx.S != nil {
+		cpy.S.Set(tx.S)
+	}
+	return cpy
+}
+
+func (tx *dynamicFeeTxdata) chainID() *big.Int      { return tx.ChainID }
+func (tx *dynamicFeeTxdata) accessList() AccessList { return tx.AccessList }
+func (tx *dynamicFeeTxdata) data() []byte           { return tx.Data }
+func (tx *dynamicFeeTxdata) gas() uint64            { return tx.Gas }
+func (tx *dynamicFeeTxdata) gasPrice() *big.Int     { return tx.GasFeeCap }
+func (tx *dynamicFeeTxdata) gasTipCap() *big.Int    { return tx.GasTipCap }
+func (tx *dynamicFeeTxdata) gasFeeCap() *big.Int    { return tx.GasFeeCap }
+func (tx *dynamicFeeTxdata) value() *big.Int        { return tx.Value }
+func (tx *dynamicFeeTxdata) nonce() uint64          { return tx.Nonce }
+func (tx *dynamicFeeTxdata) to() *common.Address    { return tx.To }
+
+func (tx *dynamicFeeTxdata) rawSignatureValues() (v, r, s *big.Int) {
+	return tx.V, tx.R, tx.S
+}
+
+func (tx *dynamicFeeTxdata) setSignatureValues(chainID, v, r, s *big.Int) {
+	tx.ChainID, tx.V, tx.R, tx.S = chainID, v, r, s
+}
@@ -0,0 +1,129 @@
+package types
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/fsn-dev/crossChain-Bridge/common"
+	"github.com/fsn-dev/crossChain-Bridge/tools/crypto"
+)
+
+const testSenderKey = "289c2857d4598e37fb9647507e47a309d6133539bf21a8b9cb6df88fd5232032"
+
+var testSender = common.HexToAddress("0x970E8128AB834E8EAC17Ab8E3812F010678CF791")
+
+// expected values are the outputs of go-ethereum v1.10.26 (types.DynamicFeeTx, types.NewLondonSigner)
+var dynamicFeeTxTests = []struct {
+	name    string
+	tx      func() *Transaction
+	sigHash string
+	raw     string
+	hash    string
+}{
+	{
+		name: "transfer",
+		tx: func() *Transaction {
+			to := common.HexToAddress("0x095e7baea6a6c7c4c2dfeb977efac326af552d87")
+			value, _ := new(big.Int).SetString("1000000000000000000", 10)
+			return NewDynamicFeeTransaction(big.NewInt(4), 7, &to, value, 21000, big.NewInt(1500000000), big.NewInt(30000000000), nil, nil)
+		},
+		sigHash: "0x1d8ce394e64e04ca6c4d38df0d0c6b508332b10700f90691d5063f6eb45686d4",
+		raw:     "0x02f87304078459682f008506fc23ac0082520894095e7baea6a6c7c4c2dfeb977efac326af552d87880de0b6b3a764000080c080a0be5784944f98c1e0643c26d5e27bce2c3b4db9405156f88316f8f259c3113714a048999861a19a24ce19b259456b0d06d1584d42c22f2d747fddcd27115b6eb468",
+		hash:    "0x4518a236eea1d3f9af8147e8b49e8bd9590b7adba10254a304b2239a42b299d0",
+	},
+	{
+		name: "erc20 transfer with access list",
+		tx: func() *Transaction {
+			to := common.HexToAddress("0xdac17f958d2ee523a2206206994597c13d831ec7")
+			data := common.FromHex("0xa9059cbb000000000000000000000000095e7baea6a6c7c4c2dfeb977efac326af552d8700000000000000000000000000000000000000000000000000000000000f4240")
+			accessList := AccessList{{
+				Address: to,
+				StorageKeys: []common.Hash{
+					common.HexToHash("0x01"),
+					common.HexToHash("0x5c1b6f5e7d5c2b3a4f9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e0d9c8b7a"),
+				},
+			}}
+			return NewDynamicFeeTransaction(big.NewInt(1), 0x1234, &to, big.NewInt(0), 90000, big.NewInt(2000000000), big.NewInt(100000000000), data, accessList)
+		},
+		sigHash: "0x7365bc4e44ff85f9e7f9753479a1d6ba7011055bac3618c728ff87c745399c29",
+		raw:     "0x02f9010f01821234847735940085174876e80083015f9094dac17f958d2ee523a2206206994597c13d831ec780b844a9059cbb000000000000000000000000095e7baea6a6c7c4c2dfeb977efac326af552d8700000000000000000000000000000000000000000000000000000000000f4240f85bf85994dac17f958d2ee523a2206206994597c13d831ec7f842a00000000000000000000000000000000000000000000000000000000000000001a05c1b6f5e7d5c2b3a4f9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e0d9c8b7a01a0f491e9392437e243056437a40995637b28485f454908728f224e3f0d3b0977d9a07dce85a0478bba32108850acb05bdf7ba6b0af904b90804b984ed6251627842f",
+		hash:    "0x61a34ec5acad6e65fd72e2f970c8f1093a05cfc14906f7501c784fc0e1aa7856",
+	},
+	{
+		name: "contract creation",
+		tx: func() *Transaction {
+			data := common.FromHex("0x6080604052348015600f57600080fd5b50")
+			return NewDynamicFeeTransaction(big.NewInt(32659), 0, nil, big.NewInt(0), 100000, big.NewInt(0), big.NewInt(1000000000), data, nil)
+		},
+		sigHash: "0xdf31414e1c5ee20a810f4876ffe0aac2f0a84d2f56767d3e380f8a60e71c5829",
+		raw:     "0x02f866827f938080843b9aca00830186a08080916080604052348015600f57600080fd5b50c001a06cd9d9ed55e51cc08f278a254f648a074e5157d715ea2244fa67c8911988da2ea024601fa0342118708ae90c701a0aab448c71fc1ed4106187ac7874315b18cb89",
+		hash:    "0x74b060e47c4b9805c7c9380c1a0c891375f9d3b6b2f1bf29c112a68c42c46a0e",
+	},
+}
+
+func TestDynamicFeeTxSigning(t *testing.T) {
+	key, err := crypto.HexToECDSA(testSenderKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range dynamicFeeTxTests {
+		tx := test.tx()
+		signer := NewLondonSigner(tx.ChainID())
+		if have := signer.Hash(tx).String(); have != test.sigHash {
+			t.Errorf("%v: wrong signing hash, want %v, have %v", test.name, test.sigHash, have)
+		}
+		signedTx, err := SignTx(tx, signer, key)
+		if err != nil {
+			t.Fatalf("%v: sign tx failed: %v", test.name, err)
+		}
+		raw, err := signedTx.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%v: marshal binary failed: %v", test.name, err)
+		}
+		if !bytes.Equal(raw, common.FromHex(test.raw)) {
+			t.Errorf("%v: wrong encoding, want %v, have %x", test.name, test.raw, raw)
+		}
+		if have := signedTx.Hash().String(); have != test.hash {
+			t.Errorf("%v: wrong tx hash, want %v, have %v", test.name, test.hash, have)
+		}
+	}
+}
+
+func TestDynamicFeeTxDecoding(t *testing.T) {
+	for _, test := range dynamicFeeTxTests {
+		var tx Transaction
+		if err := tx.UnmarshalBinary(common.FromHex(test.raw)); err != nil {
+			t.Fatalf("%v: unmarshal binary failed: %v", test.name, err)
+		}
+		if tx.Type() != DynamicFeeTxType {
+			t.Fatalf("%v: wrong tx type %v", test.name, tx.Type())
+		}
+		if have := tx.Hash().String(); have != test.hash {
+			t.Errorf("%v: wrong tx hash, want %v, have %v", test.name, test.hash, have)
+		}
+		raw, err := tx.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%v: marshal binary failed: %v", test.name, err)
+		}
+		if !bytes.Equal(raw, common.FromHex(test.raw)) {
+			t.Errorf("%v: re-encoding mismatch, want %v, have %x", test.name, test.raw, raw)
+		}
+
+		signer := MakeSigner("London", tx.ChainID())
+		if have := signer.Hash(&tx).String(); have != test.sigHash {
+			t.Errorf("%v: wrong signing hash, want %v, have %v", test.name, test.sigHash, have)
+		}
+		sender, err := Sender(signer, &tx)
+		if err != nil {
+			t.Fatalf("%v: recover sender failed: %v", test.name, err)
+		}
+		if sender != testSender {
+			t.Errorf("%v: wrong sender, want %v, have %v", test.name, testSender.String(), sender.String())
+		}
+		// wrong chain id
+		if _, err = Sender(NewLondonSigner(big.NewInt(100)), &tx); err != ErrInvalidChainID {
+			t.Errorf("%v: sender with wrong chain id, want %v, have %v", test.name, ErrInvalidChainID, err)
+		}
+	}
+}
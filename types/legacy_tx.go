@@ -0,0 +1,73 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/fsn-dev/crossChain-Bridge/common"
+)
+
+// txdata is the data of legacy transaction
+type txdata struct {
+	AccountNonce uint64          `json:"nonce"    gencodec:"required"`
+	Price        *big.Int        `json:"gasPrice" gencodec:"required"`
+	GasLimit     uint64          `json:"gas"      gencodec:"required"`
+	Recipient    *common.Address `json:"to"       rlp:"nil"` // nil means contract creation
+	Amount       *big.Int        `json:"value"    gencodec:"required"`
+	Payload      []byte          `json:"input"    gencodec:"required"`
+
+	// Signature values
+	V *big.Int `json:"v" gencodec:"required"`
+	R *big.Int `json:"r" gencodec:"required"`
+	S *big.Int `json:"s" gencodec:"required"`
+}
+
+func (tx *txdata) txType() byte { return LegacyTxType }
+
+func (tx *txdata) copy() innerTx {
+	cpy := &txdata{
+		AccountNonce: tx.AccountNonce,
+		Recipient:    copyAddressPtr(tx.Recipient),
+		Payload:      common.CopyBytes(tx.Payload),
+		GasLimit:     tx.GasLimit,
+		Amount:       new(big.Int),
+		Price:        new(big.Int),
+		V:            new(big.Int),
+		R:            new(big.Int),
+		S:            new(big.Int),
+	}
+	if tx.Amount != nil {
+		cpy.Amount.Set(tx.Amount)
+	}
+	if tx.Price != nil {
+		cpy.Price.Set(tx.Price)
+	}
+	if tx.V != nil {
+		cpy.V.Set(tx.V)
+	}
+	if tx.R != nil {
+		cpy.R.Set(tx.R)
+	}
+	if tx.S != nil {
+		cpy.S.Set(tx.S)
+	}
+	return cpy
+}
+
+func (tx *txdata) chainID() *big.Int      { return deriveChainID(tx.V) }
+func (tx *txdata) accessList() AccessList { return nil }
+func (tx *txdata) data() []byte           { return tx.Payload }
+func (tx *txdata) gas() uint64            { return tx.GasLimit }
+func (tx *txdata) gasPrice() *big.Int     { return tx.Price }
+func (tx *txdata) gasTipCap() *big.Int    { return tx.Price }
+func (tx *txdata) gasFeeCap() *big.Int    { return tx.Price }
+func (tx *txdata) value() *big.Int        { return tx.Amount }
+func (tx *txdata) nonce() uint64          { return tx.AccountNonce }
+func (tx *txdata) to() *common.Address    { return tx.Recipient }
+
+func (tx *txdata) rawSignatureValues() (v, r, s *big.Int) {
+	return tx.V, tx.R, tx.S
+}
+
+func (tx *txdata) setSignatureValues(chainID, v, r, s *big.Int) {
+	tx.V, tx.R, tx.S = v, r, s
+}
@@ -28,6 +28,7 @@ type RPCBlock struct {
 	Nonce           *hexutil.Bytes  `json:"nonce"`
 	Size            *string         `json:"size"`
 	TotalDifficulty *hexutil.Big    `json:"totalDifficulty"`
+	BaseFee         *hexutil.Big    `json:"baseFeePerGas,omitempty"`
 	Transactions    []*common.Hash  `json:"transactions"`
 	Uncles          []*common.Hash  `json:"uncles"`
 }
@@ -41,6 +42,8 @@ type RPCTransaction struct {
 	From             *common.Address `json:"from,omitempty"`
 	AccountNonce     *hexutil.Uint64 `json:"nonce"`
 	Price            *hexutil.Big    `json:"gasPrice"`
+	GasTipCap        *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	GasFeeCap        *hexutil.Big    `json:"maxFeePerGas,omitempty"`
 	GasLimit         *hexutil.Uint64 `json:"gas"`
 	Recipient        *common.Address `json:"to"`
 	Amount           *hexutil.Big    `json:"value"`
@@ -48,6 +51,16 @@ type RPCTransaction struct {
 	V                *hexutil.Big    `json:"v"`
 	R                *hexutil.Big    `json:"r"`
 	S                *hexutil.Big    `json:"s"`
+	Type             *hexutil.Uint64 `json:"type,omitempty"`
+	ChainID          *hexutil.Big    `json:"chainId,omitempty"`
+}
+
+// RPCFeeHistory struct
+type RPCFeeHistory struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
 }
 
 // RPCLog struct
@@ -1,43 +1,59 @@
 package types
 
 import (
+	"bytes"
+	"errors"
 	"io"
 	"math/big"
 	"sync/atomic"
 
 	"github.com/fsn-dev/crossChain-Bridge/common"
-	"github.com/fsn-dev/crossChain-Bridge/tools/crypto"
 	"github.com/fsn-dev/crossChain-Bridge/tools/rlp"
 	"golang.org/x/crypto/sha3"
 )
 
+// Transaction types (EIP-2718)
+const (
+	LegacyTxType     = 0x00
+	DynamicFeeTxType = 0x02
+)
+
+// transaction type errors
+var (
+	ErrTxTypeNotSupported = errors.New("transaction type not supported")
+	errEmptyTypedTx       = errors.New("empty typed transaction bytes")
+)
+
 // StorageSize type
 type StorageSize float64
 
 // Transaction struct
 type Transaction struct {
-	data txdata
+	inner innerTx // legacy tx data or typed tx data
 	// caches
 	hash atomic.Value
 	size atomic.Value
 	from atomic.Value
 }
 
-type txdata struct {
-	AccountNonce uint64          `json:"nonce"    gencodec:"required"`
-	Price        *big.Int        `json:"gasPrice" gencodec:"required"`
-	GasLimit     uint64          `json:"gas"      gencodec:"required"`
-	Recipient    *common.Address `json:"to"       rlp:"nil"` // nil means contract creation
-	Amount       *big.Int        `json:"value"    gencodec:"required"`
-	Payload      []byte          `json:"input"    gencodec:"required"`
-
-	// Signature values
-	V *big.Int `json:"v" gencodec:"required"`
-	R *big.Int `json:"r" gencodec:"required"`
-	S *big.Int `json:"s" gencodec:"required"`
-
-	// This is only used when marshaling to JSON.
-	Hash *common.Hash `json:"hash" rlp:"-"`
+// innerTx is the underlying data of a transaction
+type innerTx interface {
+	txType() byte
+	copy() innerTx
+
+	chainID() *big.Int
+	accessList() AccessList
+	data() []byte
+	gas() uint64
+	gasPrice() *big.Int
+	gasTipCap() *big.Int
+	gasFeeCap() *big.Int
+	value() *big.Int
+	nonce() uint64
+	to() *common.Address
+
+	rawSignatureValues() (v, r, s *big.Int)
+	setSignatureValues(chainID, v, r, s *big.Int)
 }
 
 // NewTransaction new tx
@@ -72,17 +88,62 @@ func newTransaction(nonce uint64, to *common.Address, amount *big.Int, gasLimit
 		d.Price.Set(gasPrice)
 	}
 
-	return &Transaction{data: d}
+	return &Transaction{inner: &d}
+}
+
+// NewDynamicFeeTransaction new EIP-1559 dynamic fee tx (to is nil means contract creation)
+func NewDynamicFeeTransaction(chainID *big.Int, nonce uint64, to *common.Address, amount *big.Int, gasLimit uint64, gasTipCap, gasFeeCap *big.Int, data []byte, accessList AccessList) *Transaction {
+	if len(data) > 0 {
+		data = common.CopyBytes(data)
+	}
+	d := dynamicFeeTxdata{
+		ChainID:    new(big.Int),
+		Nonce:      nonce,
+		GasTipCap:  new(big.Int),
+		GasFeeCap:  new(big.Int),
+		Gas:        gasLimit,
+		To:         copyAddressPtr(to),
+		Value:      new(big.Int),
+		Data:       data,
+		AccessList: accessList.copy(),
+		V:          new(big.Int),
+		R:          new(big.Int),
+		S:          new(big.Int),
+	}
+	if chainID != nil {
+		d.ChainID.Set(chainID)
+	}
+	if amount != nil {
+		d.Value.Set(amount)
+	}
+	if gasTipCap != nil {
+		d.GasTipCap.Set(gasTipCap)
+	}
+	if gasFeeCap != nil {
+		d.GasFeeCap.Set(gasFeeCap)
+	}
+
+	return &Transaction{inner: &d}
+}
+
+// Type returns the transaction type
+func (tx *Transaction) Type() uint8 {
+	return tx.inner.txType()
 }
 
 // ChainID returns which chain id this transaction was signed for (if at all)
 func (tx *Transaction) ChainID() *big.Int {
-	return deriveChainID(tx.data.V)
+	return tx.inner.chainID()
 }
 
 // Protected returns whether the transaction is protected from replay protection.
 func (tx *Transaction) Protected() bool {
-	return isProtectedV(tx.data.V)
+	switch tx := tx.inner.(type) {
+	case *txdata:
+		return tx.V != nil && isProtectedV(tx.V)
+	default:
+		return true
+	}
 }
 
 func isProtectedV(rsvV *big.Int) bool {
@@ -95,68 +156,126 @@ func isProtectedV(rsvV *big.Int) bool {
 }
 
 // EncodeRLP implements rlp.Encoder
+// legacy tx is encoded as rlp list, typed tx is encoded as rlp string of `type || rlp(tx data)`
 func (tx *Transaction) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, &tx.data)
-}
-
-// DecodeRLP implements rlp.Decoder
-func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
-	_, size, _ := s.Kind()
-	err := s.Decode(&tx.data)
-	if err == nil {
-		tx.size.Store(StorageSize(rlp.ListSize(size)))
+	if tx.Type() == LegacyTxType {
+		return rlp.Encode(w, tx.inner)
 	}
-
-	return err
+	buf := new(bytes.Buffer)
+	if err := tx.encodeTyped(buf); err != nil {
+		return err
+	}
+	return rlp.Encode(w, buf.Bytes())
 }
 
-// MarshalJSON encodes the web3 RPC transaction format.
-func (tx *Transaction) MarshalJSON() ([]byte, error) {
-	hash := tx.Hash()
-	data := tx.data
-	data.Hash = &hash
-	return data.MarshalJSON()
+func (tx *Transaction) encodeTyped(w *bytes.Buffer) error {
+	w.WriteByte(tx.Type())
+	return rlp.Encode(w, tx.inner)
 }
 
-// UnmarshalJSON decodes the web3 RPC transaction format.
-func (tx *Transaction) UnmarshalJSON(input []byte) error {
-	var dec txdata
-	if err := dec.UnmarshalJSON(input); err != nil {
-		return err
+// MarshalBinary returns the canonical encoding of the transaction (used in eth_sendRawTransaction)
+// legacy tx is encoded as rlp list, typed tx is encoded as `type || rlp(tx data)`
+func (tx *Transaction) MarshalBinary() ([]byte, error) {
+	if tx.Type() == LegacyTxType {
+		return rlp.EncodeToBytes(tx.inner)
 	}
+	buf := new(bytes.Buffer)
+	err := tx.encodeTyped(buf)
+	return buf.Bytes(), err
+}
 
-	withSignature := dec.V.Sign() != 0 || dec.R.Sign() != 0 || dec.S.Sign() != 0
-	if withSignature {
-		var V byte
-		if isProtectedV(dec.V) {
-			chainID := deriveChainID(dec.V).Uint64()
-			V = byte(dec.V.Uint64() - 35 - 2*chainID)
-		} else {
-			V = byte(dec.V.Uint64() - 27)
+// DecodeRLP implements rlp.Decoder
+func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
+	kind, size, err := s.Kind()
+	switch {
+	case err != nil:
+		return err
+	case kind == rlp.List:
+		var inner txdata
+		err = s.Decode(&inner)
+		if err == nil {
+			tx.setDecoded(&inner, int(rlp.ListSize(size)))
+		}
+		return err
+	case kind == rlp.String:
+		var b []byte
+		if b, err = s.Bytes(); err != nil {
+			return err
 		}
-		if !crypto.ValidateSignatureValues(V, dec.R, dec.S, false) {
-			return ErrInvalidSig
+		inner, err := decodeTyped(b)
+		if err == nil {
+			tx.setDecoded(inner, len(b))
 		}
+		return err
+	default:
+		return rlp.ErrExpectedList
 	}
+}
 
-	*tx = Transaction{data: dec}
+// UnmarshalBinary decodes the canonical encoding of transactions
+func (tx *Transaction) UnmarshalBinary(b []byte) error {
+	if len(b) > 0 && b[0] > 0x7f {
+		// it's a legacy transaction
+		var data txdata
+		err := rlp.DecodeBytes(b, &data)
+		if err != nil {
+			return err
+		}
+		tx.setDecoded(&data, len(b))
+		return nil
+	}
+	inner, err := decodeTyped(b)
+	if err != nil {
+		return err
+	}
+	tx.setDecoded(inner, len(b))
 	return nil
 }
 
+func decodeTyped(b []byte) (innerTx, error) {
+	if len(b) == 0 {
+		return nil, errEmptyTypedTx
+	}
+	switch b[0] {
+	case DynamicFeeTxType:
+		var inner dynamicFeeTxdata
+		err := rlp.DecodeBytes(b[1:], &inner)
+		return &inner, err
+	default:
+		return nil, ErrTxTypeNotSupported
+	}
+}
+
+func (tx *Transaction) setDecoded(inner innerTx, size int) {
+	tx.inner = inner
+	if size > 0 {
+		tx.size.Store(StorageSize(size))
+	}
+}
+
 // Data tx data
-func (tx *Transaction) Data() []byte { return common.CopyBytes(tx.data.Payload) }
+func (tx *Transaction) Data() []byte { return common.CopyBytes(tx.inner.data()) }
+
+// AccessList tx access list
+func (tx *Transaction) AccessList() AccessList { return tx.inner.accessList().copy() }
 
 // Gas tx gas
-func (tx *Transaction) Gas() uint64 { return tx.data.GasLimit }
+func (tx *Transaction) Gas() uint64 { return tx.inner.gas() }
 
-// GasPrice tx gas price
-func (tx *Transaction) GasPrice() *big.Int { return new(big.Int).Set(tx.data.Price) }
+// GasPrice tx gas price (gas fee cap of dynamic fee tx)
+func (tx *Transaction) GasPrice() *big.Int { return new(big.Int).Set(tx.inner.gasPrice()) }
+
+// GasTipCap tx gas tip cap (max priority fee per gas)
+func (tx *Transaction) GasTipCap() *big.Int { return new(big.Int).Set(tx.inner.gasTipCap()) }
+
+// GasFeeCap tx gas fee cap (max fee per gas)
+func (tx *Transaction) GasFeeCap() *big.Int { return new(big.Int).Set(tx.inner.gasFeeCap()) }
 
 // Value tx value
-func (tx *Transaction) Value() *big.Int { return new(big.Int).Set(tx.data.Amount) }
+func (tx *Transaction) Value() *big.Int { return new(big.Int).Set(tx.inner.value()) }
 
 // Nonce tx nonce
-func (tx *Transaction) Nonce() uint64 { return tx.data.AccountNonce }
+func (tx *Transaction) Nonce() uint64 { return tx.inner.nonce() }
 
 // CheckNonce check nonce
 func (tx *Transaction) CheckNonce() bool { return true }
@@ -164,11 +283,15 @@ func (tx *Transaction) CheckNonce() bool { return true }
 // To returns the recipient address of the transaction.
 // It returns nil if the transaction is a contract creation.
 func (tx *Transaction) To() *common.Address {
-	if tx.data.Recipient == nil {
+	return copyAddressPtr(tx.inner.to())
+}
+
+func copyAddressPtr(a *common.Address) *common.Address {
+	if a == nil {
 		return nil
 	}
-	to := *tx.data.Recipient
-	return &to
+	cpy := *a
+	return &cpy
 }
 
 func rlpHash(x interface{}) (h common.Hash) {
@@ -178,13 +301,27 @@ func rlpHash(x interface{}) (h common.Hash) {
 	return h
 }
 
+// prefixedRlpHash writes the prefix into the hasher before rlp-encoding x.
+func prefixedRlpHash(prefix byte, x interface{}) (h common.Hash) {
+	hw := sha3.NewLegacyKeccak256()
+	_, _ = hw.Write([]byte{prefix})
+	_ = rlp.Encode(hw, x)
+	hw.Sum(h[:0])
+	return h
+}
+
 // Hash hashes the RLP encoding of tx.
 // It uniquely identifies the transaction.
 func (tx *Transaction) Hash() common.Hash {
 	if hash := tx.hash.Load(); hash != nil {
 		return hash.(common.Hash)
 	}
-	v := rlpHash(tx)
+	var v common.Hash
+	if tx.Type() == LegacyTxType {
+		v = rlpHash(tx.inner)
+	} else {
+		v = prefixedRlpHash(tx.Type(), tx.inner)
+	}
 	tx.hash.Store(v)
 	return v
 }
@@ -203,7 +340,7 @@ func (tx *Transaction) Size() StorageSize {
 		return size.(StorageSize)
 	}
 	c := writeCounter(0)
-	_ = rlp.Encode(&c, &tx.data)
+	_ = rlp.Encode(&c, tx)
 	tx.size.Store(StorageSize(c))
 	return StorageSize(c)
 }
@@ -215,20 +352,20 @@ func (tx *Transaction) WithSignature(signer Signer, sig []byte) (*Transaction, e
 	if err != nil {
 		return nil, err
 	}
-	cpy := &Transaction{data: tx.data}
-	cpy.data.R, cpy.data.S, cpy.data.V = r, s, v
-	return cpy, nil
+	cpy := tx.inner.copy()
+	cpy.setSignatureValues(signer.ChainID(), v, r, s)
+	return &Transaction{inner: cpy}, nil
 }
 
-// Cost returns amount + gasprice * gaslimit.
+// Cost returns amount + gasprice * gaslimit (gasfeecap * gaslimit of dynamic fee tx).
 func (tx *Transaction) Cost() *big.Int {
-	total := new(big.Int).Mul(tx.data.Price, new(big.Int).SetUint64(tx.data.GasLimit))
-	total.Add(total, tx.data.Amount)
+	total := new(big.Int).Mul(tx.inner.gasPrice(), new(big.Int).SetUint64(tx.inner.gas()))
+	total.Add(total, tx.inner.value())
 	return total
 }
 
 // RawSignatureValues returns the V, R, S signature values of the transaction.
 // The return values should not be modified by the caller.
 func (tx *Transaction) RawSignatureValues() (v, r, s *big.Int) {
-	return tx.data.V, tx.data.R, tx.data.S
+	return tx.inner.rawSignatureValues()
 }
@@ -0,0 +1,198 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/fsn-dev/crossChain-Bridge/common"
+	"github.com/fsn-dev/crossChain-Bridge/common/hexutil"
+	"github.com/fsn-dev/crossChain-Bridge/tools/crypto"
+)
+
+// txJSON is the JSON representation of transactions (web3 RPC format)
+type txJSON struct {
+	Type hexutil.Uint64 `json:"type,omitempty"`
+
+	ChainID              *hexutil.Big    `json:"chainId,omitempty"`
+	AccountNonce         *hexutil.Uint64 `json:"nonce"`
+	Price                *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	GasLimit             *hexutil.Uint64 `json:"gas"`
+	Recipient            *common.Address `json:"to"`
+	Amount               *hexutil.Big    `json:"value"`
+	Payload              *hexutil.Bytes  `json:"input"`
+	AccessList           *AccessList     `json:"accessList,omitempty"`
+	V                    *hexutil.Big    `json:"v"`
+	R                    *hexutil.Big    `json:"r"`
+	S                    *hexutil.Big    `json:"s"`
+
+	// Only used for encoding
+	Hash *common.Hash `json:"hash,omitempty"`
+}
+
+// MarshalJSON encodes the web3 RPC transaction format.
+func (tx *Transaction) MarshalJSON() ([]byte, error) {
+	var enc txJSON
+	hash := tx.Hash()
+	enc.Hash = &hash
+	enc.Type = hexutil.Uint64(tx.Type())
+
+	switch inner := tx.inner.(type) {
+	case *txdata:
+		enc.AccountNonce = (*hexutil.Uint64)(&inner.AccountNonce)
+		enc.Price = (*hexutil.Big)(inner.Price)
+		enc.GasLimit = (*hexutil.Uint64)(&inner.GasLimit)
+		enc.Recipient = inner.Recipient
+		enc.Amount = (*hexutil.Big)(inner.Amount)
+		enc.Payload = (*hexutil.Bytes)(&inner.Payload)
+		enc.V = (*hexutil.Big)(inner.V)
+		enc.R = (*hexutil.Big)(inner.R)
+		enc.S = (*hexutil.Big)(inner.S)
+	case *dynamicFeeTxdata:
+		enc.ChainID = (*hexutil.Big)(inner.ChainID)
+		enc.AccountNonce = (*hexutil.Uint64)(&inner.Nonce)
+		enc.MaxPriorityFeePerGas = (*hexutil.Big)(inner.GasTipCap)
+		enc.MaxFeePerGas = (*hexutil.Big)(inner.GasFeeCap)
+		enc.GasLimit = (*hexutil.Uint64)(&inner.Gas)
+		enc.Recipient = inner.To
+		enc.Amount = (*hexutil.Big)(inner.Value)
+		enc.Payload = (*hexutil.Bytes)(&inner.Data)
+		accessList := inner.AccessList
+		if accessList == nil {
+			accessList = AccessList{}
+		}
+		enc.AccessList = &accessList
+		enc.V = (*hexutil.Big)(inner.V)
+		enc.R = (*hexutil.Big)(inner.R)
+		enc.S = (*hexutil.Big)(inner.S)
+	}
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON decodes the web3 RPC transaction format.
+//
+//nolint:gocyclo // keep checking of required fields as whole
+func (tx *Transaction) UnmarshalJSON(input []byte) error {
+	var dec txJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+
+	var inner innerTx
+	switch dec.Type {
+	case LegacyTxType:
+		var itx txdata
+		inner = &itx
+		if dec.AccountNonce == nil {
+			return errors.New("missing required field 'nonce' in transaction")
+		}
+		itx.AccountNonce = uint64(*dec.AccountNonce)
+		if dec.Price == nil {
+			return errors.New("missing required field 'gasPrice' in transaction")
+		}
+		itx.Price = (*big.Int)(dec.Price)
+		if dec.GasLimit == nil {
+			return errors.New("missing required field 'gas' in transaction")
+		}
+		itx.GasLimit = uint64(*dec.GasLimit)
+		itx.Recipient = dec.Recipient
+		if dec.Amount == nil {
+			return errors.New("missing required field 'value' in transaction")
+		}
+		itx.Amount = (*big.Int)(dec.Amount)
+		if dec.Payload == nil {
+			return errors.New("missing required field 'input' in transaction")
+		}
+		itx.Payload = *dec.Payload
+		if dec.V == nil || dec.R == nil || dec.S == nil {
+			return errors.New("missing required field 'v', 'r' or 's' in transaction")
+		}
+		itx.V = (*big.Int)(dec.V)
+		itx.R = (*big.Int)(dec.R)
+		itx.S = (*big.Int)(dec.S)
+		if withSignature(itx.V, itx.R, itx.S) {
+			var V byte
+			if isProtectedV(itx.V) {
+				chainID := deriveChainID(itx.V).Uint64()
+				V = byte(itx.V.Uint64() - 35 - 2*chainID)
+			} else {
+				V = byte(itx.V.Uint64() - 27)
+			}
+			if !crypto.ValidateSignatureValues(V, itx.R, itx.S, false) {
+				return ErrInvalidSig
+			}
+		}
+
+	case DynamicFeeTxType:
+		var itx dynamicFeeTxdata
+		inner = &itx
+		if dec.ChainID == nil {
+			return errors.New("missing required field 'chainId' in transaction")
+		}
+		itx.ChainID = (*big.Int)(dec.ChainID)
+		if dec.AccountNonce == nil {
+			return errors.New("missing required field 'nonce' in transaction")
+		}
+		itx.Nonce = uint64(*dec.AccountNonce)
+		if dec.MaxPriorityFeePerGas == nil {
+			return errors.New("missing required field 'maxPriorityFeePerGas' in transaction")
+		}
+		itx.GasTipCap = (*big.Int)(dec.MaxPriorityFeePerGas)
+		if dec.MaxFeePerGas == nil {
+			return errors.New("missing required field 'maxFeePerGas' in transaction")
+		}
+		itx.GasFeeCap = (*big.Int)(dec.MaxFeePerGas)
+		if dec.GasLimit == nil {
+			return errors.New("missing required field 'gas' in transaction")
+		}
+		itx.Gas = uint64(*dec.GasLimit)
+		itx.To = dec.Recipient
+		if dec.Amount == nil {
+			return errors.New("missing required field 'value' in transaction")
+		}
+		itx.Value = (*big.Int)(dec.Amount)
+		if dec.Payload == nil {
+			return errors.New("missing required field 'input' in transaction")
+		}
+		itx.Data = *dec.Payload
+		if dec.AccessList != nil {
+			itx.AccessList = *dec.AccessList
+		}
+		if dec.V == nil || dec.R == nil || dec.S == nil {
+			return errors.New("missing required field 'v', 'r' or 's' in transaction")
+		}
+		itx.V = (*big.Int)(dec.V)
+		itx.R = (*big.Int)(dec.R)
+		itx.S = (*big.Int)(dec.S)
+		if withSignature(itx.V, itx.R, itx.S) {
+			if itx.V.BitLen() > 8 || !crypto.ValidateSignatureValues(byte(itx.V.Uint64()), itx.R, itx.S, false) {
+				return ErrInvalidSig
+			}
+		}
+
+	default:
+		return ErrTxTypeNotSupported
+	}
+
+	*tx = Transaction{inner: inner}
+	return nil
+}
+
+func withSignature(v, r, s *big.Int) bool {
+	return v.Sign() != 0 || r.Sign() != 0 || s.Sign() != 0
+}
+
+// PrintPretty print pretty (json)
+func (tx *Transaction) PrintPretty() {
+	bs, _ := json.MarshalIndent(tx, "", "  ")
+	fmt.Println(string(bs))
+}
+
+// PrintRaw print raw encoded (hex string)
+func (tx *Transaction) PrintRaw() {
+	bs, _ := tx.MarshalBinary()
+	fmt.Println(hexutil.Bytes(bs))
+}
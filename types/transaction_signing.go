@@ -27,6 +27,8 @@ type sigCache struct {
 func MakeSigner(signType string, chainID *big.Int) Signer {
 	var signer Signer
 	switch signType {
+	case "London":
+		signer = NewLondonSigner(chainID)
 	case "EIP155":
 		signer = NewEIP155Signer(chainID)
 	case "Homestead":
@@ -85,10 +87,79 @@ type Signer interface {
 	SignatureValues(tx *Transaction, sig []byte) (r, s, v *big.Int, err error)
 	// Hash returns the hash to be signed.
 	Hash(tx *Transaction) common.Hash
+	// ChainID returns the chain id of the signer (nil or zero if not replay protected).
+	ChainID() *big.Int
 	// Equal returns true if the given signer is the same as the receiver.
 	Equal(Signer) bool
 }
 
+// LondonSigner implements Signer using the EIP-1559 rules for dynamic fee tx,
+// and the EIP155 rules for legacy tx.
+type LondonSigner struct{ EIP155Signer }
+
+// NewLondonSigner new LondonSigner
+func NewLondonSigner(chainID *big.Int) LondonSigner {
+	return LondonSigner{NewEIP155Signer(chainID)}
+}
+
+// Equal compare signer
+func (s LondonSigner) Equal(s2 Signer) bool {
+	london, ok := s2.(LondonSigner)
+	return ok && london.chainID.Cmp(s.chainID) == 0
+}
+
+// Sender get sender
+func (s LondonSigner) Sender(tx *Transaction) (common.Address, error) {
+	if tx.Type() != DynamicFeeTxType {
+		return s.EIP155Signer.Sender(tx)
+	}
+	if tx.ChainID().Cmp(s.chainID) != 0 {
+		return common.Address{}, ErrInvalidChainID
+	}
+	// dynamic fee tx use 0 and 1 as recovery id,
+	// add 27 to become equivalent to unprotected Homestead signatures.
+	V, R, S := tx.RawSignatureValues()
+	V = new(big.Int).Add(V, big.NewInt(27))
+	return recoverPlain(s.Hash(tx), R, S, V, true)
+}
+
+// SignatureValues returns signature values. This signature
+// needs to be in the [R || S || V] format where V is 0 or 1.
+func (s LondonSigner) SignatureValues(tx *Transaction, sig []byte) (rsvR, rsvS, rsvV *big.Int, err error) {
+	if tx.Type() != DynamicFeeTxType {
+		return s.EIP155Signer.SignatureValues(tx, sig)
+	}
+	// Check that chain ID of tx matches the signer.
+	if txChainID := tx.ChainID(); txChainID.Sign() != 0 && txChainID.Cmp(s.chainID) != 0 {
+		return nil, nil, nil, ErrInvalidChainID
+	}
+	rsvR, rsvS, _, err = HomesteadSigner{}.SignatureValues(tx, sig)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	rsvV = big.NewInt(int64(sig[64]))
+	return rsvR, rsvS, rsvV, nil
+}
+
+// Hash returns the hash to be signed by the sender.
+// It does not uniquely identify the transaction.
+func (s LondonSigner) Hash(tx *Transaction) common.Hash {
+	if tx.Type() != DynamicFeeTxType {
+		return s.EIP155Signer.Hash(tx)
+	}
+	return prefixedRlpHash(tx.Type(), []interface{}{
+		s.chainID,
+		tx.Nonce(),
+		tx.GasTipCap(),
+		tx.GasFeeCap(),
+		tx.Gas(),
+		tx.To(),
+		tx.Value(),
+		tx.Data(),
+		tx.AccessList(),
+	})
+}
+
 // EIP155Signer implements Signer using the EIP155 rules.
 type EIP155Signer struct {
 	chainID, chainIDMul *big.Int
@@ -105,6 +176,11 @@ func NewEIP155Signer(chainID *big.Int) EIP155Signer {
 	}
 }
 
+// ChainID returns chain id
+func (s EIP155Signer) ChainID() *big.Int {
+	return s.chainID
+}
+
 // Equal compare signer
 func (s EIP155Signer) Equal(s2 Signer) bool {
 	eip155, ok := s2.(EIP155Signer)
@@ -115,20 +191,27 @@ var big8 = big.NewInt(8)
 
 // Sender get sender
 func (s EIP155Signer) Sender(tx *Transaction) (common.Address, error) {
+	if tx.Type() != LegacyTxType {
+		return common.Address{}, ErrTxTypeNotSupported
+	}
 	if !tx.Protected() {
 		return HomesteadSigner{}.Sender(tx)
 	}
 	if tx.ChainID().Cmp(s.chainID) != 0 {
 		return common.Address{}, ErrInvalidChainID
 	}
-	V := new(big.Int).Sub(tx.data.V, s.chainIDMul)
+	V, R, S := tx.RawSignatureValues()
+	V = new(big.Int).Sub(V, s.chainIDMul)
 	V.Sub(V, big8)
-	return recoverPlain(s.Hash(tx), tx.data.R, tx.data.S, V, true)
+	return recoverPlain(s.Hash(tx), R, S, V, true)
 }
 
 // SignatureValues returns signature values. This signature
 // needs to be in the [R || S || V] format where V is 0 or 1.
 func (s EIP155Signer) SignatureValues(tx *Transaction, sig []byte) (rsvR, rsvS, rsvV *big.Int, err error) {
+	if tx.Type() != LegacyTxType {
+		return nil, nil, nil, ErrTxTypeNotSupported
+	}
 	rsvR, rsvS, rsvV, err = HomesteadSigner{}.SignatureValues(tx, sig)
 	if err != nil {
 		return nil, nil, nil, err
@@ -144,12 +227,12 @@ func (s EIP155Signer) SignatureValues(tx *Transaction, sig []byte) (rsvR, rsvS,
 // It does not uniquely identify the transaction.
 func (s EIP155Signer) Hash(tx *Transaction) common.Hash {
 	return rlpHash([]interface{}{
-		tx.data.AccountNonce,
-		tx.data.Price,
-		tx.data.GasLimit,
-		tx.data.Recipient,
-		tx.data.Amount,
-		tx.data.Payload,
+		tx.Nonce(),
+		tx.GasPrice(),
+		tx.Gas(),
+		tx.To(),
+		tx.Value(),
+		tx.Data(),
 		s.chainID, uint(0), uint(0),
 	})
 }
@@ -172,12 +255,21 @@ func (hs HomesteadSigner) SignatureValues(tx *Transaction, sig []byte) (r, s, v
 
 // Sender get sender
 func (hs HomesteadSigner) Sender(tx *Transaction) (common.Address, error) {
-	return recoverPlain(hs.Hash(tx), tx.data.R, tx.data.S, tx.data.V, true)
+	if tx.Type() != LegacyTxType {
+		return common.Address{}, ErrTxTypeNotSupported
+	}
+	v, r, s := tx.RawSignatureValues()
+	return recoverPlain(hs.Hash(tx), r, s, v, true)
 }
 
 // FrontierSigner frontier signer
 type FrontierSigner struct{}
 
+// ChainID returns chain id (nil as not replay protected)
+func (fs FrontierSigner) ChainID() *big.Int {
+	return nil
+}
+
 // Equal compare signer
 func (fs FrontierSigner) Equal(s2 Signer) bool {
 	_, ok := s2.(FrontierSigner)
@@ -200,18 +292,22 @@ func (fs FrontierSigner) SignatureValues(tx *Transaction, sig []byte) (r, s, v *
 // It does not uniquely identify the transaction.
 func (fs FrontierSigner) Hash(tx *Transaction) common.Hash {
 	return rlpHash([]interface{}{
-		tx.data.AccountNonce,
-		tx.data.Price,
-		tx.data.GasLimit,
-		tx.data.Recipient,
-		tx.data.Amount,
-		tx.data.Payload,
+		tx.Nonce(),
+		tx.GasPrice(),
+		tx.Gas(),
+		tx.To(),
+		tx.Value(),
+		tx.Data(),
 	})
 }
 
 // Sender get sender
 func (fs FrontierSigner) Sender(tx *Transaction) (common.Address, error) {
-	return recoverPlain(fs.Hash(tx), tx.data.R, tx.data.S, tx.data.V, false)
+	if tx.Type() != LegacyTxType {
+		return common.Address{}, ErrTxTypeNotSupported
+	}
+	v, r, s := tx.RawSignatureValues()
+	return recoverPlain(fs.Hash(tx), r, s, v, false)
 }
 
 func recoverPlain(sighash common.Hash, rsvR, rsvS, rsvV *big.Int, homestead bool) (common.Address, error) {
@@ -10,6 +10,9 @@ import (
 
 	"github.com/fsn-dev/crossChain-Bridge/common"
 	"github.com/fsn-dev/crossChain-Bridge/dcrm"
+	"github.com/fsn-dev/crossChain-Bridge/internal/journal"
+	"github.com/fsn-dev/crossChain-Bridge/internal/oracleindex"
+	"github.com/fsn-dev/crossChain-Bridge/log"
 	"github.com/fsn-dev/crossChain-Bridge/params"
 	"github.com/fsn-dev/crossChain-Bridge/tokens"
 	"github.com/fsn-dev/crossChain-Bridge/tokens/btc"
@@ -22,6 +25,11 @@ var (
 	acceptRingLock    sync.RWMutex
 	acceptRingMaxSize = 500
 
+	// durable journal of accept sign decisions
+	acceptJournal *journal.Journal
+	// ignored sign infos which are journaled (keyID => error), to journal them only once
+	ignoredSignInfos = make(map[string]string)
+
 	retryInterval = 3 * time.Second
 	waitInterval  = 20 * time.Second
 
@@ -35,6 +43,7 @@ var (
 func StartAcceptSignJob() {
 	acceptSignStarter.Do(func() {
 		logWorker("accept", "start accept sign job")
+		openAcceptJournal()
 		acceptSign()
 	})
 }
@@ -55,109 +64,197 @@ func acceptSign() {
 				_, _ = dcrm.DoAcceptSign(keyID, history.result, history.msgHash, history.msgContext)
 				continue
 			}
-			agreeResult := "AGREE"
-			err := verifySignInfo(info)
+			entry := &journal.Entry{KeyID: keyID, SignInfo: info}
+			agreeResult := journal.ResultAgree
+			args, outflow, rebuiltMsgHash, err := verifySignInfo(info)
+			setJournalEntryVerifyResult(entry, args, outflow, rebuiltMsgHash, err)
 			switch err {
 			case errIdentifierMismatch,
 				errInitiatorMismatch,
 				errWrongMsgContext,
+				errDepositNotIndexed,
 				tokens.ErrTxNotStable,
 				tokens.ErrTxNotFound:
 				logWorkerTrace("accept", "ignore sign info", "keyID", keyID, "err", err)
+				journalIgnoredSignInfo(entry)
 				continue
 			}
 			if err != nil {
 				logWorkerError("accept", "disagree sign", err, "keyID", keyID)
-				agreeResult = "DISAGREE"
+				agreeResult = journal.ResultDisagree
+			} else if err = checkAcceptPolicy(outflow); err != nil {
+				logWorkerError("accept", "disagree sign by policy", err, "keyID", keyID, "pairID", entry.PairID, "swapID", entry.SwapID)
+				entry.PolicyError = err.Error()
+				agreeResult = journal.ResultDisagree
+			} else if err = addSignRecord(keyID, outflow, info.MsgHash); err != nil {
+				// the sign record guards against double sign, never agree without it
+				logWorkerError("accept", "disagree sign as sign record is not saved", err, "keyID", keyID, "pairID", entry.PairID, "swapID", entry.SwapID)
+				entry.Verified = false
+				entry.VerifyError = err.Error()
+				agreeResult = journal.ResultDisagree
+			}
+			// journal the decision before sending it, not journaled decision will be made again
+			entry.Result = agreeResult
+			if appendAcceptJournal(entry) != nil {
+				continue
+			}
+			addAcceptSignHistory(keyID, agreeResult, info.MsgHash, info.MsgContext)
+			if agreeResult == journal.ResultAgree {
+				agreedOutflows.add(outflow)
 			}
 			logWorker("accept", "dcrm DoAcceptSign", "keyID", keyID, "result", agreeResult)
 			res, err := dcrm.DoAcceptSign(keyID, agreeResult, info.MsgHash, info.MsgContext)
+			journalDcrmResponse(entry, res, err)
 			if err != nil {
+				// the journaled decision is sent again in next loop
 				logWorkerError("accept", "accept sign job failed", err, "keyID", keyID, "result", res)
 			} else {
 				logWorker("accept", "accept sign job finish", "keyID", keyID, "result", agreeResult)
-				addAcceptSignHistory(keyID, agreeResult, info.MsgHash, info.MsgContext)
 			}
 		}
+		pruneIgnoredSignInfos(signInfo)
 		time.Sleep(waitInterval)
 	}
 }
 
-func verifySignInfo(signInfo *dcrm.SignInfoData) error {
+// verifySignInfo verify sign info, return the build tx args in msg context,
+// the outflow of the swap (nil if it's not a swap) and the rebuilt msgHash
+func verifySignInfo(signInfo *dcrm.SignInfoData) (args *tokens.BuildTxArgs, outflow *swapOutflow, rebuiltMsgHash []string, err error) {
 	if common.HexToAddress(signInfo.Account) != common.HexToAddress(params.GetServerDcrmUser()) {
-		return errInitiatorMismatch
+		return nil, nil, nil, errInitiatorMismatch
 	}
 	msgHash := signInfo.MsgHash
 	msgContext := signInfo.MsgContext
 	logWorker("accept", "verifySignInfo", "msgHash", msgHash, "msgContext", msgContext)
 	if len(msgContext) != 1 {
-		return errWrongMsgContext
+		return nil, nil, nil, errWrongMsgContext
 	}
-	var args tokens.BuildTxArgs
-	err := json.Unmarshal([]byte(msgContext[0]), &args)
+	args = &tokens.BuildTxArgs{}
+	err = json.Unmarshal([]byte(msgContext[0]), args)
 	if err != nil {
-		return errWrongMsgContext
+		return nil, nil, nil, errWrongMsgContext
 	}
+	// dispatch on identifier across all configed pairs
 	switch args.Identifier {
-	case params.GetIdentifier():
 	case btc.AggregateIdentifier:
-		return btc.BridgeInstance.VerifyAggregateMsgHash(msgHash, &args)
-	default:
-		return errIdentifierMismatch
+		btcBridge := btc.GetBridgeOfPair(getPairIDOfArgs(args), true)
+		if btcBridge == nil {
+			return args, nil, nil, errIdentifierMismatch
+		}
+		rebuiltMsgHash, err = btcBridge.VerifyAggregateMsgHash(msgHash, args)
+		return args, nil, rebuiltMsgHash, err
+	case btc.CpfpIdentifier:
+		pairID := getPairIDOfArgs(args)
+		btcBridge := btc.GetBridgeOfPair(pairID, true)
+		if btcBridge == nil {
+			btcBridge = btc.GetBridgeOfPair(pairID, false)
+		}
+		if btcBridge == nil {
+			return args, nil, nil, errIdentifierMismatch
+		}
+		rebuiltMsgHash, err = btcBridge.VerifyCpfpMsgHash(msgHash, args)
+		return args, nil, rebuiltMsgHash, err
 	}
-	return rebuildAndVerifyMsgHash(msgHash, &args)
+	pair := tokens.GetBridgePair(args.Identifier)
+	if pair == nil || (args.PairID != "" && args.PairID != args.Identifier) {
+		return args, nil, nil, errIdentifierMismatch
+	}
+	outflow, rebuiltMsgHash, err = rebuildAndVerifyMsgHash(pair, msgHash, args)
+	return args, outflow, rebuiltMsgHash, err
+}
+
+// getPairIDOfArgs sign request from old version server has no pair id, it's the default pair
+func getPairIDOfArgs(args *tokens.BuildTxArgs) string {
+	if args.PairID == "" {
+		return tokens.GetDefaultPairID()
+	}
+	return args.PairID
 }
 
-func rebuildAndVerifyMsgHash(msgHash []string, args *tokens.BuildTxArgs) error {
+func rebuildAndVerifyMsgHash(pair *tokens.BridgePair, msgHash []string, args *tokens.BuildTxArgs) (outflow *swapOutflow, rebuiltMsgHash []string, err error) {
 	var (
 		srcBridge, dstBridge tokens.CrossChainBridge
 		memo                 string
 	)
 	switch args.SwapType {
 	case tokens.SwapinType:
-		srcBridge = tokens.SrcBridge
-		dstBridge = tokens.DstBridge
+		srcBridge = pair.SrcBridge
+		dstBridge = pair.DstBridge
+		memo = fmt.Sprintf("%s%s", tokens.UnlockMemoPrefix, args.SwapID)
 	case tokens.SwapoutType:
-		srcBridge = tokens.DstBridge
-		dstBridge = tokens.SrcBridge
+		srcBridge = pair.DstBridge
+		dstBridge = pair.SrcBridge
 		memo = fmt.Sprintf("%s%s", tokens.UnlockMemoPrefix, args.SwapID)
 	case tokens.SwapRecallType:
-		srcBridge = tokens.SrcBridge
-		dstBridge = tokens.SrcBridge
+		srcBridge = pair.SrcBridge
+		dstBridge = pair.SrcBridge
 		memo = fmt.Sprintf("%s%s", tokens.RecallMemoPrefix, args.SwapID)
 	default:
-		return fmt.Errorf("unknown swap type %v", args.SwapType)
+		return nil, nil, fmt.Errorf("unknown swap type %v", args.SwapType)
 	}
-	var (
-		swap *tokens.TxSwapInfo
-		err  error
-	)
+	// only sign swaps found by oracle's own scanning
+	deposit, _ := oracleindex.GetDeposit(pair.PairID, args.SwapType != tokens.SwapoutType, args.SwapID)
+	if deposit == nil {
+		return nil, nil, errDepositNotIndexed
+	}
+	if args.TxType == tokens.P2shSwapinTx && !common.IsEqualIgnoreCase(args.Bind, deposit.Bind) {
+		return nil, nil, tokens.ErrWrongP2shSwapin
+	}
+	var swap *tokens.TxSwapInfo
 	switch args.TxType {
 	case tokens.P2shSwapinTx:
-		if btc.BridgeInstance == nil {
-			return tokens.ErrWrongP2shSwapin
+		btcBridge := btc.GetBridgeOfPair(pair.PairID, true)
+		if btcBridge == nil {
+			return nil, nil, tokens.ErrWrongP2shSwapin
 		}
-		swap, err = btc.BridgeInstance.VerifyP2shTransaction(args.SwapID, args.Bind, false)
+		swap, err = btcBridge.VerifyP2shTransaction(args.SwapID, args.Bind, false)
 	default:
 		swap, err = srcBridge.VerifyTransaction(args.SwapID, false)
 	}
+	if args.SwapType == tokens.SwapRecallType {
+		// only swapin with recallable error can be recalled
+		if err == nil {
+			err = tokens.ErrTxCanNotRecall
+		} else if tokens.IsRecallableError(err) {
+			err = nil
+		}
+	}
+	if err == nil && swap == nil {
+		err = tokens.ErrTxNotFound
+	}
 	if err != nil {
-		logWorkerError("accept", "verifySignInfo failed", err, "txid", args.SwapID, "swaptype", args.SwapType)
-		return err
+		logWorkerError("accept", "verifySignInfo failed", err, "pairID", pair.PairID, "txid", args.SwapID, "swaptype", args.SwapType)
+		return nil, nil, err
+	}
+	to := swap.Bind
+	if args.SwapType == tokens.SwapRecallType {
+		to = tokens.GetRecallAddress(pair.PairID, swap.From, swap.Bind)
 	}
+	outflow = newSwapOutflow(pair.PairID, args, swap, to)
 
 	buildTxArgs := &tokens.BuildTxArgs{
 		SwapInfo: args.SwapInfo,
-		To:       swap.Bind,
+		To:       to,
 		Value:    swap.Value,
 		Memo:     memo,
 		Extra:    args.Extra,
 	}
 	rawTx, err := dstBridge.BuildRawTransaction(buildTxArgs)
 	if err != nil {
-		return err
+		return outflow, nil, err
+	}
+	if calculator, ok := dstBridge.(tokens.MsgHashCalculator); ok {
+		rebuiltMsgHash, _ = calculator.CalcMsgHash(rawTx)
+	}
+	err = dstBridge.VerifyMsgHash(rawTx, msgHash, args.Extra)
+	if err != nil {
+		return outflow, rebuiltMsgHash, err
 	}
-	return dstBridge.VerifyMsgHash(rawTx, msgHash, args.Extra)
+	if outflow != nil {
+		outflow.spendKeys = getSpendKeys(buildTxArgs.Extra)
+		err = checkDoubleSign(outflow, msgHash)
+	}
+	return outflow, rebuiltMsgHash, err
 }
 
 type acceptSignInfo struct {
@@ -212,3 +309,90 @@ func getAcceptSignHistory(keyID string) *acceptSignInfo {
 
 	return nil
 }
+
+func openAcceptJournal() {
+	path := journal.DefaultFilePath()
+	j, err := journal.Open(path, func(entry *journal.Entry) {
+		if entry.IsAccepted() && entry.SignInfo != nil {
+			addAcceptSignHistory(entry.KeyID, entry.Result, entry.SignInfo.MsgHash, entry.SignInfo.MsgContext)
+		}
+		if entry.IsAgreed() {
+			agreedOutflows.add(newSwapOutflowFromJournal(entry))
+		}
+	})
+	if err != nil {
+		log.Fatal("open accept journal failed", "path", path, "err", err)
+	}
+	acceptJournal = j
+}
+
+func setJournalEntryVerifyResult(entry *journal.Entry, args *tokens.BuildTxArgs, outflow *swapOutflow, rebuiltMsgHash []string, err error) {
+	if args != nil {
+		switch args.Identifier {
+		case btc.AggregateIdentifier, btc.CpfpIdentifier:
+			entry.PairID = getPairIDOfArgs(args)
+		default:
+			entry.PairID = args.Identifier
+		}
+		entry.SwapID = args.SwapID
+		entry.SwapType = uint32(args.SwapType)
+	}
+	if outflow != nil {
+		entry.From = outflow.from
+		entry.Bind = outflow.bind
+		entry.Value = outflow.value.String()
+	}
+	entry.RebuiltMsgHash = rebuiltMsgHash
+	entry.Verified = err == nil
+	if err != nil {
+		entry.VerifyError = err.Error()
+	}
+}
+
+func appendAcceptJournal(entry *journal.Entry) error {
+	err := acceptJournal.Append(entry)
+	if err != nil {
+		logWorkerError("accept", "append accept journal failed", err, "keyID", entry.KeyID, "result", entry.Result)
+	}
+	return err
+}
+
+// journalDcrmResponse journal the response of dcrm to the sent decision
+func journalDcrmResponse(decision *journal.Entry, res string, err error) {
+	entry := &journal.Entry{
+		KeyID:        decision.KeyID,
+		PairID:       decision.PairID,
+		SwapID:       decision.SwapID,
+		SwapType:     decision.SwapType,
+		Result:       journal.ResultResponse,
+		DcrmResponse: res,
+	}
+	if err != nil {
+		entry.DcrmError = err.Error()
+	}
+	_ = appendAcceptJournal(entry)
+}
+
+// journalIgnoredSignInfo journal ignored sign info once unless the error changes
+func journalIgnoredSignInfo(entry *journal.Entry) {
+	if errStr, exist := ignoredSignInfos[entry.KeyID]; exist && errStr == entry.VerifyError {
+		return
+	}
+	entry.Result = journal.ResultIgnore
+	if appendAcceptJournal(entry) == nil {
+		ignoredSignInfos[entry.KeyID] = entry.VerifyError
+	}
+}
+
+// pruneIgnoredSignInfos forget ignored sign infos which are not pending any more
+func pruneIgnoredSignInfos(signInfo []*dcrm.SignInfoData) {
+	pending := make(map[string]struct{}, len(signInfo))
+	for _, info := range signInfo {
+		pending[info.Key] = struct{}{}
+	}
+	for keyID := range ignoredSignInfos {
+		if _, exist := pending[keyID]; !exist {
+			delete(ignoredSignInfos, keyID)
+		}
+	}
+}
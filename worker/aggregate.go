@@ -1,6 +1,7 @@
 package worker
 
 import (
+	"sync"
 	"time"
 
 	"github.com/fsn-dev/crossChain-Bridge/mongodb"
@@ -10,84 +11,109 @@ import (
 )
 
 var (
-	utxoPageLimit = 100
+	aggregateStarter sync.Once
 
-	aggSumVal   uint64
-	aggAddrs    []string
-	aggUtxos    []*electrs.ElectUtxo
-	aggOffset   int
-	aggInterval = 300 * time.Second
+	utxoPageLimit = 100
+	aggInterval   = 300 * time.Second
 )
 
+// aggregator aggregate utxos of p2sh addresses of a bridge pair
+type aggregator struct {
+	pairID string
+	bridge *btc.Bridge
+
+	aggSumVal uint64
+	aggAddrs  []string
+	aggUtxos  []*electrs.ElectUtxo
+	aggOffset int
+}
+
 // StartAggregateJob aggregate job
 func StartAggregateJob() {
-	if btc.BridgeInstance == nil {
-		return
-	}
+	aggregateStarter.Do(func() {
+		for _, pairID := range tokens.GetAllPairIDs() {
+			// p2sh deposit addresses only exist when btc is the source chain
+			bridge := btc.GetBridgeOfPair(pairID, true)
+			if bridge == nil {
+				continue
+			}
+			agg := &aggregator{pairID: pairID, bridge: bridge}
+			go agg.startAggregateJob()
+		}
+	})
+}
 
+func (agg *aggregator) startAggregateJob() {
 	for loop := 1; ; loop++ {
-		logWorker("aggregate", "start aggregate job", "loop", loop)
-		doAggregateJob()
-		logWorker("aggregate", "finish aggregate job", "loop", loop)
+		logWorker("aggregate", "start aggregate job", "pairID", agg.pairID, "loop", loop)
+		agg.doAggregateJob()
+		logWorker("aggregate", "finish aggregate job", "pairID", agg.pairID, "loop", loop)
 		time.Sleep(aggInterval)
 	}
 }
 
-func doAggregateJob() {
-	aggOffset = 0
+func (agg *aggregator) doAggregateJob() {
+	agg.aggOffset = 0
 	for {
-		p2shAddrs, err := mongodb.FindP2shAddresses(aggOffset, utxoPageLimit)
+		p2shAddrs, err := mongodb.FindP2shAddresses(agg.pairID, agg.aggOffset, utxoPageLimit)
 		if err != nil {
-			logWorkerError("aggregate", "FindP2shAddresses failed", err, "offset", aggOffset, "limit", utxoPageLimit)
+			logWorkerError("aggregate", "FindP2shAddresses failed", err, "pairID", agg.pairID, "offset", agg.aggOffset, "limit", utxoPageLimit)
 			time.Sleep(3 * time.Second)
 			continue
 		}
 		for _, p2shAddr := range p2shAddrs {
-			findUtxosAndAggregate(p2shAddr.P2shAddress)
+			agg.findUtxosAndAggregate(p2shAddr.P2shAddress)
+			if p2shAddr.P2wshAddress != "" {
+				agg.findUtxosAndAggregate(p2shAddr.P2wshAddress)
+			}
 		}
 		if len(p2shAddrs) < utxoPageLimit {
 			break
 		}
-		aggOffset += utxoPageLimit
+		agg.aggOffset += utxoPageLimit
 	}
 }
 
-func findUtxosAndAggregate(addr string) {
-	findUtxos, _ := btc.BridgeInstance.FindUtxos(addr)
+func (agg *aggregator) findUtxosAndAggregate(addr string) {
+	findUtxos, err := agg.bridge.FindTrackedUtxos(addr)
+	if err != nil {
+		logWorkerError("aggregate", "FindTrackedUtxos failed", err, "pairID", agg.pairID, "address", addr)
+		return
+	}
 	for _, utxo := range findUtxos {
 		if utxo.Value == nil || *utxo.Value == 0 {
 			continue
 		}
-		logWorker("aggregate", "find utxo", "address", addr, "utxo", utxo.String())
+		logWorker("aggregate", "find utxo", "pairID", agg.pairID, "address", addr, "utxo", utxo.String())
 
-		aggSumVal += *utxo.Value
-		aggAddrs = append(aggAddrs, addr)
-		aggUtxos = append(aggUtxos, utxo)
+		agg.aggSumVal += *utxo.Value
+		agg.aggAddrs = append(agg.aggAddrs, addr)
+		agg.aggUtxos = append(agg.aggUtxos, utxo)
 
-		if shouldAggregate() {
-			aggregate()
+		if agg.shouldAggregate() {
+			agg.aggregate()
 		}
 	}
 }
 
-func shouldAggregate() bool {
-	if len(aggUtxos) >= tokens.BtcUtxoAggregateMinCount {
+func (agg *aggregator) shouldAggregate() bool {
+	if len(agg.aggUtxos) >= tokens.BtcUtxoAggregateMinCount {
 		return true
 	}
-	if aggSumVal >= tokens.BtcUtxoAggregateMinValue {
+	if agg.aggSumVal >= tokens.BtcUtxoAggregateMinValue {
 		return true
 	}
 	return false
 }
 
-func aggregate() {
-	txHash, err := btc.BridgeInstance.AggregateUtxos(aggAddrs, aggUtxos)
+func (agg *aggregator) aggregate() {
+	txHash, err := agg.bridge.AggregateUtxos(agg.aggAddrs, agg.aggUtxos)
 	if err != nil {
-		logWorkerError("aggregate", "AggregateUtxos failed", err)
+		logWorkerError("aggregate", "AggregateUtxos failed", err, "pairID", agg.pairID)
 	} else {
-		logWorker("aggregate", "AggregateUtxos succeed", "txHash", txHash, "utxos", len(aggUtxos), "sumVal", aggSumVal)
+		logWorker("aggregate", "AggregateUtxos succeed", "pairID", agg.pairID, "txHash", txHash, "utxos", len(agg.aggUtxos), "sumVal", agg.aggSumVal)
 	}
-	aggSumVal = 0
-	aggAddrs = nil
-	aggUtxos = nil
+	agg.aggSumVal = 0
+	agg.aggAddrs = nil
+	agg.aggUtxos = nil
 }
@@ -8,21 +8,23 @@ import (
 // MatchTx struct
 type MatchTx struct {
 	SwapTx     string
+	OldSwapTxs []string
+	SwapNonce  *uint64
 	SwapHeight uint64
 	SwapTime   uint64
 	SwapValue  string
 	SwapType   tokens.SwapType
 }
 
-func addInitialSwapinResult(tx *tokens.TxSwapInfo, status mongodb.SwapStatus) error {
-	return addInitialSwapResult(tx, status, true)
+func addInitialSwapinResult(pairID string, tx *tokens.TxSwapInfo, status mongodb.SwapStatus) error {
+	return addInitialSwapResult(pairID, tx, status, true)
 }
 
-func addInitialSwapoutResult(tx *tokens.TxSwapInfo, status mongodb.SwapStatus) error {
-	return addInitialSwapResult(tx, status, false)
+func addInitialSwapoutResult(pairID string, tx *tokens.TxSwapInfo, status mongodb.SwapStatus) error {
+	return addInitialSwapResult(pairID, tx, status, false)
 }
 
-func addInitialSwapResult(tx *tokens.TxSwapInfo, status mongodb.SwapStatus, isSwapin bool) (err error) {
+func addInitialSwapResult(pairID string, tx *tokens.TxSwapInfo, status mongodb.SwapStatus, isSwapin bool) (err error) {
 	txid := tx.Hash
 	var swapType tokens.SwapType
 	if isSwapin {
@@ -49,33 +51,81 @@ func addInitialSwapResult(tx *tokens.TxSwapInfo, status mongodb.SwapStatus, isSw
 		Memo:       "",
 	}
 	if isSwapin {
-		err = mongodb.AddSwapinResult(swapResult)
+		err = mongodb.AddSwapinResult(pairID, swapResult)
 	} else {
-		err = mongodb.AddSwapoutResult(swapResult)
+		err = mongodb.AddSwapoutResult(pairID, swapResult)
+	}
+	if err == mongodb.ErrItemIsDup {
+		// verified again after the deposit is reorged
+		return updateReverifiedSwapResult(pairID, swapResult, isSwapin)
 	}
 	if err != nil {
-		logWorkerError("add", "addInitialSwapResult", err, "txid", txid)
+		logWorkerError("add", "addInitialSwapResult", err, "pairID", pairID, "txid", txid)
 	} else {
-		logWorker("add", "addInitialSwapResult", "txid", txid)
+		logWorker("add", "addInitialSwapResult", "pairID", pairID, "txid", txid)
 	}
 	return err
 }
 
-func updateSwapinResult(key string, mtx *MatchTx) error {
-	return updateSwapResult(key, mtx)
+// updateReverifiedSwapResult update deposit height of existing swap result,
+// and keep the swap result status if it's already swapped.
+func updateReverifiedSwapResult(pairID string, mr *mongodb.MgoSwapResult, isSwapin bool) (err error) {
+	txid := mr.TxID
+	var old *mongodb.MgoSwapResult
+	if isSwapin {
+		old, err = mongodb.FindSwapinResult(pairID, txid)
+	} else {
+		old, err = mongodb.FindSwapoutResult(pairID, txid)
+	}
+	if err != nil {
+		return err
+	}
+	updates := &mongodb.SwapResultUpdateItems{
+		TxHeight:  mr.TxHeight,
+		TxTime:    mr.TxTime,
+		Status:    mr.Status,
+		Timestamp: now(),
+	}
+	swapped := old.SwapTx != ""
+	if swapped {
+		updates.Status = old.Status
+	}
+	if isSwapin {
+		err = mongodb.UpdateSwapinResult(pairID, txid, updates)
+	} else {
+		err = mongodb.UpdateSwapoutResult(pairID, txid, updates)
+	}
+	if err != nil {
+		logWorkerError("add", "updateReverifiedSwapResult", err, "pairID", pairID, "txid", txid)
+		return err
+	}
+	logWorker("add", "updateReverifiedSwapResult", "pairID", pairID, "txid", txid, "txheight", mr.TxHeight, "swaptx", old.SwapTx)
+	if !swapped {
+		return nil
+	}
+	if isSwapin {
+		return mongodb.UpdateSwapinStatus(pairID, txid, mongodb.TxProcessed, now(), "")
+	}
+	return mongodb.UpdateSwapoutStatus(pairID, txid, mongodb.TxProcessed, now(), "")
 }
 
-func updateSwapoutResult(key string, mtx *MatchTx) error {
-	return updateSwapResult(key, mtx)
+func updateSwapinResult(pairID, key string, mtx *MatchTx) error {
+	return updateSwapResult(pairID, key, mtx)
 }
 
-func updateSwapResult(key string, mtx *MatchTx) (err error) {
+func updateSwapoutResult(pairID, key string, mtx *MatchTx) error {
+	return updateSwapResult(pairID, key, mtx)
+}
+
+func updateSwapResult(pairID, key string, mtx *MatchTx) (err error) {
 	updates := &mongodb.SwapResultUpdateItems{
 		Status:    mongodb.MatchTxNotStable,
 		Timestamp: now(),
 	}
 	if mtx.SwapTx != "" {
 		updates.SwapTx = mtx.SwapTx
+		updates.OldSwapTxs = mtx.OldSwapTxs
+		updates.SwapNonce = mtx.SwapNonce
 		updates.SwapValue = mtx.SwapValue
 		updates.SwapHeight = 0
 		updates.SwapTime = 0
@@ -88,41 +138,49 @@ func updateSwapResult(key string, mtx *MatchTx) (err error) {
 		updates.SwapType = uint32(mtx.SwapType)
 		fallthrough
 	case tokens.SwapinType:
-		err = mongodb.UpdateSwapinResult(key, updates)
+		err = mongodb.UpdateSwapinResult(pairID, key, updates)
 	case tokens.SwapoutType:
-		err = mongodb.UpdateSwapoutResult(key, updates)
+		err = mongodb.UpdateSwapoutResult(pairID, key, updates)
 	default:
 		err = tokens.ErrUnknownSwapType
 	}
 	if err != nil {
-		logWorkerError("update", "updateSwapResult", err, "txid", key, "swaptx", mtx.SwapTx, "swapheight", mtx.SwapHeight, "swaptime", mtx.SwapTime, "swapvalue", mtx.SwapValue, "swaptype", mtx.SwapType)
+		logWorkerError("update", "updateSwapResult", err, "pairID", pairID, "txid", key, "swaptx", mtx.SwapTx, "swapheight", mtx.SwapHeight, "swaptime", mtx.SwapTime, "swapvalue", mtx.SwapValue, "swaptype", mtx.SwapType)
 	} else {
-		logWorker("update", "updateSwapResult", "txid", key, "swaptx", mtx.SwapTx, "swapheight", mtx.SwapHeight, "swaptime", mtx.SwapTime, "swapvalue", mtx.SwapValue, "swaptype", mtx.SwapType)
+		logWorker("update", "updateSwapResult", "pairID", pairID, "txid", key, "swaptx", mtx.SwapTx, "swapheight", mtx.SwapHeight, "swaptime", mtx.SwapTime, "swapvalue", mtx.SwapValue, "swaptype", mtx.SwapType)
 	}
 	return err
 }
 
-func markSwapinResultStable(key string) error {
-	return markSwapResultStable(key, true)
+func markSwapinResultStable(pairID, key string) error {
+	return markSwapResultStable(pairID, key, true)
 }
 
-func markSwapoutResultStable(key string) error {
-	return markSwapResultStable(key, false)
+func markSwapoutResultStable(pairID, key string) error {
+	return markSwapResultStable(pairID, key, false)
 }
 
-func markSwapResultStable(key string, isSwapin bool) (err error) {
+func markSwapResultStable(pairID, key string, isSwapin bool) (err error) {
 	status := mongodb.MatchTxStable
 	timestamp := now()
 	memo := "" // unchange
 	if isSwapin {
-		err = mongodb.UpdateSwapinResultStatus(key, status, timestamp, memo)
+		err = mongodb.UpdateSwapinResultStatus(pairID, key, status, timestamp, memo)
 	} else {
-		err = mongodb.UpdateSwapoutResultStatus(key, status, timestamp, memo)
+		err = mongodb.UpdateSwapoutResultStatus(pairID, key, status, timestamp, memo)
 	}
 	if err != nil {
-		logWorkerError("stable", "markSwapResultStable", err, "txid", key, "isSwapin", isSwapin)
+		logWorkerError("stable", "markSwapResultStable", err, "pairID", pairID, "txid", key, "isSwapin", isSwapin)
 	} else {
-		logWorker("stable", "markSwapResultStable", "txid", key, "isSwapin", isSwapin)
+		logWorker("stable", "markSwapResultStable", "pairID", pairID, "txid", key, "isSwapin", isSwapin)
 	}
 	return err
 }
+
+// getSwapNonce get nonce of swap tx of account based chain (nil for utxo based chain)
+func getSwapNonce(args *tokens.BuildTxArgs) *uint64 {
+	if args == nil || args.Extra == nil || args.Extra.EthExtra == nil {
+		return nil
+	}
+	return args.Extra.EthExtra.Nonce
+}
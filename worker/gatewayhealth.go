@@ -0,0 +1,43 @@
+package worker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+)
+
+var (
+	gatewayHealthStarter       sync.Once
+	gatewayHealthCheckInterval = 60 * time.Second
+)
+
+// StartGatewayHealthCheckJob check health of gateway endpoints (of bridges with multiple endpoints)
+func StartGatewayHealthCheckJob() {
+	gatewayHealthStarter.Do(func() {
+		logWorker("gatewayhealth", "start gateway health check job")
+		for {
+			for _, pairID := range tokens.GetAllPairIDs() {
+				pair := tokens.GetBridgePair(pairID)
+				checkGatewayHealth(pair.SrcBridge)
+				checkGatewayHealth(pair.DstBridge)
+			}
+			time.Sleep(gatewayHealthCheckInterval)
+		}
+	})
+}
+
+func checkGatewayHealth(bridge tokens.CrossChainBridge) {
+	pinner, ok := bridge.(tokens.EndpointPinner)
+	if !ok {
+		return
+	}
+	_, gateway := bridge.GetTokenAndGateway()
+	endpoints := gateway.GetEndpoints()
+	if len(endpoints.URLs()) < 2 {
+		return
+	}
+	endpoints.CheckHealth(gateway.GetMaxBlockLag(), func(url string) (uint64, error) {
+		return pinner.PinEndpoint(url).GetLatestBlockNumber()
+	})
+}
@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+)
+
+var (
+	nonceStarter sync.Once
+)
+
+// StartNonceJob nonce job (reconcile reserved nonces and detect nonce gaps)
+// bridges of the same account share nonces, so only one job is started for each account.
+func StartNonceJob() {
+	nonceStarter.Do(func() {
+		started := make(map[string]struct{})
+		for _, pairID := range tokens.GetAllPairIDs() {
+			for _, isSrc := range []bool{true, false} {
+				bridge := tokens.GetCrossChainBridge(pairID, isSrc)
+				nonceManager, ok := bridge.(tokens.NonceManager)
+				if !ok {
+					continue
+				}
+				token, _ := bridge.GetTokenAndGateway()
+				account := strings.ToLower(strings.Join([]string{token.BlockChain, token.NetID, token.DcrmAddress}, ":"))
+				if _, exist := started[account]; exist {
+					continue
+				}
+				started[account] = struct{}{}
+				go startNonceJob(nonceManager, pairID, isSrc)
+			}
+		}
+	})
+}
+
+func startNonceJob(nonceManager tokens.NonceManager, pairID string, isSrc bool) {
+	logWorker("nonce", "start nonce job", "pairID", pairID, "isSrc", isSrc)
+	for {
+		err := nonceManager.InitNonces()
+		if err == nil {
+			break
+		}
+		logWorkerError("nonce", "init nonces error", err, "pairID", pairID, "isSrc", isSrc)
+		restInJob(restIntervalInNonceJob)
+	}
+	for {
+		restInJob(restIntervalInNonceJob)
+		nonceManager.CheckNonceGap()
+	}
+}
+
+func releaseNonce(bridge tokens.CrossChainBridge, rawTx interface{}) {
+	if nonceManager, ok := bridge.(tokens.NonceManager); ok {
+		nonceManager.ReleaseNonce(rawTx)
+	}
+}
@@ -0,0 +1,216 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/fsn-dev/crossChain-Bridge/internal/journal"
+	"github.com/fsn-dev/crossChain-Bridge/params"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+)
+
+var (
+	hourSeconds = int64(3600)
+	daySeconds  = int64(24 * 3600)
+
+	// outflows agreed by this oracle in the rolling day (rebuilt from accept journal after restart)
+	agreedOutflows = newOutflowHistory()
+
+	errPolicyViolated = errors.New("accept policy violated")
+)
+
+// swapOutflow value paid out by a swap
+type swapOutflow struct {
+	pairID    string
+	swapID    string
+	swapType  tokens.SwapType
+	from      string
+	bind      string   // receiver
+	value     *big.Int // deposit value
+	spendKeys []string // nonce or utxos spent by the swap tx
+	timestamp int64
+}
+
+func newSwapOutflow(pairID string, args *tokens.BuildTxArgs, swap *tokens.TxSwapInfo, to string) *swapOutflow {
+	if swap == nil || swap.Value == nil {
+		return nil
+	}
+	return &swapOutflow{
+		pairID:   pairID,
+		swapID:   args.SwapID,
+		swapType: args.SwapType,
+		from:     swap.From,
+		bind:     to,
+		value:    swap.Value,
+	}
+}
+
+func newSwapOutflowFromJournal(entry *journal.Entry) *swapOutflow {
+	if entry.Value == "" {
+		return nil
+	}
+	value, ok := new(big.Int).SetString(entry.Value, 10)
+	if !ok {
+		return nil
+	}
+	return &swapOutflow{
+		pairID:    entry.PairID,
+		swapID:    entry.SwapID,
+		swapType:  tokens.SwapType(entry.SwapType),
+		from:      entry.From,
+		bind:      entry.Bind,
+		value:     value,
+		timestamp: entry.Timestamp,
+	}
+}
+
+func (o *swapOutflow) key() string {
+	return strings.ToLower(fmt.Sprintf("%v:%v:%v", o.pairID, o.swapID, o.swapType))
+}
+
+// isSrc is outflow from source chain (swapout and recall), otherwise to dest chain (swapin)
+func (o *swapOutflow) isSrc() bool {
+	return o.swapType != tokens.SwapinType
+}
+
+// isDepositSrc is the deposit in source chain (swapin and recall), the value is in bits of its token
+func (o *swapOutflow) isDepositSrc() bool {
+	return o.swapType != tokens.SwapoutType
+}
+
+func (o *swapOutflow) toBits(value float64) *big.Int {
+	token := tokens.GetTokenConfig(o.pairID, o.isDepositSrc())
+	if token == nil || token.Decimals == nil {
+		return nil
+	}
+	return tokens.ToBits(value, *token.Decimals)
+}
+
+type outflowHistory struct {
+	mu       sync.Mutex
+	records  []*swapOutflow // in time order
+	recorded map[string]struct{}
+}
+
+func newOutflowHistory() *outflowHistory {
+	return &outflowHistory{recorded: make(map[string]struct{})}
+}
+
+// prune drop records older than a day
+func (h *outflowHistory) prune() {
+	sepTime := now() - daySeconds
+	i := 0
+	for ; i < len(h.records) && h.records[i].timestamp < sepTime; i++ {
+		delete(h.recorded, h.records[i].key())
+	}
+	h.records = h.records[i:]
+}
+
+func (h *outflowHistory) add(outflow *swapOutflow) {
+	if outflow == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	key := outflow.key()
+	if _, exist := h.recorded[key]; exist {
+		return
+	}
+	if outflow.timestamp == 0 {
+		outflow.timestamp = now()
+	} else if outflow.timestamp < now()-daySeconds {
+		return // out of the rolling day
+	}
+	h.records = append(h.records, outflow)
+	h.recorded[key] = struct{}{}
+	h.prune()
+}
+
+// check check rolling outflow caps and velocity of bind address
+func (h *outflowHistory) check(outflow *swapOutflow, policy *params.OraclePolicyConfig) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.prune()
+	if _, exist := h.recorded[outflow.key()]; exist {
+		return nil // sign the same swap again (eg. replace swap tx), the outflow is counted already
+	}
+	hourSepTime := now() - hourSeconds
+	var (
+		hourlyValue = new(big.Int).Set(outflow.value)
+		dailyValue  = new(big.Int).Set(outflow.value)
+		hourlySwaps = uint64(1)
+		dailySwaps  = uint64(1)
+	)
+	for _, record := range h.records {
+		if record.pairID == outflow.pairID && record.isSrc() == outflow.isSrc() {
+			dailyValue.Add(dailyValue, record.value)
+			if record.timestamp >= hourSepTime {
+				hourlyValue.Add(hourlyValue, record.value)
+			}
+		}
+		if strings.EqualFold(record.bind, outflow.bind) {
+			dailySwaps++
+			if record.timestamp >= hourSepTime {
+				hourlySwaps++
+			}
+		}
+	}
+	if limit := policy.MaxSwapsPerBindHourly; limit > 0 && hourlySwaps > limit {
+		return fmt.Errorf("%w: bind address %v has %v swaps in an hour, exceed %v", errPolicyViolated, outflow.bind, hourlySwaps, limit)
+	}
+	if limit := policy.MaxSwapsPerBindDaily; limit > 0 && dailySwaps > limit {
+		return fmt.Errorf("%w: bind address %v has %v swaps in a day, exceed %v", errPolicyViolated, outflow.bind, dailySwaps, limit)
+	}
+	pairPolicy := policy.GetPairPolicy(outflow.pairID, outflow.isSrc())
+	if pairPolicy == nil {
+		return nil
+	}
+	if pairPolicy.HourlyCap > 0 {
+		if hourlyCap := outflow.toBits(pairPolicy.HourlyCap); hourlyCap != nil && hourlyValue.Cmp(hourlyCap) > 0 {
+			return fmt.Errorf("%w: hourly outflow %v exceed cap %v (isSrc=%v)", errPolicyViolated, hourlyValue, hourlyCap, outflow.isSrc())
+		}
+	}
+	if pairPolicy.DailyCap > 0 {
+		if dailyCap := outflow.toBits(pairPolicy.DailyCap); dailyCap != nil && dailyValue.Cmp(dailyCap) > 0 {
+			return fmt.Errorf("%w: daily outflow %v exceed cap %v (isSrc=%v)", errPolicyViolated, dailyValue, dailyCap, outflow.isSrc())
+		}
+	}
+	return nil
+}
+
+func isInAddressList(list []string, address string) bool {
+	if address == "" {
+		return false
+	}
+	for _, item := range list {
+		if strings.EqualFold(item, address) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAcceptPolicy check verified swap against oracle policy before agreeing to sign it
+func checkAcceptPolicy(outflow *swapOutflow) error {
+	policy := params.GetOraclePolicy()
+	if policy == nil || outflow == nil {
+		return nil
+	}
+	for _, address := range []string{outflow.from, outflow.bind} {
+		if isInAddressList(policy.DenyList, address) {
+			return fmt.Errorf("%w: address %v is in deny list", errPolicyViolated, address)
+		}
+	}
+	if len(policy.AllowList) > 0 && !isInAddressList(policy.AllowList, outflow.bind) {
+		return fmt.Errorf("%w: bind address %v is not in allow list", errPolicyViolated, outflow.bind)
+	}
+	if pairPolicy := policy.GetPairPolicy(outflow.pairID, outflow.isSrc()); pairPolicy != nil && pairPolicy.MaxSwapValue > 0 {
+		if maxValue := outflow.toBits(pairPolicy.MaxSwapValue); maxValue != nil && outflow.value.Cmp(maxValue) > 0 {
+			return fmt.Errorf("%w: swap value %v exceed max value %v", errPolicyViolated, outflow.value, maxValue)
+		}
+	}
+	return agreedOutflows.check(outflow, policy)
+}
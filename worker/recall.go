@@ -3,7 +3,6 @@ package worker
 import (
 	"fmt"
 	"sync"
-	"time"
 
 	"github.com/fsn-dev/crossChain-Bridge/common"
 	"github.com/fsn-dev/crossChain-Bridge/mongodb"
@@ -11,51 +10,59 @@ import (
 )
 
 var (
-	swapinRecallStarter sync.Once
+	recallStarter sync.Once
 )
 
 // StartRecallJob recall job
 func StartRecallJob() {
-	go startSwapinRecallJob()
+	recallStarter.Do(func() {
+		startSignManager()
+		for _, pairID := range tokens.GetAllPairIDs() {
+			go startSwapinRecallJob(pairID)
+		}
+	})
 }
 
-func startSwapinRecallJob() {
-	swapinRecallStarter.Do(func() {
-		logWorker("recall", "start swapin recall job")
-		for {
-			res, err := findSwapinsToRecall()
+func startSwapinRecallJob(pairID string) {
+	logWorker("recall", "start swapin recall job", "pairID", pairID)
+	processSignIntents(pairID, tokens.SwapRecallType, processRecallSwapin)
+	for {
+		res, err := findSwapinsToRecall(pairID)
+		if err != nil {
+			logWorkerError("recall", "find recalls error", err, "pairID", pairID)
+		}
+		if len(res) > 0 {
+			logWorker("recall", "find recalls to recall", "pairID", pairID, "count", len(res))
+		}
+		for _, swap := range res {
+			err = processRecallSwapin(pairID, swap)
 			if err != nil {
-				logWorkerError("recall", "find recalls error", err)
+				logWorkerError("recall", "process recall error", err, "pairID", pairID, "txid", swap.TxID)
 			}
-			if len(res) > 0 {
-				logWorker("recall", "find recalls to recall", "count", len(res))
-			}
-			for _, swap := range res {
-				err = processRecallSwapin(swap)
-				if err != nil {
-					logWorkerError("recall", "process recall error", err, "txid", swap.TxID)
-				}
-			}
-			restInJob(restIntervalInRecallJob)
 		}
-	})
+		restInJob(restIntervalInRecallJob)
+	}
 }
 
-func findSwapinsToRecall() ([]*mongodb.MgoSwap, error) {
+func findSwapinsToRecall(pairID string) ([]*mongodb.MgoSwap, error) {
 	status := mongodb.TxToBeRecall
 	septime := getSepTimeInFind(maxRecallLifetime)
-	return mongodb.FindSwapinsWithStatus(status, septime)
+	return mongodb.FindSwapinsWithStatus(pairID, status, septime)
 }
 
-func processRecallSwapin(swap *mongodb.MgoSwap) (err error) {
+func processRecallSwapin(pairID string, swap *mongodb.MgoSwap) (err error) {
 	txid := swap.TxID
-	res, err := mongodb.FindSwapinResult(txid)
+	if signMgr.isSigning(pairID, tokens.SwapRecallType, txid) {
+		logWorkerTrace("recall", "ignore recall being signed", "pairID", pairID, "txid", txid)
+		return nil
+	}
+	res, err := mongodb.FindSwapinResult(pairID, txid)
 	if err != nil {
 		return err
 	}
 	if res.SwapTx != "" {
-		if res.Status == mongodb.TxToBeRecall {
-			_ = mongodb.UpdateSwapinStatus(txid, mongodb.TxProcessed, now(), "")
+		if swap.Status == mongodb.TxToBeRecall {
+			_ = mongodb.UpdateSwapinStatus(pairID, txid, mongodb.TxProcessed, now(), "")
 		}
 		return fmt.Errorf("%v already swapped to %v", txid, res.SwapTx)
 	}
@@ -65,57 +72,52 @@ func processRecallSwapin(swap *mongodb.MgoSwap) (err error) {
 		return fmt.Errorf("wrong value %v", res.Value)
 	}
 
+	recallValue := tokens.CalcRecallValue(pairID, value)
+	if recallValue.Sign() <= 0 {
+		err = tokens.ErrRecallValueTooSmall
+		_ = mongodb.UpdateSwapinStatus(pairID, txid, mongodb.TxRecallFailed, now(), err.Error())
+		return err
+	}
+
 	args := &tokens.BuildTxArgs{
 		SwapInfo: tokens.SwapInfo{
 			SwapID:   res.TxID,
 			SwapType: tokens.SwapRecallType,
 			TxType:   tokens.SwapTxType(swap.TxType),
 			Bind:     swap.Bind,
+			PairID:   pairID,
 		},
-		To:    res.Bind,
+		To:    tokens.GetRecallAddress(pairID, res.From, res.Bind),
 		Value: value,
 		Memo:  fmt.Sprintf("%s%s", tokens.RecallMemoPrefix, res.TxID),
 	}
-	bridge := tokens.SrcBridge
-	rawTx, err := bridge.BuildRawTransaction(args)
+	bridge := tokens.GetCrossChainBridge(pairID, true)
+	intent, err := reconcileSignIntent(pairID, bridge, args, recallValue.String())
 	if err != nil {
-		logWorkerError("recall", "BuildRawTransaction failed", err, "txid", txid)
 		return err
 	}
-
-	signedTx, txHash, err := bridge.DcrmSignTransaction(rawTx, args.GetExtraArgs())
+	rawTx, err := bridge.BuildRawTransaction(args)
 	if err != nil {
-		logWorkerError("recall", "DcrmSignTransaction failed", err, "txid", txid)
+		logWorkerError("recall", "BuildRawTransaction failed", err, "pairID", pairID, "txid", txid)
 		return err
 	}
 
-	// update database before sending transaction
-	matchTx := &MatchTx{
-		SwapTx:    txHash,
-		SwapValue: tokens.CalcSwappedValue(value, false).String(),
-		SwapType:  tokens.SwapRecallType,
-	}
-	err = updateSwapinResult(txid, matchTx)
-	if err != nil {
-		return err
-	}
-	err = mongodb.UpdateSwapinStatus(txid, mongodb.TxProcessed, now(), "")
+	err = intent.save(bridge, args)
 	if err != nil {
+		logWorkerError("recall", "save sign intent failed", err, "pairID", pairID, "txid", txid)
+		intent.abort(bridge, rawTx)
 		return err
 	}
 
-	for i := 0; i < retrySendTxCount; i++ {
-		if _, err = bridge.SendTransaction(signedTx); err == nil {
-			if tx, _ := bridge.GetTransaction(txHash); tx != nil {
-				break
-			}
-		}
-		time.Sleep(retrySendTxInterval)
-	}
-	if err != nil {
-		_ = mongodb.UpdateSwapinStatus(txid, mongodb.TxRecallFailed, now(), err.Error())
-		_ = mongodb.UpdateSwapinResultStatus(txid, mongodb.TxRecallFailed, now(), err.Error())
-		return err
-	}
-	return nil
+	return signAndSendSwapTx(&swapTxTask{
+		pairID:    pairID,
+		txid:      txid,
+		swapType:  tokens.SwapRecallType,
+		value:     value,
+		swapValue: recallValue.String(),
+		bridge:    bridge,
+		rawTx:     rawTx,
+		args:      args,
+		intent:    intent,
+	})
 }
@@ -0,0 +1,268 @@
+package worker
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/fsn-dev/crossChain-Bridge/mongodb"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+)
+
+var (
+	reorgStarter sync.Once
+
+	// swap results may carry heights of reorged blocks
+	reorgAffectedStatuses = []mongodb.SwapStatus{
+		mongodb.MatchTxEmpty,
+		mongodb.MatchTxNotStable,
+		mongodb.MatchTxStable,
+		mongodb.TxWithWrongMemo,
+		mongodb.TxWithWrongValue,
+		mongodb.TxSwapFailed,
+	}
+)
+
+// StartReorgJob reorg job (detect chain reorg and roll back affected swaps)
+func StartReorgJob() {
+	reorgStarter.Do(func() {
+		for _, pairID := range tokens.GetAllPairIDs() {
+			go startReorgCheckJob(pairID, true)
+			go startReorgCheckJob(pairID, false)
+		}
+	})
+}
+
+func startReorgCheckJob(pairID string, isSrc bool) {
+	bridge := tokens.GetCrossChainBridge(pairID, isSrc)
+	getter, ok := bridge.(tokens.BlockHashGetter)
+	if !ok {
+		logWorker("reorg", "bridge does not support reorg check", "pairID", pairID, "isSrc", isSrc)
+		return
+	}
+	logWorker("reorg", "start reorg check job", "pairID", pairID, "isSrc", isSrc)
+	for {
+		err := checkReorg(pairID, isSrc, bridge, getter)
+		if err != nil {
+			logWorkerError("reorg", "check reorg error", err, "pairID", pairID, "isSrc", isSrc)
+		}
+		restInJob(restIntervalInReorgJob)
+	}
+}
+
+// getReorgTrackedBlocks blocks deeper than confirmations should be tracked to roll back stable swaps
+func getReorgTrackedBlocks(confirmations uint64) uint64 {
+	if tracked := 2 * confirmations; tracked > minReorgTrackedBlocks {
+		return tracked
+	}
+	return minReorgTrackedBlocks
+}
+
+// checkReorg compare stored hashes of recent blocks with the chain, and track new blocks
+func checkReorg(pairID string, isSrc bool, bridge tokens.CrossChainBridge, getter tokens.BlockHashGetter) error {
+	latest, err := bridge.GetLatestBlockNumber()
+	if err != nil {
+		return err
+	}
+	info, err := mongodb.FindBlockHashes(pairID, isSrc)
+	if err != nil {
+		return err
+	}
+	hashes := info.Hashes
+
+	forkIndex, newHash, err := findReorgForkIndex(getter, hashes, latest)
+	if err != nil {
+		return err
+	}
+	if forkIndex < len(hashes) {
+		err = processReorg(pairID, isSrc, bridge, hashes[forkIndex:], newHash)
+		if err != nil {
+			return err
+		}
+		hashes = hashes[:forkIndex]
+	}
+
+	token, _ := bridge.GetTokenAndGateway()
+	tracked := getReorgTrackedBlocks(*token.Confirmations)
+	var start uint64
+	if latest >= tracked {
+		start = latest - tracked + 1
+	}
+	if len(hashes) > 0 && hashes[len(hashes)-1].Height >= start {
+		start = hashes[len(hashes)-1].Height + 1
+	}
+	appended := 0
+	for height := start; height <= latest; height++ {
+		hash, errh := getter.GetBlockHash(height)
+		if errh != nil {
+			break // track the rest next time
+		}
+		hashes = append(hashes, mongodb.MgoBlockHash{Height: height, Hash: hash})
+		appended++
+	}
+	if forkIndex == len(info.Hashes) && appended == 0 {
+		return nil
+	}
+	if uint64(len(hashes)) > tracked {
+		hashes = hashes[uint64(len(hashes))-tracked:]
+	}
+	return mongodb.UpdateBlockHashes(pairID, isSrc, hashes)
+}
+
+// findReorgForkIndex find index of the lowest replaced block (len(hashes) if no reorg).
+// stored blocks above latest are skipped as the gateway may lag behind.
+// as block hash commits its parent, stop checking when a matched block is found.
+func findReorgForkIndex(getter tokens.BlockHashGetter, hashes []mongodb.MgoBlockHash, latest uint64) (forkIndex int, newHash string, err error) {
+	forkIndex = len(hashes)
+	for i := len(hashes) - 1; i >= 0; i-- {
+		item := hashes[i]
+		if item.Height > latest {
+			continue
+		}
+		hash, errh := getter.GetBlockHash(item.Height)
+		if errh != nil {
+			return 0, "", errh
+		}
+		if hash == item.Hash {
+			break
+		}
+		forkIndex = i
+		newHash = hash
+	}
+	return forkIndex, newHash, nil
+}
+
+// processReorg roll back swaps affected by the replaced blocks, and add reorg event
+func processReorg(pairID string, isSrc bool, bridge tokens.CrossChainBridge, reorged []mongodb.MgoBlockHash, newHash string) error {
+	fork := reorged[0]
+	logWorker("reorg", "chain reorg detected", "pairID", pairID, "isSrc", isSrc, "forkHeight", fork.Height, "depth", len(reorged), "oldHash", fork.Hash, "newHash", newHash)
+	side := "dst"
+	if isSrc {
+		side = "src"
+	}
+	event := &mongodb.MgoReorgEvent{
+		Key:        fmt.Sprintf("%v:%v:%v", side, fork.Height, fork.Hash),
+		IsSrc:      isSrc,
+		ForkHeight: fork.Height,
+		Depth:      uint64(len(reorged)),
+		OldHash:    fork.Hash,
+		NewHash:    newHash,
+	}
+	for _, isSwapin := range []bool{true, false} {
+		err := rollbackReorgedSwaps(pairID, isSrc, isSwapin, bridge, fork.Height, event)
+		if err != nil {
+			return err
+		}
+	}
+	event.Timestamp = now()
+	err := mongodb.AddReorgEvent(pairID, event)
+	if err == mongodb.ErrItemIsDup {
+		return nil // already processed before
+	}
+	return err
+}
+
+func rollbackReorgedSwaps(pairID string, isSrc, isSwapin bool, bridge tokens.CrossChainBridge, forkHeight uint64, event *mongodb.MgoReorgEvent) error {
+	// deposits of swapin are on source chain, and of swapout on dest chain
+	isDepositChain := isSwapin == isSrc
+	septime := getSepTimeInFind(maxReorgLifetime)
+	for _, status := range reorgAffectedStatuses {
+		var (
+			res []*mongodb.MgoSwapResult
+			err error
+		)
+		if isSwapin {
+			res, err = mongodb.FindSwapinResultsWithStatus(pairID, status, septime)
+		} else {
+			res, err = mongodb.FindSwapoutResultsWithStatus(pairID, status, septime)
+		}
+		if err != nil {
+			return err
+		}
+		for _, swap := range res {
+			affected := false
+			if isDepositChain && swap.TxHeight >= forkHeight {
+				if err = rollbackReorgedDeposit(pairID, swap, isSwapin, forkHeight); err != nil {
+					return err
+				}
+				affected = true
+			}
+			// swap tx of swapin is on dest chain, and of swapout (and recall) on source chain
+			isSwapTxChain := (tokens.SwapType(swap.SwapType) != tokens.SwapinType) == isSrc
+			if isSwapTxChain && swap.SwapTx != "" && swap.SwapHeight != 0 && swap.SwapHeight >= forkHeight {
+				missing, errr := rollbackReorgedSwapTx(pairID, swap, isSwapin, bridge)
+				if errr != nil {
+					return errr
+				}
+				if missing {
+					event.MissingTxs = append(event.MissingTxs, swap.SwapTx)
+				}
+				affected = true
+			}
+			if !affected {
+				continue
+			}
+			if isSwapin {
+				event.Swapins = append(event.Swapins, swap.TxID)
+			} else {
+				event.Swapouts = append(event.Swapouts, swap.TxID)
+			}
+		}
+	}
+	return nil
+}
+
+// rollbackReorgedDeposit verify deposit again (the swap result is kept)
+func rollbackReorgedDeposit(pairID string, swap *mongodb.MgoSwapResult, isSwapin bool, forkHeight uint64) error {
+	logWorker("reorg", "roll back reorged deposit", "pairID", pairID, "txid", swap.TxID, "txheight", swap.TxHeight, "swaptx", swap.SwapTx, "isSwapin", isSwapin)
+	memo := fmt.Sprintf("deposit is reorged at height %v", forkHeight)
+	if isSwapin {
+		return mongodb.UpdateSwapinStatus(pairID, swap.TxID, mongodb.TxNotStable, now(), memo)
+	}
+	return mongodb.UpdateSwapoutStatus(pairID, swap.TxID, mongodb.TxNotStable, now(), memo)
+}
+
+// rollbackReorgedSwapTx update swap height if swap tx is mined again,
+// otherwise reset swap height (then it can be replaced), and rebroadcast it if it's missing.
+func rollbackReorgedSwapTx(pairID string, swap *mongodb.MgoSwapResult, isSwapin bool, bridge tokens.CrossChainBridge) (missing bool, err error) {
+	swapType := tokens.SwapType(swap.SwapType)
+	logWorker("reorg", "roll back reorged swap tx", "pairID", pairID, "txid", swap.TxID, "swaptx", swap.SwapTx, "swapheight", swap.SwapHeight, "status", swap.Status)
+	var matchTx *MatchTx
+	if txStatus := bridge.GetTransactionStatus(swap.SwapTx); txStatus != nil && txStatus.BlockHeight != 0 {
+		matchTx = &MatchTx{
+			SwapHeight: txStatus.BlockHeight,
+			SwapTime:   txStatus.BlockTime,
+			SwapType:   swapType,
+		}
+	} else {
+		matchTx = &MatchTx{
+			SwapTx:    swap.SwapTx,
+			SwapValue: swap.SwapValue,
+			SwapType:  swapType,
+		}
+		if _, errt := bridge.GetTransaction(swap.SwapTx); errt != nil {
+			missing = !rebroadcastSwapTx(pairID, swap, isSwapin, bridge)
+		}
+	}
+	if err = updateSwapResult(pairID, swap.Key, matchTx); err != nil {
+		return missing, err
+	}
+	if swap.Status == mongodb.MatchTxStable && swapType != tokens.SwapRecallType {
+		_ = mongodb.RevertSwapStatistics(pairID, swap.Value, swap.SwapValue, isSwapin)
+	}
+	return missing, nil
+}
+
+// rebroadcastSwapTx send swap tx again if it's signed by this server (and still in swap history)
+func rebroadcastSwapTx(pairID string, swap *mongodb.MgoSwapResult, isSwapin bool, bridge tokens.CrossChainBridge) bool {
+	history := getSwapHistory(pairID, swap.TxID, isSwapin)
+	if history == nil || history.matchTx != swap.SwapTx || history.signedTx == nil {
+		logWorker("reorg", "reorged swap tx is missing", "pairID", pairID, "txid", swap.TxID, "swaptx", swap.SwapTx)
+		return false
+	}
+	if _, err := bridge.SendTransaction(history.signedTx); err != nil {
+		logWorkerError("reorg", "rebroadcast reorged swap tx failed", err, "pairID", pairID, "txid", swap.TxID, "swaptx", swap.SwapTx)
+		return false
+	}
+	logWorker("reorg", "rebroadcast reorged swap tx success", "pairID", pairID, "txid", swap.TxID, "swaptx", swap.SwapTx)
+	return true
+}
@@ -0,0 +1,353 @@
+package worker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fsn-dev/crossChain-Bridge/common"
+	"github.com/fsn-dev/crossChain-Bridge/mongodb"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+)
+
+var (
+	replaceStarter sync.Once
+)
+
+// StartReplaceJob replace job (bump fee of stuck swap txs)
+func StartReplaceJob() {
+	replaceStarter.Do(func() {
+		startSignManager()
+		for _, pairID := range tokens.GetAllPairIDs() {
+			go startSwapinReplaceJob(pairID)
+			go startSwapoutReplaceJob(pairID)
+		}
+	})
+}
+
+func startSwapinReplaceJob(pairID string) {
+	logWorker("replace", "start swapin replace job", "pairID", pairID)
+	for {
+		res, err := findSwapinResultsToReplace(pairID)
+		if err != nil {
+			logWorkerError("replace", "find swapin results error", err, "pairID", pairID)
+		}
+		for _, swap := range res {
+			err = processSwapinReplace(pairID, swap)
+			if err != nil {
+				logWorkerError("replace", "process swapin replace error", err, "pairID", pairID, "txid", swap.TxID)
+			}
+		}
+		restInJob(restIntervalInReplaceJob)
+	}
+}
+
+func startSwapoutReplaceJob(pairID string) {
+	logWorker("replace", "start swapout replace job", "pairID", pairID)
+	for {
+		res, err := findSwapoutResultsToReplace(pairID)
+		if err != nil {
+			logWorkerError("replace", "find swapout results error", err, "pairID", pairID)
+		}
+		for _, swap := range res {
+			err = processSwapoutReplace(pairID, swap)
+			if err != nil {
+				logWorkerError("replace", "process swapout replace error", err, "pairID", pairID, "txid", swap.TxID)
+			}
+		}
+		restInJob(restIntervalInReplaceJob)
+	}
+}
+
+func findSwapinResultsToReplace(pairID string) ([]*mongodb.MgoSwapResult, error) {
+	status := mongodb.MatchTxNotStable
+	septime := getSepTimeInFind(maxReplaceLifetime)
+	return mongodb.FindSwapinResultsWithStatus(pairID, status, septime)
+}
+
+func findSwapoutResultsToReplace(pairID string) ([]*mongodb.MgoSwapResult, error) {
+	status := mongodb.MatchTxNotStable
+	septime := getSepTimeInFind(maxReplaceLifetime)
+	return mongodb.FindSwapoutResultsWithStatus(pairID, status, septime)
+}
+
+func processSwapinReplace(pairID string, res *mongodb.MgoSwapResult) error {
+	return processReplaceSwap(pairID, res, true)
+}
+
+func processSwapoutReplace(pairID string, res *mongodb.MgoSwapResult) error {
+	return processReplaceSwap(pairID, res, false)
+}
+
+func getSwapTxBridge(pairID string, swapType tokens.SwapType) tokens.CrossChainBridge {
+	if swapType == tokens.SwapinType {
+		return tokens.GetCrossChainBridge(pairID, false)
+	}
+	return tokens.GetCrossChainBridge(pairID, true)
+}
+
+func processReplaceSwap(pairID string, res *mongodb.MgoSwapResult, isSwapin bool) (err error) {
+	if res.SwapTx == "" || res.SwapHeight != 0 {
+		return nil
+	}
+	swapType := tokens.SwapType(res.SwapType)
+	bridge := getSwapTxBridge(pairID, swapType)
+	replacer, ok := bridge.(tokens.TxReplacer)
+	if !ok {
+		return nil
+	}
+	pendingAge, maxReplaceCount := replacer.GetReplaceConfig()
+	if pendingAge < 0 || now()-res.Timestamp < pendingAge {
+		return nil
+	}
+	if signMgr.isSigning(pairID, swapType, res.TxID) {
+		logWorkerTrace("replace", "ignore swap being signed", "pairID", pairID, "txid", res.TxID)
+		return nil
+	}
+	if len(res.OldSwapTxs) >= maxReplaceCount {
+		logWorkerTrace("replace", "swap tx is replaced too many times", "pairID", pairID, "txid", res.TxID, "swaptx", res.SwapTx, "count", len(res.OldSwapTxs))
+		return nil
+	}
+
+	txid := res.TxID
+	var extra *tokens.AllExtras
+	if _, errt := bridge.GetTransaction(res.SwapTx); errt != nil {
+		// swap tx is not sent successfully or dropped from tx pool, its nonce is kept reserved,
+		// send it again to fill the nonce, or replace it with the same nonce if it's missing
+		if rebroadcastSwapTx(pairID, res, isSwapin, bridge) {
+			return nil
+		}
+		if res.SwapNonce == nil {
+			return errt
+		}
+		nonce := *res.SwapNonce
+		extra = &tokens.AllExtras{EthExtra: &tokens.EthExtraArgs{Nonce: &nonce}}
+		logWorker("replace", "start replace missing swap tx", "pairID", pairID, "txid", txid, "swaptx", res.SwapTx, "swaptype", swapType, "nonce", nonce)
+	} else {
+		logWorker("replace", "start replace stuck swap tx", "pairID", pairID, "txid", txid, "swaptx", res.SwapTx, "swaptype", swapType)
+		extra, err = replacer.GetReplaceTxExtra(res.SwapTx)
+		switch err {
+		case nil:
+		case tokens.ErrTxNotReplaceable, tokens.ErrRelayFeeExceedLimit:
+			return accelerateSwapTx(pairID, res, bridge, isSwapin, err)
+		default:
+			return err
+		}
+	}
+
+	value, err := common.GetBigIntFromStr(res.Value)
+	if err != nil {
+		return fmt.Errorf("wrong value %v", res.Value)
+	}
+
+	args := &tokens.BuildTxArgs{
+		SwapInfo: tokens.SwapInfo{
+			SwapID:   txid,
+			SwapType: swapType,
+			PairID:   pairID,
+		},
+		To:    res.Bind,
+		Value: value,
+		Extra: extra,
+	}
+	switch swapType {
+	case tokens.SwapinType, tokens.SwapRecallType:
+		swap, errf := mongodb.FindSwapin(pairID, txid)
+		if errf != nil {
+			return errf
+		}
+		args.TxType = tokens.SwapTxType(swap.TxType)
+		args.Bind = swap.Bind
+		if swapType == tokens.SwapRecallType {
+			args.To = tokens.GetRecallAddress(pairID, res.From, res.Bind)
+			args.Memo = fmt.Sprintf("%s%s", tokens.RecallMemoPrefix, txid)
+		} else {
+			args.Memo = fmt.Sprintf("%s%s", tokens.UnlockMemoPrefix, txid)
+		}
+	case tokens.SwapoutType:
+		args.Memo = fmt.Sprintf("%s%s", tokens.UnlockMemoPrefix, txid)
+	default:
+		return tokens.ErrUnknownSwapType
+	}
+
+	rawTx, err := bridge.BuildRawTransaction(args)
+	if err != nil {
+		logWorkerError("replace", "BuildRawTransaction failed", err, "pairID", pairID, "txid", txid)
+		return accelerateSwapTx(pairID, res, bridge, isSwapin, err)
+	}
+
+	intent := newReplaceSignIntent(pairID, args)
+	err = intent.save(bridge, args)
+	if err != nil {
+		logWorkerError("replace", "save sign intent failed", err, "pairID", pairID, "txid", txid)
+		return err
+	}
+
+	return signAndSendSwapTx(&swapTxTask{
+		pairID:    pairID,
+		txid:      txid,
+		swapType:  swapType,
+		value:     value,
+		swapValue: res.SwapValue,
+		bridge:    bridge,
+		rawTx:     rawTx,
+		args:      args,
+		intent:    intent,
+		replaced:  res,
+	})
+}
+
+// onReplaceTxSigned record signed replacement in swap result (with the replaced one in history), then send it
+func onReplaceTxSigned(t *swapTxTask, signedTx interface{}, txHash string) (err error) {
+	pairID, txid, bridge, res := t.pairID, t.txid, t.bridge, t.replaced
+	isSwapin := t.swapType != tokens.SwapoutType
+
+	// update database before sending transaction
+	addSwapHistory(pairID, txid, t.value, txHash, signedTx, isSwapin)
+	oldSwapTxs := make([]string, 0, len(res.OldSwapTxs)+1)
+	oldSwapTxs = append(oldSwapTxs, res.OldSwapTxs...)
+	oldSwapTxs = append(oldSwapTxs, res.SwapTx)
+	matchTx := &MatchTx{
+		SwapTx:     txHash,
+		OldSwapTxs: oldSwapTxs,
+		SwapNonce:  getSwapNonce(t.args),
+		SwapValue:  res.SwapValue,
+		SwapType:   t.swapType,
+	}
+	err = updateSwapResult(pairID, txid, matchTx)
+	t.intent.remove()
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < retrySendTxCount; i++ {
+		if _, err = bridge.SendTransaction(signedTx); err == nil {
+			if tx, _ := bridge.GetTransaction(txHash); tx != nil {
+				break
+			}
+		}
+		time.Sleep(retrySendTxInterval)
+	}
+	if err != nil {
+		// keep the pending tx as swap tx, and the replacement in history (in case it is broadcasted)
+		logWorkerError("replace", "send replace tx failed", err, "pairID", pairID, "txid", txid, "swaptx", txHash)
+		oldSwapTxs = append(oldSwapTxs[:len(oldSwapTxs)-1], txHash)
+		matchTx = &MatchTx{
+			SwapTx:     res.SwapTx,
+			OldSwapTxs: oldSwapTxs,
+			SwapValue:  res.SwapValue,
+			SwapType:   t.swapType,
+		}
+		_ = updateSwapResult(pairID, txid, matchTx)
+		return err
+	}
+	logWorker("replace", "replace stuck swap tx success", "pairID", pairID, "txid", txid, "swaptx", txHash, "replaced", res.SwapTx)
+	return nil
+}
+
+// accelerateSwapTx accelerate stuck swap tx by child-pays-for-parent if it can not be replaced
+func accelerateSwapTx(pairID string, res *mongodb.MgoSwapResult, bridge tokens.CrossChainBridge, isSwapin bool, replaceErr error) (err error) {
+	accelerator, ok := bridge.(tokens.TxAccelerator)
+	if !ok {
+		return replaceErr
+	}
+	txid := res.TxID
+	swapType := tokens.SwapType(res.SwapType)
+	logWorker("replace", "accelerate stuck swap tx", "pairID", pairID, "txid", txid, "swaptx", res.SwapTx, "replaceErr", replaceErr)
+	args := &tokens.BuildTxArgs{
+		SwapInfo: tokens.SwapInfo{
+			SwapID: txid,
+			PairID: pairID,
+		},
+	}
+	rawTx, err := accelerator.BuildAccelerateTransaction(args, res.SwapTx)
+	if err != nil {
+		logWorkerError("replace", "build accelerate tx failed", err, "pairID", pairID, "txid", txid, "swaptx", res.SwapTx)
+		return err
+	}
+
+	onSigned := func(signedTx interface{}, childTx string) error {
+		_, err := bridge.SendTransaction(signedTx)
+		if err != nil {
+			logWorkerError("replace", "send accelerate tx failed", err, "pairID", pairID, "txid", txid, "swaptx", res.SwapTx, "childTx", childTx)
+			unlockUtxos(bridge, rawTx, txid)
+			return err
+		}
+		logWorker("replace", "accelerate stuck swap tx success", "pairID", pairID, "txid", txid, "swaptx", res.SwapTx, "childTx", childTx)
+		// update timestamp to wait another pending age
+		if isSwapin {
+			return mongodb.UpdateSwapinResultStatus(pairID, txid, mongodb.MatchTxNotStable, now(), "")
+		}
+		return mongodb.UpdateSwapoutResultStatus(pairID, txid, mongodb.MatchTxNotStable, now(), "")
+	}
+
+	signArgs := args.GetExtraArgs()
+	requester, ok := bridge.(tokens.DcrmSignRequester)
+	if !ok {
+		signedTx, childTx, errs := bridge.DcrmSignTransaction(rawTx, signArgs)
+		if errs != nil {
+			logWorkerError("replace", "sign accelerate tx failed", errs, "pairID", pairID, "txid", txid)
+			unlockUtxos(bridge, rawTx, txid)
+			return errs
+		}
+		return onSigned(signedTx, childTx)
+	}
+
+	msgHash, msgContext, err := requester.GetDcrmSignMsgHash(rawTx, signArgs)
+	if err != nil {
+		logWorkerError("replace", "GetDcrmSignMsgHash of accelerate tx failed", err, "pairID", pairID, "txid", txid)
+		unlockUtxos(bridge, rawTx, txid)
+		return err
+	}
+	err = signMgr.submit(&signTask{
+		pairID:     pairID,
+		swapID:     txid,
+		swapType:   swapType,
+		msgHash:    msgHash,
+		msgContext: msgContext,
+		callback: func(rsv []string, err error) {
+			if err == nil {
+				var (
+					signedTx interface{}
+					childTx  string
+				)
+				signedTx, childTx, err = requester.MakeDcrmSignedTransaction(rawTx, rsv, signArgs)
+				if err == nil {
+					_ = onSigned(signedTx, childTx)
+					return
+				}
+			}
+			logWorkerError("replace", "sign accelerate tx failed", err, "pairID", pairID, "txid", txid)
+			unlockUtxos(bridge, rawTx, txid)
+		},
+	})
+	if err != nil {
+		unlockUtxos(bridge, rawTx, txid)
+		return err
+	}
+	logWorker("replace", "submit accelerate tx to sign", "pairID", pairID, "txid", txid, "swaptx", res.SwapTx, "msghash", msgHash)
+	return nil
+}
+
+// switchToMinedOldSwapTx switch swap tx to the replaced one if it is mined
+func switchToMinedOldSwapTx(pairID string, res *mongodb.MgoSwapResult, bridge tokens.CrossChainBridge) error {
+	for i, oldSwapTx := range res.OldSwapTxs {
+		txStatus := bridge.GetTransactionStatus(oldSwapTx)
+		if txStatus == nil || txStatus.BlockHeight == 0 {
+			continue
+		}
+		logWorker("replace", "replaced swap tx is mined", "pairID", pairID, "txid", res.TxID, "swaptx", oldSwapTx, "replacement", res.SwapTx)
+		oldSwapTxs := make([]string, 0, len(res.OldSwapTxs))
+		oldSwapTxs = append(oldSwapTxs, res.OldSwapTxs[:i]...)
+		oldSwapTxs = append(oldSwapTxs, res.OldSwapTxs[i+1:]...)
+		oldSwapTxs = append(oldSwapTxs, res.SwapTx)
+		matchTx := &MatchTx{
+			SwapTx:     oldSwapTx,
+			OldSwapTxs: oldSwapTxs,
+			SwapValue:  res.SwapValue,
+			SwapType:   tokens.SwapType(res.SwapType),
+		}
+		return updateSwapResult(pairID, res.Key, matchTx)
+	}
+	return nil
+}
@@ -6,11 +6,15 @@ import (
 
 // StartScanJob scan job
 func StartScanJob(isServer bool) {
-	go tokens.SrcBridge.StartPoolTransactionScanJob()
-	go tokens.SrcBridge.StartChainTransactionScanJob()
-	go tokens.SrcBridge.StartSwapHistoryScanJob()
+	for _, pairID := range tokens.GetAllPairIDs() {
+		pair := tokens.GetBridgePair(pairID)
 
-	go tokens.DstBridge.StartPoolTransactionScanJob()
-	go tokens.DstBridge.StartChainTransactionScanJob()
-	go tokens.DstBridge.StartSwapHistoryScanJob()
+		go pair.SrcBridge.StartPoolTransactionScanJob()
+		go pair.SrcBridge.StartChainTransactionScanJob()
+		go pair.SrcBridge.StartSwapHistoryScanJob()
+
+		go pair.DstBridge.StartPoolTransactionScanJob()
+		go pair.DstBridge.StartChainTransactionScanJob()
+		go pair.DstBridge.StartSwapHistoryScanJob()
+	}
 }
@@ -0,0 +1,201 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fsn-dev/crossChain-Bridge/mongodb"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+)
+
+// signIntent durable intent to sign swap tx. it's stored before dcrm signing and removed
+// after the swap tx is recorded in swap result, so a swap whose intent exists may be signed
+// and sent before crash, and it must be reconciled with chain before signing again.
+type signIntent struct {
+	*mongodb.MgoSignIntent
+	pairID  string
+	reused  bool // the intent exists before this attempt, its extra args are reused
+	replace bool // the intent of replacing pending swap tx, which reuses its nonce (or utxos)
+}
+
+// newReplaceSignIntent new sign intent of replacing pending swap tx.
+// the replacement is recorded in swap result before sending, so the intent is only
+// used to hold the nonce while signing, and it's removed at startup if left.
+func newReplaceSignIntent(pairID string, args *tokens.BuildTxArgs) *signIntent {
+	return &signIntent{
+		MgoSignIntent: &mongodb.MgoSignIntent{
+			Key:      mongodb.GetSignIntentKey(args.SwapID, args.SwapType),
+			TxID:     args.SwapID,
+			SwapType: uint32(args.SwapType),
+		},
+		pairID:  pairID,
+		replace: true,
+	}
+}
+
+// reconcileSignIntent reconcile existing sign intent of swap with chain before building swap tx.
+// if swap tx of the intent is found, the swap result is updated and an error is returned.
+// if not found, the extra args (nonce or utxos) of the intent is reused to build swap tx,
+// then the new swap tx conflicts with the old one, and at most one of them can be confirmed.
+func reconcileSignIntent(pairID string, bridge tokens.CrossChainBridge, args *tokens.BuildTxArgs, swapValue string) (*signIntent, error) {
+	key := mongodb.GetSignIntentKey(args.SwapID, args.SwapType)
+	intent := &signIntent{
+		MgoSignIntent: &mongodb.MgoSignIntent{
+			Key:      key,
+			TxID:     args.SwapID,
+			SwapType: uint32(args.SwapType),
+		},
+		pairID: pairID,
+	}
+	existing, err := mongodb.FindSignIntent(pairID, key)
+	if err == mongodb.ErrItemNotFound {
+		return intent, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var extra tokens.AllExtras
+	if err = json.Unmarshal([]byte(existing.Extra), &extra); err != nil {
+		return nil, fmt.Errorf("wrong extra of sign intent %v: %w", key, err)
+	}
+	args.Extra = &extra
+
+	finder, ok := bridge.(tokens.SwapTxFinder)
+	if !ok {
+		return nil, fmt.Errorf("sign intent %v exist, but bridge can not find swap tx", key)
+	}
+	txHash, err := finder.FindSwapTx(args, existing.BlockHeight)
+	switch {
+	case err == tokens.ErrSwapTxConflicted:
+		// the old swap tx can never be confirmed, sign a new one
+		logWorker("signintent", "swap tx of sign intent is conflicted", "pairID", pairID, "key", key, "extra", existing.Extra)
+		_ = mongodb.RemoveSignIntent(pairID, key)
+		args.Extra = nil
+		return intent, nil
+	case err != nil:
+		logWorkerError("signintent", "find swap tx of sign intent failed", err, "pairID", pairID, "key", key)
+		return nil, err
+	case txHash != "":
+		err = recordSwapTxOfSignIntent(pairID, args, txHash, swapValue)
+		if err != nil {
+			return nil, err
+		}
+		_ = mongodb.RemoveSignIntent(pairID, key)
+		return nil, fmt.Errorf("%v already swapped to %v (found by sign intent)", args.SwapID, txHash)
+	}
+	logWorker("signintent", "swap tx of sign intent is not found, reuse its extra", "pairID", pairID, "key", key, "extra", existing.Extra)
+	intent.MgoSignIntent = existing
+	intent.reused = true
+	return intent, nil
+}
+
+func recordSwapTxOfSignIntent(pairID string, args *tokens.BuildTxArgs, txHash, swapValue string) error {
+	matchTx := &MatchTx{
+		SwapTx:    txHash,
+		SwapNonce: getSwapNonce(args),
+		SwapValue: swapValue,
+		SwapType:  args.SwapType,
+	}
+	err := updateSwapResult(pairID, args.SwapID, matchTx)
+	if err != nil {
+		return err
+	}
+	if args.SwapType == tokens.SwapoutType {
+		err = mongodb.UpdateSwapoutStatus(pairID, args.SwapID, mongodb.TxProcessed, now(), "")
+	} else {
+		err = mongodb.UpdateSwapinStatus(pairID, args.SwapID, mongodb.TxProcessed, now(), "")
+	}
+	if err != nil {
+		return err
+	}
+	logWorker("signintent", "record swap tx of sign intent", "pairID", pairID, "txid", args.SwapID, "swapType", args.SwapType, "swapTx", txHash)
+	return nil
+}
+
+// save store sign intent with the extra args of built swap tx (before dcrm signing),
+// the block height of the first attempt is kept, swap tx is searched from it.
+func (intent *signIntent) save(bridge tokens.CrossChainBridge, args *tokens.BuildTxArgs) error {
+	extra, err := json.Marshal(args.Extra)
+	if err != nil {
+		return err
+	}
+	if !intent.reused {
+		intent.BlockHeight, err = bridge.GetLatestBlockNumber()
+		if err != nil {
+			return err
+		}
+	}
+	intent.Extra = string(extra)
+	intent.Timestamp = now()
+	return mongodb.UpdateSignIntent(intent.pairID, intent.MgoSignIntent)
+}
+
+// remove remove sign intent after swap tx is recorded in swap result
+func (intent *signIntent) remove() {
+	_ = mongodb.RemoveSignIntent(intent.pairID, intent.Key)
+}
+
+// abort handle failure before swap tx is signed. nonce (or utxos) is released and intent
+// is removed only if it's a new intent, the reused one may be signed and sent before.
+// bridge releases the nonce only if it's the highest reserved one.
+// the nonce (or utxos) of replacement is never released, it's used by the pending swap tx.
+func (intent *signIntent) abort(bridge tokens.CrossChainBridge, rawTx interface{}) {
+	if intent.reused {
+		return
+	}
+	intent.remove()
+	if !intent.replace {
+		releaseNonce(bridge, rawTx)
+		unlockUtxos(bridge, rawTx, intent.TxID)
+	}
+}
+
+// processSignIntents reconcile swaps of existing sign intents at startup,
+// it's called in the swap job of the swap type, so the swap is not processed concurrently.
+func processSignIntents(pairID string, swapType tokens.SwapType, process func(string, *mongodb.MgoSwap) error) {
+	intents, err := mongodb.FindSignIntents(pairID)
+	if err != nil {
+		logWorkerError("signintent", "find sign intents error", err, "pairID", pairID)
+		return
+	}
+	isSwapin := swapType != tokens.SwapoutType
+	for _, intent := range intents {
+		if tokens.SwapType(intent.SwapType) != swapType {
+			continue
+		}
+		txid := intent.TxID
+		logWorker("signintent", "reconcile sign intent", "pairID", pairID, "key", intent.Key, "blockHeight", intent.BlockHeight)
+		var (
+			swap *mongodb.MgoSwap
+			res  *mongodb.MgoSwapResult
+		)
+		if isSwapin {
+			swap, err = mongodb.FindSwapin(pairID, txid)
+			if err == nil {
+				res, err = mongodb.FindSwapinResult(pairID, txid)
+			}
+		} else {
+			swap, err = mongodb.FindSwapout(pairID, txid)
+			if err == nil {
+				res, err = mongodb.FindSwapoutResult(pairID, txid)
+			}
+		}
+		if err != nil {
+			logWorkerError("signintent", "find swap of sign intent error", err, "pairID", pairID, "key", intent.Key)
+			continue
+		}
+		if res.SwapTx != "" {
+			// swap tx is recorded but the intent is not removed before crash
+			_ = mongodb.RemoveSignIntent(pairID, intent.Key)
+			continue
+		}
+		if (swapType == tokens.SwapRecallType && swap.Status != mongodb.TxToBeRecall) ||
+			(swapType != tokens.SwapRecallType && swap.Status != mongodb.TxNotSwapped) {
+			logWorker("signintent", "sign intent of swap with unexpected status", "pairID", pairID, "key", intent.Key, "status", swap.Status)
+			continue
+		}
+		if err = process(pairID, swap); err != nil {
+			logWorkerError("signintent", "process swap of sign intent error", err, "pairID", pairID, "key", intent.Key)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsn-dev/crossChain-Bridge/dcrm"
+	"github.com/fsn-dev/crossChain-Bridge/mongodb"
+	"github.com/fsn-dev/crossChain-Bridge/params"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+)
+
+var (
+	signManagerStarter sync.Once
+
+	signMgr = newSignManager()
+
+	errAlreadySigning = errors.New("swap is being signed")
+)
+
+// signTask msgHash of swap tx to be signed by dcrm, callback is called with the sign result
+type signTask struct {
+	pairID     string
+	swapID     string
+	swapType   tokens.SwapType
+	msgHash    []string
+	msgContext []string
+	callback   func(rsv []string, err error)
+}
+
+func (t *signTask) key() string {
+	return getSignTaskKey(t.pairID, t.swapType, t.swapID)
+}
+
+func getSignTaskKey(pairID string, swapType tokens.SwapType, swapID string) string {
+	return strings.ToLower(fmt.Sprintf("%v:%v:%v", pairID, swapType, swapID))
+}
+
+// signRequest submitted sign request (keyID) whose sign status is polling or polled
+type signRequest struct {
+	*mongodb.MgoSignRequest
+	task *signTask // nil if it's resumed after restart and not claimed by swap job
+	done bool
+	rsv  []string
+	err  error
+}
+
+func (req *signRequest) key() string {
+	return getSignTaskKey(req.PairID, tokens.SwapType(req.SwapType), req.SwapID)
+}
+
+func (req *signRequest) isSameMsgHash(msgHash []string) bool {
+	if len(req.MsgHash) != len(msgHash) {
+		return false
+	}
+	for i, hash := range msgHash {
+		if !strings.EqualFold(hash, req.MsgHash[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// signManager submit sign tasks to dcrm sign groups (limited by concurrency of each group),
+// poll sign status of submitted requests concurrently, and hand sign results to callbacks.
+// keyIDs of pending requests are stored, they are polled again and can be claimed after restart.
+type signManager struct {
+	mu       sync.Mutex
+	queue    []*signTask
+	tasks    map[string]*signTask    // queued or signing tasks, key is pairID:swapType:swapID
+	requests map[string]*signRequest // key is keyID
+	loads    map[string]int          // count of polling requests of sign group
+	next     int                     // start index of picking sign group
+	notify   chan struct{}
+}
+
+func newSignManager() *signManager {
+	return &signManager{
+		tasks:    make(map[string]*signTask),
+		requests: make(map[string]*signRequest),
+		loads:    make(map[string]int),
+		notify:   make(chan struct{}, 1),
+	}
+}
+
+// startSignManager resume pending sign requests and start dispatching sign tasks
+func startSignManager() {
+	signManagerStarter.Do(func() {
+		signMgr.resume()
+		go signMgr.run()
+	})
+}
+
+func (m *signManager) resume() {
+	records, err := mongodb.FindSignRequests()
+	if err != nil {
+		logWorkerError("sign", "find pending sign requests error", err)
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, record := range records {
+		req := &signRequest{MgoSignRequest: record}
+		m.requests[record.Key] = req
+		m.loads[record.GroupID]++
+		logWorker("sign", "resume pending sign request", "keyID", record.Key, "pairID", record.PairID, "swapID", record.SwapID, "swapType", record.SwapType, "groupID", record.GroupID)
+		go m.poll(req)
+	}
+}
+
+// isSigning is swap queued or being signed, swap jobs should not process it again
+func (m *signManager) isSigning(pairID string, swapType tokens.SwapType, swapID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, exist := m.tasks[getSignTaskKey(pairID, swapType, swapID)]
+	return exist
+}
+
+// submit queue sign task. if a resumed request of the swap has the same msgHash,
+// it's claimed by the task and no new sign request is submitted.
+func (m *signManager) submit(task *signTask) error {
+	key := task.key()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exist := m.tasks[key]; exist {
+		return errAlreadySigning
+	}
+	m.tasks[key] = task
+	for _, req := range m.requests {
+		if req.task != nil || req.key() != key || !req.isSameMsgHash(task.msgHash) {
+			continue
+		}
+		req.task = task
+		logWorker("sign", "claim resumed sign request", "keyID", req.Key, "key", key, "done", req.done)
+		if req.done {
+			go m.complete(req)
+		}
+		return nil
+	}
+	m.queue = append(m.queue, task)
+	m.wakeup()
+	return nil
+}
+
+func (m *signManager) wakeup() {
+	select {
+	case m.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (m *signManager) run() {
+	logWorker("sign", "start sign manager")
+	for {
+		m.dispatch()
+		m.prune()
+		select {
+		case <-m.notify:
+		case <-time.After(restIntervalInSignJob):
+		}
+	}
+}
+
+// pickSignGroup pick the least loaded sign group under its concurrency limit
+func (m *signManager) pickSignGroup() string {
+	groups := dcrm.GetSignGroups()
+	dcrmConfig := params.GetConfig().Dcrm
+	picked, minLoad := "", 0
+	for i := range groups {
+		group := groups[(m.next+i)%len(groups)]
+		load := m.loads[group]
+		if load >= dcrmConfig.GetSignConcurrency(group) {
+			continue
+		}
+		if picked == "" || load < minLoad {
+			picked, minLoad = group, load
+		}
+	}
+	if picked != "" {
+		m.next++
+	}
+	return picked
+}
+
+func (m *signManager) dispatch() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for len(m.queue) > 0 {
+		group := m.pickSignGroup()
+		if group == "" {
+			return
+		}
+		task := m.queue[0]
+		m.queue = m.queue[1:]
+		m.loads[group]++
+		m.mu.Unlock()
+		m.sign(group, task)
+		m.mu.Lock()
+	}
+}
+
+func (m *signManager) sign(group string, task *signTask) {
+	record := &mongodb.MgoSignRequest{
+		PairID:    task.pairID,
+		SwapID:    task.swapID,
+		SwapType:  uint32(task.swapType),
+		GroupID:   group,
+		MsgHash:   task.msgHash,
+		Timestamp: now(),
+	}
+	req := &signRequest{MgoSignRequest: record, task: task}
+	keyID, err := dcrm.DoSignInGroup(group, task.msgHash, task.msgContext)
+	if err != nil {
+		logWorkerError("sign", "submit sign request failed", err, "key", task.key(), "groupID", group)
+		m.mu.Lock()
+		m.loads[group]--
+		m.mu.Unlock()
+		req.done, req.err = true, err
+		go m.complete(req)
+		return
+	}
+	record.Key = keyID
+	logWorker("sign", "submit sign request success", "keyID", keyID, "key", task.key(), "groupID", group, "msghash", task.msgHash)
+	// keep polling even if storing failed, the request is just not resumed after restart
+	_ = mongodb.UpdateSignRequest(record)
+	m.mu.Lock()
+	m.requests[keyID] = req
+	m.mu.Unlock()
+	go m.poll(req)
+}
+
+func (m *signManager) poll(req *signRequest) {
+	rsv, err := dcrm.WaitSignResult(req.Key, len(req.MsgHash))
+	if err != nil {
+		logWorkerError("sign", "get sign result failed", err, "keyID", req.Key, "key", req.key())
+	} else {
+		logWorker("sign", "get sign result success", "keyID", req.Key, "key", req.key())
+	}
+	m.mu.Lock()
+	m.loads[req.GroupID]--
+	req.done, req.rsv, req.err = true, rsv, err
+	claimed := req.task != nil
+	m.mu.Unlock()
+	m.wakeup()
+	if claimed {
+		m.complete(req)
+	}
+}
+
+// complete call back with sign result, then remove the finished request
+func (m *signManager) complete(req *signRequest) {
+	task := req.task
+	task.callback(req.rsv, req.err)
+	m.mu.Lock()
+	delete(m.tasks, task.key())
+	if req.Key != "" {
+		delete(m.requests, req.Key)
+	}
+	m.mu.Unlock()
+	if req.Key != "" {
+		_ = mongodb.RemoveSignRequest(req.Key)
+	}
+}
+
+// prune remove finished resumed requests which are not claimed,
+// failed ones are useless, and succeeded ones are kept for a while to be claimed.
+func (m *signManager) prune() {
+	sepTime := getSepTimeInFind(maxSignRequestLifetime)
+	var pruned []string
+	m.mu.Lock()
+	for keyID, req := range m.requests {
+		if req.task != nil || !req.done {
+			continue
+		}
+		if req.err != nil || req.Timestamp < sepTime {
+			delete(m.requests, keyID)
+			pruned = append(pruned, keyID)
+		}
+	}
+	m.mu.Unlock()
+	for _, keyID := range pruned {
+		logWorker("sign", "prune unclaimed sign request", "keyID", keyID)
+		_ = mongodb.RemoveSignRequest(keyID)
+	}
+}
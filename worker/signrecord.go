@@ -0,0 +1,59 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fsn-dev/crossChain-Bridge/internal/oracleindex"
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+)
+
+var (
+	errDepositNotIndexed = errors.New("deposit is not found by oracle scanning")
+	errDoubleSign        = errors.New("swap is signed with another msgHash which does not conflict with this one")
+)
+
+// getSpendKeys get nonce or utxos spent by swap tx, txs spending the same one conflict with each other
+func getSpendKeys(extra *tokens.AllExtras) (keys []string) {
+	if extra == nil {
+		return nil
+	}
+	if ethExtra := extra.EthExtra; ethExtra != nil && ethExtra.Nonce != nil {
+		keys = append(keys, fmt.Sprintf("nonce:%v", *ethExtra.Nonce))
+	}
+	if btcExtra := extra.BtcExtra; btcExtra != nil {
+		for _, point := range btcExtra.PreviousOutPoints {
+			keys = append(keys, fmt.Sprintf("utxo:%v:%v", point.Hash, point.Index))
+		}
+	}
+	return keys
+}
+
+// checkDoubleSign a swap can be signed again with another msgHash only if it's a replacement
+// (conflicts with the signed one), otherwise the swap may be paid twice.
+func checkDoubleSign(outflow *swapOutflow, msgHash []string) error {
+	records, err := oracleindex.GetSignRecords(outflow.pairID, uint32(outflow.swapType), outflow.swapID)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if record.IsSameMsgHash(msgHash) || record.IsConflictWith(outflow.spendKeys) {
+			continue
+		}
+		return fmt.Errorf("%w: signed keyID %v msgHash %v", errDoubleSign, record.KeyID, record.MsgHash)
+	}
+	return nil
+}
+
+// addSignRecord persist sign record before agreeing, so the swap can not be signed again with another msgHash
+func addSignRecord(keyID string, outflow *swapOutflow, msgHash []string) error {
+	if outflow == nil {
+		return nil
+	}
+	record := &oracleindex.SignRecord{
+		KeyID:     keyID,
+		MsgHash:   msgHash,
+		SpendKeys: outflow.spendKeys,
+	}
+	return oracleindex.AddSignRecord(outflow.pairID, uint32(outflow.swapType), outflow.swapID, record)
+}
@@ -9,100 +9,99 @@ import (
 )
 
 var (
-	swapinStableStarter  sync.Once
-	swapoutStableStarter sync.Once
+	stableStarter sync.Once
 )
 
 // StartStableJob stable job
 func StartStableJob() {
-	go startSwapinStableJob()
-	go startSwapoutStableJob()
+	stableStarter.Do(func() {
+		for _, pairID := range tokens.GetAllPairIDs() {
+			go startSwapinStableJob(pairID)
+			go startSwapoutStableJob(pairID)
+		}
+	})
 }
 
-func startSwapinStableJob() {
-	swapinStableStarter.Do(func() {
-		logWorker("stable", "start update swapin stable job")
-		for {
-			res, err := findSwapinResultsToStable()
+func startSwapinStableJob(pairID string) {
+	logWorker("stable", "start update swapin stable job", "pairID", pairID)
+	for {
+		res, err := findSwapinResultsToStable(pairID)
+		if err != nil {
+			logWorkerError("stable", "find swapin results error", err, "pairID", pairID)
+		}
+		if len(res) > 0 {
+			logWorker("stable", "find swapin results to stable", "pairID", pairID, "count", len(res))
+		}
+		for _, swap := range res {
+			err = processSwapinStable(pairID, swap)
 			if err != nil {
-				logWorkerError("stable", "find swapin results error", err)
-			}
-			if len(res) > 0 {
-				logWorker("stable", "find swapin results to stable", "count", len(res))
+				logWorkerError("stable", "process swapin stable error", err, "pairID", pairID)
 			}
-			for _, swap := range res {
-				err = processSwapinStable(swap)
-				if err != nil {
-					logWorkerError("stable", "process swapin stable error", err)
-				}
-			}
-			restInJob(restIntervalInStableJob)
 		}
-	})
+		restInJob(restIntervalInStableJob)
+	}
 }
 
-func startSwapoutStableJob() {
-	swapoutStableStarter.Do(func() {
-		logWorker("stable", "start update swapout stable job")
-		for {
-			res, err := findSwapoutResultsToStable()
+func startSwapoutStableJob(pairID string) {
+	logWorker("stable", "start update swapout stable job", "pairID", pairID)
+	for {
+		res, err := findSwapoutResultsToStable(pairID)
+		if err != nil {
+			logWorkerError("stable", "find swapout results error", err, "pairID", pairID)
+		}
+		if len(res) > 0 {
+			logWorker("stable", "find swapout results to stable", "pairID", pairID, "count", len(res))
+		}
+		for _, swap := range res {
+			err = processSwapoutStable(pairID, swap)
 			if err != nil {
-				logWorkerError("stable", "find swapout results error", err)
-			}
-			if len(res) > 0 {
-				logWorker("stable", "find swapout results to stable", "count", len(res))
+				logWorkerError("recall", "process swapout stable error", err, "pairID", pairID)
 			}
-			for _, swap := range res {
-				err = processSwapoutStable(swap)
-				if err != nil {
-					logWorkerError("recall", "process swapout stable error", err)
-				}
-			}
-			restInJob(restIntervalInStableJob)
 		}
-	})
+		restInJob(restIntervalInStableJob)
+	}
 }
 
-func findSwapinResultsToStable() ([]*mongodb.MgoSwapResult, error) {
+func findSwapinResultsToStable(pairID string) ([]*mongodb.MgoSwapResult, error) {
 	status := mongodb.MatchTxNotStable
 	septime := getSepTimeInFind(maxStableLifetime)
-	return mongodb.FindSwapinResultsWithStatus(status, septime)
+	return mongodb.FindSwapinResultsWithStatus(pairID, status, septime)
 }
 
-func findSwapoutResultsToStable() ([]*mongodb.MgoSwapResult, error) {
+func findSwapoutResultsToStable(pairID string) ([]*mongodb.MgoSwapResult, error) {
 	status := mongodb.MatchTxNotStable
 	septime := getSepTimeInFind(maxStableLifetime)
-	return mongodb.FindSwapoutResultsWithStatus(status, septime)
+	return mongodb.FindSwapoutResultsWithStatus(pairID, status, septime)
 }
 
-func processSwapinStable(swap *mongodb.MgoSwapResult) error {
+func processSwapinStable(pairID string, swap *mongodb.MgoSwapResult) error {
 	swapTxID := swap.SwapTx
-	logWorker("stable", "start processSwapinStable", "swaptxid", swapTxID, "status", swap.Status)
+	logWorker("stable", "start processSwapinStable", "pairID", pairID, "swaptxid", swapTxID, "status", swap.Status)
 	var (
 		txStatus      *tokens.TxStatus
 		confirmations uint64
+		bridge        tokens.CrossChainBridge
 	)
 	if swap.SwapType == uint32(tokens.SwapRecallType) {
-		txStatus = tokens.SrcBridge.GetTransactionStatus(swapTxID)
-		token, _ := tokens.SrcBridge.GetTokenAndGateway()
-		confirmations = *token.Confirmations
+		bridge = tokens.GetCrossChainBridge(pairID, true)
 	} else {
-		txStatus = tokens.DstBridge.GetTransactionStatus(swapTxID)
-		token, _ := tokens.DstBridge.GetTokenAndGateway()
-		confirmations = *token.Confirmations
+		bridge = tokens.GetCrossChainBridge(pairID, false)
 	}
+	txStatus = bridge.GetTransactionStatus(swapTxID)
+	token, _ := bridge.GetTokenAndGateway()
+	confirmations = *token.Confirmations
 
 	if txStatus == nil {
 		return fmt.Errorf("[processSwapinStable] tx status is empty, swapTxID=%v", swapTxID)
 	}
 
 	if txStatus.BlockHeight == 0 {
-		return nil
+		return switchToMinedOldSwapTx(pairID, swap, bridge)
 	}
 
 	if swap.SwapHeight != 0 {
 		if txStatus.Confirmations >= confirmations {
-			return markSwapinResultStable(swap.Key)
+			return markSwapinResultStable(pairID, swap.Key)
 		}
 		return nil
 	}
@@ -112,18 +111,19 @@ func processSwapinStable(swap *mongodb.MgoSwapResult) error {
 		SwapTime:   txStatus.BlockTime,
 		SwapType:   tokens.SwapinType,
 	}
-	return updateSwapinResult(swap.Key, matchTx)
+	return updateSwapinResult(pairID, swap.Key, matchTx)
 }
 
-func processSwapoutStable(swap *mongodb.MgoSwapResult) (err error) {
+func processSwapoutStable(pairID string, swap *mongodb.MgoSwapResult) (err error) {
 	swapTxID := swap.SwapTx
-	logWorker("stable", "start processSwapoutStable", "swaptxid", swapTxID, "status", swap.Status)
+	logWorker("stable", "start processSwapoutStable", "pairID", pairID, "swaptxid", swapTxID, "status", swap.Status)
 
 	var txStatus *tokens.TxStatus
 	var confirmations uint64
 
-	txStatus = tokens.SrcBridge.GetTransactionStatus(swapTxID)
-	token, _ := tokens.SrcBridge.GetTokenAndGateway()
+	bridge := tokens.GetCrossChainBridge(pairID, true)
+	txStatus = bridge.GetTransactionStatus(swapTxID)
+	token, _ := bridge.GetTokenAndGateway()
 	confirmations = *token.Confirmations
 
 	if txStatus == nil {
@@ -131,12 +131,12 @@ func processSwapoutStable(swap *mongodb.MgoSwapResult) (err error) {
 	}
 
 	if txStatus.BlockHeight == 0 {
-		return nil
+		return switchToMinedOldSwapTx(pairID, swap, bridge)
 	}
 
 	if swap.SwapHeight != 0 {
 		if txStatus.Confirmations >= confirmations {
-			return markSwapoutResultStable(swap.Key)
+			return markSwapoutResultStable(pairID, swap.Key)
 		}
 		return nil
 	}
@@ -146,5 +146,5 @@ func processSwapoutStable(swap *mongodb.MgoSwapResult) (err error) {
 		SwapTime:   txStatus.BlockTime,
 		SwapType:   tokens.SwapoutType,
 	}
-	return updateSwapoutResult(swap.Key, matchTx)
+	return updateSwapoutResult(pairID, swap.Key, matchTx)
 }
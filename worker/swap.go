@@ -13,8 +13,7 @@ import (
 )
 
 var (
-	swapinSwapStarter  sync.Once
-	swapoutSwapStarter sync.Once
+	swapStarter sync.Once
 
 	swapRing        *ring.Ring
 	swapRingLock    sync.RWMutex
@@ -23,90 +22,97 @@ var (
 
 // StartSwapJob swap job
 func StartSwapJob() {
-	go startSwapinSwapJob()
-	go startSwapoutSwapJob()
+	swapStarter.Do(func() {
+		startSignManager()
+		for _, pairID := range tokens.GetAllPairIDs() {
+			go startSwapinSwapJob(pairID)
+			go startSwapoutSwapJob(pairID)
+		}
+	})
 }
 
-func startSwapinSwapJob() {
-	swapinSwapStarter.Do(func() {
-		logWorker("swap", "start swapin swap job")
-		for {
-			res, err := findSwapinsToSwap()
+func startSwapinSwapJob(pairID string) {
+	logWorker("swap", "start swapin swap job", "pairID", pairID)
+	processSignIntents(pairID, tokens.SwapinType, processSwapinSwap)
+	for {
+		res, err := findSwapinsToSwap(pairID)
+		if err != nil {
+			logWorkerError("swapin", "find swapins error", err, "pairID", pairID)
+		}
+		if len(res) > 0 {
+			logWorker("swapin", "find swapins to swap", "pairID", pairID, "count", len(res))
+		}
+		for _, swap := range res {
+			err = processSwapinSwap(pairID, swap)
 			if err != nil {
-				logWorkerError("swapin", "find swapins error", err)
-			}
-			if len(res) > 0 {
-				logWorker("swapin", "find swapins to swap", "count", len(res))
+				logWorkerError("swapin", "process swapin swap error", err, "pairID", pairID, "txid", swap.TxID)
 			}
-			for _, swap := range res {
-				err = processSwapinSwap(swap)
-				if err != nil {
-					logWorkerError("swapin", "process swapin swap error", err, "txid", swap.TxID)
-				}
-			}
-			restInJob(restIntervalInDoSwapJob)
 		}
-	})
+		restInJob(restIntervalInDoSwapJob)
+	}
 }
 
-func startSwapoutSwapJob() {
-	swapoutSwapStarter.Do(func() {
-		logWorker("swapout", "start swapout swap job")
-		for {
-			res, err := findSwapoutsToSwap()
+func startSwapoutSwapJob(pairID string) {
+	logWorker("swapout", "start swapout swap job", "pairID", pairID)
+	processSignIntents(pairID, tokens.SwapoutType, processSwapoutSwap)
+	for {
+		res, err := findSwapoutsToSwap(pairID)
+		if err != nil {
+			logWorkerError("swapout", "find swapouts error", err, "pairID", pairID)
+		}
+		if len(res) > 0 {
+			logWorker("swapout", "find swapouts to swap", "pairID", pairID, "count", len(res))
+		}
+		for _, swap := range res {
+			err = processSwapoutSwap(pairID, swap)
 			if err != nil {
-				logWorkerError("swapout", "find swapouts error", err)
+				logWorkerError("swapout", "process swapout swap error", err, "pairID", pairID)
 			}
-			if len(res) > 0 {
-				logWorker("swapout", "find swapouts to swap", "count", len(res))
-			}
-			for _, swap := range res {
-				err = processSwapoutSwap(swap)
-				if err != nil {
-					logWorkerError("swapout", "process swapout swap error", err)
-				}
-			}
-			restInJob(restIntervalInDoSwapJob)
 		}
-	})
+		restInJob(restIntervalInDoSwapJob)
+	}
 }
 
-func findSwapinsToSwap() ([]*mongodb.MgoSwap, error) {
+func findSwapinsToSwap(pairID string) ([]*mongodb.MgoSwap, error) {
 	status := mongodb.TxNotSwapped
 	septime := getSepTimeInFind(maxDoSwapLifetime)
-	return mongodb.FindSwapinsWithStatus(status, septime)
+	return mongodb.FindSwapinsWithStatus(pairID, status, septime)
 }
 
-func findSwapoutsToSwap() ([]*mongodb.MgoSwap, error) {
+func findSwapoutsToSwap(pairID string) ([]*mongodb.MgoSwap, error) {
 	status := mongodb.TxNotSwapped
 	septime := getSepTimeInFind(maxDoSwapLifetime)
-	return mongodb.FindSwapoutsWithStatus(status, septime)
+	return mongodb.FindSwapoutsWithStatus(pairID, status, septime)
 }
 
-func processSwapinSwap(swap *mongodb.MgoSwap) (err error) {
+func processSwapinSwap(pairID string, swap *mongodb.MgoSwap) (err error) {
 	txid := swap.TxID
-	bridge := tokens.DstBridge
-	logWorker("swapin", "start processSwapinSwap", "txid", txid, "status", swap.Status)
-	res, err := mongodb.FindSwapinResult(txid)
+	bridge := tokens.GetCrossChainBridge(pairID, false)
+	if signMgr.isSigning(pairID, tokens.SwapinType, txid) {
+		logWorkerTrace("swapin", "ignore swap being signed", "pairID", pairID, "txid", txid)
+		return nil
+	}
+	logWorker("swapin", "start processSwapinSwap", "pairID", pairID, "txid", txid, "status", swap.Status)
+	res, err := mongodb.FindSwapinResult(pairID, txid)
 	if err != nil {
 		return err
 	}
 	if res.SwapTx != "" {
 		if res.Status == mongodb.TxNotSwapped {
-			_ = mongodb.UpdateSwapinStatus(txid, mongodb.TxProcessed, now(), "")
+			_ = mongodb.UpdateSwapinStatus(pairID, txid, mongodb.TxProcessed, now(), "")
 		}
 		return fmt.Errorf("%v already swapped to %v", txid, res.SwapTx)
 	}
 
-	history := getSwapHistory(txid, true)
+	history := getSwapHistory(pairID, txid, true)
 	if history != nil {
 		if _, err = bridge.GetTransaction(history.matchTx); err == nil {
 			matchTx := &MatchTx{
 				SwapTx:   history.matchTx,
 				SwapType: tokens.SwapinType,
 			}
-			_ = updateSwapinResult(txid, matchTx)
-			logWorker("swapin", "ignore swapped swapin", "txid", txid, "matchTx", history.matchTx)
+			_ = updateSwapinResult(pairID, txid, matchTx)
+			logWorker("swapin", "ignore swapped swapin", "pairID", pairID, "txid", txid, "matchTx", history.matchTx)
 			return fmt.Errorf("found swapped in history, txid=%v, matchTx=%v", txid, history.matchTx)
 		}
 	}
@@ -122,81 +128,71 @@ func processSwapinSwap(swap *mongodb.MgoSwap) (err error) {
 			SwapType: tokens.SwapinType,
 			TxType:   tokens.SwapTxType(swap.TxType),
 			Bind:     swap.Bind,
+			PairID:   pairID,
 		},
 		To:    res.Bind,
 		Value: value,
+		Memo:  fmt.Sprintf("%s%s", tokens.UnlockMemoPrefix, res.TxID),
 	}
-	rawTx, err := bridge.BuildRawTransaction(args)
+	swapValue := tokens.CalcSwappedValue(pairID, value, true).String()
+	intent, err := reconcileSignIntent(pairID, bridge, args, swapValue)
 	if err != nil {
-		logWorkerError("swapin", "BuildRawTransaction failed", err, "txid", txid)
 		return err
 	}
-
-	signedTx, txHash, err := bridge.DcrmSignTransaction(rawTx, args.GetExtraArgs())
+	rawTx, err := bridge.BuildRawTransaction(args)
 	if err != nil {
-		logWorkerError("swapin", "DcrmSignTransaction failed", err, "txid", txid)
+		logWorkerError("swapin", "BuildRawTransaction failed", err, "pairID", pairID, "txid", txid)
 		return err
 	}
 
-	// update database before sending transaction
-	addSwapHistory(txid, value, txHash, true)
-	matchTx := &MatchTx{
-		SwapTx:    txHash,
-		SwapValue: tokens.CalcSwappedValue(value, true).String(),
-		SwapType:  tokens.SwapinType,
-	}
-	err = updateSwapinResult(txid, matchTx)
+	err = intent.save(bridge, args)
 	if err != nil {
-		logWorkerError("swapin", "updateSwapinResult failed", err, "txid", txid)
-		return err
-	}
-	err = mongodb.UpdateSwapinStatus(txid, mongodb.TxProcessed, now(), "")
-	if err != nil {
-		logWorkerError("swapin", "UpdateSwapinStatus failed", err, "txid", txid)
+		logWorkerError("swapin", "save sign intent failed", err, "pairID", pairID, "txid", txid)
+		intent.abort(bridge, rawTx)
 		return err
 	}
 
-	for i := 0; i < retrySendTxCount; i++ {
-		if _, err = bridge.SendTransaction(signedTx); err == nil {
-			if tx, _ := bridge.GetTransaction(txHash); tx != nil {
-				break
-			}
-		}
-		time.Sleep(retrySendTxInterval)
-	}
-	if err != nil {
-		logWorkerError("swapin", "update swapin status to TxSwapFailed", err, "txid", txid)
-		_ = mongodb.UpdateSwapinStatus(txid, mongodb.TxSwapFailed, now(), err.Error())
-		_ = mongodb.UpdateSwapinResultStatus(txid, mongodb.TxSwapFailed, now(), err.Error())
-		return err
-	}
-	return nil
+	return signAndSendSwapTx(&swapTxTask{
+		pairID:    pairID,
+		txid:      txid,
+		swapType:  tokens.SwapinType,
+		value:     value,
+		swapValue: swapValue,
+		bridge:    bridge,
+		rawTx:     rawTx,
+		args:      args,
+		intent:    intent,
+	})
 }
 
-func processSwapoutSwap(swap *mongodb.MgoSwap) (err error) {
+func processSwapoutSwap(pairID string, swap *mongodb.MgoSwap) (err error) {
 	txid := swap.TxID
-	bridge := tokens.SrcBridge
-	logWorker("swapout", "start processSwapoutSwap", "txid", txid, "status", swap.Status)
-	res, err := mongodb.FindSwapoutResult(txid)
+	bridge := tokens.GetCrossChainBridge(pairID, true)
+	if signMgr.isSigning(pairID, tokens.SwapoutType, txid) {
+		logWorkerTrace("swapout", "ignore swap being signed", "pairID", pairID, "txid", txid)
+		return nil
+	}
+	logWorker("swapout", "start processSwapoutSwap", "pairID", pairID, "txid", txid, "status", swap.Status)
+	res, err := mongodb.FindSwapoutResult(pairID, txid)
 	if err != nil {
 		return err
 	}
 	if res.SwapTx != "" {
 		if res.Status == mongodb.TxNotSwapped {
-			_ = mongodb.UpdateSwapoutStatus(txid, mongodb.TxProcessed, now(), "")
+			_ = mongodb.UpdateSwapoutStatus(pairID, txid, mongodb.TxProcessed, now(), "")
 		}
 		return fmt.Errorf("%v already swapped to %v", txid, res.SwapTx)
 	}
 
-	history := getSwapHistory(txid, false)
+	history := getSwapHistory(pairID, txid, false)
 	if history != nil {
 		if _, err = bridge.GetTransaction(history.matchTx); err == nil {
 			matchTx := &MatchTx{
 				SwapTx:   history.matchTx,
 				SwapType: tokens.SwapoutType,
 			}
-			_ = updateSwapoutResult(txid, matchTx)
-			logWorker("swapout", "ignore swapped swapout", "txid", txid, "matchTx", history.matchTx)
+			_ = updateSwapoutResult(pairID, txid, matchTx)
+			logWorker("swapout", "ignore swapped swapout", "pairID", pairID, "txid", txid, "matchTx", history.matchTx)
 			return fmt.Errorf("found swapped out history, txid=%v, matchTx=%v", txid, history.matchTx)
 		}
 	}
@@ -210,38 +206,157 @@ func processSwapoutSwap(swap *mongodb.MgoSwap) (err error) {
 		SwapInfo: tokens.SwapInfo{
 			SwapID:   res.TxID,
 			SwapType: tokens.SwapoutType,
+			PairID:   pairID,
 		},
 		To:    res.Bind,
 		Value: value,
 		Memo:  fmt.Sprintf("%s%s", tokens.UnlockMemoPrefix, res.TxID),
 	}
+	swapValue := tokens.CalcSwappedValue(pairID, value, false).String()
+	intent, err := reconcileSignIntent(pairID, bridge, args, swapValue)
+	if err != nil {
+		return err
+	}
 	rawTx, err := bridge.BuildRawTransaction(args)
 	if err != nil {
-		logWorkerError("swapout", "BuildRawTransaction failed", err, "txid", txid)
+		logWorkerError("swapout", "BuildRawTransaction failed", err, "pairID", pairID, "txid", txid)
 		return err
 	}
 
-	signedTx, txHash, err := bridge.DcrmSignTransaction(rawTx, args.GetExtraArgs())
+	err = intent.save(bridge, args)
+	if err != nil {
+		logWorkerError("swapout", "save sign intent failed", err, "pairID", pairID, "txid", txid)
+		intent.abort(bridge, rawTx)
+		return err
+	}
+
+	return signAndSendSwapTx(&swapTxTask{
+		pairID:    pairID,
+		txid:      txid,
+		swapType:  tokens.SwapoutType,
+		value:     value,
+		swapValue: swapValue,
+		bridge:    bridge,
+		rawTx:     rawTx,
+		args:      args,
+		intent:    intent,
+	})
+}
+
+// swapTxTask built swap tx to be signed and sent
+type swapTxTask struct {
+	pairID    string
+	txid      string
+	swapType  tokens.SwapType
+	value     *big.Int
+	swapValue string
+	bridge    tokens.CrossChainBridge
+	rawTx     interface{}
+	args      *tokens.BuildTxArgs
+	intent    *signIntent
+	replaced  *mongodb.MgoSwapResult // swap result whose pending swap tx is replaced, nil if not replacing
+}
+
+func (t *swapTxTask) job() string {
+	if t.replaced != nil {
+		return "replace"
+	}
+	switch t.swapType {
+	case tokens.SwapinType:
+		return "swapin"
+	case tokens.SwapoutType:
+		return "swapout"
+	default:
+		return "recall"
+	}
+}
+
+// signAndSendSwapTx sign swap tx by the sign manager if the bridge supports it,
+// the swap tx is sent in the callback, otherwise sign and send it synchronously.
+func signAndSendSwapTx(t *swapTxTask) error {
+	signArgs := t.args.GetExtraArgs()
+	requester, ok := t.bridge.(tokens.DcrmSignRequester)
+	if !ok {
+		signedTx, txHash, err := t.bridge.DcrmSignTransaction(t.rawTx, signArgs)
+		if err != nil {
+			logWorkerError(t.job(), "DcrmSignTransaction failed", err, "pairID", t.pairID, "txid", t.txid)
+			t.intent.abort(t.bridge, t.rawTx)
+			return err
+		}
+		return t.onSigned(signedTx, txHash)
+	}
+
+	msgHash, msgContext, err := requester.GetDcrmSignMsgHash(t.rawTx, signArgs)
 	if err != nil {
-		logWorkerError("swapout", "DcrmSignTransaction failed", err, "txid", txid)
+		logWorkerError(t.job(), "GetDcrmSignMsgHash failed", err, "pairID", t.pairID, "txid", t.txid)
+		t.intent.abort(t.bridge, t.rawTx)
 		return err
 	}
+	err = signMgr.submit(&signTask{
+		pairID:     t.pairID,
+		swapID:     t.txid,
+		swapType:   t.swapType,
+		msgHash:    msgHash,
+		msgContext: msgContext,
+		callback: func(rsv []string, err error) {
+			if err == nil {
+				var (
+					signedTx interface{}
+					txHash   string
+				)
+				signedTx, txHash, err = requester.MakeDcrmSignedTransaction(t.rawTx, rsv, signArgs)
+				if err == nil {
+					_ = t.onSigned(signedTx, txHash)
+					return
+				}
+			}
+			logWorkerError(t.job(), "DcrmSignTransaction failed", err, "pairID", t.pairID, "txid", t.txid)
+			t.intent.abort(t.bridge, t.rawTx)
+		},
+	})
+	if err != nil {
+		t.intent.abort(t.bridge, t.rawTx)
+		return err
+	}
+	logWorker(t.job(), "submit swap tx to sign", "pairID", t.pairID, "txid", t.txid, "msghash", msgHash)
+	return nil
+}
+
+func (t *swapTxTask) onSigned(signedTx interface{}, txHash string) error {
+	if t.replaced != nil {
+		return onReplaceTxSigned(t, signedTx, txHash)
+	}
+	return onSwapTxSigned(t, signedTx, txHash)
+}
+
+// onSwapTxSigned record signed swap tx in database, then send it
+func onSwapTxSigned(t *swapTxTask, signedTx interface{}, txHash string) (err error) {
+	pairID, txid, bridge := t.pairID, t.txid, t.bridge
+	isSwapin := t.swapType != tokens.SwapoutType
 
 	// update database before sending transaction
-	addSwapHistory(txid, value, txHash, false)
+	if t.swapType != tokens.SwapRecallType {
+		addSwapHistory(pairID, txid, t.value, txHash, signedTx, isSwapin)
+	}
 	matchTx := &MatchTx{
 		SwapTx:    txHash,
-		SwapValue: tokens.CalcSwappedValue(value, false).String(),
-		SwapType:  tokens.SwapoutType,
+		SwapNonce: getSwapNonce(t.args),
+		SwapValue: t.swapValue,
+		SwapType:  t.swapType,
 	}
-	err = updateSwapoutResult(txid, matchTx)
+	err = updateSwapResult(pairID, txid, matchTx)
 	if err != nil {
-		logWorkerError("swapout", "updateSwapoutResult failed", err, "txid", txid)
+		logWorkerError(t.job(), "updateSwapResult failed", err, "pairID", pairID, "txid", txid)
 		return err
 	}
-	err = mongodb.UpdateSwapoutStatus(txid, mongodb.TxProcessed, now(), "")
+	t.intent.remove()
+	if isSwapin {
+		err = mongodb.UpdateSwapinStatus(pairID, txid, mongodb.TxProcessed, now(), "")
+	} else {
+		err = mongodb.UpdateSwapoutStatus(pairID, txid, mongodb.TxProcessed, now(), "")
+	}
 	if err != nil {
-		logWorkerError("swapout", "UpdateSwapoutStatus failed", err, "txid", txid)
+		logWorkerError(t.job(), "update swap status to TxProcessed failed", err, "pairID", pairID, "txid", txid)
 		return err
 	}
 
@@ -254,27 +369,47 @@ func processSwapoutSwap(swap *mongodb.MgoSwap) (err error) {
 		time.Sleep(retrySendTxInterval)
 	}
 	if err != nil {
-		logWorkerError("swapout", "update swapout status to TxSwapFailed", err, "txid", txid)
-		_ = mongodb.UpdateSwapoutStatus(txid, mongodb.TxSwapFailed, now(), err.Error())
-		_ = mongodb.UpdateSwapoutResultStatus(txid, mongodb.TxSwapFailed, now(), err.Error())
+		if _, ok := bridge.(tokens.NonceManager); ok {
+			// swap tx may be broadcast by some nodes, its nonce is kept reserved,
+			// and the swap tx is sent again or replaced by the replace job
+			logWorkerError(t.job(), "send swap tx failed, leave it to replace job", err, "pairID", pairID, "txid", txid, "swaptx", txHash)
+			return err
+		}
+		failedStatus := mongodb.TxSwapFailed
+		if t.swapType == tokens.SwapRecallType {
+			failedStatus = mongodb.TxRecallFailed
+		}
+		logWorkerError(t.job(), "update swap status to failed", err, "pairID", pairID, "txid", txid, "status", failedStatus)
+		if isSwapin {
+			_ = mongodb.UpdateSwapinStatus(pairID, txid, failedStatus, now(), err.Error())
+			_ = mongodb.UpdateSwapinResultStatus(pairID, txid, failedStatus, now(), err.Error())
+		} else {
+			_ = mongodb.UpdateSwapoutStatus(pairID, txid, failedStatus, now(), err.Error())
+			_ = mongodb.UpdateSwapoutResultStatus(pairID, txid, failedStatus, now(), err.Error())
+		}
+		return err
 	}
-	return err
+	return nil
 }
 
 type swapInfo struct {
+	pairID   string
 	txid     string
 	value    *big.Int
 	matchTx  string
+	signedTx interface{} // to rebroadcast
 	isSwapin bool
 }
 
-func addSwapHistory(txid string, value *big.Int, matchTx string, isSwapin bool) {
+func addSwapHistory(pairID, txid string, value *big.Int, matchTx string, signedTx interface{}, isSwapin bool) {
 	// Create the new item as its own ring
 	item := ring.New(1)
 	item.Value = &swapInfo{
+		pairID:   pairID,
 		txid:     txid,
 		value:    value,
 		matchTx:  matchTx,
+		signedTx: signedTx,
 		isSwapin: isSwapin,
 	}
 
@@ -293,7 +428,7 @@ func addSwapHistory(txid string, value *big.Int, matchTx string, isSwapin bool)
 	}
 }
 
-func getSwapHistory(txid string, isSwapin bool) *swapInfo {
+func getSwapHistory(pairID, txid string, isSwapin bool) *swapInfo {
 	swapRingLock.RLock()
 	defer swapRingLock.RUnlock()
 
@@ -304,7 +439,7 @@ func getSwapHistory(txid string, isSwapin bool) *swapInfo {
 	r := swapRing
 	for i := 0; i < r.Len(); i++ {
 		item := r.Value.(*swapInfo)
-		if item.txid == txid && item.isSwapin == isSwapin {
+		if item.pairID == pairID && item.txid == txid && item.isSwapin == isSwapin {
 			return item
 		}
 		r = r.Prev()
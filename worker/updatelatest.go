@@ -17,33 +17,37 @@ func StartUpdateLatestBlockHeightJob() {
 	updateLatestBlockHeightStarter.Do(func() {
 		logWorker("updatelatest", "start update latest block height job")
 		for {
-			updateSrcLatestBlockHeight()
-			updateDstLatestBlockHeight()
+			for _, pairID := range tokens.GetAllPairIDs() {
+				updateSrcLatestBlockHeight(pairID)
+				updateDstLatestBlockHeight(pairID)
+			}
 			time.Sleep(updateLatestBlockHeightInterval)
 		}
 	})
 }
 
-func updateSrcLatestBlockHeight() {
-	srcLatest, err := tokens.SrcBridge.GetLatestBlockNumber()
+func updateSrcLatestBlockHeight(pairID string) {
+	pair := tokens.GetBridgePair(pairID)
+	srcLatest, err := pair.SrcBridge.GetLatestBlockNumber()
 	if err != nil {
-		logWorkerError("updatelatest", "get src latest block number error", err)
+		logWorkerError("updatelatest", "get src latest block number error", err, "pairID", pairID)
 		return
 	}
-	if tokens.SrcLatestBlockHeight != srcLatest {
-		tokens.SrcLatestBlockHeight = srcLatest
-		logWorker("updatelatest", "update src latest block number", "latest", srcLatest)
+	if pair.SrcLatestBlockHeight != srcLatest {
+		pair.SrcLatestBlockHeight = srcLatest
+		logWorker("updatelatest", "update src latest block number", "pairID", pairID, "latest", srcLatest)
 	}
 }
 
-func updateDstLatestBlockHeight() {
-	dstLatest, err := tokens.DstBridge.GetLatestBlockNumber()
+func updateDstLatestBlockHeight(pairID string) {
+	pair := tokens.GetBridgePair(pairID)
+	dstLatest, err := pair.DstBridge.GetLatestBlockNumber()
 	if err != nil {
-		logWorkerError("updatelatest", "get dest latest block number error", err)
+		logWorkerError("updatelatest", "get dest latest block number error", err, "pairID", pairID)
 		return
 	}
-	if tokens.DstLatestBlockHeight != dstLatest {
-		tokens.DstLatestBlockHeight = dstLatest
-		logWorker("updatelatest", "update dest latest block number", "latest", dstLatest)
+	if pair.DstLatestBlockHeight != dstLatest {
+		pair.DstLatestBlockHeight = dstLatest
+		logWorker("updatelatest", "update dest latest block number", "pairID", pairID, "latest", dstLatest)
 	}
 }
@@ -19,6 +19,18 @@ var (
 	maxStableLifetime       = int64(7 * 24 * 3600)
 	restIntervalInStableJob = 3 * time.Second
 
+	maxReplaceLifetime       = int64(7 * 24 * 3600)
+	restIntervalInReplaceJob = 60 * time.Second
+
+	restIntervalInNonceJob = 60 * time.Second
+
+	maxSignRequestLifetime = int64(24 * 3600)
+	restIntervalInSignJob  = 3 * time.Second
+
+	maxReorgLifetime              = int64(7 * 24 * 3600)
+	restIntervalInReorgJob        = 30 * time.Second
+	minReorgTrackedBlocks  uint64 = 10
+
 	retrySendTxCount    = 3
 	retrySendTxInterval = 1 * time.Second
 )
@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"sync"
+
+	"github.com/fsn-dev/crossChain-Bridge/tokens"
+	"github.com/fsn-dev/crossChain-Bridge/tokens/btc"
+)
+
+var (
+	utxoTrackerStarter sync.Once
+)
+
+// StartUtxoTrackerJob utxo tracker job of btc bridges (sync utxos and check expired locks)
+func StartUtxoTrackerJob() {
+	utxoTrackerStarter.Do(func() {
+		for _, pairID := range tokens.GetAllPairIDs() {
+			for _, isSrc := range []bool{true, false} {
+				bridge := btc.GetBridgeOfPair(pairID, isSrc)
+				if bridge == nil {
+					continue
+				}
+				logWorker("utxotracker", "start utxo tracker job", "pairID", pairID, "isSrc", isSrc)
+				go bridge.StartUtxoTrackerJob()
+			}
+		}
+	})
+}
+
+func unlockUtxos(bridge tokens.CrossChainBridge, rawTx interface{}, swapID string) {
+	if utxoLocker, ok := bridge.(tokens.UtxoLocker); ok {
+		utxoLocker.UnlockUtxos(rawTx, swapID)
+	}
+}
@@ -9,92 +9,92 @@ import (
 )
 
 var (
-	swapinVerifyStarter  sync.Once
-	swapoutVerifyStarter sync.Once
+	verifyStarter sync.Once
 )
 
 // StartVerifyJob verify job
 func StartVerifyJob() {
-	go startSwapinVerifyJob()
-	go startSwapoutVerifyJob()
+	verifyStarter.Do(func() {
+		for _, pairID := range tokens.GetAllPairIDs() {
+			go startSwapinVerifyJob(pairID)
+			go startSwapoutVerifyJob(pairID)
+		}
+	})
 }
 
-func startSwapinVerifyJob() {
-	swapinVerifyStarter.Do(func() {
-		logWorker("verify", "start swapin verify job")
-		for {
-			res, err := findSwapinsToVerify()
-			if err != nil {
-				logWorkerError("verify", "find swapins error", err)
-			}
-			if len(res) > 0 {
-				logWorker("verify", "find swapins to verify", "count", len(res))
-			}
-			for _, swap := range res {
-				err = processSwapinVerify(swap)
-				switch err {
-				case nil, tokens.ErrTxNotStable, tokens.ErrTxNotFound:
-				default:
-					logWorkerError("verify", "process swapin verify error", err, "txid", swap.TxID)
-				}
+func startSwapinVerifyJob(pairID string) {
+	logWorker("verify", "start swapin verify job", "pairID", pairID)
+	for {
+		res, err := findSwapinsToVerify(pairID)
+		if err != nil {
+			logWorkerError("verify", "find swapins error", err, "pairID", pairID)
+		}
+		if len(res) > 0 {
+			logWorker("verify", "find swapins to verify", "pairID", pairID, "count", len(res))
+		}
+		for _, swap := range res {
+			err = processSwapinVerify(pairID, swap)
+			switch err {
+			case nil, tokens.ErrTxNotStable, tokens.ErrTxNotFound:
+			default:
+				logWorkerError("verify", "process swapin verify error", err, "pairID", pairID, "txid", swap.TxID)
 			}
-			restInJob(restIntervalInVerifyJob)
 		}
-	})
+		restInJob(restIntervalInVerifyJob)
+	}
 }
 
-func startSwapoutVerifyJob() {
-	swapoutVerifyStarter.Do(func() {
-		logWorker("verify", "start swapout verify job")
-		for {
-			res, err := findSwapoutsToVerify()
-			if err != nil {
-				logWorkerError("verify", "find swapouts error", err)
-			}
-			if len(res) > 0 {
-				logWorker("verify", "find swapouts to verify", "count", len(res))
-			}
-			for _, swap := range res {
-				err = processSwapoutVerify(swap)
-				switch err {
-				case nil, tokens.ErrTxNotStable, tokens.ErrTxNotFound:
-				default:
-					logWorkerError("verify", "process swapout verify error", err, "txid", swap.TxID)
-				}
+func startSwapoutVerifyJob(pairID string) {
+	logWorker("verify", "start swapout verify job", "pairID", pairID)
+	for {
+		res, err := findSwapoutsToVerify(pairID)
+		if err != nil {
+			logWorkerError("verify", "find swapouts error", err, "pairID", pairID)
+		}
+		if len(res) > 0 {
+			logWorker("verify", "find swapouts to verify", "pairID", pairID, "count", len(res))
+		}
+		for _, swap := range res {
+			err = processSwapoutVerify(pairID, swap)
+			switch err {
+			case nil, tokens.ErrTxNotStable, tokens.ErrTxNotFound:
+			default:
+				logWorkerError("verify", "process swapout verify error", err, "pairID", pairID, "txid", swap.TxID)
 			}
-			restInJob(restIntervalInVerifyJob)
 		}
-	})
+		restInJob(restIntervalInVerifyJob)
+	}
 }
 
-func findSwapinsToVerify() ([]*mongodb.MgoSwap, error) {
+func findSwapinsToVerify(pairID string) ([]*mongodb.MgoSwap, error) {
 	status := mongodb.TxNotStable
 	septime := getSepTimeInFind(maxVerifyLifetime)
-	return mongodb.FindSwapinsWithStatus(status, septime)
+	return mongodb.FindSwapinsWithStatus(pairID, status, septime)
 }
 
-func findSwapoutsToVerify() ([]*mongodb.MgoSwap, error) {
+func findSwapoutsToVerify(pairID string) ([]*mongodb.MgoSwap, error) {
 	status := mongodb.TxNotStable
 	septime := getSepTimeInFind(maxVerifyLifetime)
-	return mongodb.FindSwapoutsWithStatus(status, septime)
+	return mongodb.FindSwapoutsWithStatus(pairID, status, septime)
 }
 
-func processSwapinVerify(swap *mongodb.MgoSwap) (err error) {
+func processSwapinVerify(pairID string, swap *mongodb.MgoSwap) (err error) {
 	txid := swap.TxID
 	var swapInfo *tokens.TxSwapInfo
 	switch tokens.SwapTxType(swap.TxType) {
 	case tokens.SwapinTx:
-		swapInfo, err = tokens.SrcBridge.VerifyTransaction(txid, false)
+		swapInfo, err = tokens.GetCrossChainBridge(pairID, true).VerifyTransaction(txid, false)
 	case tokens.P2shSwapinTx:
-		if btc.BridgeInstance == nil {
+		btcBridge := btc.GetBridgeOfPair(pairID, true)
+		if btcBridge == nil {
 			return tokens.ErrWrongP2shSwapin
 		}
-		swapInfo, err = btc.BridgeInstance.VerifyP2shTransaction(txid, swap.Bind, false)
+		swapInfo, err = btcBridge.VerifyP2shTransaction(txid, swap.Bind, false)
 	default:
 		return tokens.ErrWrongSwapinTxType
 	}
 	if swapInfo.Height != 0 &&
-		swapInfo.Height < tokens.GetTokenConfig(true).InitialHeight {
+		swapInfo.Height < tokens.GetTokenConfig(pairID, true).InitialHeight {
 		err = tokens.ErrTxBeforeInitialHeight
 	}
 
@@ -105,25 +105,28 @@ func processSwapinVerify(swap *mongodb.MgoSwap) (err error) {
 		return err
 	case tokens.ErrTxWithWrongMemo:
 		resultStatus = mongodb.TxWithWrongMemo
-		err = mongodb.UpdateSwapinStatus(txid, mongodb.TxCanRecall, now(), err.Error())
+		err = mongodb.UpdateSwapinStatus(pairID, txid, mongodb.TxCanRecall, now(), err.Error())
+	case tokens.ErrTxWithWrongValue:
+		resultStatus = mongodb.TxWithWrongValue
+		err = mongodb.UpdateSwapinStatus(pairID, txid, mongodb.TxCanRecall, now(), err.Error())
 	case nil:
-		err = mongodb.UpdateSwapinStatus(txid, mongodb.TxNotSwapped, now(), "")
+		err = mongodb.UpdateSwapinStatus(pairID, txid, mongodb.TxNotSwapped, now(), "")
 	default:
-		return mongodb.UpdateSwapinStatus(txid, mongodb.TxVerifyFailed, now(), err.Error())
+		return mongodb.UpdateSwapinStatus(pairID, txid, mongodb.TxVerifyFailed, now(), err.Error())
 	}
 
 	if err != nil {
-		logWorkerError("verify", "processSwapinVerify", err, "txid", txid)
+		logWorkerError("verify", "processSwapinVerify", err, "pairID", pairID, "txid", txid)
 		return err
 	}
-	return addInitialSwapinResult(swapInfo, resultStatus)
+	return addInitialSwapinResult(pairID, swapInfo, resultStatus)
 }
 
-func processSwapoutVerify(swap *mongodb.MgoSwap) error {
+func processSwapoutVerify(pairID string, swap *mongodb.MgoSwap) error {
 	txid := swap.TxID
-	swapInfo, err := tokens.DstBridge.VerifyTransaction(txid, false)
+	swapInfo, err := tokens.GetCrossChainBridge(pairID, false).VerifyTransaction(txid, false)
 	if swapInfo.Height != 0 &&
-		swapInfo.Height < tokens.GetTokenConfig(false).InitialHeight {
+		swapInfo.Height < tokens.GetTokenConfig(pairID, false).InitialHeight {
 		err = tokens.ErrTxBeforeInitialHeight
 	}
 
@@ -134,16 +137,16 @@ func processSwapoutVerify(swap *mongodb.MgoSwap) error {
 		return err
 	case tokens.ErrTxWithWrongMemo:
 		resultStatus = mongodb.TxWithWrongMemo
-		err = mongodb.UpdateSwapoutStatus(txid, mongodb.TxCanRecall, now(), err.Error())
+		err = mongodb.UpdateSwapoutStatus(pairID, txid, mongodb.TxCanRecall, now(), err.Error())
 	case nil:
-		err = mongodb.UpdateSwapoutStatus(txid, mongodb.TxNotSwapped, now(), "")
+		err = mongodb.UpdateSwapoutStatus(pairID, txid, mongodb.TxNotSwapped, now(), "")
 	default:
-		return mongodb.UpdateSwapoutStatus(txid, mongodb.TxVerifyFailed, now(), err.Error())
+		return mongodb.UpdateSwapoutStatus(pairID, txid, mongodb.TxVerifyFailed, now(), err.Error())
 	}
 
 	if err != nil {
-		logWorkerError("verify", "processSwapoutVerify", err, "txid", txid)
+		logWorkerError("verify", "processSwapoutVerify", err, "pairID", pairID, "txid", txid)
 		return err
 	}
-	return addInitialSwapoutResult(swapInfo, resultStatus)
+	return addInitialSwapoutResult(pairID, swapInfo, resultStatus)
 }
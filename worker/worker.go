@@ -16,6 +16,9 @@ func StartWork(isServer bool) {
 	client.InitHTTPClient()
 	bridge.InitCrossChainBridge(isServer)
 
+	go StartGatewayHealthCheckJob()
+	time.Sleep(interval)
+
 	go StartScanJob(isServer)
 	time.Sleep(interval)
 
@@ -27,6 +30,9 @@ func StartWork(isServer bool) {
 	go StartUpdateLatestBlockHeightJob()
 	time.Sleep(interval)
 
+	go StartUtxoTrackerJob()
+	time.Sleep(interval)
+
 	go StartVerifyJob()
 	time.Sleep(interval)
 
@@ -36,5 +42,17 @@ func StartWork(isServer bool) {
 	go StartStableJob()
 	time.Sleep(interval)
 
+	go StartReorgJob()
+	time.Sleep(interval)
+
+	go StartRecallJob()
+	time.Sleep(interval)
+
+	go StartNonceJob()
+	time.Sleep(interval)
+
+	go StartReplaceJob()
+	time.Sleep(interval)
+
 	go StartAggregateJob()
 }